@@ -12,5 +12,6 @@ require (
 	github.com/vishvananda/netlink v1.1.0
 	github.com/vishvananda/netns v0.0.0-20200728191858-db3c7e526aae
 	go.uber.org/goleak v1.1.10
-	golang.org/x/sys v0.0.0-20201018230417-eeed37f84f13 // indirect
+	golang.org/x/sys v0.0.0-20201018230417-eeed37f84f13
+	google.golang.org/grpc v1.33.2
 )
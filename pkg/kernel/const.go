@@ -21,6 +21,33 @@ package kernel
 const (
 	// FamilyAll is netlink.FAMILY_ALL
 	FamilyAll = 0x0
+	// FamilyV4 is netlink.FAMILY_V4
+	FamilyV4 = 0x2
+	// FamilyV6 is netlink.FAMILY_V6
+	FamilyV6 = 0xa
 	// NudReachable is netlink.NUD_REACHABLE
 	NudReachable = 0x02
+	// NudPermanent is netlink.NUD_PERMANENT
+	NudPermanent = 0x80
+	// TuntapModeTun is netlink.TUNTAP_MODE_TUN
+	TuntapModeTun = 0x1
+	// TuntapModeTap is netlink.TUNTAP_MODE_TAP
+	TuntapModeTap = 0x2
+	// TuntapDefaults is netlink.TUNTAP_DEFAULTS
+	TuntapDefaults = 0xa000
+	// RouteProtoNSM is the routing protocol number of the routes added by NSM, add "78 nsm" into
+	// /etc/iproute2/rt_protos to see them with "ip route show proto nsm"
+	RouteProtoNSM = 0x4e
+	// RouteFlagOnlink is netlink.FLAG_ONLINK
+	RouteFlagOnlink = 0x4
+	// RouteTableMain is unix.RT_TABLE_MAIN
+	RouteTableMain = 0xfe
+	// RouteScopeLink is netlink.SCOPE_LINK
+	RouteScopeLink = 0xfd
+	// AddrFlagNoDAD is unix.IFA_F_NODAD
+	AddrFlagNoDAD = 0x2
+	// AddrFlagDADFailed is unix.IFA_F_DADFAILED
+	AddrFlagDADFailed = 0x8
+	// AddrFlagTentative is unix.IFA_F_TENTATIVE
+	AddrFlagTentative = 0x40
 )
@@ -18,11 +18,39 @@ package kernel
 
 import (
 	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
 )
 
 const (
 	// FamilyAll is netlink.FAMILY_ALL
 	FamilyAll = netlink.FAMILY_ALL
+	// FamilyV4 is netlink.FAMILY_V4
+	FamilyV4 = netlink.FAMILY_V4
+	// FamilyV6 is netlink.FAMILY_V6
+	FamilyV6 = netlink.FAMILY_V6
 	// NudReachable is netlink.NUD_REACHABLE
 	NudReachable = netlink.NUD_REACHABLE
+	// NudPermanent is netlink.NUD_PERMANENT
+	NudPermanent = netlink.NUD_PERMANENT
+	// TuntapModeTun is netlink.TUNTAP_MODE_TUN
+	TuntapModeTun = netlink.TUNTAP_MODE_TUN
+	// TuntapModeTap is netlink.TUNTAP_MODE_TAP
+	TuntapModeTap = netlink.TUNTAP_MODE_TAP
+	// TuntapDefaults is netlink.TUNTAP_DEFAULTS
+	TuntapDefaults = netlink.TUNTAP_DEFAULTS
+	// RouteProtoNSM is the routing protocol number of the routes added by NSM, add "78 nsm" into
+	// /etc/iproute2/rt_protos to see them with "ip route show proto nsm"
+	RouteProtoNSM = 0x4e
+	// RouteFlagOnlink is netlink.FLAG_ONLINK
+	RouteFlagOnlink = int(netlink.FLAG_ONLINK)
+	// RouteTableMain is unix.RT_TABLE_MAIN
+	RouteTableMain = unix.RT_TABLE_MAIN
+	// RouteScopeLink is netlink.SCOPE_LINK
+	RouteScopeLink = netlink.SCOPE_LINK
+	// AddrFlagNoDAD is unix.IFA_F_NODAD
+	AddrFlagNoDAD = unix.IFA_F_NODAD
+	// AddrFlagDADFailed is unix.IFA_F_DADFAILED
+	AddrFlagDADFailed = unix.IFA_F_DADFAILED
+	// AddrFlagTentative is unix.IFA_F_TENTATIVE
+	AddrFlagTentative = unix.IFA_F_TENTATIVE
 )
@@ -0,0 +1,235 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package billing provides periodic export of the per connection traffic byte deltas with the connection labels to a
+// pluggable sink, so chargeback integrations can be built on top of the kernel Forwarder
+package billing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ifstats"
+)
+
+const defaultInterval = time.Minute
+
+// Record is the connection kernel interface traffic since the previous exported record of the connection
+type Record struct {
+	// ConnectionID is the connection ID
+	ConnectionID string
+	// Labels are the connection labels
+	Labels map[string]string
+	// IfName is the connection kernel interface name
+	IfName string
+	// Since is the time of the previous exported record or the connection start
+	Since time.Time
+	// Until is the time the counters are read
+	Until time.Time
+	// RxBytes is the number of bytes received by the kernel interface since the previous record
+	RxBytes uint64
+	// TxBytes is the number of bytes sent by the kernel interface since the previous record
+	TxBytes uint64
+	// RxPackets is the number of packets received by the kernel interface since the previous record
+	RxPackets uint64
+	// TxPackets is the number of packets sent by the kernel interface since the previous record
+	TxPackets uint64
+}
+
+// Sink exports the records. If it returns an error, the traffic is not lost: it is included into the next records.
+type Sink func(ctx context.Context, records []*Record) error
+
+type entry struct {
+	labels   map[string]string
+	netNSURL string
+	ifName   string
+	since    time.Time
+	last     ifstats.Stats
+}
+
+// Exporter exports the traffic byte deltas of the live connections: the ones passed through the Exporter chain
+// element
+type Exporter struct {
+	sink     Sink
+	interval time.Duration
+
+	lock       sync.Mutex
+	entries    map[string]*entry
+	exportLock sync.Mutex
+}
+
+// New returns a new Exporter exporting the records to the sink
+func New(sink Sink, options ...Option) *Exporter {
+	e := &Exporter{
+		sink:     sink,
+		interval: defaultInterval,
+		entries:  make(map[string]*entry),
+	}
+	for _, opt := range options {
+		opt(e)
+	}
+	return e
+}
+
+// Start starts exporting the records each interval, it stops when ctx is done
+func (e *Exporter) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := e.Export(ctx); err != nil {
+				log.Entry(ctx).Warnf("failed to export traffic records: %s", err.Error())
+			}
+		}
+	}()
+}
+
+// Export reads the counters of all live connections and exports the records once. Connections with the already
+// deleted kernel interfaces are skipped.
+func (e *Exporter) Export(ctx context.Context) error {
+	e.lock.Lock()
+	connIDs := make([]string, 0, len(e.entries))
+	for connID := range e.entries {
+		connIDs = append(connIDs, connID)
+	}
+	e.lock.Unlock()
+
+	return e.export(ctx, connIDs...)
+}
+
+// export reads the counters of the given connections and exports their records, the counters baseline is moved only
+// if the sink succeeds
+func (e *Exporter) export(ctx context.Context, connIDs ...string) error {
+	e.exportLock.Lock()
+	defer e.exportLock.Unlock()
+
+	var records []*Record
+	var current []*ifstats.Stats
+	for _, connID := range connIDs {
+		e.lock.Lock()
+		ent, ok := e.entries[connID]
+		var copied entry
+		if ok {
+			copied = *ent
+		}
+		e.lock.Unlock()
+		if !ok {
+			continue
+		}
+
+		value, err := readCounters(copied.netNSURL, copied.ifName)
+		if err != nil {
+			log.Entry(ctx).Debugf("failed to read traffic counters of connection %s: %s", connID, err.Error())
+			continue
+		}
+		records = append(records, newRecord(connID, &copied, value))
+		current = append(current, value)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := e.sink(ctx, records); err != nil {
+		return errors.Wrap(err, "traffic records sink failed")
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for i, record := range records {
+		if ent, ok := e.entries[record.ConnectionID]; ok {
+			ent.last = *current[i]
+			ent.since = record.Until
+		}
+	}
+	return nil
+}
+
+func newRecord(connID string, ent *entry, value *ifstats.Stats) *Record {
+	increase := value.Sub(&ent.last)
+	return &Record{
+		ConnectionID: connID,
+		Labels:       ent.labels,
+		IfName:       ent.ifName,
+		Since:        ent.since,
+		Until:        time.Now(),
+		RxBytes:      increase.RxBytes,
+		TxBytes:      increase.TxBytes,
+		RxPackets:    increase.RxPackets,
+		TxPackets:    increase.TxPackets,
+	}
+}
+
+// readCounters reads the kernel interface counters in the given net NS or in the current one if there is no URL
+func readCounters(netNSURL, ifName string) (*ifstats.Stats, error) {
+	if netNSURL == "" {
+		return ifstats.Get(ifName)
+	}
+	return ifstats.GetAt(netNSURL, ifName)
+}
+
+// setLive starts tracking the connection, the counters baseline is read on the first Request
+func (e *Exporter) setLive(connID string, connLabels map[string]string, netNSURL, ifName string) {
+	labels := make(map[string]string, len(connLabels))
+	for k, v := range connLabels {
+		labels[k] = v
+	}
+
+	e.lock.Lock()
+	ent, ok := e.entries[connID]
+	if ok && ent.netNSURL == netNSURL && ent.ifName == ifName {
+		ent.labels = labels
+		e.lock.Unlock()
+		return
+	}
+	e.lock.Unlock()
+
+	// the traffic before the connection is established is not billed
+	baseline, err := readCounters(netNSURL, ifName)
+	if err != nil {
+		baseline = new(ifstats.Stats)
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.entries[connID] = &entry{
+		labels:   labels,
+		netNSURL: netNSURL,
+		ifName:   ifName,
+		since:    time.Now(),
+		last:     *baseline,
+	}
+}
+
+// deleteLive exports the last connection record and stops tracking the connection
+func (e *Exporter) deleteLive(ctx context.Context, connID string) {
+	if err := e.export(ctx, connID); err != nil {
+		log.Entry(ctx).Warnf("failed to export the last traffic record of connection %s: %s", connID, err.Error())
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	delete(e.entries, connID)
+}
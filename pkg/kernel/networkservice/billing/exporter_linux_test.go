@@ -0,0 +1,119 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package billing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/billing"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName   = "billing-1"
+	peerName = "billing-2"
+	frameLen = 64
+)
+
+// sendFrame sends a raw Ethernet frame via the net interface
+func sendFrame(t *testing.T, link netlink.Link) {
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_RAW, 0)
+	require.NoError(t, err)
+	defer func() { _ = unix.Close(fd) }()
+
+	frame := make([]byte, frameLen)
+	copy(frame, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	require.NoError(t, unix.Sendto(fd, frame, 0, &unix.SockaddrLinklayer{Ifindex: link.Attrs().Index}))
+}
+
+type sink struct {
+	records []*billing.Record
+	err     error
+}
+
+func (s *sink) export(_ context.Context, records []*billing.Record) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func TestExporter(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+	require.NoError(t, netlink.LinkSetUp(link))
+	peer, err := netlink.LinkByName(peerName)
+	require.NoError(t, err)
+	require.NoError(t, netlink.LinkSetUp(peer))
+	// the frames are dropped until the kernel link watch activates the net interface qdisc
+	require.Eventually(t, func() bool {
+		up, linkErr := netlink.LinkByName(ifName)
+		return linkErr == nil && up.Attrs().OperState == netlink.OperUp
+	}, time.Second, 10*time.Millisecond)
+
+	s := new(sink)
+	exporter := billing.New(s.export)
+	server := exporter.NewServer()
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.InterfaceNameKey: ifName,
+				},
+			},
+			Labels: map[string]string{"tenant": "tenant-1"},
+		},
+	})
+	require.NoError(t, err)
+
+	sendFrame(t, link)
+	require.NoError(t, exporter.Export(context.TODO()))
+	require.Len(t, s.records, 1)
+	require.Equal(t, "conn-1", s.records[0].ConnectionID)
+	require.Equal(t, "tenant-1", s.records[0].Labels["tenant"])
+	require.GreaterOrEqual(t, s.records[0].TxBytes, uint64(frameLen))
+
+	// the traffic is not lost if the sink fails
+	s.err = errors.New("sink is unavailable")
+	sendFrame(t, link)
+	require.Error(t, exporter.Export(context.TODO()))
+	s.err = nil
+	sendFrame(t, link)
+
+	// the last record is exported on Close
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Len(t, s.records, 2)
+	require.GreaterOrEqual(t, s.records[1].TxBytes, uint64(2*frameLen))
+	require.Equal(t, s.records[0].Until, s.records[1].Since)
+
+	require.NoError(t, exporter.Export(context.TODO()))
+	require.Len(t, s.records, 2)
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package billing
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type billingServer struct {
+	exporter *Exporter
+}
+
+// NewServer returns a new server chain element marking the connections with the kernel mechanism live for the
+// Exporter. The last connection record is exported on Close before the kernel interface is deleted, so it should
+// precede the chain elements creating the kernel interface.
+func (e *Exporter) NewServer() networkservice.NetworkServiceServer {
+	return &billingServer{
+		exporter: e,
+	}
+}
+
+func (s *billingServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
+		s.exporter.setLive(conn.GetId(), conn.GetLabels(), mech.GetNetNSURL(), mech.GetInterfaceName(conn))
+	}
+
+	return conn, nil
+}
+
+func (s *billingServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.exporter.deleteLive(ctx, conn.GetId())
+	return next.Server(ctx).Close(ctx, conn)
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpfprog
+
+import (
+	"os"
+	"runtime"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// filterPriority differs from the other tc filters added by NSM (e.g. traffic class)
+	filterPriority = 2
+	filterName     = "nsm"
+
+	bpfObjGet = 7
+)
+
+func (s *bpfProgServer) attach(link netlink.Link) error {
+	if s.xdp != nil {
+		if err := s.xdp.use(func(fd int) error { return netlink.LinkSetXdpFd(link, fd) }); err != nil {
+			return errors.Wrapf(err, "failed to attach XDP program: %v", link.Attrs().Name)
+		}
+	}
+	if s.tcIngress == nil && s.tcEgress == nil {
+		return nil
+	}
+
+	qdisc := &netlink.GenericQdisc{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(0xffff, 0),
+			Parent:    netlink.HANDLE_CLSACT,
+		},
+		QdiscType: "clsact",
+	}
+	if err := netlink.QdiscAdd(qdisc); err != nil && !os.IsExist(err) {
+		return errors.Wrapf(err, "failed to add clsact qdisc: %v", link.Attrs().Name)
+	}
+
+	for parent, program := range map[uint32]*Program{
+		netlink.HANDLE_MIN_INGRESS: s.tcIngress,
+		netlink.HANDLE_MIN_EGRESS:  s.tcEgress,
+	} {
+		if program == nil {
+			continue
+		}
+		parent := parent
+		if err := program.use(func(fd int) error { return netlink.FilterReplace(newFilter(link, parent, fd)) }); err != nil {
+			return errors.Wrapf(err, "failed to attach tc program: %v", link.Attrs().Name)
+		}
+	}
+	return nil
+}
+
+func (s *bpfProgServer) detach(link netlink.Link) error {
+	if s.xdp != nil {
+		if err := netlink.LinkSetXdpFd(link, -1); err != nil {
+			return errors.Wrapf(err, "failed to detach XDP program: %v", link.Attrs().Name)
+		}
+	}
+	for parent, program := range map[uint32]*Program{
+		netlink.HANDLE_MIN_INGRESS: s.tcIngress,
+		netlink.HANDLE_MIN_EGRESS:  s.tcEgress,
+	} {
+		if program == nil {
+			continue
+		}
+		if err := netlink.FilterDel(newFilter(link, parent, -1)); err != nil && !os.IsNotExist(err) && err != unix.EINVAL {
+			return errors.Wrapf(err, "failed to detach tc program: %v", link.Attrs().Name)
+		}
+	}
+	return nil
+}
+
+func newFilter(link netlink.Link, parent uint32, fd int) *netlink.BpfFilter {
+	return &netlink.BpfFilter{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    parent,
+			Handle:    netlink.MakeHandle(0, 1),
+			Priority:  filterPriority,
+			Protocol:  unix.ETH_P_ALL,
+		},
+		Fd:           fd,
+		Name:         filterName,
+		DirectAction: true,
+	}
+}
+
+// use calls f with the program file descriptor, the pinned program is opened for the call only: the attached program is
+// referenced by the kernel
+func (p *Program) use(f func(fd int) error) error {
+	if p.path == "" {
+		return f(p.fd)
+	}
+
+	fd, err := objGet(p.path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	return f(fd)
+}
+
+// objGet opens the BPF object pinned at the path with bpf(BPF_OBJ_GET)
+func objGet(path string) (int, error) {
+	pathname, err := unix.BytePtrFromString(path)
+	if err != nil {
+		return -1, errors.Wrapf(err, "invalid BPF object path: %v", path)
+	}
+
+	attr := struct {
+		pathname  uint64
+		bpfFd     uint32
+		fileFlags uint32
+	}{
+		pathname: uint64(uintptr(unsafe.Pointer(pathname))),
+	}
+	fd, _, errno := unix.Syscall(unix.SYS_BPF, bpfObjGet, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr))
+	runtime.KeepAlive(pathname)
+	if errno != 0 {
+		return -1, errors.Wrapf(errno, "failed to open pinned BPF object: %v", path)
+	}
+	return int(fd), nil
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpfprog
+
+// Option is an option for the BPF program server
+type Option func(s *bpfProgServer)
+
+// WithXDP sets XDP program for the connection kernel interface
+func WithXDP(program *Program) Option {
+	return func(s *bpfProgServer) {
+		s.xdp = program
+	}
+}
+
+// WithTCIngress sets tc program for the connection kernel interface ingress traffic
+func WithTCIngress(program *Program) Option {
+	return func(s *bpfProgServer) {
+		s.tcIngress = program
+	}
+}
+
+// WithTCEgress sets tc program for the connection kernel interface egress traffic
+func WithTCEgress(program *Program) Option {
+	return func(s *bpfProgServer) {
+		s.tcEgress = program
+	}
+}
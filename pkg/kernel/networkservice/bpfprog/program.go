@@ -0,0 +1,41 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpfprog
+
+// Program is a BPF program loaded into the kernel
+type Program struct {
+	fd   int
+	path string
+}
+
+// FD returns the program referenced with the file descriptor, the caller owns the file descriptor and should keep it
+// open while the program is used by the connections. XDP programs should have BPF_PROG_TYPE_XDP type, tc programs
+// should have BPF_PROG_TYPE_SCHED_CLS type.
+func FD(fd int) *Program {
+	return &Program{
+		fd: fd,
+	}
+}
+
+// Pinned returns the program pinned at the path in bpffs (e.g. /sys/fs/bpf/acl), the program is opened on every
+// attach, so it can be replaced by re-pinning
+func Pinned(path string) *Program {
+	return &Program{
+		fd:   -1,
+		path: path,
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bpfprog provides chain element attaching BPF programs to the connection kernel interface
+package bpfprog
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type bpfProgServer struct {
+	xdp       *Program
+	tcIngress *Program
+	tcEgress  *Program
+}
+
+// NewServer returns a new BPF program server chain element. It attaches the programs set with the options to the
+// connection kernel interface: XDP program to the driver (falling back to the generic XDP), tc programs as the
+// direct action bpf filters to the clsact qdisc hooks. The programs are detached on Close. It should be placed after
+// the netns chain element.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	s := &bpfProgServer{}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+func (s *bpfProgServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	mech := kernel.ToMechanism(request.GetConnection().GetMechanism())
+	if mech == nil || s.xdp == nil && s.tcIngress == nil && s.tcEgress == nil {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	ifName := mech.GetInterfaceName(request.GetConnection())
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+	if err = s.attach(link); err != nil {
+		s.detachLogged(ctx, link)
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		s.detachLogged(ctx, link)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (s *bpfProgServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil && (s.xdp != nil || s.tcIngress != nil || s.tcEgress != nil) {
+		if link, err := netlink.LinkByName(mech.GetInterfaceName(conn)); err == nil {
+			s.detachLogged(ctx, link)
+		}
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func (s *bpfProgServer) detachLogged(ctx context.Context, link netlink.Link) {
+	if err := s.detach(link); err != nil {
+		log.Entry(ctx).WithField("bpfProgServer", "detach").Warnf("failed to detach BPF programs: %s", err.Error())
+	}
+}
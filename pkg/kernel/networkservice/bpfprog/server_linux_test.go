@@ -0,0 +1,145 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bpfprog_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/bpfprog"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName   = "bpfprog-1"
+	peerName = "bpfprog-2"
+
+	bpfProgLoad      = 5
+	progTypeSchedCls = 3
+	progTypeXDP      = 6
+	xdpPass          = 2
+	tcActOK          = 0
+)
+
+// loadProgram loads `r0 = ret; exit` BPF program
+func loadProgram(t *testing.T, progType uint32, ret int32) int {
+	insns := []uint64{
+		0xb7 | uint64(uint32(ret))<<32, // mov64 r0, ret
+		0x95,                           // exit
+	}
+	license := []byte("GPL\x00")
+
+	attr := struct {
+		progType uint32
+		insnCnt  uint32
+		insns    uint64
+		license  uint64
+	}{
+		progType: progType,
+		insnCnt:  uint32(len(insns)),
+		insns:    uint64(uintptr(unsafe.Pointer(&insns[0]))),
+		license:  uint64(uintptr(unsafe.Pointer(&license[0]))),
+	}
+	fd, _, errno := unix.Syscall(unix.SYS_BPF, bpfProgLoad, uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr))
+	if errno == syscall.EPERM || errno == syscall.ENOSYS {
+		t.Skipf("BPF programs loading is not permitted: %s", errno.Error())
+	}
+	require.Zero(t, errno)
+	return int(fd)
+}
+
+func TestBPFProgServer(t *testing.T) {
+	xdpFd := loadProgram(t, progTypeXDP, xdpPass)
+	defer func() { _ = unix.Close(xdpFd) }()
+	tcFd := loadProgram(t, progTypeSchedCls, tcActOK)
+	defer func() { _ = unix.Close(tcFd) }()
+
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	conn := &networkservice.Connection{
+		Id: "conn-1",
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+	}
+
+	server := bpfprog.NewServer(
+		bpfprog.WithXDP(bpfprog.FD(xdpFd)),
+		bpfprog.WithTCIngress(bpfprog.FD(tcFd)),
+		bpfprog.WithTCEgress(bpfprog.FD(tcFd)),
+	)
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	require.True(t, link.Attrs().Xdp.Attached)
+	for _, parent := range []uint32{netlink.HANDLE_MIN_INGRESS, netlink.HANDLE_MIN_EGRESS} {
+		filters, err := netlink.FilterList(link, parent)
+		require.NoError(t, err)
+		require.Len(t, filters, 1)
+		require.Equal(t, "bpf", filters[0].Type())
+	}
+
+	// refresh replaces the programs
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	link, err = netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	require.False(t, link.Attrs().Xdp.Attached)
+	for _, parent := range []uint32{netlink.HANDLE_MIN_INGRESS, netlink.HANDLE_MIN_EGRESS} {
+		filters, err := netlink.FilterList(link, parent)
+		require.NoError(t, err)
+		require.Empty(t, filters)
+	}
+}
+
+func TestBPFProgServer_PinnedNotFound(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	conn := &networkservice.Connection{
+		Id: "conn-1",
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+	}
+
+	server := bpfprog.NewServer(bpfprog.WithTCIngress(bpfprog.Pinned("/sys/fs/bpf/not-exists")))
+
+	_, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.Error(t, err)
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package coalesce provides chain element coalescing the refresh storms: when multiple Requests for the same
+// connection queue up (e.g. on control plane hiccups), only the latest one is applied to the kernel
+package coalesce
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// call is a queued Request, all the Requests coalesced into it get its result
+type call struct {
+	ctx     context.Context
+	request *networkservice.NetworkServiceRequest
+	dropped bool
+	start   chan struct{}
+	done    chan struct{}
+	conn    *networkservice.Connection
+	err     error
+}
+
+// executor runs the connection Requests one by one, keeping only the latest queued one
+type executor struct {
+	pending *call
+}
+
+type coalesceServer struct {
+	lock      sync.Mutex
+	executors map[string]*executor
+}
+
+// NewServer returns a new coalesce server chain element. Requests for the connection are passed to the next chain
+// element one by one, while some Request is in progress only the latest queued Request is kept: earlier queued ones
+// get the latest one result. Close drops the queued Requests.
+func NewServer() networkservice.NetworkServiceServer {
+	return &coalesceServer{
+		executors: make(map[string]*executor),
+	}
+}
+
+func (s *coalesceServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	connID := request.GetConnection().GetId()
+
+	s.lock.Lock()
+	e, running := s.executors[connID]
+	if !running {
+		s.executors[connID] = new(executor)
+		s.lock.Unlock()
+
+		conn, err := next.Server(ctx).Request(ctx, request)
+		s.finish(connID)
+		return conn, err
+	}
+	if c := e.pending; c != nil {
+		log.Entry(ctx).WithField("coalesceServer", "Request").Debugf("coalescing refresh for connection %s", connID)
+		c.ctx, c.request = ctx, request
+		s.lock.Unlock()
+
+		return c.wait(ctx)
+	}
+	c := &call{
+		ctx:     ctx,
+		request: request,
+		start:   make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	e.pending = c
+	s.lock.Unlock()
+
+	// the call creator runs it even if its context is done, so the executor is never stuck
+	<-c.start
+	if !c.dropped {
+		c.conn, c.err = next.Server(c.ctx).Request(c.ctx, c.request)
+		s.finish(connID)
+	}
+	close(c.done)
+
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.conn.Clone(), nil
+}
+
+func (s *coalesceServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.lock.Lock()
+	if e, ok := s.executors[conn.GetId()]; ok && e.pending != nil {
+		e.pending.dropped = true
+		e.pending.err = errors.Errorf("connection is closed: %v", conn.GetId())
+		close(e.pending.start)
+		e.pending = nil
+	}
+	s.lock.Unlock()
+
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+// finish starts the queued call or deletes the executor if there is no one
+func (s *coalesceServer) finish(connID string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	e := s.executors[connID]
+	if e.pending == nil {
+		delete(s.executors, connID)
+		return
+	}
+	close(e.pending.start)
+	e.pending = nil
+}
+
+func (c *call) wait(ctx context.Context) (*networkservice.Connection, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.done:
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.conn.Clone(), nil
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coalesce_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/coalesce"
+)
+
+type blockingServer struct {
+	lock     sync.Mutex
+	srcAddrs []string
+	started  chan struct{}
+	release  chan struct{}
+}
+
+func (s *blockingServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.lock.Lock()
+	s.srcAddrs = append(s.srcAddrs, request.GetConnection().GetContext().GetIpContext().GetSrcIpAddr())
+	s.lock.Unlock()
+
+	s.started <- struct{}{}
+	<-s.release
+
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *blockingServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func request(srcIPAddr string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Context: &networkservice.ConnectionContext{
+				IpContext: &networkservice.IPContext{
+					SrcIpAddr: srcIPAddr,
+				},
+			},
+		},
+	}
+}
+
+type result struct {
+	conn *networkservice.Connection
+	err  error
+}
+
+func TestCoalesceServer(t *testing.T) {
+	blocking := &blockingServer{
+		started: make(chan struct{}, 10),
+		release: make(chan struct{}),
+	}
+	server := chain.NewNetworkServiceServer(
+		coalesce.NewServer(),
+		blocking,
+	)
+
+	results := make([]chan *result, 4)
+	send := func(i int, srcIPAddr string) {
+		results[i] = make(chan *result, 1)
+		go func() {
+			conn, err := server.Request(context.TODO(), request(srcIPAddr))
+			results[i] <- &result{conn: conn, err: err}
+		}()
+	}
+
+	send(0, "10.0.0.1/32")
+	<-blocking.started
+
+	// refresh storm while the first Request is in progress
+	for i, srcIPAddr := range []string{"10.0.0.2/32", "10.0.0.3/32", "10.0.0.4/32"} {
+		send(i+1, srcIPAddr)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	blocking.release <- struct{}{}
+	<-blocking.started
+	blocking.release <- struct{}{}
+
+	for i, srcIPAddr := range []string{"10.0.0.1/32", "10.0.0.4/32", "10.0.0.4/32", "10.0.0.4/32"} {
+		r := <-results[i]
+		require.NoError(t, r.err)
+		require.Equal(t, srcIPAddr, r.conn.GetContext().GetIpContext().GetSrcIpAddr())
+	}
+	require.Equal(t, []string{"10.0.0.1/32", "10.0.0.4/32"}, blocking.srcAddrs)
+
+	// executor is released after the storm
+	send(0, "10.0.0.5/32")
+	<-blocking.started
+	blocking.release <- struct{}{}
+	require.NoError(t, (<-results[0]).err)
+}
+
+func TestCoalesceServer_Close(t *testing.T) {
+	blocking := &blockingServer{
+		started: make(chan struct{}, 10),
+		release: make(chan struct{}),
+	}
+	server := chain.NewNetworkServiceServer(
+		coalesce.NewServer(),
+		blocking,
+	)
+
+	first := make(chan error, 1)
+	go func() {
+		_, err := server.Request(context.TODO(), request("10.0.0.1/32"))
+		first <- err
+	}()
+	<-blocking.started
+
+	queued := make(chan error, 1)
+	go func() {
+		_, err := server.Request(context.TODO(), request("10.0.0.2/32"))
+		queued <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	_, err := server.Close(context.TODO(), request("").GetConnection())
+	require.NoError(t, err)
+	require.Error(t, <-queued)
+
+	blocking.release <- struct{}{}
+	require.NoError(t, <-first)
+	require.Equal(t, []string{"10.0.0.1/32"}, blocking.srcAddrs)
+}
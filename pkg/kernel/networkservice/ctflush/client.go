@@ -0,0 +1,58 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctflush
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type ctFlushClient struct{}
+
+// NewClient returns a new conntrack flush client chain element. On Close it deletes the conntrack entries of the
+// connection IP addresses from the current net NS.
+func NewClient() networkservice.NetworkServiceClient {
+	return &ctFlushClient{}
+}
+
+func (c *ctFlushClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	return next.Client(ctx).Request(ctx, request, opts...)
+}
+
+func (c *ctFlushClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var flushErr error
+	if kernel.ToMechanism(conn.GetMechanism()) != nil {
+		flushErr = flush(ctx, conn, "ctFlushClient")
+	}
+
+	if err != nil && flushErr != nil {
+		return nil, errors.Wrap(err, flushErr.Error())
+	}
+	if flushErr != nil {
+		return nil, flushErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctflush
+
+import (
+	"context"
+	"net"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ipaddrs"
+)
+
+// flush deletes the conntrack entries having any of the connection Src and Dst IP addresses in any direction from the
+// current net NS, so NAT-ed entries are deleted too
+func flush(ctx context.Context, conn *networkservice.Connection, logField string) error {
+	var ips []net.IP
+	for _, ipAddrs := range []string{
+		conn.GetContext().GetIpContext().GetSrcIpAddr(),
+		conn.GetContext().GetIpContext().GetDstIpAddr(),
+	} {
+		ipNets, err := ipaddrs.Parse(ipAddrs)
+		if err != nil {
+			return err
+		}
+		for _, ipNet := range ipNets {
+			ips = append(ips, ipNet.IP)
+		}
+	}
+	if len(ips) == 0 {
+		return nil
+	}
+
+	count, err := deleteFlows(ips)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		log.Entry(ctx).WithField(logField, "Close").Debugf("flushed %d conntrack entries of connection %s", count, conn.GetId())
+	}
+	return nil
+}
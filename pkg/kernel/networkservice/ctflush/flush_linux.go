@@ -0,0 +1,49 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctflush
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+)
+
+// ipFilter matches the conntrack flows having any of the IP addresses in any direction
+type ipFilter []net.IP
+
+func (f ipFilter) MatchConntrackFlow(flow *netlink.ConntrackFlow) bool {
+	for _, ip := range f {
+		if ip.Equal(flow.Forward.SrcIP) || ip.Equal(flow.Forward.DstIP) ||
+			ip.Equal(flow.Reverse.SrcIP) || ip.Equal(flow.Reverse.DstIP) {
+			return true
+		}
+	}
+	return false
+}
+
+func deleteFlows(ips []net.IP) (uint, error) {
+	var result uint
+	for _, family := range []netlink.InetFamily{netlink.FAMILY_V4, netlink.FAMILY_V6} {
+		count, err := netlink.ConntrackDeleteFilter(netlink.ConntrackTable, family, ipFilter(ips))
+		if err != nil {
+			return result, errors.Wrapf(err, "failed to flush conntrack entries: %v", ips)
+		}
+		result += count
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ctflush provides chain elements flushing the connection conntrack entries on Close
+package ctflush
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type ctFlushServer struct{}
+
+// NewServer returns a new conntrack flush server chain element. On Close it deletes the conntrack entries of the
+// connection IP addresses from the current net NS, so the stale NAT and conntrack state doesn't blackhole the
+// re-established connection reusing the same IP addresses. It should follow the netns chain element to flush the
+// Client's net NS entries.
+func NewServer() networkservice.NetworkServiceServer {
+	return &ctFlushServer{}
+}
+
+func (s *ctFlushServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *ctFlushServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var flushErr error
+	if kernel.ToMechanism(conn.GetMechanism()) != nil {
+		flushErr = flush(ctx, conn, "ctFlushServer")
+	}
+
+	if err != nil && flushErr != nil {
+		return nil, errors.Wrap(err, flushErr.Error())
+	}
+	if flushErr != nil {
+		return nil, flushErr
+	}
+	return &empty.Empty{}, err
+}
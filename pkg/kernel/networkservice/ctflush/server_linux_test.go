@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ctflush_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ctflush"
+)
+
+func newConn(srcIPAddr string) *networkservice.Connection {
+	return &networkservice.Connection{
+		Id: "conn-1",
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+		},
+		Context: &networkservice.ConnectionContext{
+			IpContext: &networkservice.IPContext{
+				SrcIpAddr: srcIPAddr,
+				DstIpAddr: "10.0.30.2/32",
+			},
+		},
+	}
+}
+
+func TestCTFlushServer(t *testing.T) {
+	server := ctflush.NewServer()
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: newConn("10.0.30.1/32,fd00::30:1/128"),
+	})
+	require.NoError(t, err)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
+
+func TestCTFlushClient_InvalidIPAddress(t *testing.T) {
+	client := ctflush.NewClient()
+
+	conn, err := client.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: newConn("10.0.30.1"),
+	})
+	require.NoError(t, err)
+
+	_, err = client.Close(context.TODO(), conn)
+	require.Error(t, err)
+
+	// only the kernel mechanism connections are flushed
+	conn.Mechanism = nil
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
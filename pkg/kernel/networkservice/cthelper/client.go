@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cthelper
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nft"
+)
+
+type ctHelperClient struct{}
+
+// NewClient returns a new conntrack helper client chain element. It enables conntrack helpers requested with
+// HelpersLabel for the traffic going through the net interface selected by the returned connection mechanism.
+// Helpers are programmed as a separate nftables table per connection in the current net NS, the table is deleted on
+// refresh if the helpers are dropped.
+func NewClient() networkservice.NetworkServiceClient {
+	return &ctHelperClient{}
+}
+
+func (c *ctHelperClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return conn, nil
+	}
+
+	table, err := newTable(conn, mech.GetInterfaceName(conn), metadata.IsClient(c))
+	if err == nil {
+		if table == nil {
+			err = deleteTable(ctx, metadata.IsClient(c))
+		} else if err = nft.Apply(ctx, table); err == nil {
+			store(ctx, metadata.IsClient(c), table)
+		}
+	}
+	if err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c *ctHelperClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	deleteErr := deleteTable(ctx, metadata.IsClient(c))
+
+	if err != nil && deleteErr != nil {
+		return nil, errors.Wrap(err, deleteErr.Error())
+	}
+	if deleteErr != nil {
+		return nil, deleteErr
+	}
+	return &empty.Empty{}, err
+}
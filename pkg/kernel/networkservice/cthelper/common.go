@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cthelper
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nft"
+)
+
+// HelpersLabel is a connection label with comma separated list of conntrack helpers to enable: "ftp,tftp,sip"
+const HelpersLabel = "conntrackHelpers"
+
+type helper struct {
+	protocol string
+	port     int
+}
+
+var helpers = map[string]helper{
+	"ftp":  {protocol: "tcp", port: 21},
+	"tftp": {protocol: "udp", port: 69},
+	"sip":  {protocol: "udp", port: 5060},
+}
+
+// tableName returns the nftables table name of the connection side, so both Client and Server sides of the same
+// connection can have their own table in the same net NS
+func tableName(connID string, isClient bool) string {
+	if isClient {
+		return nft.TableName(connID + "/client")
+	}
+	return nft.TableName(connID)
+}
+
+// newTable returns nftables table assigning the requested conntrack helpers to the connections going through the
+// net interface, it returns nil if no helpers are requested
+func newTable(conn *networkservice.Connection, ifName string, isClient bool) (*nft.Table, error) {
+	value := conn.GetLabels()[HelpersLabel]
+	if value == "" {
+		return nil, nil
+	}
+
+	table := &nft.Table{
+		Family: nft.FamilyInet,
+		Name:   tableName(conn.GetId(), isClient),
+	}
+	prerouting := table.AddChain(&nft.Chain{
+		Name: "prerouting",
+		Type: "filter",
+		Hook: "prerouting",
+	})
+	output := table.AddChain(&nft.Chain{
+		Name: "output",
+		Type: "filter",
+		Hook: "output",
+	})
+
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		h, ok := helpers[name]
+		if !ok {
+			return nil, errors.Errorf("unsupported conntrack helper: %v", name)
+		}
+		table.AddObject(`ct helper %s { type "%s" protocol %s; l3proto inet; }`, name, name, h.protocol)
+		prerouting.AddRule(`iifname "%s" %s dport %d ct helper set "%s"`, ifName, h.protocol, h.port, name)
+		output.AddRule(`oifname "%s" %s dport %d ct helper set "%s"`, ifName, h.protocol, h.port, name)
+	}
+
+	return table, nil
+}
+
+// deleteTable deletes the connection table applied on the previous Request, e.g. on refresh if the helpers have been
+// dropped from the connection
+func deleteTable(ctx context.Context, isClient bool) error {
+	if table, ok := loadAndDelete(ctx, isClient); ok {
+		return nft.Delete(ctx, table.Family, table.Name)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cthelper
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nft"
+)
+
+type keyType struct{}
+
+// store stores the connection nftables table applied to the current net NS
+func store(ctx context.Context, isClient bool, table *nft.Table) {
+	metadata.Map(ctx, isClient).Store(keyType{}, table)
+}
+
+func load(ctx context.Context, isClient bool) (*nft.Table, bool) {
+	if raw, ok := metadata.Map(ctx, isClient).Load(keyType{}); ok {
+		return raw.(*nft.Table), true
+	}
+	return nil, false
+}
+
+func loadAndDelete(ctx context.Context, isClient bool) (*nft.Table, bool) {
+	if raw, ok := metadata.Map(ctx, isClient).LoadAndDelete(keyType{}); ok {
+		return raw.(*nft.Table), true
+	}
+	return nil, false
+}
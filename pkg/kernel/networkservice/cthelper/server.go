@@ -0,0 +1,92 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cthelper provides chain element enabling conntrack helpers for the Client's net interface
+package cthelper
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nft"
+)
+
+type ctHelperServer struct{}
+
+// NewServer returns a new conntrack helper server chain element. It enables conntrack helpers requested with
+// HelpersLabel for the traffic going through the Client's net interface. Helpers are programmed as a separate
+// nftables table per connection in the current net NS, the table is deleted on refresh if the helpers are dropped.
+func NewServer() networkservice.NetworkServiceServer {
+	return &ctHelperServer{}
+}
+
+func (s *ctHelperServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	mech := kernel.ToMechanism(request.GetConnection().GetMechanism())
+	if mech == nil {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	table, err := newTable(request.GetConnection(), mech.GetInterfaceName(request.GetConnection()), metadata.IsClient(s))
+	if err != nil {
+		return nil, err
+	}
+	if table == nil {
+		if err = deleteTable(ctx, metadata.IsClient(s)); err != nil {
+			return nil, err
+		}
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	_, loaded := load(ctx, metadata.IsClient(s))
+	if err = nft.Apply(ctx, table); err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		// the table applied on the previous Request is already replaced, so it is kept on refresh
+		if !loaded {
+			if deleteErr := nft.Delete(ctx, table.Family, table.Name); deleteErr != nil {
+				log.Entry(ctx).WithField("ctHelperServer", "Request").Warnf("failed to delete conntrack helpers: %s", deleteErr.Error())
+			}
+		}
+		return nil, err
+	}
+	store(ctx, metadata.IsClient(s), table)
+	return conn, nil
+}
+
+func (s *ctHelperServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	deleteErr := deleteTable(ctx, metadata.IsClient(s))
+
+	if err != nil && deleteErr != nil {
+		return nil, errors.Wrap(err, deleteErr.Error())
+	}
+	if deleteErr != nil {
+		return nil, deleteErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customnetlink
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+// applied is a state of the kernel settings applied by the element. Only the settings actually changed by the element
+// are stored, so cleanup never touches anything created by someone else.
+type applied struct {
+	ifName  string
+	spec    *parsedSpec
+	addrs   []*netlink.Addr
+	routes  []*netlink.Route
+	sysctls *sysctl.Snapshot
+}
+
+// apply applies the spec to the net interface, on error it reverts everything already applied
+func apply(spec *parsedSpec, ifName string) (*applied, error) {
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+
+	sysctls, err := sysctl.Take()
+	if err != nil {
+		return nil, err
+	}
+	state := &applied{
+		ifName:  ifName,
+		sysctls: sysctls,
+	}
+	if err := state.apply(spec, link); err != nil {
+		if revertErr := state.revert(); revertErr != nil {
+			return nil, errors.Wrap(err, revertErr.Error())
+		}
+		return nil, err
+	}
+	return state, nil
+}
+
+// refresh refreshes the connection state applied on the previous Request with the spec, it returns false if there is
+// no such state or it has been reverted. The state is reverted and deleted if the net interface has changed, if the
+// spec is nil (has been dropped from the connection) or on failure.
+func refresh(ctx context.Context, isClient bool, spec *parsedSpec, ifName string) (bool, error) {
+	state, ok := load(ctx, isClient)
+	if !ok {
+		return false, nil
+	}
+
+	var err error
+	if spec != nil && state.ifName == ifName {
+		if err = state.refresh(spec); err == nil {
+			return true, nil
+		}
+	}
+
+	_, _ = loadAndDelete(ctx, isClient)
+	if revertErr := state.revert(); revertErr != nil {
+		if err != nil {
+			return false, errors.Wrap(err, revertErr.Error())
+		}
+		return false, revertErr
+	}
+	return false, err
+}
+
+// refresh applies the spec to the net interface again on refresh: the settings dropped from the previously applied
+// spec are reverted, the rest are applied again, so the settings changed by someone else since the previous Request
+// are fixed
+func (a *applied) refresh(spec *parsedSpec) error {
+	link, err := netlink.LinkByName(a.ifName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get net interface: %v", a.ifName)
+	}
+	for _, route := range spec.routes {
+		route.LinkIndex = link.Attrs().Index
+	}
+
+	routes := a.routes[:0]
+	for _, route := range a.routes {
+		if containsRoute(spec.routes, route) {
+			routes = append(routes, route)
+			continue
+		}
+		if err := netlink.RouteDel(route); err != nil && err != unix.ESRCH {
+			return errors.Wrapf(err, "failed to delete route: %v", route.Dst)
+		}
+	}
+	a.routes = routes
+
+	addrs := a.addrs[:0]
+	for _, addr := range a.addrs {
+		if containsAddr(spec.addrs, addr) {
+			addrs = append(addrs, addr)
+			continue
+		}
+		if err := netlink.AddrDel(link, addr); err != nil && err != unix.EADDRNOTAVAIL {
+			return errors.Wrapf(err, "failed to delete IP address from the net interface: %v %v", a.ifName, addr)
+		}
+	}
+	a.addrs = addrs
+
+	for name := range a.spec.sysctls {
+		if _, ok := spec.sysctls[name]; !ok {
+			if err := a.sysctls.Revert(sysctlPath(name, a.ifName)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return a.apply(spec, link)
+}
+
+func (a *applied) apply(spec *parsedSpec, link netlink.Link) error {
+	a.spec = spec
+
+	for name, value := range spec.sysctls {
+		if err := a.sysctls.Set(sysctlPath(name, a.ifName), value); err != nil {
+			return err
+		}
+	}
+
+	ipAddrs, err := netlink.AddrList(link, kernel.FamilyAll)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the net interface IP addresses: %v", a.ifName)
+	}
+addrs:
+	for _, addr := range spec.addrs {
+		for i := range ipAddrs {
+			if addr.Equal(ipAddrs[i]) {
+				continue addrs
+			}
+		}
+		if err := netlink.AddrAdd(link, addr); err != nil {
+			return errors.Wrapf(err, "failed to add IP address to the net interface: %v %v", a.ifName, addr)
+		}
+		if !containsAddr(a.addrs, addr) {
+			a.addrs = append(a.addrs, addr)
+		}
+	}
+
+	for _, route := range spec.routes {
+		route.LinkIndex = link.Attrs().Index
+		if err := netlink.RouteAdd(route); err != nil {
+			if os.IsExist(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to add route: %v", route.Dst)
+		}
+		if !containsRoute(a.routes, route) {
+			a.routes = append(a.routes, route)
+		}
+	}
+
+	return nil
+}
+
+// revert reverts the applied settings, it doesn't stop on errors and returns them combined
+func (a *applied) revert() error {
+	link, err := netlink.LinkByName(a.ifName)
+	if err != nil {
+		// the net interface has been already deleted with all its settings
+		return nil
+	}
+
+	var errs []string
+	for i := len(a.routes) - 1; i >= 0; i-- {
+		if err := netlink.RouteDel(a.routes[i]); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to delete route: %v", a.routes[i].Dst).Error())
+		}
+	}
+	for i := len(a.addrs) - 1; i >= 0; i-- {
+		if err := netlink.AddrDel(link, a.addrs[i]); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to delete IP address from the net interface: %v %v", a.ifName, a.addrs[i]).Error())
+		}
+	}
+	if err := a.sysctls.Restore(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) != 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+func containsAddr(addrs []*netlink.Addr, addr *netlink.Addr) bool {
+	for _, a := range addrs {
+		if a.Equal(*addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsRoute(routes []*netlink.Route, route *netlink.Route) bool {
+	for _, r := range routes {
+		if r.Equal(*route) {
+			return true
+		}
+	}
+	return false
+}
+
+// sysctlPath returns slashed sysctl name for the per interface parameter, interface name can contain dots
+func sysctlPath(name, ifName string) string {
+	parts := strings.SplitN(name, ".", 2)
+	return strings.Join([]string{"net", parts[0], "conf", ifName, parts[1]}, "/")
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customnetlink
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type customNetlinkClient struct{}
+
+// NewClient returns a new custom netlink client chain element applying the Spec to the net interface selected by the
+// returned connection mechanism in the current net NS. The spec is applied again on each refresh. Applied settings are
+// reverted on Close.
+func NewClient() networkservice.NetworkServiceClient {
+	return &customNetlinkClient{}
+}
+
+func (c *customNetlinkClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	value, ok := conn.GetContext().GetExtraContext()[SpecKey]
+	if mech == nil || !ok {
+		// the spec dropped on refresh is reverted
+		if _, err = refresh(ctx, metadata.IsClient(c), nil, ""); err == nil {
+			return conn, nil
+		}
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	spec, err := parseSpec(value)
+	if err == nil {
+		// the spec is applied again on refresh, so the settings changed since the previous Request are fixed
+		ifName := mech.GetInterfaceName(conn)
+		var refreshed bool
+		if refreshed, err = refresh(ctx, metadata.IsClient(c), spec, ifName); err == nil {
+			if refreshed {
+				return conn, nil
+			}
+			var state *applied
+			if state, err = apply(spec, ifName); err == nil {
+				store(ctx, metadata.IsClient(c), state)
+				return conn, nil
+			}
+		}
+	}
+	_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+	return nil, err
+}
+
+func (c *customNetlinkClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var revertErr error
+	if state, ok := loadAndDelete(ctx, metadata.IsClient(c)); ok {
+		revertErr = state.revert()
+	}
+
+	if err != nil && revertErr != nil {
+		return nil, errors.Wrap(err, revertErr.Error())
+	}
+	if revertErr != nil {
+		return nil, revertErr
+	}
+	return &empty.Empty{}, err
+}
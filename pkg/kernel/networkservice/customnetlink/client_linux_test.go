@@ -0,0 +1,72 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customnetlink_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	kernelconst "github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/customnetlink"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+func TestCustomNetlinkClient_InvalidSpec(t *testing.T) {
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		customnetlink.NewClient(),
+	)
+
+	_, err := client.Request(context.TODO(), request(`{"addresses": ["10.0.0.1"]}`))
+	require.Error(t, err)
+}
+
+func TestCustomNetlinkClient_ApplyAndRevert(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+	require.NoError(t, netlink.LinkSetUp(link))
+
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		customnetlink.NewClient(),
+	)
+
+	conn, err := client.Request(context.TODO(), request(`{
+		"addresses": ["10.0.0.1/24"],
+		"routes": [{"prefix": "10.1.0.0/16", "via": "10.0.0.254"}]
+	}`))
+	require.NoError(t, err)
+
+	addrs, err := netlink.AddrList(link, kernelconst.FamilyV4)
+	require.NoError(t, err)
+	require.Len(t, addrs, 1)
+	routes, err := netlink.RouteList(link, kernelconst.FamilyV4)
+	require.NoError(t, err)
+	require.Len(t, routes, 2)
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	addrs, err = netlink.AddrList(link, kernelconst.FamilyV4)
+	require.NoError(t, err)
+	require.Empty(t, addrs)
+}
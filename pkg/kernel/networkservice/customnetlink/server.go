@@ -0,0 +1,105 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package customnetlink provides chain element applying the kernel settings requested with a declarative spec in the
+// connection extra context. It is an escape hatch for the settings not yet modeled by the dedicated chain elements.
+package customnetlink
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// SpecKey is a connection extra context key with the JSON encoded Spec
+const SpecKey = "kernelCustomSpec"
+
+type customNetlinkServer struct{}
+
+// NewServer returns a new custom netlink server chain element applying the Spec to the Client's net interface in the
+// current net NS. The spec is applied again on each refresh: the settings dropped from the spec are reverted, the
+// settings changed by someone else are fixed. Applied settings are reverted on Close.
+func NewServer() networkservice.NetworkServiceServer {
+	return &customNetlinkServer{}
+}
+
+func (s *customNetlinkServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	mech := kernel.ToMechanism(request.GetConnection().GetMechanism())
+	value, ok := request.GetConnection().GetContext().GetExtraContext()[SpecKey]
+	if mech == nil || !ok {
+		// the spec dropped on refresh is reverted
+		if _, err := refresh(ctx, metadata.IsClient(s), nil, ""); err != nil {
+			return nil, err
+		}
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	spec, err := parseSpec(value)
+	if err != nil {
+		return nil, err
+	}
+
+	// the spec is applied again on refresh, so the settings changed since the previous Request are fixed
+	ifName := mech.GetInterfaceName(request.GetConnection())
+	refreshed, err := refresh(ctx, metadata.IsClient(s), spec, ifName)
+	if err != nil {
+		return nil, err
+	}
+	if refreshed {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	state, err := apply(spec, ifName)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if revertErr := state.revert(); revertErr != nil {
+			log.Entry(ctx).WithField("customNetlinkServer", "Request").Warnf("failed to revert custom spec: %s", revertErr.Error())
+		}
+		return nil, err
+	}
+
+	store(ctx, metadata.IsClient(s), state)
+
+	return conn, nil
+}
+
+func (s *customNetlinkServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var revertErr error
+	if state, ok := loadAndDelete(ctx, metadata.IsClient(s)); ok {
+		revertErr = state.revert()
+	}
+
+	if err != nil && revertErr != nil {
+		return nil, errors.Wrap(err, revertErr.Error())
+	}
+	if revertErr != nil {
+		return nil, revertErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customnetlink_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	kernelconst "github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/customnetlink"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+const (
+	ifName   = "custom-1"
+	peerName = "custom-2"
+)
+
+func request(spec string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.InterfaceNameKey: ifName,
+				},
+			},
+			Context: &networkservice.ConnectionContext{
+				ExtraContext: map[string]string{
+					customnetlink.SpecKey: spec,
+				},
+			},
+		},
+	}
+}
+
+func TestCustomNetlinkServer_InvalidSpec(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		customnetlink.NewServer(),
+	)
+
+	for _, spec := range []string{
+		`not a json`,
+		`{"unknown": []}`,
+		`{"addresses": ["10.0.0.1"]}`,
+		`{"routes": [{"prefix": "10.1.0.0/16", "via": "fe80::1"}]}`,
+		`{"sysctls": {"kernel.panic": "1"}}`,
+		`{"sysctls": {"ipv4.rp_filter": "1; reboot"}}`,
+	} {
+		_, err := server.Request(context.TODO(), request(spec))
+		require.Error(t, err, spec)
+	}
+}
+
+func TestCustomNetlinkServer_ApplyAndRevert(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+	require.NoError(t, netlink.LinkSetUp(link))
+
+	rpFilter := "net/ipv4/conf/" + ifName + "/rp_filter"
+	require.NoError(t, sysctl.Set(rpFilter, "0"))
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		customnetlink.NewServer(),
+	)
+
+	conn, err := server.Request(context.TODO(), request(`{
+		"addresses": ["10.0.0.1/24"],
+		"routes": [{"prefix": "10.1.0.0/16", "via": "10.0.0.254"}],
+		"sysctls": {"ipv4.rp_filter": "2"}
+	}`))
+	require.NoError(t, err)
+
+	addrs, err := netlink.AddrList(link, kernelconst.FamilyV4)
+	require.NoError(t, err)
+	require.Len(t, addrs, 1)
+	value, err := sysctl.Get(rpFilter)
+	require.NoError(t, err)
+	require.Equal(t, "2", value)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	addrs, err = netlink.AddrList(link, kernelconst.FamilyV4)
+	require.NoError(t, err)
+	require.Empty(t, addrs)
+	value, err = sysctl.Get(rpFilter)
+	require.NoError(t, err)
+	require.Equal(t, "0", value)
+}
+
+func TestCustomNetlinkServer_Refresh(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	require.NoError(t, netlink.LinkSetUp(link))
+
+	rpFilter := "net/ipv4/conf/" + ifName + "/rp_filter"
+	require.NoError(t, sysctl.Set(rpFilter, "0"))
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		customnetlink.NewServer(),
+	)
+
+	conn, err := server.Request(context.TODO(), request(`{
+		"addresses": ["10.0.0.1/24"],
+		"routes": [{"prefix": "10.1.0.0/16", "via": "10.0.0.254"}],
+		"sysctls": {"ipv4.rp_filter": "2"}
+	}`))
+	require.NoError(t, err)
+
+	// the address is deleted by someone else
+	addr, err := netlink.ParseAddr("10.0.0.1/24")
+	require.NoError(t, err)
+	require.NoError(t, netlink.AddrDel(link, addr))
+
+	// the route and the sysctl are dropped from the spec on refresh
+	conn.GetContext().GetExtraContext()[customnetlink.SpecKey] = `{"addresses": ["10.0.0.1/24"]}`
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	addrs, err := netlink.AddrList(link, kernelconst.FamilyV4)
+	require.NoError(t, err)
+	require.Len(t, addrs, 1)
+
+	routes, err := netlink.RouteList(link, kernelconst.FamilyV4)
+	require.NoError(t, err)
+	for _, route := range routes {
+		require.NotEqual(t, "10.1.0.0/16", route.Dst.String())
+	}
+
+	value, err := sysctl.Get(rpFilter)
+	require.NoError(t, err)
+	require.Equal(t, "0", value)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	addrs, err = netlink.AddrList(link, kernelconst.FamilyV4)
+	require.NoError(t, err)
+	require.Empty(t, addrs)
+}
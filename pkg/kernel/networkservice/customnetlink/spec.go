@@ -0,0 +1,123 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customnetlink
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+)
+
+const (
+	maxSpecLength  = 4096
+	maxSpecEntries = 64
+)
+
+var (
+	sysctlNameRegexp  = regexp.MustCompile(`^(ipv4|ipv6)\.[a-z0-9_]+$`)
+	sysctlValueRegexp = regexp.MustCompile(`^[0-9A-Za-z_.:-]{1,64}$`)
+)
+
+// Spec is a declarative spec of the Client's net interface kernel settings, it is passed in the SpecKey extra context
+// as JSON:
+//
+//	{
+//		"addresses": ["10.0.0.1/24", "fe80::1/64"],
+//		"routes": [{"prefix": "10.1.0.0/16", "via": "10.0.0.254"}],
+//		"sysctls": {"ipv4.rp_filter": "2", "ipv6.accept_ra": "0"}
+//	}
+//
+// Sysctls are per interface parameters, "ipv4.rp_filter" stands for net.ipv4.conf.<interface>.rp_filter.
+type Spec struct {
+	Addresses []string          `json:"addresses,omitempty"`
+	Routes    []*Route          `json:"routes,omitempty"`
+	Sysctls   map[string]string `json:"sysctls,omitempty"`
+}
+
+// Route is a Spec route
+type Route struct {
+	Prefix string `json:"prefix"`
+	Via    string `json:"via,omitempty"`
+}
+
+// parsedSpec is a validated Spec
+type parsedSpec struct {
+	addrs   []*netlink.Addr
+	routes  []*netlink.Route
+	sysctls map[string]string
+}
+
+// parseSpec strictly parses and validates the Spec
+func parseSpec(value string) (*parsedSpec, error) {
+	if len(value) > maxSpecLength {
+		return nil, errors.Errorf("spec is too long: %v > %v", len(value), maxSpecLength)
+	}
+
+	decoder := json.NewDecoder(bytes.NewBufferString(value))
+	decoder.DisallowUnknownFields()
+
+	spec := new(Spec)
+	if err := decoder.Decode(spec); err != nil {
+		return nil, errors.Wrap(err, "invalid spec")
+	}
+	if len(spec.Addresses)+len(spec.Routes)+len(spec.Sysctls) > maxSpecEntries {
+		return nil, errors.Errorf("too many spec entries: > %v", maxSpecEntries)
+	}
+
+	parsed := &parsedSpec{
+		sysctls: spec.Sysctls,
+	}
+	for _, addrString := range spec.Addresses {
+		addr, err := netlink.ParseAddr(addrString)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid IP address: %v", addrString)
+		}
+		parsed.addrs = append(parsed.addrs, addr)
+	}
+	for _, route := range spec.Routes {
+		_, dst, err := net.ParseCIDR(route.Prefix)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid route CIDR: %v", route.Prefix)
+		}
+		parsedRoute := &netlink.Route{Dst: dst, Protocol: kernel.RouteProtoNSM}
+		if route.Via != "" {
+			if parsedRoute.Gw = net.ParseIP(route.Via); parsedRoute.Gw == nil {
+				return nil, errors.Errorf("invalid route gateway: %v", route.Via)
+			}
+			if (parsedRoute.Gw.To4() == nil) != (dst.IP.To4() == nil) {
+				return nil, errors.Errorf("route gateway IP family doesn't match the prefix: %v via %v", route.Prefix, route.Via)
+			}
+		}
+		parsed.routes = append(parsed.routes, parsedRoute)
+	}
+	for name, value := range spec.Sysctls {
+		if !sysctlNameRegexp.MatchString(name) {
+			return nil, errors.Errorf("invalid sysctl name: %v", name)
+		}
+		if !sysctlValueRegexp.MatchString(value) {
+			return nil, errors.Errorf("invalid sysctl value: %v = %v", name, value)
+		}
+	}
+
+	return parsed, nil
+}
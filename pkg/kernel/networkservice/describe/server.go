@@ -0,0 +1,60 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package describe provides chain element registering the connections net interfaces for reading back their effective
+// kernel configuration
+package describe
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+
+	describetool "github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/describe"
+)
+
+type describeServer struct {
+	registry *describetool.Registry
+}
+
+// NewServer returns a new describe server chain element registering the connections net interfaces in the registry
+func NewServer(registry *describetool.Registry) networkservice.NetworkServiceServer {
+	return &describeServer{
+		registry: registry,
+	}
+}
+
+func (s *describeServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
+		s.registry.Register(conn.GetId(), mech.GetNetNSURL(), mech.GetInterfaceName(conn))
+	}
+
+	return conn, nil
+}
+
+func (s *describeServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.registry.Unregister(conn.GetId())
+	return next.Server(ctx).Close(ctx, conn)
+}
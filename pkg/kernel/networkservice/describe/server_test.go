@@ -0,0 +1,59 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package describe_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/describe"
+	describetool "github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/describe"
+)
+
+func TestDescribeServer(t *testing.T) {
+	registry := describetool.NewRegistry()
+	server := describe.NewServer(registry)
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.InterfaceNameKey: "nsm-1",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// not kernel mechanism connections are not registered
+	_, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-2"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"conn-1"}, registry.Connections())
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, registry.Connections())
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnscontext
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type dnsContextClient struct {
+	*manager
+}
+
+// NewClient returns a new DNS context client chain element writing the DNS context returned by the Endpoint into the
+// resolver configuration of the kernel mechanism net NS, e.g. "file:///proc/self/ns/net" for the Client running the
+// chain itself. It requires metadata chain element.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &dnsContextClient{
+		manager: newManager(options),
+	}
+}
+
+func (c *dnsContextClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := c.apply(conn)
+	if err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	// DNS context can be changed or dropped on refresh
+	if previous, ok := loadAndDelete(ctx, metadata.IsClient(c)); ok && previous != path {
+		if err := c.remove(conn.GetId(), previous); err != nil {
+			log.Entry(ctx).WithField("dnsContextClient", "Request").Warnf("failed to remove DNS context: %s", err.Error())
+		}
+	}
+	if path != "" {
+		store(ctx, metadata.IsClient(c), path)
+	}
+
+	return conn, nil
+}
+
+func (c *dnsContextClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var removeErr error
+	if path, ok := loadAndDelete(ctx, metadata.IsClient(c)); ok {
+		removeErr = c.remove(conn.GetId(), path)
+	}
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnscontext
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+)
+
+// manager keeps the DNS configs of all the connections per resolver configuration file, so several connections of
+// the same Client are merged into a single file
+type manager struct {
+	pathFunc func(netNSURL string) (string, error)
+
+	mu      sync.Mutex
+	configs map[string]map[string][]*networkservice.DNSConfig
+}
+
+func newManager(options []Option) *manager {
+	m := &manager{
+		pathFunc: ResolvConfPath,
+		configs:  make(map[string]map[string][]*networkservice.DNSConfig),
+	}
+	for _, opt := range options {
+		opt(m)
+	}
+	return m
+}
+
+// apply writes the connection DNS configs into the Client's resolver configuration, it returns the file path or ""
+// if there is nothing to apply
+func (m *manager) apply(conn *networkservice.Connection) (string, error) {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	configs := conn.GetContext().GetDnsContext().GetConfigs()
+	if mech == nil || len(configs) == 0 {
+		return "", nil
+	}
+
+	path, err := m.pathFunc(mech.GetNetNSURL())
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	original, err := readOriginal(path)
+	if err != nil {
+		return "", err
+	}
+
+	pathConfigs, ok := m.configs[path]
+	if !ok {
+		pathConfigs = make(map[string][]*networkservice.DNSConfig)
+		m.configs[path] = pathConfigs
+	}
+	pathConfigs[conn.GetId()] = configs
+
+	if err := write(path, render(path, original, m.merged(path))); err != nil {
+		delete(pathConfigs, conn.GetId())
+		return "", err
+	}
+	return path, nil
+}
+
+// remove deletes the connection DNS configs from the resolver configuration, the original one is restored after the
+// last connection is closed
+func (m *manager) remove(connID, path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.configs[path], connID)
+	if len(m.configs[path]) == 0 {
+		delete(m.configs, path)
+		return restore(path)
+	}
+
+	original, err := readOriginal(path)
+	if err != nil {
+		return err
+	}
+	return write(path, render(path, original, m.merged(path)))
+}
+
+// merged returns the DNS configs of all the path connections ordered by the connection ID, so the result doesn't
+// depend on the Requests order
+func (m *manager) merged(path string) []*networkservice.DNSConfig {
+	ids := make([]string, 0, len(m.configs[path]))
+	for id := range m.configs[path] {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var result []*networkservice.DNSConfig
+	for _, id := range ids {
+		result = append(result, m.configs[path][id]...)
+	}
+	return result
+}
@@ -0,0 +1,28 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnscontext
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(m *manager)
+
+// WithResolvConfPath sets the function returning the Client's resolver configuration file path for the Client's net
+// NS URL, default is ResolvConfPath
+func WithResolvConfPath(pathFunc func(netNSURL string) (string, error)) Option {
+	return func(m *manager) {
+		m.pathFunc = pathFunc
+	}
+}
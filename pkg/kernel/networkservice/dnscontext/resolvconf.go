@@ -0,0 +1,199 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnscontext
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+const (
+	// OriginalSuffix is the suffix of the original resolver configuration file saved on the first change
+	OriginalSuffix = ".nsm-orig"
+	// CreatedSuffix is the suffix of the empty file marking the resolver configuration file created by NSM, it is
+	// saved instead of the original one if there is no resolver configuration file on the first change
+	CreatedSuffix = ".nsm-created"
+
+	currentResolvConfPath = "/etc/resolv.conf"
+
+	header = "# Generated by NSM, the original configuration is saved to "
+)
+
+var pidNetNSPath = regexp.MustCompile(`^/proc/([0-9]+|self)/ns/net$`)
+
+// ResolvConfPath returns the resolver configuration file path of the Client with the given net NS URL. For the
+// process net NS "file:///proc/<pid>/ns/net" it is "/proc/<pid>/root/etc/resolv.conf", the Client's mount NS view of
+// the file. For the named net NS "file:///var/run/netns/<name>" it is "/etc/netns/<name>/resolv.conf", which is bind
+// mounted over "/etc/resolv.conf" by "ip netns exec". Empty net NS URL means the current net NS, it is
+// "/etc/resolv.conf".
+func ResolvConfPath(netNSURL string) (string, error) {
+	if netNSURL == "" {
+		return currentResolvConfPath, nil
+	}
+	u, err := url.Parse(netNSURL)
+	if err != nil || (u.Scheme != "" && u.Scheme != "file") {
+		return "", errors.Errorf("invalid net NS URL: %v", netNSURL)
+	}
+	if match := pidNetNSPath.FindStringSubmatch(u.Path); match != nil {
+		return filepath.Join("/proc", match[1], "root", "etc", "resolv.conf"), nil
+	}
+	if dir := filepath.Dir(u.Path); dir == "/var/run/netns" || dir == "/run/netns" {
+		return filepath.Join("/etc", "netns", filepath.Base(u.Path), "resolv.conf"), nil
+	}
+	return "", errors.Errorf("resolver configuration file is unknown for the net NS: %v", netNSURL)
+}
+
+// render returns the resolver configuration with the NSM DNS configs taking precedence over the original ones: the
+// NSM name servers go first and the NSM search domains are prepended to the original ones
+func render(path string, original []byte, configs []*networkservice.DNSConfig) []byte {
+	var servers, domains, originalDomains []string
+	for _, config := range configs {
+		servers = appendUnique(servers, config.GetDnsServerIps()...)
+		domains = appendUnique(domains, config.GetSearchDomains()...)
+	}
+
+	var rest []string
+	scanner := bufio.NewScanner(bytes.NewReader(original))
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "search", "domain":
+			// only the last search or domain line is used by the resolver
+			originalDomains = fields[1:]
+		case "nameserver":
+			if len(fields) > 1 {
+				servers = appendUnique(servers, fields[1])
+			}
+		default:
+			if !strings.HasPrefix(line, header) {
+				rest = append(rest, line)
+			}
+		}
+	}
+
+	domains = appendUnique(domains, originalDomains...)
+
+	buf := bytes.NewBufferString(header + filepath.Base(path) + OriginalSuffix + "\n")
+	if len(domains) > 0 {
+		buf.WriteString("search " + strings.Join(domains, " ") + "\n")
+	}
+	for _, server := range servers {
+		buf.WriteString("nameserver " + server + "\n")
+	}
+	for _, line := range rest {
+		buf.WriteString(line + "\n")
+	}
+	return buf.Bytes()
+}
+
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, v := range list {
+			if v == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// readOriginal returns the original resolver configuration, it saves it on the first change. The saved copy is
+// reused, so the original configuration survives the Forwarder restart. Missing resolver configuration is the same as
+// the empty one, but it is marked as created by NSM instead, so it is deleted on restore.
+func readOriginal(path string) ([]byte, error) {
+	original, err := ioutil.ReadFile(path + OriginalSuffix)
+	if err == nil {
+		return original, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, errors.Wrapf(err, "failed to read the original resolver configuration: %v", path+OriginalSuffix)
+	}
+	if _, err = os.Stat(path + CreatedSuffix); err == nil {
+		return nil, nil
+	}
+
+	savedPath := path + OriginalSuffix
+	if original, err = ioutil.ReadFile(path); os.IsNotExist(err) {
+		savedPath = path + CreatedSuffix
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to read the resolver configuration: %v", path)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create the resolver configuration directory: %v", path)
+	}
+	if err = ioutil.WriteFile(savedPath, original, 0o644); err != nil {
+		return nil, errors.Wrapf(err, "failed to save the original resolver configuration: %v", savedPath)
+	}
+	return original, nil
+}
+
+// write writes the resolver configuration in place, the file is often a bind mount (e.g. in the Kubernetes pods), so
+// it can't be replaced with rename
+func write(path string, data []byte) error {
+	if err := ioutil.WriteFile(path, data, 0o644); err != nil {
+		return errors.Wrapf(err, "failed to write the resolver configuration: %v", path)
+	}
+	return nil
+}
+
+// restore writes back the original resolver configuration and deletes the saved copy, the resolver configuration
+// created by NSM is deleted
+func restore(path string) error {
+	if _, err := os.Stat(path + CreatedSuffix); err == nil {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to delete the resolver configuration: %v", path)
+		}
+		if err := os.Remove(path + CreatedSuffix); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to delete the resolver configuration mark: %v", path+CreatedSuffix)
+		}
+		return nil
+	}
+
+	original, err := ioutil.ReadFile(path + OriginalSuffix)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the original resolver configuration: %v", path+OriginalSuffix)
+	}
+	if err := write(path, original); err != nil {
+		return err
+	}
+	if err := os.Remove(path + OriginalSuffix); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to delete the original resolver configuration: %v", path+OriginalSuffix)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dnscontext provides chain elements applying the connection DNS context to the Client's resolver
+// configuration: the NSM name servers and search domains take precedence over the original ones, which are restored
+// after the last Client's connection is closed
+package dnscontext
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type dnsContextServer struct {
+	*manager
+}
+
+// NewServer returns a new DNS context server chain element writing the DNS context returned by the Endpoint into the
+// resolver configuration of the Client with the kernel mechanism net NS URL (see ResolvConfPath). It requires
+// metadata chain element.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &dnsContextServer{
+		manager: newManager(options),
+	}
+}
+
+func (s *dnsContextServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := s.apply(conn)
+	if err != nil {
+		_, _ = next.Server(ctx).Close(ctx, conn.Clone())
+		return nil, err
+	}
+
+	// DNS context can be changed or dropped on refresh
+	if previous, ok := loadAndDelete(ctx, metadata.IsClient(s)); ok && previous != path {
+		if err := s.remove(conn.GetId(), previous); err != nil {
+			log.Entry(ctx).WithField("dnsContextServer", "Request").Warnf("failed to remove DNS context: %s", err.Error())
+		}
+	}
+	if path != "" {
+		store(ctx, metadata.IsClient(s), path)
+	}
+
+	return conn, nil
+}
+
+func (s *dnsContextServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var removeErr error
+	if path, ok := loadAndDelete(ctx, metadata.IsClient(s)); ok {
+		removeErr = s.remove(conn.GetId(), path)
+	}
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
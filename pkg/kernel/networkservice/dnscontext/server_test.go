@@ -0,0 +1,199 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnscontext_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/dnscontext"
+)
+
+const original = `search default.svc.cluster.local cluster.local
+nameserver 10.96.0.10
+options ndots:5
+`
+
+func newConn(id string, configs ...*networkservice.DNSConfig) *networkservice.Connection {
+	return &networkservice.Connection{
+		Id: id,
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.NetNSURL: "file:///proc/1000/ns/net",
+			},
+		},
+		Context: &networkservice.ConnectionContext{
+			DnsContext: &networkservice.DNSContext{
+				Configs: configs,
+			},
+		},
+	}
+}
+
+func TestResolvConfPath(t *testing.T) {
+	for netNSURL, expected := range map[string]string{
+		"file:///proc/1000/ns/net":  "/proc/1000/root/etc/resolv.conf",
+		"file:///proc/self/ns/net":  "/proc/self/root/etc/resolv.conf",
+		"file:///var/run/netns/ns1": "/etc/netns/ns1/resolv.conf",
+		"file:///run/netns/ns1":     "/etc/netns/ns1/resolv.conf",
+		"":                          "/etc/resolv.conf",
+	} {
+		path, err := dnscontext.ResolvConfPath(netNSURL)
+		require.NoError(t, err)
+		require.Equal(t, expected, path)
+	}
+
+	_, err := dnscontext.ResolvConfPath("file:///tmp/ns1")
+	require.Error(t, err)
+}
+
+func TestDNSContextServer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dnscontext")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "resolv.conf")
+	require.NoError(t, ioutil.WriteFile(path, []byte(original), 0o600))
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		dnscontext.NewServer(dnscontext.WithResolvConfPath(func(string) (string, error) { return path, nil })),
+	)
+
+	conn1, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: newConn("conn-1", &networkservice.DNSConfig{
+			DnsServerIps:  []string{"172.16.0.1"},
+			SearchDomains: []string{"nsm1.local"},
+		}),
+	})
+	require.NoError(t, err)
+
+	conn2, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: newConn("conn-2", &networkservice.DNSConfig{
+			DnsServerIps:  []string{"172.16.0.2", "10.96.0.10"},
+			SearchDomains: []string{"nsm2.local"},
+		}),
+	})
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, `# Generated by NSM, the original configuration is saved to resolv.conf.nsm-orig
+search nsm1.local nsm2.local default.svc.cluster.local cluster.local
+nameserver 172.16.0.1
+nameserver 172.16.0.2
+nameserver 10.96.0.10
+options ndots:5
+`, string(data))
+
+	_, err = server.Close(context.TODO(), conn1)
+	require.NoError(t, err)
+
+	data, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "search nsm2.local default.svc.cluster.local cluster.local\n")
+	require.NotContains(t, string(data), "172.16.0.1")
+
+	// DNS context is dropped on refresh
+	conn2.GetContext().DnsContext = nil
+	conn2, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn2})
+	require.NoError(t, err)
+
+	data, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, original, string(data))
+
+	_, err = os.Stat(path + dnscontext.OriginalSuffix)
+	require.True(t, os.IsNotExist(err))
+
+	_, err = server.Close(context.TODO(), conn2)
+	require.NoError(t, err)
+}
+
+func TestDNSContextServer_NoResolvConf(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dnscontext")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "netns", "ns1", "resolv.conf")
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		dnscontext.NewServer(dnscontext.WithResolvConfPath(func(string) (string, error) { return path, nil })),
+	)
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: newConn("conn-1", &networkservice.DNSConfig{
+			DnsServerIps: []string{"172.16.0.1"},
+		}),
+	})
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "nameserver 172.16.0.1\n")
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+	_, err = os.Stat(path + dnscontext.CreatedSuffix)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestDNSContextServer_EmptyResolvConf(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dnscontext")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "resolv.conf")
+	require.NoError(t, ioutil.WriteFile(path, nil, 0o600))
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		dnscontext.NewServer(dnscontext.WithResolvConfPath(func(string) (string, error) { return path, nil })),
+	)
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: newConn("conn-1", &networkservice.DNSConfig{
+			DnsServerIps: []string{"172.16.0.1"},
+		}),
+	})
+	require.NoError(t, err)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	// the existing empty resolver configuration is restored, not deleted
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Empty(t, data)
+	_, err = os.Stat(path + dnscontext.OriginalSuffix)
+	require.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethernetcontext
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// PFLinks is the PF net interfaces API used to configure the VFs
+type PFLinks = pfLinks
+
+// NewVFServerWithLinks returns a new VF ethernet context server chain element using the given PF net interfaces API
+func NewVFServerWithLinks(links PFLinks, options ...Option) networkservice.NetworkServiceServer {
+	return newVFServer(links, options...)
+}
+
+// NewVFClientWithLinks returns a new VF ethernet context client chain element using the given PF net interfaces API
+func NewVFClientWithLinks(links PFLinks, options ...Option) networkservice.NetworkServiceClient {
+	return newVFClient(links, options...)
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethernetcontext
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type macEthernetContextClient struct{}
+
+// NewClient returns a new ethernet context client chain element setting Dst MAC address to the Endpoint's net
+// interface in its net NS, or writing back the actual MAC address if Dst MAC address is not set. The original MAC
+// address is restored on Close.
+func NewClient() networkservice.NetworkServiceClient {
+	return &macEthernetContextClient{}
+}
+
+func (c *macEthernetContextClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	isClient := metadata.IsClient(c)
+
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := setMACAddr(conn, isClient)
+	if err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	storeOriginalMAC(ctx, isClient, original)
+
+	return conn, nil
+}
+
+func (c *macEthernetContextClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var restoreErr error
+	if original, ok := loadAndDeleteOriginalMAC(ctx, metadata.IsClient(c)); ok {
+		restoreErr = restoreMACAddr(conn, original)
+	}
+
+	if err != nil && restoreErr != nil {
+		return nil, errors.Wrap(err, restoreErr.Error())
+	}
+	if restoreErr != nil {
+		return nil, restoreErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethernetcontext
+
+import (
+	"net"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// setMACAddr sets the ethernet context MAC address to the connection kernel interface in its net NS. Server side
+// interface gets Src MAC address, Client side interface gets Dst one. If there is no MAC address for the side, the
+// actual net interface MAC address is written back into the ethernet context. It returns the replaced MAC address,
+// nil if the MAC address is not changed.
+func setMACAddr(conn *networkservice.Connection, isClient bool) (net.HardwareAddr, error) {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil, nil
+	}
+
+	if conn.GetContext() == nil {
+		conn.Context = new(networkservice.ConnectionContext)
+	}
+	if conn.GetContext().GetEthernetContext() == nil {
+		conn.GetContext().EthernetContext = new(networkservice.EthernetContext)
+	}
+	ethernetContext := conn.GetContext().GetEthernetContext()
+
+	macAddrField := &ethernetContext.SrcMac
+	if isClient {
+		macAddrField = &ethernetContext.DstMac
+	}
+
+	var macAddr net.HardwareAddr
+	if *macAddrField != "" {
+		var err error
+		if macAddr, err = net.ParseMAC(*macAddrField); err != nil {
+			return nil, errors.Wrapf(err, "invalid MAC address: %v", *macAddrField)
+		}
+	}
+
+	var original net.HardwareAddr
+	ifName := mech.GetInterfaceName(conn)
+	return original, nshandle.RunInURL(mech.GetNetNSURL(), func() error {
+		link, err := netlink.LinkByName(ifName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+		}
+
+		if macAddr == nil {
+			*macAddrField = link.Attrs().HardwareAddr.String()
+			return nil
+		}
+		if link.Attrs().HardwareAddr.String() == macAddr.String() {
+			return nil
+		}
+		if err := netlink.LinkSetHardwareAddr(link, macAddr); err != nil {
+			return errors.Wrapf(err, "failed to set MAC address for the net interface: %v %v", ifName, macAddr)
+		}
+		original = link.Attrs().HardwareAddr
+		return nil
+	})
+}
+
+// restoreMACAddr sets the original MAC address back to the connection kernel interface in its net NS. The net
+// interface and the net NS can be already deleted, there is nothing to restore then.
+func restoreMACAddr(conn *networkservice.Connection, original net.HardwareAddr) error {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil
+	}
+
+	ifName := mech.GetInterfaceName(conn)
+	err := nshandle.RunInURL(mech.GetNetNSURL(), func() error {
+		link, err := netlink.LinkByName(ifName)
+		if _, ok := err.(netlink.LinkNotFoundError); ok {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+		}
+
+		if link.Attrs().HardwareAddr.String() == original.String() {
+			return nil
+		}
+		if err := netlink.LinkSetHardwareAddr(link, original); err != nil {
+			return errors.Wrapf(err, "failed to restore MAC address for the net interface: %v %v", ifName, original)
+		}
+		return nil
+	})
+	if os.IsNotExist(errors.Cause(err)) {
+		return nil
+	}
+	return err
+}
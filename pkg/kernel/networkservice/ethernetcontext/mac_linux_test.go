@@ -0,0 +1,134 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethernetcontext_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ethernetcontext"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName   = "ethctx-1"
+	peerName = "ethctx-2"
+	macAddr  = "0a:00:00:00:00:01"
+)
+
+func newConn(ethernetContext *networkservice.EthernetContext) *networkservice.Connection {
+	return &networkservice.Connection{
+		Id: "conn-1",
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.NetNSURL:         "file:///proc/self/ns/net",
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+		Context: &networkservice.ConnectionContext{
+			EthernetContext: ethernetContext,
+		},
+	}
+}
+
+func linkMACAddr(t *testing.T) string {
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	return link.Attrs().HardwareAddr.String()
+}
+
+func TestMACEthernetContextServer(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ethernetcontext.NewServer(),
+	)
+
+	// actual MAC address is written back
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: newConn(nil)})
+	require.NoError(t, err)
+	require.Equal(t, link.Attrs().HardwareAddr.String(), conn.GetContext().GetEthernetContext().GetSrcMac())
+	require.Empty(t, conn.GetContext().GetEthernetContext().GetDstMac())
+
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: newConn(&networkservice.EthernetContext{SrcMac: macAddr}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, macAddr, linkMACAddr(t))
+	require.Equal(t, macAddr, conn.GetContext().GetEthernetContext().GetSrcMac())
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Equal(t, link.Attrs().HardwareAddr.String(), linkMACAddr(t))
+
+	_, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: newConn(&networkservice.EthernetContext{SrcMac: "invalid"}),
+	})
+	require.Error(t, err)
+}
+
+func TestMACEthernetContextServer_Rollback(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ethernetcontext.NewServer(),
+		injecterror.NewServer(),
+	)
+
+	_, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: newConn(&networkservice.EthernetContext{SrcMac: macAddr}),
+	})
+	require.Error(t, err)
+	require.Equal(t, link.Attrs().HardwareAddr.String(), linkMACAddr(t))
+}
+
+func TestMACEthernetContextClient(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		ethernetcontext.NewClient(),
+	)
+
+	conn, err := client.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: newConn(&networkservice.EthernetContext{SrcMac: macAddr}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, link.Attrs().HardwareAddr.String(), conn.GetContext().GetEthernetContext().GetDstMac())
+
+	conn, err = client.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: newConn(&networkservice.EthernetContext{DstMac: macAddr}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, macAddr, linkMACAddr(t))
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Equal(t, link.Attrs().HardwareAddr.String(), linkMACAddr(t))
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethernetcontext
+
+import (
+	"context"
+	"net"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/metamap"
+)
+
+type macKeyType struct{}
+
+// storeOriginalMAC stores the MAC address replaced on the first change, so it is restored on Close. Without metadata
+// chain element in the chain nothing is stored and the MAC address is not restored on Close.
+func storeOriginalMAC(ctx context.Context, isClient bool, original net.HardwareAddr) {
+	if m, ok := metamap.Load(ctx, isClient); ok && original != nil {
+		m.LoadOrStore(macKeyType{}, original)
+	}
+}
+
+func loadAndDeleteOriginalMAC(ctx context.Context, isClient bool) (net.HardwareAddr, bool) {
+	m, ok := metamap.Load(ctx, isClient)
+	if !ok {
+		return nil, false
+	}
+	if raw, ok := m.LoadAndDelete(macKeyType{}); ok {
+		return raw.(net.HardwareAddr), true
+	}
+	return nil, false
+}
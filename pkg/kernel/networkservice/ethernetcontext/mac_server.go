@@ -0,0 +1,79 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethernetcontext
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type macEthernetContextServer struct{}
+
+// NewServer returns a new ethernet context server chain element setting Src MAC address to the Client's net
+// interface in its net NS, or writing back the actual MAC address if Src MAC address is not set. The original MAC
+// address is restored on Close.
+func NewServer() networkservice.NetworkServiceServer {
+	return &macEthernetContextServer{}
+}
+
+func (s *macEthernetContextServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	isClient := metadata.IsClient(s)
+
+	original, err := setMACAddr(request.GetConnection(), isClient)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if original != nil {
+			if restoreErr := restoreMACAddr(request.GetConnection(), original); restoreErr != nil {
+				log.Entry(ctx).WithField("macEthernetContextServer", "Request").
+					Warnf("failed to restore MAC address for connection %s: %s", request.GetConnection().GetId(), restoreErr.Error())
+			}
+		}
+		return nil, err
+	}
+
+	storeOriginalMAC(ctx, isClient, original)
+
+	return conn, nil
+}
+
+func (s *macEthernetContextServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var restoreErr error
+	if original, ok := loadAndDeleteOriginalMAC(ctx, metadata.IsClient(s)); ok {
+		restoreErr = restoreMACAddr(conn, original)
+	}
+
+	if err != nil && restoreErr != nil {
+		return nil, errors.Wrap(err, restoreErr.Error())
+	}
+	if restoreErr != nil {
+		return nil, restoreErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethernetcontext
+
+// Option is an option for the VF ethernet context server and client
+type Option func(a *vfAttributes)
+
+// WithTrust sets VF trust mode. It can be overridden per connection with the TrustLabel label or
+// mechanism parameter.
+func WithTrust(trust bool) Option {
+	return func(a *vfAttributes) {
+		a.trust = &trust
+	}
+}
+
+// WithSpoofCheck sets VF spoof checking. It can be overridden per connection with the SpoofCheckLabel
+// label or mechanism parameter.
+func WithSpoofCheck(spoofCheck bool) Option {
+	return func(a *vfAttributes) {
+		a.spoofCheck = &spoofCheck
+	}
+}
+
+// WithTxRate sets VF min and max TX rate in Mbps, 0 means no limit. It can be overridden per connection with the
+// MinTxRateLabel and MaxTxRateLabel labels or mechanism parameters.
+func WithTxRate(minTxRate, maxTxRate int) Option {
+	return func(a *vfAttributes) {
+		a.minTxRate = &minTxRate
+		a.maxTxRate = &maxTxRate
+	}
+}
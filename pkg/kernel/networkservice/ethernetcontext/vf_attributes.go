@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethernetcontext
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+const (
+	// TrustLabel is a connection label or a mechanism parameter setting VF trust mode: "true" or "false"
+	TrustLabel = "sriovTrust"
+	// SpoofCheckLabel is a connection label or a mechanism parameter setting VF spoof checking: "true" or "false"
+	SpoofCheckLabel = "sriovSpoofCheck"
+	// MinTxRateLabel is a connection label or a mechanism parameter setting VF min TX rate in Mbps
+	MinTxRateLabel = "sriovMinTxRate"
+	// MaxTxRateLabel is a connection label or a mechanism parameter setting VF max TX rate in Mbps
+	MaxTxRateLabel = "sriovMaxTxRate"
+)
+
+// vfAttributes are VF attributes configured on the PF, nil means "not configured"
+type vfAttributes struct {
+	trust      *bool
+	spoofCheck *bool
+	minTxRate  *int
+	maxTxRate  *int
+}
+
+// withConnection returns a copy of attributes overridden with the values from the connection labels and then from
+// the connection mechanism parameters, so the mechanism selected for the connection has the last word
+func (a vfAttributes) withConnection(conn *networkservice.Connection) (*vfAttributes, error) {
+	for _, values := range []map[string]string{conn.GetLabels(), conn.GetMechanism().GetParameters()} {
+		if err := a.override(values); err != nil {
+			return nil, err
+		}
+	}
+	return &a, nil
+}
+
+func (a *vfAttributes) override(values map[string]string) error {
+	for key, attr := range map[string]**bool{
+		TrustLabel:      &a.trust,
+		SpoofCheckLabel: &a.spoofCheck,
+	} {
+		if value, ok := values[key]; ok {
+			boolValue, err := strconv.ParseBool(value)
+			if err != nil {
+				return errors.Wrapf(err, "invalid %s value: %v", key, value)
+			}
+			*attr = &boolValue
+		}
+	}
+	for key, attr := range map[string]**int{
+		MinTxRateLabel: &a.minTxRate,
+		MaxTxRateLabel: &a.maxTxRate,
+	} {
+		if value, ok := values[key]; ok {
+			intValue, err := strconv.Atoi(value)
+			if err != nil || intValue < 0 {
+				return errors.Errorf("invalid %s value: %v", key, value)
+			}
+			*attr = &intValue
+		}
+	}
+	return nil
+}
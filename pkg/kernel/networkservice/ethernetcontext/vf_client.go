@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethernetcontext
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+)
+
+type vfEthernetContextClient struct {
+	attrs vfAttributes
+	links pfLinks
+}
+
+// NewVFClient returns a new VF ethernet context client chain element configuring the connection VF on its PF when
+// Request returns. The VF state is taken before the first change and the changed VF attributes are restored on Close.
+func NewVFClient(options ...Option) networkservice.NetworkServiceClient {
+	return newVFClient(netlinkPFLinks{}, options...)
+}
+
+func newVFClient(links pfLinks, options ...Option) networkservice.NetworkServiceClient {
+	c := &vfEthernetContextClient{
+		links: links,
+	}
+	for _, opt := range options {
+		opt(&c.attrs)
+	}
+	return c
+}
+
+func (c *vfEthernetContextClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	isClient := metadata.IsClient(c)
+
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	vfConfig, ok := vfconfig.Load(ctx, isClient)
+	if !ok {
+		return conn, nil
+	}
+
+	state, loaded := loadVFState(ctx, isClient)
+	if !loaded {
+		if state, err = readVFState(c.links, vfConfig.PFInterfaceName, vfConfig.VFNum); err != nil {
+			_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+			return nil, err
+		}
+	}
+
+	vfRequest, err := newVFRequest(conn, &c.attrs, isClient)
+	if err == nil {
+		err = state.apply(c.links, vfRequest)
+	}
+	if err != nil {
+		if restoreErr := state.restore(c.links); restoreErr != nil {
+			log.Entry(ctx).WithField("vfEthernetContextClient", "Request").
+				Warnf("failed to restore VF state for connection %s: %s", conn.GetId(), restoreErr.Error())
+		}
+		deleteVFState(ctx, isClient)
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	storeVFState(ctx, isClient, state)
+
+	return conn, nil
+}
+
+func (c *vfEthernetContextClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var restoreErr error
+	if state, ok := loadAndDeleteVFState(ctx, metadata.IsClient(c)); ok {
+		restoreErr = state.restore(c.links)
+	}
+
+	if err != nil && restoreErr != nil {
+		return nil, errors.Wrap(err, restoreErr.Error())
+	}
+	if restoreErr != nil {
+		return nil, restoreErr
+	}
+	return &empty.Empty{}, err
+}
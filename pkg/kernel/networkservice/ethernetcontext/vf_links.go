@@ -0,0 +1,54 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build !linux
+
+package ethernetcontext
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+)
+
+var errVFNotSupported = errors.New("VF configuration is supported only on linux")
+
+type netlinkPFLinks struct{}
+
+func (netlinkPFLinks) LinkByName(name string) (netlink.Link, error) {
+	return netlink.LinkByName(name)
+}
+
+func (netlinkPFLinks) LinkSetVfHardwareAddr(netlink.Link, int, net.HardwareAddr) error {
+	return errVFNotSupported
+}
+
+func (netlinkPFLinks) LinkSetVfVlanQos(netlink.Link, int, int, int) error {
+	return errVFNotSupported
+}
+
+func (netlinkPFLinks) LinkSetVfTrust(netlink.Link, int, bool) error {
+	return errVFNotSupported
+}
+
+func (netlinkPFLinks) LinkSetVfSpoofchk(netlink.Link, int, bool) error {
+	return errVFNotSupported
+}
+
+func (netlinkPFLinks) LinkSetVfRate(netlink.Link, int, int, int) error {
+	return errVFNotSupported
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethernetcontext
+
+import (
+	"net"
+
+	"github.com/vishvananda/netlink"
+)
+
+type netlinkPFLinks struct{}
+
+func (netlinkPFLinks) LinkByName(name string) (netlink.Link, error) {
+	return netlink.LinkByName(name)
+}
+
+func (netlinkPFLinks) LinkSetVfHardwareAddr(pfLink netlink.Link, vfNum int, macAddr net.HardwareAddr) error {
+	return netlink.LinkSetVfHardwareAddr(pfLink, vfNum, macAddr)
+}
+
+func (netlinkPFLinks) LinkSetVfVlanQos(pfLink netlink.Link, vfNum, vlan, qos int) error {
+	return netlink.LinkSetVfVlanQos(pfLink, vfNum, vlan, qos)
+}
+
+func (netlinkPFLinks) LinkSetVfTrust(pfLink netlink.Link, vfNum int, trust bool) error {
+	return netlink.LinkSetVfTrust(pfLink, vfNum, trust)
+}
+
+func (netlinkPFLinks) LinkSetVfSpoofchk(pfLink netlink.Link, vfNum int, spoofCheck bool) error {
+	return netlink.LinkSetVfSpoofchk(pfLink, vfNum, spoofCheck)
+}
+
+func (netlinkPFLinks) LinkSetVfRate(pfLink netlink.Link, vfNum, minTxRate, maxTxRate int) error {
+	return netlink.LinkSetVfRate(pfLink, vfNum, minTxRate, maxTxRate)
+}
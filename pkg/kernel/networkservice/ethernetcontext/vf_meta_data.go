@@ -0,0 +1,61 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethernetcontext
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/metamap"
+)
+
+type vfKeyType struct{}
+
+// storeVFState stores the VF state taken before the first change, so it is restored on Close. Without metadata chain
+// element in the chain nothing is stored and the VF state is not restored on Close.
+func storeVFState(ctx context.Context, isClient bool, state *vfState) {
+	if m, ok := metamap.Load(ctx, isClient); ok {
+		m.Store(vfKeyType{}, state)
+	}
+}
+
+func loadVFState(ctx context.Context, isClient bool) (*vfState, bool) {
+	m, ok := metamap.Load(ctx, isClient)
+	if !ok {
+		return nil, false
+	}
+	if raw, ok := m.Load(vfKeyType{}); ok {
+		return raw.(*vfState), true
+	}
+	return nil, false
+}
+
+func loadAndDeleteVFState(ctx context.Context, isClient bool) (*vfState, bool) {
+	m, ok := metamap.Load(ctx, isClient)
+	if !ok {
+		return nil, false
+	}
+	if raw, ok := m.LoadAndDelete(vfKeyType{}); ok {
+		return raw.(*vfState), true
+	}
+	return nil, false
+}
+
+func deleteVFState(ctx context.Context, isClient bool) {
+	if m, ok := metamap.Load(ctx, isClient); ok {
+		m.Delete(vfKeyType{})
+	}
+}
@@ -19,54 +19,100 @@ package ethernetcontext
 
 import (
 	"context"
-	"net"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/pkg/errors"
-	"github.com/vishvananda/netlink"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
 
 	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vfconfig"
 )
 
-type vfEthernetContextServer struct{}
+type vfEthernetContextServer struct {
+	attrs vfAttributes
+	links pfLinks
+}
 
-// NewVFServer returns a new VF ethernet context server chain element
-func NewVFServer() networkservice.NetworkServiceServer {
-	return &vfEthernetContextServer{}
+// NewVFServer returns a new VF ethernet context server chain element configuring the connection VF on its PF. The VF
+// state is taken before the first change and the changed VF attributes are restored on Close.
+func NewVFServer(options ...Option) networkservice.NetworkServiceServer {
+	return newVFServer(netlinkPFLinks{}, options...)
+}
+
+func newVFServer(links pfLinks, options ...Option) networkservice.NetworkServiceServer {
+	s := &vfEthernetContextServer{
+		links: links,
+	}
+	for _, opt := range options {
+		opt(&s.attrs)
+	}
+	return s
 }
 
 func (s *vfEthernetContextServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
-	if vfConfig := vfconfig.Config(ctx); vfConfig != nil {
-		pfLink, err := netlink.LinkByName(vfConfig.PFInterfaceName)
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to get PF network interface: %v", vfConfig.PFInterfaceName)
-		}
+	isClient := metadata.IsClient(s)
 
-		if ethernetContext := request.GetConnection().GetContext().GetEthernetContext(); ethernetContext != nil {
-			if macAddrString := ethernetContext.GetSrcMac(); macAddrString != "" {
-				var macAddr net.HardwareAddr
-				macAddr, err = net.ParseMAC(ethernetContext.GetSrcMac())
-				if err != nil {
-					return nil, errors.Wrapf(err, "invalid MAC address: %v", ethernetContext.GetSrcMac())
-				}
-				if err = netlink.LinkSetVfHardwareAddr(pfLink, vfConfig.VFNum, macAddr); err != nil {
-					return nil, errors.Wrapf(err, "failed to set MAC address for the VF: %v", macAddr)
-				}
-			}
-
-			if vlanTag := int(ethernetContext.GetVlanTag()); vlanTag != 0 {
-				if err = netlink.LinkSetVfVlan(pfLink, vfConfig.VFNum, vlanTag); err != nil {
-					return nil, errors.Wrapf(err, "failed to set VLAN for the VF: %v", vlanTag)
-				}
-			}
+	vfConfig, ok := vfconfig.Load(ctx, isClient)
+	if !ok {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	vfRequest, err := newVFRequest(request.GetConnection(), &s.attrs, isClient)
+	if err != nil {
+		return nil, err
+	}
+
+	state, loaded := loadVFState(ctx, isClient)
+	if !loaded {
+		if state, err = readVFState(s.links, vfConfig.PFInterfaceName, vfConfig.VFNum); err != nil {
+			return nil, err
 		}
 	}
-	return next.Server(ctx).Request(ctx, request)
+
+	if err = state.apply(s.links, vfRequest); err != nil {
+		s.rollback(ctx, request.GetConnection(), state, loaded)
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		s.rollback(ctx, request.GetConnection(), state, loaded)
+		return nil, err
+	}
+
+	storeVFState(ctx, isClient, state)
+
+	return conn, nil
+}
+
+// rollback restores the VF state if it is taken by the failed Request, the failed refresh keeps the VF configured for
+// the established connection
+func (s *vfEthernetContextServer) rollback(ctx context.Context, conn *networkservice.Connection, state *vfState, loaded bool) {
+	if loaded {
+		return
+	}
+	if err := state.restore(s.links); err != nil {
+		log.Entry(ctx).WithField("vfEthernetContextServer", "Request").
+			Warnf("failed to restore VF state for connection %s: %s", conn.GetId(), err.Error())
+	}
 }
 
 func (s *vfEthernetContextServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
-	return next.Server(ctx).Close(ctx, conn)
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var restoreErr error
+	if state, ok := loadAndDeleteVFState(ctx, metadata.IsClient(s)); ok {
+		restoreErr = state.restore(s.links)
+	}
+
+	if err != nil && restoreErr != nil {
+		return nil, errors.Wrap(err, restoreErr.Error())
+	}
+	if restoreErr != nil {
+		return nil, restoreErr
+	}
+	return &empty.Empty{}, err
 }
@@ -0,0 +1,196 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethernetcontext
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// pfLinks is the PF net interfaces API used to configure the VFs
+type pfLinks interface {
+	LinkByName(name string) (netlink.Link, error)
+	LinkSetVfHardwareAddr(pfLink netlink.Link, vfNum int, macAddr net.HardwareAddr) error
+	LinkSetVfVlanQos(pfLink netlink.Link, vfNum, vlan, qos int) error
+	LinkSetVfTrust(pfLink netlink.Link, vfNum int, trust bool) error
+	LinkSetVfSpoofchk(pfLink netlink.Link, vfNum int, spoofCheck bool) error
+	LinkSetVfRate(pfLink netlink.Link, vfNum, minTxRate, maxTxRate int) error
+}
+
+// vfState is the VF state on the PF taken before the first change for the connection, so the changed VF attributes
+// are restored exactly on Close. Trust mode can't be read from the PF, so it is restored to the kernel default: off.
+type vfState struct {
+	pfName               string
+	vfNum                int
+	macAddr              net.HardwareAddr
+	vlan, qos            int
+	spoofCheck           bool
+	minTxRate, maxTxRate int
+
+	changed vfChanges
+}
+
+// vfChanges are the VF attributes changed for the connection
+type vfChanges struct {
+	macAddr, vlan, trust, spoofCheck, txRate bool
+}
+
+// vfRequest is the VF configuration requested for the connection, nil means "not configured"
+type vfRequest struct {
+	macAddr net.HardwareAddr
+	vlan    int
+	attrs   *vfAttributes
+}
+
+// newVFRequest parses the VF configuration for the connection before anything is changed. Server side VF gets Src MAC
+// address, Client side VF gets Dst one.
+func newVFRequest(conn *networkservice.Connection, attrs *vfAttributes, isClient bool) (*vfRequest, error) {
+	connAttrs, err := attrs.withConnection(conn)
+	if err != nil {
+		return nil, err
+	}
+	r := &vfRequest{
+		vlan:  int(conn.GetContext().GetEthernetContext().GetVlanTag()),
+		attrs: connAttrs,
+	}
+
+	macAddrString := conn.GetContext().GetEthernetContext().GetSrcMac()
+	if isClient {
+		macAddrString = conn.GetContext().GetEthernetContext().GetDstMac()
+	}
+	if macAddrString != "" {
+		if r.macAddr, err = net.ParseMAC(macAddrString); err != nil {
+			return nil, errors.Wrapf(err, "invalid MAC address: %v", macAddrString)
+		}
+	}
+	return r, nil
+}
+
+// readVFState reads the VF state from the PF
+func readVFState(links pfLinks, pfName string, vfNum int) (*vfState, error) {
+	pfLink, err := links.LinkByName(pfName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get PF network interface: %v", pfName)
+	}
+	for i := range pfLink.Attrs().Vfs {
+		if vf := &pfLink.Attrs().Vfs[i]; vf.ID == vfNum {
+			return &vfState{
+				pfName:     pfName,
+				vfNum:      vfNum,
+				macAddr:    vf.Mac,
+				vlan:       vf.Vlan,
+				qos:        vf.Qos,
+				spoofCheck: vf.Spoofchk,
+				minTxRate:  int(vf.MinTxRate),
+				maxTxRate:  int(vf.MaxTxRate),
+			}, nil
+		}
+	}
+	return nil, errors.Errorf("failed to read VF state: %v VF %v", pfName, vfNum)
+}
+
+// apply applies the requested VF configuration, the VF attributes are marked changed before the change, so the
+// partially applied configuration is restored as well
+func (s *vfState) apply(links pfLinks, r *vfRequest) error {
+	pfLink, err := links.LinkByName(s.pfName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get PF network interface: %v", s.pfName)
+	}
+
+	if r.macAddr != nil {
+		s.changed.macAddr = true
+		if err := links.LinkSetVfHardwareAddr(pfLink, s.vfNum, r.macAddr); err != nil {
+			return errors.Wrapf(err, "failed to set MAC address for the VF: %v", r.macAddr)
+		}
+	}
+	if r.vlan != 0 {
+		s.changed.vlan = true
+		if err := links.LinkSetVfVlanQos(pfLink, s.vfNum, r.vlan, 0); err != nil {
+			return errors.Wrapf(err, "failed to set VLAN for the VF: %v", r.vlan)
+		}
+	}
+	if r.attrs.trust != nil {
+		s.changed.trust = true
+		if err := links.LinkSetVfTrust(pfLink, s.vfNum, *r.attrs.trust); err != nil {
+			return errors.Wrapf(err, "failed to set trust mode for the VF: %v", *r.attrs.trust)
+		}
+	}
+	if r.attrs.spoofCheck != nil {
+		s.changed.spoofCheck = true
+		if err := links.LinkSetVfSpoofchk(pfLink, s.vfNum, *r.attrs.spoofCheck); err != nil {
+			return errors.Wrapf(err, "failed to set spoof checking for the VF: %v", *r.attrs.spoofCheck)
+		}
+	}
+	if r.attrs.minTxRate != nil || r.attrs.maxTxRate != nil {
+		var minTxRate, maxTxRate int
+		if r.attrs.minTxRate != nil {
+			minTxRate = *r.attrs.minTxRate
+		}
+		if r.attrs.maxTxRate != nil {
+			maxTxRate = *r.attrs.maxTxRate
+		}
+		s.changed.txRate = true
+		if err := links.LinkSetVfRate(pfLink, s.vfNum, minTxRate, maxTxRate); err != nil {
+			return errors.Wrapf(err, "failed to set TX rate for the VF: %v-%v", minTxRate, maxTxRate)
+		}
+	}
+	return nil
+}
+
+// restore returns the changed VF attributes to the state taken before the first change. It tries all the attributes
+// and returns the first error.
+func (s *vfState) restore(links pfLinks) error {
+	pfLink, err := links.LinkByName(s.pfName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get PF network interface: %v", s.pfName)
+	}
+
+	var errs []error
+	if s.changed.macAddr && s.macAddr != nil {
+		if err := links.LinkSetVfHardwareAddr(pfLink, s.vfNum, s.macAddr); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to restore MAC address for the VF: %v", s.macAddr))
+		}
+	}
+	if s.changed.vlan {
+		if err := links.LinkSetVfVlanQos(pfLink, s.vfNum, s.vlan, s.qos); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to restore VLAN for the VF: %v", s.vlan))
+		}
+	}
+	if s.changed.trust {
+		if err := links.LinkSetVfTrust(pfLink, s.vfNum, false); err != nil {
+			errs = append(errs, errors.Wrap(err, "failed to restore trust mode for the VF"))
+		}
+	}
+	if s.changed.spoofCheck {
+		if err := links.LinkSetVfSpoofchk(pfLink, s.vfNum, s.spoofCheck); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to restore spoof checking for the VF: %v", s.spoofCheck))
+		}
+	}
+	if s.changed.txRate {
+		if err := links.LinkSetVfRate(pfLink, s.vfNum, s.minTxRate, s.maxTxRate); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to restore TX rate for the VF: %v-%v", s.minTxRate, s.maxTxRate))
+		}
+	}
+	if len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
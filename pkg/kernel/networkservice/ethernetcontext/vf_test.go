@@ -0,0 +1,203 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethernetcontext_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ethernetcontext"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+)
+
+const (
+	pfName = "pf-1"
+	vfNum  = 1
+	vfMAC  = "0a:00:00:00:00:03"
+)
+
+// fakePFLinks is the PF with a single VF, trust mode is kept aside as the kernel doesn't report it
+type fakePFLinks struct {
+	pf    *netlink.Device
+	trust bool
+}
+
+func newFakePFLinks() *fakePFLinks {
+	return &fakePFLinks{
+		pf: &netlink.Device{LinkAttrs: netlink.LinkAttrs{
+			Name: pfName,
+			Vfs: []netlink.VfInfo{{
+				ID:        vfNum,
+				Mac:       net.HardwareAddr{0x0a, 0, 0, 0, 0, 0x02},
+				Vlan:      5,
+				Qos:       1,
+				Spoofchk:  true,
+				MaxTxRate: 200,
+			}},
+		}},
+	}
+}
+
+func (f *fakePFLinks) vf() *netlink.VfInfo {
+	return &f.pf.Vfs[0]
+}
+
+func (f *fakePFLinks) LinkByName(name string) (netlink.Link, error) {
+	if name != pfName {
+		return nil, netlink.LinkNotFoundError{}
+	}
+	return f.pf, nil
+}
+
+func (f *fakePFLinks) LinkSetVfHardwareAddr(_ netlink.Link, _ int, macAddr net.HardwareAddr) error {
+	f.vf().Mac = macAddr
+	return nil
+}
+
+func (f *fakePFLinks) LinkSetVfVlanQos(_ netlink.Link, _, vlan, qos int) error {
+	f.vf().Vlan, f.vf().Qos = vlan, qos
+	return nil
+}
+
+func (f *fakePFLinks) LinkSetVfTrust(_ netlink.Link, _ int, trust bool) error {
+	f.trust = trust
+	return nil
+}
+
+func (f *fakePFLinks) LinkSetVfSpoofchk(_ netlink.Link, _ int, spoofCheck bool) error {
+	f.vf().Spoofchk = spoofCheck
+	return nil
+}
+
+func (f *fakePFLinks) LinkSetVfRate(_ netlink.Link, _, minTxRate, maxTxRate int) error {
+	f.vf().MinTxRate, f.vf().MaxTxRate = uint32(minTxRate), uint32(maxTxRate)
+	return nil
+}
+
+func vfContext() context.Context {
+	return vfconfig.WithConfig(context.TODO(), &vfconfig.VFConfig{PFInterfaceName: pfName, VFNum: vfNum})
+}
+
+func newVFConn(labels map[string]string) *networkservice.Connection {
+	return &networkservice.Connection{
+		Id:     "conn-1",
+		Labels: labels,
+		Context: &networkservice.ConnectionContext{
+			EthernetContext: &networkservice.EthernetContext{
+				SrcMac:  vfMAC,
+				VlanTag: 100,
+			},
+		},
+	}
+}
+
+func TestVFEthernetContextServer(t *testing.T) {
+	links := newFakePFLinks()
+	original := *links.vf()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ethernetcontext.NewVFServerWithLinks(links, ethernetcontext.WithTrust(true), ethernetcontext.WithTxRate(10, 100)),
+	)
+
+	conn, err := server.Request(vfContext(), &networkservice.NetworkServiceRequest{
+		Connection: newVFConn(map[string]string{ethernetcontext.SpoofCheckLabel: "false"}),
+	})
+	require.NoError(t, err)
+	require.Equal(t, vfMAC, links.vf().Mac.String())
+	require.Equal(t, 100, links.vf().Vlan)
+	require.Equal(t, 0, links.vf().Qos)
+	require.False(t, links.vf().Spoofchk)
+	require.Equal(t, uint32(10), links.vf().MinTxRate)
+	require.Equal(t, uint32(100), links.vf().MaxTxRate)
+	require.True(t, links.trust)
+
+	// refresh keeps the state taken before the first change
+	conn, err = server.Request(vfContext(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	// Close doesn't depend on the connection labels
+	conn.Labels = map[string]string{ethernetcontext.TrustLabel: "invalid"}
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Equal(t, original, *links.vf())
+	require.False(t, links.trust)
+}
+
+func TestVFEthernetContextServer_Rollback(t *testing.T) {
+	links := newFakePFLinks()
+	original := *links.vf()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ethernetcontext.NewVFServerWithLinks(links, ethernetcontext.WithTrust(true), ethernetcontext.WithSpoofCheck(false)),
+		injecterror.NewServer(),
+	)
+
+	_, err := server.Request(vfContext(), &networkservice.NetworkServiceRequest{Connection: newVFConn(nil)})
+	require.Error(t, err)
+	require.Equal(t, original, *links.vf())
+	require.False(t, links.trust)
+}
+
+func TestVFEthernetContextServer_InvalidLabels(t *testing.T) {
+	links := newFakePFLinks()
+	original := *links.vf()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ethernetcontext.NewVFServerWithLinks(links),
+	)
+
+	_, err := server.Request(vfContext(), &networkservice.NetworkServiceRequest{
+		Connection: newVFConn(map[string]string{ethernetcontext.MaxTxRateLabel: "-1"}),
+	})
+	require.Error(t, err)
+	require.Equal(t, original, *links.vf())
+}
+
+func TestVFEthernetContextClient(t *testing.T) {
+	links := newFakePFLinks()
+	original := *links.vf()
+
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		ethernetcontext.NewVFClientWithLinks(links, ethernetcontext.WithSpoofCheck(false)),
+	)
+
+	conn := newVFConn(nil)
+	conn.GetContext().GetEthernetContext().SrcMac, conn.GetContext().GetEthernetContext().DstMac = "", vfMAC
+
+	conn, err := client.Request(vfContext(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Equal(t, vfMAC, links.vf().Mac.String())
+	require.Equal(t, 100, links.vf().Vlan)
+	require.False(t, links.vf().Spoofchk)
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Equal(t, original, *links.vf())
+}
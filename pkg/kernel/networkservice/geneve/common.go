@@ -0,0 +1,159 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geneve
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/common"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/tunnellink"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/geneve"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/vni"
+)
+
+// Geneve mechanism. There is no Geneve mechanism in the used API version, so it is defined here with the same
+// parameters as the VPP Forwarder uses.
+const (
+	// MECHANISM is the Geneve mechanism type
+	MECHANISM = "GENEVE"
+
+	// SrcIP is the Client side tunnel IP address parameter key
+	SrcIP = common.SrcIP
+	// DstIP is the Endpoint side tunnel IP address parameter key
+	DstIP = common.DstIP
+	// VNI is the Geneve virtual network ID parameter key
+	VNI = "vni"
+	// DstPortKey is the remote side UDP port parameter key, default is DefaultDstPort
+	DstPortKey = "dstPort"
+
+	// DefaultDstPort is the IANA assigned Geneve UDP port
+	DefaultDstPort = 6081
+
+	linkPrefix = "nsmgn"
+)
+
+// LinkName returns the connection Geneve net interface name in the Forwarder's net NS
+func LinkName(conn *networkservice.Connection) string {
+	return linkprovider.LinkName(linkPrefix, conn)
+}
+
+// linker is the common part of the geneve client and server
+type linker struct {
+	allocator  *vni.Allocator
+	dstPort    uint16
+	bridgeName string
+}
+
+func newLinker(options []Option) *linker {
+	l := &linker{
+		dstPort: DefaultDstPort,
+	}
+	for _, opt := range options {
+		opt(l)
+	}
+	return l
+}
+
+// newGeneve returns the Geneve net interface description for the connection, it returns nil if the connection has no
+// Geneve mechanism. Geneve net interface has no local IP, so only the remote side IP is used: the mechanism Src IP for
+// the server side and the mechanism Dst IP for the client side.
+func (l *linker) newGeneve(conn *networkservice.Connection, isClient bool) (*geneve.Geneve, error) {
+	mech := conn.GetMechanism()
+	if mech.GetType() != MECHANISM {
+		return nil, nil
+	}
+	params := mech.GetParameters()
+
+	remote := net.ParseIP(params[SrcIP])
+	if isClient {
+		remote = net.ParseIP(params[DstIP])
+	}
+	if remote == nil {
+		return nil, errors.Errorf("Geneve mechanism has no remote IP: %v", params)
+	}
+
+	vni, err := strconv.ParseUint(params[VNI], 10, 24)
+	if err != nil || vni == 0 {
+		return nil, errors.Errorf("invalid Geneve VNI: %v", params[VNI])
+	}
+
+	dstPort := l.dstPort
+	if value := params[DstPortKey]; value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 16)
+		if err != nil || parsed == 0 {
+			return nil, errors.Errorf("invalid Geneve destination port: %v", value)
+		}
+		dstPort = uint16(parsed)
+	}
+
+	return &geneve.Geneve{
+		Name:    LinkName(conn),
+		VNI:     uint32(vni),
+		Remote:  remote,
+		DstPort: dstPort,
+	}, nil
+}
+
+// geneveLink is the Geneve net interface description used by the tunnel chain elements, netlink has no Geneve
+// support in the used version
+type geneveLink struct {
+	netlink.GenericLink
+	geneve *geneve.Geneve
+}
+
+// kind returns the Geneve part of the tunnel chain elements
+func (l *linker) kind() *tunnellink.Kind {
+	return &tunnellink.Kind{
+		Name:          "Geneve",
+		MechanismType: MECHANISM,
+		IDKey:         VNI,
+		LinkName:      LinkName,
+		NewLink: func(conn *networkservice.Connection, isClient bool) (netlink.Link, error) {
+			expected, err := l.newGeneve(conn, isClient)
+			if err != nil || expected == nil {
+				return nil, err
+			}
+			return &geneveLink{
+				GenericLink: netlink.GenericLink{
+					LinkAttrs: netlink.LinkAttrs{Name: expected.Name},
+					LinkType:  geneve.LinkType,
+				},
+				geneve: expected,
+			}, nil
+		},
+		SameLink: func(existing, expected netlink.Link) bool {
+			existingGeneve, err := geneve.Get(existing.Attrs().Name)
+			return err == nil && sameGeneve(existingGeneve, expected.(*geneveLink).geneve)
+		},
+		AddLink: func(link netlink.Link) error {
+			return geneve.Add(link.(*geneveLink).geneve)
+		},
+	}
+}
+
+func sameGeneve(existing, expected *geneve.Geneve) bool {
+	return existing.VNI == expected.VNI &&
+		existing.DstPort == expected.DstPort &&
+		existing.Remote.Equal(expected.Remote)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geneve_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/geneve"
+	genevetool "github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/geneve"
+)
+
+func request(vni string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "geneve-conn",
+			Mechanism: &networkservice.Mechanism{
+				Type: geneve.MECHANISM,
+				Parameters: map[string]string{
+					geneve.SrcIP: "172.16.5.1",
+					geneve.DstIP: "172.16.5.2",
+					geneve.VNI:   vni,
+				},
+			},
+		},
+	}
+}
+
+func TestGeneveServer(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		geneve.NewServer(),
+	)
+
+	conn, err := server.Request(context.TODO(), request("10"))
+	if errors.Cause(err) == syscall.EOPNOTSUPP {
+		t.Skip("Geneve is not supported by the kernel")
+	}
+	require.NoError(t, err)
+
+	link, err := genevetool.Get(geneve.LinkName(conn))
+	require.NoError(t, err)
+	require.Equal(t, uint32(10), link.VNI)
+	require.Equal(t, uint16(geneve.DefaultDstPort), link.DstPort)
+	require.Equal(t, "172.16.5.1", link.Remote.String())
+
+	// refresh with the changed VNI recreates the net interface
+	conn, err = server.Request(context.TODO(), request("11"))
+	require.NoError(t, err)
+
+	link, err = genevetool.Get(geneve.LinkName(conn))
+	require.NoError(t, err)
+	require.Equal(t, uint32(11), link.VNI)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	_, err = netlink.LinkByName(geneve.LinkName(conn))
+	require.Error(t, err)
+}
+
+func TestGeneveServer_InvalidMechanism(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		geneve.NewServer(),
+	)
+
+	_, err := server.Request(context.TODO(), request(""))
+	require.Error(t, err)
+
+	_, err = server.Request(context.TODO(), request("16777216"))
+	require.Error(t, err)
+
+	req := request("10")
+	req.GetConnection().GetMechanism().GetParameters()[geneve.DstPortKey] = "port"
+	_, err = server.Request(context.TODO(), req)
+	require.Error(t, err)
+}
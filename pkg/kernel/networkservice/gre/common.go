@@ -0,0 +1,204 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gre
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/common"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/tunnellink"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/tunnel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/vni"
+)
+
+// GRE mechanism. There is no GRE mechanism in the used API version, so it is defined here with the common Src/Dst IP
+// parameters.
+const (
+	// MECHANISM is the GRE mechanism type
+	MECHANISM = "GRE"
+
+	// SrcIP is the Client side tunnel IP address parameter key
+	SrcIP = common.SrcIP
+	// DstIP is the Endpoint side tunnel IP address parameter key
+	DstIP = common.DstIP
+	// KeyKey is the GRE key parameter key, no key is used if it is not set
+	KeyKey = "greKey"
+	// ModeKey is the GRE mode parameter key: ModeGRE or ModeGRETAP, default is ModeGRE
+	ModeKey = "greMode"
+
+	// ModeGRE is the L3 GRE tunnel mode
+	ModeGRE = "gre"
+	// ModeGRETAP is the L2 GRE tunnel mode
+	ModeGRETAP = "gretap"
+
+	linkPrefix = "nsmgre"
+	// greKeyFlag is the kernel GRE_KEY flag, netlink defines it only on linux
+	greKeyFlag = 0x2000
+)
+
+// LinkName returns the connection GRE net interface name in the Forwarder's net NS
+func LinkName(conn *networkservice.Connection) string {
+	return linkprovider.LinkName(linkPrefix, conn)
+}
+
+// linker is the common part of the gre client and server
+type linker struct {
+	allocator     *vni.Allocator
+	bridgeName    string
+	tunnelOptions []tunnel.Option
+}
+
+func newLinker(options []Option) *linker {
+	l := &linker{}
+	for _, opt := range options {
+		opt(l)
+	}
+	return l
+}
+
+// newLink returns the GRE net interface description for the connection, it returns nil if the connection has no GRE
+// mechanism. Server side is the Endpoint of the GRE mechanism, so its local IP is the mechanism Dst IP.
+func (l *linker) newLink(conn *networkservice.Connection, isClient bool) (netlink.Link, error) {
+	mech := conn.GetMechanism()
+	if mech.GetType() != MECHANISM {
+		return nil, nil
+	}
+
+	local, remote := net.ParseIP(mech.GetParameters()[DstIP]), net.ParseIP(mech.GetParameters()[SrcIP])
+	if isClient {
+		local, remote = remote, local
+	}
+	if local == nil || remote == nil {
+		return nil, errors.Errorf("GRE mechanism has no local or remote IP: %v", mech.GetParameters())
+	}
+
+	var key uint32
+	if value := mech.GetParameters()[KeyKey]; value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, errors.Errorf("invalid GRE key: %v", value)
+		}
+		key = uint32(parsed)
+	}
+
+	attrs := netlink.LinkAttrs{Name: LinkName(conn)}
+	var link netlink.Link
+	switch mode := mech.GetParameters()[ModeKey]; mode {
+	case "", ModeGRE:
+		link = &netlink.Gretun{LinkAttrs: attrs, Local: local, Remote: remote, IKey: key, OKey: key}
+	case ModeGRETAP:
+		link = &netlink.Gretap{LinkAttrs: attrs, Local: local, Remote: remote, IKey: key, OKey: key}
+	default:
+		return nil, errors.Errorf("invalid GRE mode: %v", mode)
+	}
+
+	if err := tunnel.Apply(link, l.tunnelOptions...); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// kind returns the GRE part of the tunnel chain elements
+func (l *linker) kind() *tunnellink.Kind {
+	return &tunnellink.Kind{
+		Name:          "GRE",
+		MechanismType: MECHANISM,
+		IDKey:         KeyKey,
+		LinkName:      LinkName,
+		NewLink:       l.newLink,
+		SameLink:      sameLink,
+		Bridged: func(link netlink.Link) bool {
+			_, ok := link.(*netlink.Gretap)
+			return ok
+		},
+	}
+}
+
+// sameLink returns true if the existing net interface has all the configured GRE attributes of the expected one
+func sameLink(existing, expected netlink.Link) bool {
+	switch expected := expected.(type) {
+	case *netlink.Gretun:
+		existing, ok := existing.(*netlink.Gretun)
+		return ok && gretunAttrs(existing) == gretunAttrs(expected)
+	case *netlink.Gretap:
+		existing, ok := existing.(*netlink.Gretap)
+		return ok && gretapAttrs(existing) == gretapAttrs(expected)
+	}
+	return false
+}
+
+// greAttrs are the comparable GRE attributes, GRE_KEY flags are set by the kernel for the non zero keys
+type greAttrs struct {
+	iKey, oKey                                    uint32
+	iFlags, oFlags                                uint16
+	local, remote                                 string
+	ttl, tos, pMtuDisc                            uint8
+	link                                          uint32
+	encapType, encapFlags, encapSport, encapDport uint16
+}
+
+func gretunAttrs(gre *netlink.Gretun) greAttrs {
+	return greAttrs{
+		iKey:       gre.IKey,
+		oKey:       gre.OKey,
+		iFlags:     keyFlags(gre.IFlags, gre.IKey),
+		oFlags:     keyFlags(gre.OFlags, gre.OKey),
+		local:      gre.Local.String(),
+		remote:     gre.Remote.String(),
+		ttl:        gre.Ttl,
+		tos:        gre.Tos,
+		pMtuDisc:   gre.PMtuDisc,
+		link:       gre.Link,
+		encapType:  gre.EncapType,
+		encapFlags: gre.EncapFlags,
+		encapSport: gre.EncapSport,
+		encapDport: gre.EncapDport,
+	}
+}
+
+func gretapAttrs(gre *netlink.Gretap) greAttrs {
+	return greAttrs{
+		iKey:       gre.IKey,
+		oKey:       gre.OKey,
+		iFlags:     keyFlags(gre.IFlags, gre.IKey),
+		oFlags:     keyFlags(gre.OFlags, gre.OKey),
+		local:      gre.Local.String(),
+		remote:     gre.Remote.String(),
+		ttl:        gre.Ttl,
+		tos:        gre.Tos,
+		pMtuDisc:   gre.PMtuDisc,
+		link:       gre.Link,
+		encapType:  gre.EncapType,
+		encapFlags: gre.EncapFlags,
+		encapSport: gre.EncapSport,
+		encapDport: gre.EncapDport,
+	}
+}
+
+func keyFlags(flags uint16, key uint32) uint16 {
+	if key != 0 {
+		return flags | greKeyFlag
+	}
+	return flags
+}
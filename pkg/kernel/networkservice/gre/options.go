@@ -0,0 +1,50 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gre
+
+import (
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/tunnel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/vni"
+)
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(l *linker)
+
+// WithBridgeName sets the bridge name in the current net NS to attach the GRETAP net interfaces to. The bridge should
+// already exist.
+func WithBridgeName(bridgeName string) Option {
+	return func(l *linker) {
+		l.bridgeName = bridgeName
+	}
+}
+
+// WithTunnelOptions sets the QoS options applied to the GRE net interfaces on creation
+func WithTunnelOptions(options ...tunnel.Option) Option {
+	return func(l *linker) {
+		l.tunnelOptions = options
+	}
+}
+
+// WithKeyAllocator sets the GRE keys allocator used by the server: the GRE key is allocated for the connections with
+// no GRE key in the mechanism parameters, already set one is reserved to detect the conflicts. It should allocate in
+// [1, vni.MaxGREKey] range. Allocator with the node lock should be used if there are multiple Forwarder instances on the
+// node.
+func WithKeyAllocator(allocator *vni.Allocator) Option {
+	return func(l *linker) {
+		l.allocator = allocator
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gre_test
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/gre"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/tunnel"
+)
+
+var (
+	srcIP = net.ParseIP("172.16.3.1").To4()
+	dstIP = net.ParseIP("172.16.3.2").To4()
+)
+
+func request(mode, key string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "gre-conn",
+			Mechanism: &networkservice.Mechanism{
+				Type: gre.MECHANISM,
+				Parameters: map[string]string{
+					gre.SrcIP:   srcIP.String(),
+					gre.DstIP:   dstIP.String(),
+					gre.ModeKey: mode,
+					gre.KeyKey:  key,
+				},
+			},
+		},
+	}
+}
+
+func TestGREServer(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		gre.NewServer(),
+	)
+
+	conn, err := server.Request(context.TODO(), request(gre.ModeGRE, "10"))
+	if errors.Cause(err) == syscall.EOPNOTSUPP {
+		t.Skip("GRE is not supported by the kernel")
+	}
+	require.NoError(t, err)
+
+	link, err := netlink.LinkByName(gre.LinkName(conn))
+	require.NoError(t, err)
+	require.IsType(t, new(netlink.Gretun), link)
+	require.Equal(t, uint32(10), link.(*netlink.Gretun).IKey)
+	require.True(t, link.(*netlink.Gretun).Local.Equal(dstIP))
+	require.True(t, link.(*netlink.Gretun).Remote.Equal(srcIP))
+
+	// refresh with the same parameters keeps the net interface
+	conn, err = server.Request(context.TODO(), request(gre.ModeGRE, "10"))
+	require.NoError(t, err)
+	index := link.Attrs().Index
+	link, err = netlink.LinkByName(gre.LinkName(conn))
+	require.NoError(t, err)
+	require.Equal(t, index, link.Attrs().Index)
+
+	// refresh with the changed mode recreates the net interface
+	conn, err = server.Request(context.TODO(), request(gre.ModeGRETAP, "10"))
+	require.NoError(t, err)
+
+	link, err = netlink.LinkByName(gre.LinkName(conn))
+	require.NoError(t, err)
+	require.IsType(t, new(netlink.Gretap), link)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	_, err = netlink.LinkByName(gre.LinkName(conn))
+	require.Error(t, err)
+}
+
+func TestGREServer_TunnelOptions(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		gre.NewServer(),
+	)
+
+	conn, err := server.Request(context.TODO(), request(gre.ModeGRE, "20"))
+	if errors.Cause(err) == syscall.EOPNOTSUPP {
+		t.Skip("GRE is not supported by the kernel")
+	}
+	require.NoError(t, err)
+	defer func() { _, _ = server.Close(context.TODO(), conn) }()
+
+	link, err := netlink.LinkByName(gre.LinkName(conn))
+	require.NoError(t, err)
+
+	// the net interface with the same key but not the same TOS is recreated
+	ecnServer := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		gre.NewServer(gre.WithTunnelOptions(tunnel.WithECNPropagation())),
+	)
+	ecnConn, err := ecnServer.Request(context.TODO(), request(gre.ModeGRE, "20"))
+	require.NoError(t, err)
+	defer func() { _, _ = ecnServer.Close(context.TODO(), ecnConn) }()
+
+	ecnLink, err := netlink.LinkByName(gre.LinkName(ecnConn))
+	require.NoError(t, err)
+	require.NotEqual(t, link.Attrs().Index, ecnLink.Attrs().Index)
+	require.NotEqual(t, link.(*netlink.Gretun).Tos, ecnLink.(*netlink.Gretun).Tos)
+}
+
+func TestGREServer_InvalidMechanism(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		gre.NewServer(),
+	)
+
+	_, err := server.Request(context.TODO(), request("ipip", ""))
+	require.Error(t, err)
+
+	_, err = server.Request(context.TODO(), request(gre.ModeGRE, "key"))
+	require.Error(t, err)
+
+	req := request(gre.ModeGRE, "")
+	delete(req.GetConnection().GetMechanism().GetParameters(), gre.SrcIP)
+	_, err = server.Request(context.TODO(), req)
+	require.Error(t, err)
+}
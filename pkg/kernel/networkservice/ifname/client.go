@@ -0,0 +1,56 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ifname provides chain element setting kernel mechanism interface name with the naming strategy
+package ifname
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/naming"
+)
+
+type ifNameClient struct {
+	nameFunc naming.NameFunc
+}
+
+// NewClient returns a new client chain element setting the interface name returned by nameFunc for the kernel
+// mechanism preferences having no interface name set
+func NewClient(nameFunc naming.NameFunc) networkservice.NetworkServiceClient {
+	return &ifNameClient{
+		nameFunc: nameFunc,
+	}
+}
+
+func (c *ifNameClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	for _, mechanism := range request.GetMechanismPreferences() {
+		if mech := kernel.ToMechanism(mechanism); mech != nil && mech.GetParameters()[kernel.InterfaceNameKey] == "" {
+			mech.SetInterfaceName(c.nameFunc(request.GetConnection()))
+		}
+	}
+	return next.Client(ctx).Request(ctx, request, opts...)
+}
+
+func (c *ifNameClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifsysctl
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type ifSysctlClient struct {
+	*ifSysctl
+}
+
+// NewClient returns a new client chain element setting the configured per interface kernel parameters of the net
+// interface selected by the returned connection mechanism in its net NS (the current one if there is no net NS URL)
+// on Request and restoring the original values on Close. The parameters are set again on refresh, so the values
+// changed by someone else are fixed.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &ifSysctlClient{
+		ifSysctl: newIfSysctl(options),
+	}
+}
+
+func (c *ifSysctlClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil || len(c.params) == 0 {
+		return conn, nil
+	}
+
+	// the parameters are set again on refresh, but the original values stored on the first Request are kept
+	prev, _ := load(ctx, metadata.IsClient(c))
+	snapshot, err := c.apply(prev, mech.GetNetNSURL(), mech.GetInterfaceName(conn))
+	if err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	store(ctx, metadata.IsClient(c), snapshot)
+
+	return conn, nil
+}
+
+func (c *ifSysctlClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var restoreErr error
+	if snapshot, ok := loadAndDelete(ctx, metadata.IsClient(c)); ok {
+		restoreErr = snapshot.restore()
+	}
+
+	if err != nil && restoreErr != nil {
+		return nil, errors.Wrap(err, restoreErr.Error())
+	}
+	if restoreErr != nil {
+		return nil, restoreErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifsysctl_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ifsysctl"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+func TestIfSysctlClient(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	name := "net/ipv4/conf/" + ifName + "/arp_ignore"
+	original, err := sysctl.GetInt(name)
+	require.NoError(t, err)
+	require.NotEqual(t, int64(1), original)
+
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		ifsysctl.NewClient(ifsysctl.WithARPIgnore(1)),
+	)
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.InterfaceNameKey: ifName,
+					kernel.NetNSURL:         "file:///proc/self/ns/net",
+				},
+			},
+		},
+	}
+
+	conn, err := client.Request(context.TODO(), request)
+	require.NoError(t, err)
+	value, err := sysctl.GetInt(name)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), value)
+
+	// refresh sets the value changed by someone else again, but keeps the original values
+	require.NoError(t, sysctl.Set(name, "2"))
+	conn, err = client.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	value, err = sysctl.GetInt(name)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), value)
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	value, err = sysctl.GetInt(name)
+	require.NoError(t, err)
+	require.Equal(t, original, value)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifsysctl
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+// param is a per interface kernel parameter: net.<family>.conf.<interface>.<name>
+type param struct {
+	family string
+	name   string
+	value  string
+}
+
+// ifSysctl is the common part of the ifsysctl client and server
+type ifSysctl struct {
+	params []*param
+}
+
+func newIfSysctl(options []Option) *ifSysctl {
+	p := &ifSysctl{}
+	for _, opt := range options {
+		opt(p)
+	}
+	return p
+}
+
+// applied is the snapshot of the original values of the set parameters in the net NS and for the net interface they
+// have been set for
+type applied struct {
+	netNSURL string
+	ifName   string
+	snapshot *sysctl.Snapshot
+}
+
+// apply sets the parameters for the net interface in the given net NS or in the current one if there is no URL. The
+// per interface parameters are per net NS, so they can't be set from another net NS. On refresh prev is the state
+// applied on the previous Request: the parameters are set again with its snapshot, so the values changed by someone
+// else since then are fixed and the original values are kept. If the net NS or the net interface has changed, prev
+// is restored. On the first Request error it restores the already set parameters.
+func (p *ifSysctl) apply(prev *applied, netNSURL, ifName string) (*applied, error) {
+	a := prev
+	if a == nil || a.netNSURL != netNSURL || a.ifName != ifName {
+		a = &applied{netNSURL: netNSURL, ifName: ifName}
+	}
+	err := runIn(netNSURL, func() (err error) {
+		if a.snapshot == nil {
+			if a.snapshot, err = sysctl.Take(); err != nil {
+				return err
+			}
+		}
+		for _, prm := range p.params {
+			path := strings.Join([]string{"net", prm.family, "conf", ifName, prm.name}, "/")
+			if err := a.snapshot.Set(path, prm.value); err != nil {
+				if a == prev {
+					return err
+				}
+				if restoreErr := a.snapshot.Restore(); restoreErr != nil {
+					return errors.Wrap(err, restoreErr.Error())
+				}
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if prev != nil && a != prev {
+		if err := prev.restore(); err != nil {
+			if restoreErr := a.restore(); restoreErr != nil {
+				return nil, errors.Wrap(err, restoreErr.Error())
+			}
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// restore sets back the original values, there is nothing to restore if the net NS has been already deleted
+func (a *applied) restore() error {
+	err := runIn(a.netNSURL, a.snapshot.Restore)
+	if err != nil && os.IsNotExist(errors.Cause(err)) {
+		return nil
+	}
+	return err
+}
+
+func runIn(netNSURL string, runner func() error) error {
+	if netNSURL == "" {
+		return runner()
+	}
+	return nshandle.RunInURL(netNSURL, runner)
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifsysctl
+
+import (
+	"strconv"
+)
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(p *ifSysctl)
+
+func withParam(family, name, value string) Option {
+	return func(p *ifSysctl) {
+		for _, prm := range p.params {
+			if prm.family == family && prm.name == name {
+				prm.value = value
+				return
+			}
+		}
+		p.params = append(p.params, &param{
+			family: family,
+			name:   name,
+			value:  value,
+		})
+	}
+}
+
+// WithAcceptRA sets net.ipv6.conf.<interface>.accept_ra: 0 - don't accept Router Advertisements, 1 - accept if
+// forwarding is disabled, 2 - accept even if forwarding is enabled. Setting it to 0 prevents the Client's net
+// interface from picking up rogue RAs from the underlay in bridged scenarios.
+func WithAcceptRA(value int) Option {
+	return withParam("ipv6", "accept_ra", strconv.Itoa(value))
+}
+
+// WithRouterSolicitations sets net.ipv6.conf.<interface>.router_solicitations: number of Router Solicitations to
+// send until assuming no routers are present, -1 - send until a router is found, 0 - don't send
+func WithRouterSolicitations(value int) Option {
+	return withParam("ipv6", "router_solicitations", strconv.Itoa(value))
+}
+
+// WithARPAnnounce sets net.ipv4.conf.<interface>.arp_announce: 0 - use any local address, 1 - try to avoid local
+// addresses not in the target's subnet, 2 - always use the best local address for the target. Interfaces carrying
+// VIPs shared across multiple connections should use 2 to prevent ARP flux.
+func WithARPAnnounce(value int) Option {
+	return withParam("ipv4", "arp_announce", strconv.Itoa(value))
+}
+
+// WithARPIgnore sets net.ipv4.conf.<interface>.arp_ignore: 0 - reply for any local target address, 1 - reply only if
+// the target address is configured on the incoming interface, 2 - additionally the sender address should be in the
+// same subnet, see ip-sysctl.txt for the rest of the values.
+func WithARPIgnore(value int) Option {
+	return withParam("ipv4", "arp_ignore", strconv.Itoa(value))
+}
+
+// WithoutICMPRedirects sets net.ipv4.conf.<interface>.send_redirects, net.ipv4.conf.<interface>.accept_redirects and
+// net.ipv6.conf.<interface>.accept_redirects to 0, so the ICMP redirects from the overlapping underlay routers don't
+// corrupt the Client's routing tables. IPv4 redirects are still sent if net.ipv4.conf.all.send_redirects is enabled.
+func WithoutICMPRedirects() Option {
+	return func(p *ifSysctl) {
+		withParam("ipv4", "send_redirects", "0")(p)
+		withParam("ipv4", "accept_redirects", "0")(p)
+		withParam("ipv6", "accept_redirects", "0")(p)
+	}
+}
+
+// WithRPFilter sets net.ipv4.conf.<interface>.rp_filter: 0 - no source validation, 1 - strict reverse path
+// validation, 2 - loose reverse path validation. The effective value is the max of the interface one and
+// net.ipv4.conf.all.rp_filter, so asymmetric policy routed connections may need it to be disabled on both.
+func WithRPFilter(value int) Option {
+	return withParam("ipv4", "rp_filter", strconv.Itoa(value))
+}
+
+// WithForwarding sets net.ipv4.conf.<interface>.forwarding and net.ipv6.conf.<interface>.forwarding: enables or
+// disables forwarding of the packets received on the Client's net interface.
+func WithForwarding(enabled bool) Option {
+	value := "0"
+	if enabled {
+		value = "1"
+	}
+	return func(p *ifSysctl) {
+		withParam("ipv4", "forwarding", value)(p)
+		withParam("ipv6", "forwarding", value)(p)
+	}
+}
+
+// WithoutIPv6 sets net.ipv6.conf.<interface>.disable_ipv6 to 1, so the Client's net interface gets no IPv6 link-local
+// address and doesn't take part in the IPv6 neighbor discovery.
+func WithoutIPv6() Option {
+	return withParam("ipv6", "disable_ipv6", "1")
+}
+
+// WithAcceptLocal sets net.ipv4.conf.<interface>.accept_local: 0 - drop the packets with local source addresses,
+// 1 - accept them, it is required for the loopback-like topologies when both ends of the connection are on the same
+// node.
+func WithAcceptLocal(value int) Option {
+	return withParam("ipv4", "accept_local", strconv.Itoa(value))
+}
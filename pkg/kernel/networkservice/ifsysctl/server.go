@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ifsysctl provides chain element setting the per interface kernel parameters of the Client's net interface
+package ifsysctl
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type ifSysctlServer struct {
+	*ifSysctl
+}
+
+// NewServer returns a new server chain element setting the configured per interface kernel parameters of the Client's
+// net interface in its net NS (the current one if there is no net NS URL) on Request and restoring the original
+// values on Close. The parameters are set again on refresh, so the values changed by someone else are fixed.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &ifSysctlServer{
+		ifSysctl: newIfSysctl(options),
+	}
+}
+
+func (s *ifSysctlServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	mech := kernel.ToMechanism(request.GetConnection().GetMechanism())
+	if mech == nil || len(s.params) == 0 {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	// the parameters are set again on refresh, but the original values stored on the first Request are kept
+	prev, _ := load(ctx, metadata.IsClient(s))
+	snapshot, err := s.apply(prev, mech.GetNetNSURL(), mech.GetInterfaceName(request.GetConnection()))
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if snapshot == prev {
+			return nil, err
+		}
+		if restoreErr := snapshot.restore(); restoreErr != nil {
+			log.Entry(ctx).WithField("ifSysctlServer", "Request").Warnf("failed to restore sysctls: %s", restoreErr.Error())
+		}
+		return nil, err
+	}
+
+	store(ctx, metadata.IsClient(s), snapshot)
+
+	return conn, nil
+}
+
+func (s *ifSysctlServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var restoreErr error
+	if snapshot, ok := loadAndDelete(ctx, metadata.IsClient(s)); ok {
+		restoreErr = snapshot.restore()
+	}
+
+	if err != nil && restoreErr != nil {
+		return nil, errors.Wrap(err, restoreErr.Error())
+	}
+	if restoreErr != nil {
+		return nil, restoreErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifsysctl_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ifsysctl"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+const (
+	ifName   = "sysctl-1"
+	peerName = "sysctl-2"
+)
+
+func TestIfSysctlServer(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	params := map[string]int64{
+		"net/ipv6/conf/" + ifName + "/accept_ra":            0,
+		"net/ipv6/conf/" + ifName + "/router_solicitations": 0,
+		"net/ipv4/conf/" + ifName + "/arp_announce":         2,
+		"net/ipv4/conf/" + ifName + "/arp_ignore":           1,
+		"net/ipv4/conf/" + ifName + "/send_redirects":       0,
+		"net/ipv4/conf/" + ifName + "/accept_redirects":     0,
+		"net/ipv6/conf/" + ifName + "/accept_redirects":     0,
+		"net/ipv4/conf/" + ifName + "/rp_filter":            2,
+		"net/ipv4/conf/" + ifName + "/forwarding":           1,
+		"net/ipv6/conf/" + ifName + "/forwarding":           1,
+		"net/ipv4/conf/" + ifName + "/accept_local":         1,
+		"net/ipv6/conf/" + ifName + "/disable_ipv6":         1,
+	}
+	originals := make(map[string]int64)
+	for name := range params {
+		value, err := sysctl.GetInt(name)
+		require.NoError(t, err)
+		require.NotEqual(t, params[name], value)
+		originals[name] = value
+	}
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ifsysctl.NewServer(
+			ifsysctl.WithAcceptRA(0),
+			ifsysctl.WithRouterSolicitations(0),
+			ifsysctl.WithARPAnnounce(2),
+			ifsysctl.WithARPIgnore(1),
+			ifsysctl.WithoutICMPRedirects(),
+			ifsysctl.WithRPFilter(2),
+			ifsysctl.WithForwarding(true),
+			ifsysctl.WithAcceptLocal(1),
+			ifsysctl.WithoutIPv6(),
+		),
+	)
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.InterfaceNameKey: ifName,
+					kernel.NetNSURL:         "file:///proc/self/ns/net",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	for name, expected := range params {
+		value, err := sysctl.GetInt(name)
+		require.NoError(t, err)
+		require.Equal(t, expected, value, name)
+	}
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	for name, expected := range originals {
+		value, err := sysctl.GetInt(name)
+		require.NoError(t, err)
+		require.Equal(t, expected, value, name)
+	}
+}
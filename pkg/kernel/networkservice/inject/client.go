@@ -0,0 +1,103 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type injectClient struct {
+	*injector
+}
+
+// NewClient - returns a new networkservice.NetworkServiceClient that moves network interface provided by the
+// LinkProvider into the network namespace selected by the returned connection mechanism on Request and back to the
+// current network namespace on Close
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &injectClient{
+		injector: newInjector(options),
+	}
+}
+
+func (c *injectClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	logCtx := log.WithField(ctx, "injectClient", "Request")
+	logEntry := log.Entry(logCtx)
+	isClient := metadata.IsClient(c)
+
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return conn, nil
+	}
+
+	if injected, ok := loadInjected(ctx, isClient); ok {
+		// the Client can refresh with the requested name instead of the applied one
+		ifName := c.resolve(logCtx, conn, injected)
+		mech.SetInterfaceName(ifName)
+		logEntry.Infof("network interface %s is already in the target namespace for connection %s",
+			ifName, conn.GetId())
+		return conn, nil
+	}
+
+	injected, err := c.create(logCtx, conn)
+	if err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	storeInjected(ctx, isClient, injected)
+
+	return conn, nil
+}
+
+func (c *injectClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	logCtx := log.WithField(ctx, "injectClient", "Close")
+
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if injected, ok := loadInjected(ctx, metadata.IsClient(c)); ok && mech != nil {
+		mech.SetInterfaceName(c.resolve(logCtx, conn, injected))
+	}
+
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var injectErr error
+	if mech != nil {
+		injectErr = c.remove(logCtx, conn)
+	}
+
+	if err != nil && injectErr != nil {
+		return nil, errors.Wrap(err, injectErr.Error())
+	}
+	if injectErr != nil {
+		return nil, injectErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject_test
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/inject"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+func TestInjectClient_Veth(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	targetNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		inject.NewClient(inject.WithLinkProvider(linkprovider.NewVeth())),
+	)
+
+	conn, err = client.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	_, err = netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.NoError(t, err)
+	require.NoError(t, nshandle.RunIn(curNetNS, targetNetNS, func() error {
+		_, linkErr := netlink.LinkByName(ifName)
+		return linkErr
+	}))
+
+	// refresh
+	conn, err = client.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	_, err = netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.Error(t, err)
+}
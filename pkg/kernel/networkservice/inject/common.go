@@ -0,0 +1,478 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ifindex"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nodelock"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/optime"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/owned"
+)
+
+// uniqueNamePrefixLen is the max length of the requested net interface name kept in the unique one
+const uniqueNamePrefixLen = 7
+
+// injector is the common part of the inject client and server
+type injector struct {
+	linkProvider     linkprovider.LinkProvider
+	preemptionPolicy PreemptionPolicy
+	locker           *nodelock.Locker
+	ifIndexCache     *ifindex.Cache
+
+	mu    sync.Mutex
+	locks map[string]*nodelock.Lock
+}
+
+func newInjector(options []Option) *injector {
+	i := &injector{
+		linkProvider:     linkprovider.NewExisting(),
+		preemptionPolicy: PreemptionPolicyAdopt,
+		locks:            make(map[string]*nodelock.Lock),
+	}
+	for _, opt := range options {
+		opt(i)
+	}
+	return i
+}
+
+// create moves the connection net interface into the Client's net NS, it returns the net interface name and index
+// in the Client's net NS
+func (i *injector) create(ctx context.Context, conn *networkservice.Connection) (*injectedLink, error) {
+	logEntry := log.Entry(ctx)
+	mech := kernel.ToMechanism(conn.GetMechanism())
+
+	curNetNS, err := nshandle.Current()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = curNetNS.Close() }()
+
+	var clientNetNS netns.NsHandle
+	clientNetNS, err = nshandle.FromURL(mech.GetNetNSURL())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = clientNetNS.Close() }()
+
+	ifName := mech.GetInterfaceName(conn)
+
+	// the name is locked before the preemption, so the net interface injected by another Forwarder instance is not
+	// preempted
+	locked, err := i.lock(conn, ifName, clientNetNS)
+	if err != nil {
+		return nil, err
+	}
+
+	adopted, newIfName, err := i.preempt(ctx, conn, ifName, curNetNS, clientNetNS)
+	if err != nil {
+		return nil, i.unlockFailed(conn, locked, err)
+	}
+	renamed := newIfName != ifName
+	if renamed {
+		logEntry.Infof("network interface %s already exists in the Client's namespace, using %s for connection %s",
+			ifName, newIfName, conn.GetId())
+		ifName = newIfName
+	}
+
+	if adopted {
+		logEntry.Infof("adopted network interface %s in the Client's namespace for connection %s", ifName, conn.GetId())
+	} else {
+		if err = i.inject(ctx, conn, ifName, curNetNS, clientNetNS); err != nil {
+			return nil, i.unlockFailed(conn, locked, err)
+		}
+		logEntry.Infof("moved network interface %s into the Client's namespace for connection %s", ifName, conn.GetId())
+	}
+
+	injected := &injectedLink{ifName: ifName}
+	if err = nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		link, linkErr := i.ifIndexCache.Link(clientNetNS, &netlink.Handle{}, ifName)
+		if linkErr != nil {
+			return errors.Wrapf(linkErr, "failed to get net interface: %v", ifName)
+		}
+		injected.ifIndex = link.Attrs().Index
+		return nil
+	}); err != nil {
+		if !adopted {
+			// the injected net interface is not tracked by the connection, so it is not left in the Client's net NS
+			err = i.release(ctx, conn, ifName, curNetNS, clientNetNS, err)
+		}
+		return nil, i.unlockFailed(conn, locked, err)
+	}
+
+	if renamed {
+		// the applied name is reported back to the Client with the mechanism only after the successful injection
+		mech.SetInterfaceName(ifName)
+	}
+
+	return injected, nil
+}
+
+// resolve returns the current name of the injected net interface in the Client's net NS. The net interface is looked
+// up by the index recorded at the injection, so it is found after being renamed in the Client's net NS (e.g. by
+// udev), if the index is not found, it falls back to the lookup by the injected name and the connection ownership mark.
+func (i *injector) resolve(ctx context.Context, conn *networkservice.Connection, injected *injectedLink) string {
+	logEntry := log.Entry(ctx)
+	mech := kernel.ToMechanism(conn.GetMechanism())
+
+	curNetNS, err := nshandle.Current()
+	if err != nil {
+		return injected.ifName
+	}
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, err := nshandle.FromURL(mech.GetNetNSURL())
+	if err != nil {
+		return injected.ifName
+	}
+	defer func() { _ = clientNetNS.Close() }()
+
+	ifName := injected.ifName
+	_ = nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		if link, linkErr := netlink.LinkByIndex(injected.ifIndex); linkErr == nil {
+			ifName = link.Attrs().Name
+			return nil
+		}
+		if _, linkErr := netlink.LinkByName(injected.ifName); linkErr == nil {
+			return nil
+		}
+		links, linkErr := netlink.LinkList()
+		if linkErr != nil {
+			return linkErr
+		}
+		for _, link := range links {
+			if owned.IsConnLink(link, conn.GetId()) {
+				ifName = link.Attrs().Name
+				return nil
+			}
+		}
+		return nil
+	})
+
+	if ifName != injected.ifName {
+		logEntry.Infof("network interface %s is renamed to %s in the Client's namespace for connection %s",
+			injected.ifName, ifName, conn.GetId())
+	}
+	return ifName
+}
+
+// remove moves the connection net interface back into the Forwarder's net NS and releases its name lock
+func (i *injector) remove(ctx context.Context, conn *networkservice.Connection) error {
+	err := i.removeLink(ctx, conn)
+
+	if unlockErr := i.unlock(conn); unlockErr != nil {
+		if err != nil {
+			return errors.Wrap(err, unlockErr.Error())
+		}
+		return unlockErr
+	}
+	return err
+}
+
+func (i *injector) removeLink(ctx context.Context, conn *networkservice.Connection) error {
+	logEntry := log.Entry(ctx)
+	mech := kernel.ToMechanism(conn.GetMechanism())
+
+	curNetNS, err := nshandle.Current()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = curNetNS.Close() }()
+
+	var clientNetNS netns.NsHandle
+	clientNetNS, err = nshandle.FromURL(mech.GetNetNSURL())
+	if os.IsNotExist(errors.Cause(err)) {
+		// the Client's pod is already deleted, the virtual net interfaces are deleted with its net NS and the physical
+		// ones are moved back into the init net NS by the kernel
+		logEntry.Infof("the Client's namespace is already deleted for connection %s", conn.GetId())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = clientNetNS.Close() }()
+
+	ifName := mech.GetInterfaceName(conn)
+	if err = i.eject(ctx, conn, ifName, curNetNS, clientNetNS); err != nil {
+		return err
+	}
+	logEntry.Infof("moved network interface %s into the Forwarder's namespace for connection %s", ifName, conn.GetId())
+
+	return nil
+}
+
+// preempt applies the preemption policy if the net interface with the given name already exists in the Client's net
+// NS, it returns true if the existing net interface is adopted and the net interface name to use
+func (i *injector) preempt(ctx context.Context, conn *networkservice.Connection, ifName string, curNetNS, clientNetNS netns.NsHandle) (bool, string, error) {
+	if curNetNS.Equal(clientNetNS) {
+		// the provided net interface can already have the required name
+		if link, err := i.linkProvider.AdoptLink(ctx, conn); err == nil && link.Attrs().Name == ifName {
+			return true, ifName, nil
+		}
+	}
+
+	if !i.exists(ifName, curNetNS, clientNetNS) {
+		return false, ifName, nil
+	}
+
+	switch i.preemptionPolicy {
+	case PreemptionPolicyAdopt:
+		if !i.isConnLink(ifName, conn, curNetNS, clientNetNS) {
+			return false, "", errors.Errorf("net interface already exists in the Client's net NS and is not created for the connection: %v", ifName)
+		}
+		return true, ifName, nil
+	case PreemptionPolicyReplace:
+		return false, ifName, removeStale(ifName, curNetNS, clientNetNS)
+	case PreemptionPolicyRename:
+		newIfName := uniqueName(ifName, conn)
+		if i.exists(newIfName, curNetNS, clientNetNS) {
+			return false, "", errors.Errorf("net interface already exists in the Client's net NS: %v, %v", ifName, newIfName)
+		}
+		return false, newIfName, nil
+	default:
+		return false, "", errors.Errorf("net interface already exists in the Client's net NS: %v", ifName)
+	}
+}
+
+func (i *injector) inject(ctx context.Context, conn *networkservice.Connection, ifName string, curNetNS, clientNetNS netns.NsHandle) error {
+	link, err := i.linkProvider.AdoptLink(ctx, conn)
+	if err != nil {
+		if link, err = i.linkProvider.CreateLink(ctx, conn); err != nil {
+			return err
+		}
+	}
+
+	if !curNetNS.Equal(clientNetNS) {
+		if err = optime.Time(ctx, "LinkSetNsFd", link, func() error { return netlink.LinkSetNsFd(link, int(clientNetNS)) }); err != nil {
+			err = errors.Wrapf(err, "failed to move net interface to net NS: %v %v", link.Attrs().Name, clientNetNS)
+			return i.release(ctx, conn, link.Attrs().Name, curNetNS, curNetNS, err)
+		}
+	}
+
+	if err = nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		if setErr := setName(link.Attrs().Name, ifName); setErr != nil {
+			return setErr
+		}
+		return setConn(ctx, ifName, conn)
+	}); err != nil {
+		// the net interface can be renamed before the failure
+		name := link.Attrs().Name
+		if i.exists(ifName, curNetNS, clientNetNS) {
+			name = ifName
+		}
+		return i.release(ctx, conn, name, curNetNS, clientNetNS, err)
+	}
+
+	return nil
+}
+
+// release moves the net interface provided for the connection back into the Forwarder's net NS and deletes it with
+// the LinkProvider, it is used to roll back the failed injection. It returns the injection error.
+func (i *injector) release(ctx context.Context, conn *networkservice.Connection, ifName string, curNetNS, linkNetNS netns.NsHandle, injectErr error) error {
+	logEntry := log.Entry(ctx).WithField("injector", "release")
+
+	if !curNetNS.Equal(linkNetNS) {
+		if err := moveInterfaceToAnotherNamespace(ifName, curNetNS, linkNetNS, curNetNS); err != nil {
+			logEntry.Warnf("failed to move network interface %s into the Forwarder's namespace for connection %s: %s",
+				ifName, conn.GetId(), err.Error())
+			return injectErr
+		}
+	}
+
+	link, err := netlink.LinkByName(ifName)
+	if err == nil {
+		err = i.linkProvider.DeleteLink(ctx, conn, link)
+	}
+	if err != nil {
+		logEntry.Warnf("failed to delete network interface %s for connection %s: %s", ifName, conn.GetId(), err.Error())
+	}
+
+	return injectErr
+}
+
+func (i *injector) eject(ctx context.Context, conn *networkservice.Connection, ifName string, curNetNS, clientNetNS netns.NsHandle) error {
+	if !curNetNS.Equal(clientNetNS) {
+		if err := moveInterfaceToAnotherNamespace(ifName, curNetNS, clientNetNS, curNetNS); err != nil {
+			return err
+		}
+	}
+
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+
+	return i.linkProvider.DeleteLink(ctx, conn, link)
+}
+
+// lock locks the requested net interface name in the Client's net NS on the node, so the Forwarder instances on the
+// node don't inject the net interfaces with the same name into the same net NS. It returns true if the lock is taken by
+// this call, the connection already holding the lock keeps it.
+func (i *injector) lock(conn *networkservice.Connection, ifName string, clientNetNS netns.NsHandle) (bool, error) {
+	if i.locker == nil {
+		return false, nil
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if _, ok := i.locks[conn.GetId()]; ok {
+		return false, nil
+	}
+
+	lock, err := i.locker.TryLock(nodelock.KindIfName, clientNetNS.UniqueId()+"-"+ifName)
+	if nodelock.IsLocked(err) {
+		return false, errors.Wrapf(err, "net interface name is already used in the Client's net NS on the node: %v", ifName)
+	}
+	if err != nil {
+		return false, err
+	}
+	i.locks[conn.GetId()] = lock
+
+	return true, nil
+}
+
+func (i *injector) unlock(conn *networkservice.Connection) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	lock, ok := i.locks[conn.GetId()]
+	if !ok {
+		return nil
+	}
+	delete(i.locks, conn.GetId())
+
+	return lock.Unlock()
+}
+
+// unlockFailed releases the lock taken by the failed injection, it returns the injection error
+func (i *injector) unlockFailed(conn *networkservice.Connection, locked bool, injectErr error) error {
+	if locked {
+		_ = i.unlock(conn)
+	}
+	return injectErr
+}
+
+func (i *injector) exists(ifName string, curNetNS, clientNetNS netns.NsHandle) bool {
+	return nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		_, err := i.ifIndexCache.Link(clientNetNS, &netlink.Handle{}, ifName)
+		return err
+	}) == nil
+}
+
+// isConnLink returns true if the net interface in the Client's net NS is created for the connection
+func (i *injector) isConnLink(ifName string, conn *networkservice.Connection, curNetNS, clientNetNS netns.NsHandle) bool {
+	return nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		link, err := i.ifIndexCache.Link(clientNetNS, &netlink.Handle{}, ifName)
+		if err != nil {
+			return err
+		}
+		if !owned.IsConnLink(link, conn.GetId()) {
+			return errors.Errorf("net interface is not created for the connection: %v", ifName)
+		}
+		return nil
+	}) == nil
+}
+
+// setConn marks the net interface as created for the connection, so it is adopted only by the same connection after
+// the Forwarder restart and is found after being renamed in the Client's net NS. The mark is a best effort: the
+// existing net interface can have the alias set by someone else, such net interface is injected without the mark.
+func setConn(ctx context.Context, ifName string, conn *networkservice.Connection) error {
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+	if owned.IsForeign(link) {
+		log.Entry(ctx).WithField("injector", "setConn").
+			Warnf("the net interface alias is used by someone else, it is not marked for the connection %s: %v %v",
+				conn.GetId(), ifName, link.Attrs().Alias)
+		return nil
+	}
+	return owned.SetConnAt(&netlink.Handle{}, link, conn.GetId())
+}
+
+// uniqueName returns the net interface name with the connection unique suffix, it fits into the linux interface name
+// length limit
+func uniqueName(ifName string, conn *networkservice.Connection) string {
+	if len(ifName) > uniqueNamePrefixLen {
+		ifName = ifName[:uniqueNamePrefixLen]
+	}
+	return linkprovider.LinkName(ifName, conn)
+}
+
+func setName(oldName, newName string) error {
+	if oldName == newName {
+		return nil
+	}
+
+	link, err := netlink.LinkByName(oldName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get net interface: %v", oldName)
+	}
+
+	if err := netlink.LinkSetName(link, newName); err != nil {
+		return errors.Wrapf(err, "failed to rename net interface: %v -> %v", oldName, newName)
+	}
+
+	return nil
+}
+
+// removeStale deletes the stale net interface from the Client's net NS, if the net interface cannot be deleted (e.g.
+// it is a physical device), it is moved into the Forwarder's net NS
+func removeStale(ifName string, curNetNS, clientNetNS netns.NsHandle) error {
+	if err := nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		link, err := netlink.LinkByName(ifName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+		}
+		return netlink.LinkDel(link)
+	}); err == nil {
+		return nil
+	}
+	if curNetNS.Equal(clientNetNS) {
+		return errors.Errorf("failed to delete stale net interface: %v", ifName)
+	}
+	return moveInterfaceToAnotherNamespace(ifName, curNetNS, clientNetNS, curNetNS)
+}
+
+func moveInterfaceToAnotherNamespace(ifName string, curNetNS, fromNetNS, toNetNS netns.NsHandle) error {
+	return nshandle.RunIn(curNetNS, fromNetNS, func() error {
+		link, err := netlink.LinkByName(ifName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+		}
+
+		if err := netlink.LinkSetNsFd(link, int(toNetNS)); err != nil {
+			return errors.Wrapf(err, "failed to move net interface to net NS: %v %v", ifName, toNetNS)
+		}
+
+		return nil
+	})
+}
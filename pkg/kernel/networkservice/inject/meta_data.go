@@ -0,0 +1,52 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/metamap"
+)
+
+type keyType struct{}
+
+// injectedLink is the connection net interface injected into the Client's net NS, the index is recorded at the
+// injection, because the name can be changed in the Client's net NS (e.g. by udev)
+type injectedLink struct {
+	ifName  string
+	ifIndex int
+}
+
+// storeInjected marks the connection net interface as injected, so the following Requests are refreshes. Without
+// metadata chain element in the chain nothing is stored: every Request injects the net interface, refresh finds it in
+// the Client's net NS and handles it with the PreemptionPolicy, renames in the Client's net NS are not tracked.
+func storeInjected(ctx context.Context, isClient bool, link *injectedLink) {
+	if m, ok := metamap.Load(ctx, isClient); ok {
+		m.Store(keyType{}, link)
+	}
+}
+
+func loadInjected(ctx context.Context, isClient bool) (*injectedLink, bool) {
+	m, ok := metamap.Load(ctx, isClient)
+	if !ok {
+		return nil, false
+	}
+	if raw, ok := m.Load(keyType{}); ok {
+		return raw.(*injectedLink), true
+	}
+	return nil, false
+}
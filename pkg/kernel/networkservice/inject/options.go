@@ -0,0 +1,101 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject
+
+import (
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ifindex"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nodelock"
+)
+
+// PreemptionPolicy is a policy for the case when the net interface with the requested name already exists in the
+// Client's pod network namespace on the first Request of the connection
+type PreemptionPolicy int
+
+const (
+	// PreemptionPolicyAdopt adopts the existing net interface as the connection one if it is created for the
+	// connection (e.g. before the Forwarder restart), it fails the Request otherwise. Net interfaces created for the
+	// connections are marked in the net interface alias.
+	PreemptionPolicyAdopt PreemptionPolicy = iota
+	// PreemptionPolicyFail fails the Request
+	PreemptionPolicyFail
+	// PreemptionPolicyReplace deletes the existing net interface (LinkDel, so the veth peer is deleted with it) and
+	// injects the new one. It is not an in-place replace: nothing is carried over from the existing net interface
+	// (IP addresses, routes, MAC, ...) and there is a window with no net interface of the requested name. If the
+	// existing net interface cannot be deleted (e.g. it is a physical device), it is moved into the Forwarder's network
+	// namespace.
+	PreemptionPolicyReplace
+	// PreemptionPolicyRename keeps the existing net interface and injects the new one with the connection unique name:
+	// the requested name (truncated to 7 characters) with the hash suffix. The applied name is reported back in the
+	// connection mechanism parameters.
+	PreemptionPolicyRename
+)
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(i *injector)
+
+// WithLinkProvider sets LinkProvider used to get the network interface to inject into the Client's pod network
+// namespace. Default is linkprovider.NewExisting().
+func WithLinkProvider(linkProvider linkprovider.LinkProvider) Option {
+	return func(i *injector) {
+		i.linkProvider = linkProvider
+	}
+}
+
+// WithMacvlan sets LinkProvider creating macvlan sub-interface in the given mode on the given parent net interface
+// for each connection, so multiple Clients can share one uplink instead of moving it into the single Client's pod
+// network namespace. Same as WithLinkProvider(linkprovider.NewMacvlan(parentName, mode)).
+func WithMacvlan(parentName string, mode netlink.MacvlanMode) Option {
+	return WithLinkProvider(linkprovider.NewMacvlan(parentName, mode))
+}
+
+// WithCustomLink sets LinkProvider creating net interface of any kind from the template for each connection, it is
+// the escape hatch for the net interface kinds not modeled by the LinkProviders. Same as
+// WithLinkProvider(linkprovider.NewCustom(prefix, template)).
+func WithCustomLink(prefix string, template netlink.Link) Option {
+	return WithLinkProvider(linkprovider.NewCustom(prefix, template))
+}
+
+// WithPreemptionPolicy sets PreemptionPolicy. Default is PreemptionPolicyAdopt, it also keeps connections working
+// across the Forwarder restarts. Other policies require metadata chain element in the chain: without it the injected
+// net interface is not tracked and refresh is handled as the first Request.
+func WithPreemptionPolicy(preemptionPolicy PreemptionPolicy) Option {
+	return func(i *injector) {
+		i.preemptionPolicy = preemptionPolicy
+	}
+}
+
+// WithNodeLock enables node level conflict detection with the given Locker: the requested net interface name is locked
+// in the Client's net NS until Close, so the Forwarder instances on the node don't inject the net interfaces with the
+// same name into the same net NS. Request fails if the name is locked by another connection, PreemptionPolicy applies
+// only to the net interfaces with the not locked names.
+func WithNodeLock(locker *nodelock.Locker) Option {
+	return func(i *injector) {
+		i.locker = locker
+	}
+}
+
+// WithIfIndexCache sets the net interface index cache used to look up the net interfaces in the Client's net NS on
+// each Request, by default they are looked up by name. The cache can be shared with the other chain elements and
+// warmed up after the Forwarder restart (see warmup).
+func WithIfIndexCache(cache *ifindex.Cache) Option {
+	return func(i *injector) {
+		i.ifIndexCache = cache
+	}
+}
@@ -22,94 +22,79 @@ import (
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/pkg/errors"
-	"github.com/vishvananda/netlink"
-	"github.com/vishvananda/netns"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
 	"github.com/networkservicemesh/sdk/pkg/tools/log"
-
-	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
 )
 
-type injectServer struct{}
+type injectServer struct {
+	*injector
+}
 
-// NewServer - returns a new networkservice.NetworkServiceServer that moves given network interface into the Client's
-// pod network namespace on Request and back to Forwarder's network namespace on Close
-func NewServer() networkservice.NetworkServiceServer {
-	return &injectServer{}
+// NewServer - returns a new networkservice.NetworkServiceServer that moves network interface provided by the
+// LinkProvider into the Client's pod network namespace on Request and back to Forwarder's network namespace on Close
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &injectServer{
+		injector: newInjector(options),
+	}
 }
 
 func (s *injectServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
-	logEntry := log.Entry(ctx).WithField("injectServer", "Request")
+	logCtx := log.WithField(ctx, "injectServer", "Request")
+	logEntry := log.Entry(logCtx)
+	isClient := metadata.IsClient(s)
 
-	connID := request.GetConnection().GetId()
 	mech := kernel.ToMechanism(request.GetConnection().GetMechanism())
 	if mech == nil {
 		return next.Server(ctx).Request(ctx, request)
 	}
 
-	curNetNS, err := nshandle.Current()
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = curNetNS.Close() }()
-
-	var clientNetNS netns.NsHandle
-	clientNetNS, err = nshandle.FromURL(mech.GetNetNSURL())
-	if err != nil {
-		return nil, err
+	if injected, ok := loadInjected(ctx, isClient); ok {
+		// the Client can refresh with the requested name instead of the applied one
+		ifName := s.resolve(logCtx, request.GetConnection(), injected)
+		mech.SetInterfaceName(ifName)
+		logEntry.Infof("network interface %s is already in the Client's namespace for connection %s",
+			ifName, request.GetConnection().GetId())
+		return next.Server(ctx).Request(ctx, request)
 	}
-	defer func() { _ = clientNetNS.Close() }()
 
-	ifName := mech.GetInterfaceName(request.GetConnection())
-	err = moveInterfaceToAnotherNamespace(ifName, curNetNS, curNetNS, clientNetNS)
+	injected, err := s.create(logCtx, request.GetConnection())
 	if err != nil {
 		return nil, err
 	}
-	logEntry.Infof("moved network interface %s into the Client's namespace for connection %s", ifName, connID)
 
 	conn, err := next.Server(ctx).Request(ctx, request)
 	if err != nil {
-		if errMovingBack := moveInterfaceToAnotherNamespace(ifName, curNetNS, clientNetNS, curNetNS); errMovingBack != nil {
-			logEntry.Warnf("failed to move network interface %s into the Forwarder's namespace for connection %s", ifName, connID)
-		} else {
-			logEntry.Infof("moved network interface %s into the Forwarder's namespace for connection %s", ifName, connID)
+		if removeErr := s.remove(logCtx, request.GetConnection()); removeErr != nil {
+			logEntry.Warnf("failed to move network interface into the Forwarder's namespace for connection %s: %s",
+				request.GetConnection().GetId(), removeErr.Error())
 		}
+		return nil, err
 	}
-	return conn, err
+
+	storeInjected(ctx, isClient, injected)
+
+	return conn, nil
 }
 
 func (s *injectServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
-	logEntry := log.Entry(ctx).WithField("injectServer", "Close")
+	logCtx := log.WithField(ctx, "injectServer", "Close")
+
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if injected, ok := loadInjected(ctx, metadata.IsClient(s)); ok && mech != nil {
+		mech.SetInterfaceName(s.resolve(logCtx, conn, injected))
+	}
 
 	_, err := next.Server(ctx).Close(ctx, conn)
 
 	var injectErr error
-	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
-		var curNetNS, clientNetNS netns.NsHandle
-		var ifName string
-
-		if curNetNS, injectErr = nshandle.Current(); injectErr != nil {
-			goto exit
-		}
-		defer func() { _ = curNetNS.Close() }()
-
-		if clientNetNS, injectErr = nshandle.FromURL(mech.GetNetNSURL()); injectErr != nil {
-			goto exit
-		}
-		defer func() { _ = clientNetNS.Close() }()
-
-		ifName = mech.GetInterfaceName(conn)
-		if injectErr = moveInterfaceToAnotherNamespace(ifName, curNetNS, clientNetNS, curNetNS); injectErr != nil {
-			goto exit
-		}
-
-		logEntry.Infof("moved network interface %s into the Forwarder's namespace for connection %s", ifName, conn.GetId())
+	if mech != nil {
+		injectErr = s.remove(logCtx, conn)
 	}
 
-exit:
 	if err != nil && injectErr != nil {
 		return nil, errors.Wrap(err, injectErr.Error())
 	}
@@ -118,18 +103,3 @@ exit:
 	}
 	return &empty.Empty{}, err
 }
-
-func moveInterfaceToAnotherNamespace(ifName string, curNetNS, fromNetNS, toNetNS netns.NsHandle) error {
-	return nshandle.RunIn(curNetNS, fromNetNS, func() error {
-		link, err := netlink.LinkByName(ifName)
-		if err != nil {
-			return errors.Wrapf(err, "failed to get net interface: %v", ifName)
-		}
-
-		if err := netlink.LinkSetNsFd(link, int(toNetNS)); err != nil {
-			return errors.Wrapf(err, "failed to move net interface to net NS: %v %v", ifName, toNetNS)
-		}
-
-		return nil
-	})
-}
@@ -0,0 +1,834 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package inject_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"runtime"
+	"strconv"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/inject"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ifindex"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nodelock"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+const (
+	netNSPath = "/run/netns"
+	ifName    = "nsm-1"
+)
+
+func newClientNetNS(t *testing.T, curNetNS netns.NsHandle) (clientNetNS netns.NsHandle, conn *networkservice.Connection, cleanup func()) {
+	netNSName := uuid.New().String()
+	clientNetNS, err := netns.NewNamed(netNSName)
+	require.NoError(t, err)
+	require.NoError(t, netns.Set(curNetNS))
+
+	conn = &networkservice.Connection{
+		Id: uuid.New().String(),
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.NetNSURL:         (&url.URL{Scheme: "file", Path: path.Join(netNSPath, netNSName)}).String(),
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+	}
+
+	return clientNetNS, conn, func() {
+		_ = clientNetNS.Close()
+		_ = netns.DeleteNamed(netNSName)
+	}
+}
+
+func TestInjectServer_Veth(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	netNSName := uuid.New().String()
+	clientNetNS, err := netns.NewNamed(netNSName)
+	require.NoError(t, err)
+	require.NoError(t, netns.Set(curNetNS))
+	defer func() {
+		_ = clientNetNS.Close()
+		_ = netns.DeleteNamed(netNSName)
+	}()
+
+	conn := &networkservice.Connection{
+		Id: uuid.New().String(),
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.NetNSURL:         (&url.URL{Scheme: "file", Path: path.Join(netNSPath, netNSName)}).String(),
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+	}
+
+	server := inject.NewServer(inject.WithLinkProvider(linkprovider.NewVeth()))
+
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	_, err = netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.NoError(t, err)
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		_, linkErr := netlink.LinkByName(ifName)
+		return linkErr
+	}))
+
+	// refresh
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	_, err = netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.Error(t, err)
+}
+
+func TestInjectServer_VethMetadata(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithLinkProvider(linkprovider.NewVeth())),
+	)
+
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	_, err = netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.NoError(t, err)
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		_, linkErr := netlink.LinkByName(ifName)
+		return linkErr
+	}))
+
+	// refresh
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	_, err = netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.Error(t, err)
+}
+
+func TestInjectServer_PreemptionPolicy(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	// stale net interface from the previous connection
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		return netlink.LinkAdd(&netlink.Veth{
+			LinkAttrs: netlink.LinkAttrs{Name: ifName},
+			PeerName:  "stale-peer",
+		})
+	}))
+
+	_, err = chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(
+			inject.WithLinkProvider(linkprovider.NewVeth()),
+			inject.WithPreemptionPolicy(inject.PreemptionPolicyFail),
+		),
+	).Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn.Clone()})
+	require.Error(t, err)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(
+			inject.WithLinkProvider(linkprovider.NewVeth()),
+			inject.WithPreemptionPolicy(inject.PreemptionPolicyReplace),
+		),
+	)
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	require.Error(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		_, linkErr := netlink.LinkByName("stale-peer")
+		return linkErr
+	}))
+	_, err = netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.NoError(t, err)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
+
+func TestInjectServer_LinkType(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithLinkProvider(linkprovider.NewSelector(
+			linkprovider.NewVeth(),
+			map[string]linkprovider.LinkProvider{
+				linkprovider.LinkTypeVeth: linkprovider.NewVeth(),
+				linkprovider.LinkTypeTap:  linkprovider.NewTap(),
+			},
+		))),
+	)
+
+	badConn := conn.Clone()
+	badConn.GetMechanism().GetParameters()[linkprovider.LinkTypeKey] = "unknown"
+	_, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: badConn})
+	require.Error(t, err)
+
+	conn.GetMechanism().GetParameters()[linkprovider.LinkTypeKey] = linkprovider.LinkTypeTap
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		link, linkErr := netlink.LinkByName(ifName)
+		if linkErr != nil {
+			return linkErr
+		}
+		require.Equal(t, "tuntap", link.Type())
+		return nil
+	}))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	require.Error(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		_, linkErr := netlink.LinkByName(ifName)
+		return linkErr
+	}))
+}
+
+func TestInjectServer_VLAN(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	const baseName = "vlan-base"
+	kerneltest.AddVeth(t, baseName, baseName + "-p")
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithLinkProvider(linkprovider.NewSelector(
+			linkprovider.NewVeth(),
+			map[string]linkprovider.LinkProvider{
+				linkprovider.LinkTypeVLAN: linkprovider.NewVLAN(baseName),
+			},
+		))),
+	)
+
+	conn.GetMechanism().GetParameters()[linkprovider.LinkTypeKey] = linkprovider.LinkTypeVLAN
+
+	for _, vlanID := range []string{"0", "4095", "vlan"} {
+		badConn := conn.Clone()
+		badConn.GetMechanism().GetParameters()[linkprovider.VLANIDKey] = vlanID
+		_, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: badConn})
+		require.Error(t, err)
+	}
+
+	conn.GetMechanism().GetParameters()[linkprovider.VLANIDKey] = "100"
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	if errors.Cause(err) == syscall.EOPNOTSUPP {
+		t.Skip("802.1Q VLAN net interfaces are not supported by the kernel")
+	}
+	require.NoError(t, err)
+
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		link, linkErr := netlink.LinkByName(ifName)
+		if linkErr != nil {
+			return linkErr
+		}
+		vlan, ok := link.(*netlink.Vlan)
+		require.True(t, ok)
+		require.Equal(t, 100, vlan.VlanId)
+		return nil
+	}))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
+
+func TestInjectServer_PreemptionPolicyRename(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	// net interface with the same name created by someone else
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		return netlink.LinkAdd(&netlink.Veth{
+			LinkAttrs: netlink.LinkAttrs{Name: ifName},
+			PeerName:  "foreign-peer",
+		})
+	}))
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(
+			inject.WithLinkProvider(linkprovider.NewVeth()),
+			inject.WithPreemptionPolicy(inject.PreemptionPolicyRename),
+		),
+	)
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	appliedName := conn.GetMechanism().GetParameters()[kernel.InterfaceNameKey]
+	require.NotEqual(t, ifName, appliedName)
+	require.LessOrEqual(t, len(appliedName), 15)
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		for _, name := range []string{ifName, appliedName} {
+			if _, linkErr := netlink.LinkByName(name); linkErr != nil {
+				return linkErr
+			}
+		}
+		return nil
+	}))
+
+	// refresh with the requested name keeps the applied one
+	refreshConn := conn.Clone()
+	refreshConn.GetMechanism().GetParameters()[kernel.InterfaceNameKey] = ifName
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: refreshConn})
+	require.NoError(t, err)
+	require.Equal(t, appliedName, conn.GetMechanism().GetParameters()[kernel.InterfaceNameKey])
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		if _, linkErr := netlink.LinkByName(appliedName); linkErr == nil {
+			return errors.New("net interface is not removed")
+		}
+		_, linkErr := netlink.LinkByName(ifName)
+		return linkErr
+	}))
+}
+
+func TestInjectServer_Renamed(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithLinkProvider(linkprovider.NewVeth())),
+	)
+
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	// udev renames the net interface in the Client's net NS
+	const newIfName = "eth-renamed"
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		link, linkErr := netlink.LinkByName(ifName)
+		if linkErr != nil {
+			return linkErr
+		}
+		return netlink.LinkSetName(link, newIfName)
+	}))
+
+	// refresh reports the current name
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Equal(t, newIfName, conn.GetMechanism().GetParameters()[kernel.InterfaceNameKey])
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	_, err = netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.Error(t, err)
+	require.Error(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		_, linkErr := netlink.LinkByName(newIfName)
+		return linkErr
+	}))
+}
+
+func TestInjectServer_Macvlan(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	const parentName = "mv-parent"
+	kerneltest.AddVeth(t, parentName, parentName + "-p")
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithMacvlan(parentName, netlink.MACVLAN_MODE_BRIDGE)),
+	)
+
+	// multiple Clients share the same parent net interface
+	var conns []*networkservice.Connection
+	for i := 0; i < 2; i++ {
+		clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+		defer cleanup()
+
+		conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+		if errors.Cause(err) == syscall.EOPNOTSUPP {
+			t.Skip("macvlan net interfaces are not supported by the kernel")
+		}
+		require.NoError(t, err)
+		conns = append(conns, conn)
+
+		require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+			link, linkErr := netlink.LinkByName(ifName)
+			if linkErr != nil {
+				return linkErr
+			}
+			macvlan, ok := link.(*netlink.Macvlan)
+			require.True(t, ok)
+			require.Equal(t, netlink.MACVLAN_MODE_BRIDGE, macvlan.Mode)
+			return nil
+		}))
+	}
+
+	_, err = netlink.LinkByName(parentName)
+	require.NoError(t, err)
+
+	for _, conn := range conns {
+		_, err = server.Close(context.TODO(), conn)
+		require.NoError(t, err)
+	}
+
+	links, err := netlink.LinkList()
+	require.NoError(t, err)
+	for _, link := range links {
+		require.NotEqual(t, "macvlan", link.Type())
+	}
+}
+
+func TestInjectServer_RequestFailed(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	// the next chain element fails, so the injected net interface should be rolled back
+	_, err = chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithLinkProvider(linkprovider.NewVeth())),
+		injecterror.NewServer(),
+	).Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.Error(t, err)
+
+	_, err = netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.Error(t, err)
+	require.Error(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		_, linkErr := netlink.LinkByName(ifName)
+		return linkErr
+	}))
+}
+
+func TestInjectServer_NodeLock(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	dir, err := ioutil.TempDir("", "inject")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// servers in the different Forwarder instances
+	newServer := func() networkservice.NetworkServiceServer {
+		locker, lockerErr := nodelock.NewLocker(dir)
+		require.NoError(t, lockerErr)
+		return chain.NewNetworkServiceServer(
+			metadata.NewServer(),
+			inject.NewServer(
+				inject.WithLinkProvider(linkprovider.NewVeth()),
+				inject.WithPreemptionPolicy(inject.PreemptionPolicyReplace),
+				inject.WithNodeLock(locker),
+			),
+		)
+	}
+	server1, server2 := newServer(), newServer()
+
+	conn, err = server1.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	// the net interface of another Forwarder instance is not replaced
+	otherConn := conn.Clone()
+	otherConn.Id = uuid.New().String()
+	_, err = server2.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: otherConn.Clone()})
+	require.True(t, nodelock.IsLocked(err))
+	_, err = netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.NoError(t, err)
+
+	// refresh keeps the lock
+	conn, err = server1.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	_, err = server1.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	otherConn, err = server2.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: otherConn})
+	require.NoError(t, err)
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		_, linkErr := netlink.LinkByName(ifName)
+		return linkErr
+	}))
+
+	_, err = server2.Close(context.TODO(), otherConn)
+	require.NoError(t, err)
+}
+
+func TestInjectServer_RefreshIdempotent(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithLinkProvider(linkprovider.NewVeth())),
+	)
+
+	clientLinks := func() (result []string) {
+		require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+			links, linkErr := netlink.LinkList()
+			for _, link := range links {
+				result = append(result, link.Attrs().Name+"@"+strconv.Itoa(link.Attrs().Index))
+			}
+			return linkErr
+		}))
+		return result
+	}
+
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	links := clientLinks()
+	require.Len(t, links, 2)
+
+	for i := 0; i < 3; i++ {
+		conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+		require.NoError(t, err)
+		require.Equal(t, links, clientLinks())
+	}
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Len(t, clientLinks(), 1)
+}
+
+func TestInjectServer_IfIndexCache(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(
+			inject.WithLinkProvider(linkprovider.NewVeth()),
+			inject.WithIfIndexCache(ifindex.NewCache(ctx)),
+		),
+	)
+
+	clientLink := func() (link netlink.Link) {
+		require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() (linkErr error) {
+			link, linkErr = netlink.LinkByName(ifName)
+			return linkErr
+		}))
+		return link
+	}
+
+	conn, err = server.Request(ctx, &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	index := clientLink().Attrs().Index
+
+	for i := 0; i < 3; i++ {
+		conn, err = server.Request(ctx, &networkservice.NetworkServiceRequest{Connection: conn})
+		require.NoError(t, err)
+		require.Equal(t, index, clientLink().Attrs().Index)
+	}
+
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+
+	// the cached index of the ejected net interface is not used by the next connection
+	conn, err = server.Request(ctx, &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.NotEqual(t, index, clientLink().Attrs().Index)
+
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+func TestInjectServer_NetNSGone(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	_, conn, cleanup := newClientNetNS(t, curNetNS)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithLinkProvider(linkprovider.NewVeth())),
+	)
+
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	// the Client's pod is deleted before the connection Close, veth is deleted with the net NS
+	cleanup()
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		_, linkErr := netlink.LinkByName(linkprovider.VethPeerName(conn))
+		return linkErr != nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestInjectServer_CustomLink(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	const customPeerName = "custom-peer"
+	template := &netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{MTU: 1400},
+		PeerName:  customPeerName,
+	}
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithCustomLink("nsmcl", template)),
+	)
+
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Empty(t, template.Name)
+
+	_, err = netlink.LinkByName(customPeerName)
+	require.NoError(t, err)
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		link, linkErr := netlink.LinkByName(ifName)
+		if linkErr != nil {
+			return linkErr
+		}
+		require.Equal(t, "veth", link.Type())
+		require.Equal(t, 1400, link.Attrs().MTU)
+		return nil
+	}))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	_, err = netlink.LinkByName(customPeerName)
+	require.Error(t, err)
+}
+
+func TestInjectServer_ForeignLink(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	// net interface with the same name created by someone else is not adopted
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		return netlink.LinkAdd(&netlink.Veth{
+			LinkAttrs: netlink.LinkAttrs{Name: ifName},
+			PeerName:  "foreign-peer",
+		})
+	}))
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithLinkProvider(linkprovider.NewVeth())),
+	)
+	_, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.Error(t, err)
+}
+
+func TestInjectServer_ForeignAlias(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	// the existing net interface alias is set by the operator
+	link := kerneltest.AddVeth(t, ifName, "alias-peer")
+	require.NoError(t, netlink.LinkSetAlias(link, "uplink"))
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(),
+	)
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		injected, linkErr := netlink.LinkByName(ifName)
+		if linkErr != nil {
+			return linkErr
+		}
+		require.Equal(t, "uplink", injected.Attrs().Alias)
+		return nil
+	}))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	link, err = netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	require.Equal(t, "uplink", link.Attrs().Alias)
+}
+
+func TestInjectServer_InjectFailure(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	// the file is not a net NS, so the created net interface fails to move into it
+	notNetNS, err := ioutil.TempFile("", "netns")
+	require.NoError(t, err)
+	_ = notNetNS.Close()
+	defer func() { _ = os.Remove(notNetNS.Name()) }()
+
+	conn := &networkservice.Connection{
+		Id: uuid.New().String(),
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.NetNSURL:         (&url.URL{Scheme: "file", Path: notNetNS.Name()}).String(),
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+	}
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithLinkProvider(linkprovider.NewVeth())),
+	)
+	_, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.Error(t, err)
+
+	// the created veth pair is deleted
+	_, err = netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.Error(t, err)
+}
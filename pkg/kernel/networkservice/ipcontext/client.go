@@ -0,0 +1,89 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipcontext
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext/neighbors"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext/routes"
+)
+
+type ipContextClient struct {
+	*ipContext
+}
+
+// NewClient returns a new ip context client chain element applying Dst IP context to the Endpoint's net interface.
+// It can be used together with the server one to program both kernel interfaces of the same connection. Dst routes,
+// routes to the extra prefixes and IP context neighbors are applied with the routes and neighbors client chain
+// elements preceding it, unless WithoutRoutes and WithoutNeighbors are set.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	c := newIPContext(options)
+	var client networkservice.NetworkServiceClient = &ipContextClient{
+		ipContext: c,
+	}
+	if c.withoutRoutes && c.withoutNeighbors {
+		return client
+	}
+
+	// client chain elements apply after the Request, so routes are applied after the IP addresses
+	var elements []networkservice.NetworkServiceClient
+	if !c.withoutNeighbors {
+		elements = append(elements, neighbors.NewClient())
+	}
+	if !c.withoutRoutes {
+		elements = append(elements, routes.NewClient())
+	}
+	return chain.NewNetworkServiceClient(append(elements, client)...)
+}
+
+func (c *ipContextClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.create(ctx, conn, metadata.IsClient(c)); err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c *ipContextClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	removeErr := remove(ctx, conn, metadata.IsClient(c))
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,344 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipcontext
+
+import (
+	"context"
+	"net"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/announce"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/desiredstate"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ifindex"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ipaddrs"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/owned"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+const dadPollInterval = 50 * time.Millisecond
+
+// ipContext is the common part of the ipcontext client and server
+type ipContext struct {
+	dad              bool
+	dadTimeout       time.Duration
+	withoutRoutes    bool
+	withoutNeighbors bool
+	ifIndexCache     *ifindex.Cache
+}
+
+func newIPContext(options []Option) *ipContext {
+	c := &ipContext{}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// create applies IP context to the connection kernel interface in its net NS, kernel is programmed with the netlink
+// handle in the target net NS, so the calling goroutine net NS is not switched. Server side interface gets Src IP
+// addresses, Client side interface gets Dst ones, both IPv4 and IPv6 addresses can be listed for the dual-stack
+// connection (see ipaddrs). The net interface is set up even if there is no IP address for the side. IP addresses
+// are announced to the neighbors on each Request, so they learn the new location right after the heal. Routes and
+// neighbors are applied by the routes and neighbors chain elements. If there is the reconciler in the chain, the IP
+// addresses are only described in its state (see describe).
+func (c *ipContext) create(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	mech := kernelmech.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil
+	}
+	if state := desiredstate.State(ctx); state != nil {
+		return c.describe(state, conn, isClient)
+	}
+
+	netNS, err := netNSHandle(mech.GetNetNSURL())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = netNS.Close() }()
+
+	handle, err := netlink.NewHandleAt(netNS)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create netlink handle in net NS: %v", mech.GetNetNSURL())
+	}
+	defer handle.Delete()
+
+	ifName := mech.GetInterfaceName(conn)
+	link, err := c.ifIndexCache.Link(netNS, handle, ifName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+
+	if link.Attrs().OperState != netlink.OperUp {
+		if err = handle.LinkSetUp(link); err != nil {
+			return errors.Wrapf(err, "failed to set up net interface: %v", ifName)
+		}
+	}
+
+	ipContext := conn.GetContext().GetIpContext()
+	ipAddrString := ipContext.GetSrcIpAddr()
+	if isClient {
+		ipAddrString = ipContext.GetDstIpAddr()
+	}
+	if ipAddrString == "" {
+		return nil
+	}
+
+	ipAddrs, err := toAddrs(ipAddrString, c.dad)
+	if err != nil {
+		return err
+	}
+	ipv6, _ := loadIPv6(ctx, isClient)
+	ipv6, err = enableIPv6(ipv6, ipAddrs, mech.GetNetNSURL(), ifName)
+	if ipv6 != nil {
+		storeIPv6(ctx, isClient, ipv6)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := setIPAddrs(ctx, handle, ipAddrs, link); err != nil {
+		return err
+	}
+	if c.dad {
+		// tentative IPv6 addresses can't be announced
+		if err := waitDAD(ctx, handle, ipAddrs, link, c.dadTimeout); err != nil {
+			return err
+		}
+	}
+
+	announceAddrs(ctx, ipAddrs, netNS, link)
+
+	return nil
+}
+
+// describe describes the net interface set up with the IP addresses in the reconciler state, IPv6 is enabled on the net
+// interface for the IPv6 addresses. The reconciler doesn't wait for DAD and doesn't announce the IP addresses.
+func (c *ipContext) describe(state *desiredstate.KernelDesiredState, conn *networkservice.Connection, isClient bool) error {
+	ifName := kernelmech.ToMechanism(conn.GetMechanism()).GetInterfaceName(conn)
+	link := state.Link(ifName)
+	link.Up = true
+
+	ipContext := conn.GetContext().GetIpContext()
+	ipAddrString := ipContext.GetSrcIpAddr()
+	if isClient {
+		ipAddrString = ipContext.GetDstIpAddr()
+	}
+	ipAddrs, err := toAddrs(ipAddrString, c.dad)
+	if err != nil {
+		return err
+	}
+	for _, ipAddr := range ipAddrs {
+		if !ipaddrs.IsIPv4(ipAddr.IP) {
+			state.SetSysctl("net/ipv6/conf/"+ifName+"/disable_ipv6", "0")
+		}
+	}
+	link.Addrs = append(link.Addrs, ipAddrs...)
+	return nil
+}
+
+// remove deletes IP addresses added by create from the connection kernel interface in its net NS and restores
+// disable_ipv6 changed by create. The IP addresses described in the reconciler state are deleted by the reconciler.
+func remove(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	if desiredstate.State(ctx) != nil {
+		return nil
+	}
+	err := removeAddrs(conn)
+
+	if ipv6, ok := loadAndDeleteIPv6(ctx, isClient); ok {
+		if restoreErr := ipv6.restore(); restoreErr != nil {
+			if err != nil {
+				return errors.Wrap(err, restoreErr.Error())
+			}
+			return restoreErr
+		}
+	}
+	return err
+}
+
+func removeAddrs(conn *networkservice.Connection) error {
+	mech := kernelmech.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil
+	}
+
+	netNS, err := netNSHandle(mech.GetNetNSURL())
+	if os.IsNotExist(errors.Cause(err)) {
+		// IP addresses are deleted with the net NS
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = netNS.Close() }()
+
+	handle, err := netlink.NewHandleAt(netNS)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create netlink handle in net NS: %v", mech.GetNetNSURL())
+	}
+	defer handle.Delete()
+
+	link, err := handle.LinkByName(mech.GetInterfaceName(conn))
+	if err != nil {
+		// there is nothing to clean up if the net interface has been already deleted
+		return nil
+	}
+
+	return owned.DeleteAddrsAt(handle, link)
+}
+
+// netNSHandle returns the net NS handle by the mechanism net NS URL, empty URL means the current net NS
+func netNSHandle(netNSURL string) (netns.NsHandle, error) {
+	if netNSURL == "" {
+		return nshandle.Current()
+	}
+	return nshandle.FromURL(netNSURL)
+}
+
+// toAddrs parses the IP context IP address field, by default IPv6 addresses are added with no DAD: IP context
+// addresses are unique by the IPAM, and the tentative address cannot be used until DAD completes
+func toAddrs(ipAddrString string, dad bool) ([]*netlink.Addr, error) {
+	ipNets, err := ipaddrs.Parse(ipAddrString)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]*netlink.Addr, 0, len(ipNets))
+	for _, ipNet := range ipNets {
+		addr := &netlink.Addr{IPNet: ipNet}
+		if !dad && !ipaddrs.IsIPv4(ipNet.IP) {
+			addr.Flags = kernel.AddrFlagNoDAD
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+// ipv6State is the snapshot of the disable_ipv6 original value in the net NS IPv6 has been enabled in
+type ipv6State struct {
+	netNSURL string
+	snapshot *sysctl.Snapshot
+}
+
+// enableIPv6 enables IPv6 on the net interface if there is some IPv6 address to add, IPv6 can be disabled by default
+// for the new net interfaces in the net NS. Sysctls are accessible only from inside the net NS, so it is the only
+// step switching the net NS, and only for the connections with IPv6 addresses. The original value is kept in the
+// given state, a new one is returned if there is no state yet and IPv6 has been enabled.
+func enableIPv6(state *ipv6State, ipAddrs []*netlink.Addr, netNSURL, ifName string) (*ipv6State, error) {
+	for _, ipAddr := range ipAddrs {
+		if ipaddrs.IsIPv4(ipAddr.IP) {
+			continue
+		}
+		err := runIn(netNSURL, func() error {
+			name := "net/ipv6/conf/" + ifName + "/disable_ipv6"
+			if disabled, err := sysctl.GetInt(name); err != nil || disabled == 0 {
+				// no IPv6 sysctl means no IPv6 in the kernel, netlink reports it better on IP address add
+				return nil
+			}
+			if state == nil {
+				snapshot, err := sysctl.Take()
+				if err != nil {
+					return err
+				}
+				state = &ipv6State{netNSURL: netNSURL, snapshot: snapshot}
+			}
+			return state.snapshot.Set(name, "0")
+		})
+		return state, err
+	}
+	return state, nil
+}
+
+// restore sets disable_ipv6 back to the original value, there is nothing to restore if the net NS has been already
+// deleted
+func (s *ipv6State) restore() error {
+	err := runIn(s.netNSURL, s.snapshot.Restore)
+	if os.IsNotExist(errors.Cause(err)) {
+		return nil
+	}
+	return err
+}
+
+func runIn(netNSURL string, runner func() error) error {
+	if netNSURL == "" {
+		return runner()
+	}
+	return nshandle.RunInURL(netNSURL, runner)
+}
+
+// announceAddrs sends gratuitous ARP (unsolicited NA) for the IP addresses through the net interface in its net NS,
+// the sockets are opened in the net NS. Announcement is a best effort: the neighbors still learn the IP addresses on
+// their entries expiration, so the failure is only logged.
+func announceAddrs(ctx context.Context, ipAddrs []*netlink.Addr, netNS netns.NsHandle, link netlink.Link) {
+	ips := make([]net.IP, 0, len(ipAddrs))
+	for _, ipAddr := range ipAddrs {
+		ips = append(ips, ipAddr.IP)
+	}
+	if err := announce.AddrsAt(netNS, link, ips); err != nil {
+		log.Entry(ctx).WithField("ipcontext", "announce").Warnf("failed to announce IP addresses: %s", err.Error())
+	}
+}
+
+// waitDAD waits for the IPv6 addresses to leave the tentative state, it fails if DAD has failed for some of them or
+// hasn't completed until the timeout. Such IPv6 addresses are deleted.
+func waitDAD(ctx context.Context, handle *netlink.Handle, ipAddrs []*netlink.Addr, link netlink.Link, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		current, err := handle.AddrList(link, kernel.FamilyV6)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the net interface IP addresses: %v", link.Attrs().Name)
+		}
+
+		var tentative []*netlink.Addr
+		for i := range current {
+			if !containsAddr(ipAddrs, &current[i]) {
+				continue
+			}
+			switch {
+			case current[i].Flags&kernel.AddrFlagDADFailed != 0:
+				_ = handle.AddrDel(link, &current[i])
+				return errors.Errorf("duplicate IPv6 address detected on the net interface: %v %v",
+					link.Attrs().Name, current[i].IPNet)
+			case current[i].Flags&kernel.AddrFlagTentative != 0:
+				tentative = append(tentative, &current[i])
+			}
+		}
+		if len(tentative) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			for _, addr := range tentative {
+				_ = handle.AddrDel(link, addr)
+			}
+			return errors.Errorf("timeout waiting for IPv6 DAD on the net interface: %v %v", link.Attrs().Name, tentative[0].IPNet)
+		case <-time.After(dadPollInterval):
+		}
+	}
+}
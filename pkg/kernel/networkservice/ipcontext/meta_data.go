@@ -0,0 +1,55 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipcontext
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/metamap"
+)
+
+type ipv6KeyType struct{}
+
+// storeIPv6 stores the disable_ipv6 original value, so it is restored on Close. Without metadata chain element in the
+// chain nothing is stored and IPv6 is kept enabled on Close.
+func storeIPv6(ctx context.Context, isClient bool, state *ipv6State) {
+	if m, ok := metamap.Load(ctx, isClient); ok {
+		m.Store(ipv6KeyType{}, state)
+	}
+}
+
+func loadIPv6(ctx context.Context, isClient bool) (*ipv6State, bool) {
+	m, ok := metamap.Load(ctx, isClient)
+	if !ok {
+		return nil, false
+	}
+	if raw, ok := m.Load(ipv6KeyType{}); ok {
+		return raw.(*ipv6State), true
+	}
+	return nil, false
+}
+
+func loadAndDeleteIPv6(ctx context.Context, isClient bool) (*ipv6State, bool) {
+	m, ok := metamap.Load(ctx, isClient)
+	if !ok {
+		return nil, false
+	}
+	if raw, ok := m.LoadAndDelete(ipv6KeyType{}); ok {
+		return raw.(*ipv6State), true
+	}
+	return nil, false
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package neighbors
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type neighborsClient struct{}
+
+// NewClient returns a new neighbors client chain element adding IP context neighbors to the Endpoint's net interface
+// on Request and deleting them on Close
+func NewClient() networkservice.NetworkServiceClient {
+	return &neighborsClient{}
+}
+
+func (c *neighborsClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apply(ctx, conn, metadata.IsClient(c)); err != nil {
+		// the connection is closed, so the neighbors applied by the previous Request are deleted as well
+		_ = remove(ctx, conn, metadata.IsClient(c))
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c *neighborsClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	removeErr := remove(ctx, conn, metadata.IsClient(c))
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
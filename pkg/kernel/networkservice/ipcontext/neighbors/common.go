@@ -0,0 +1,118 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package neighbors
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/desiredstate"
+)
+
+// apply reconciles the IP context neighbors of the connection kernel interface in its net NS with the applied ones
+// stored in metadata: the missing ones are set, the ones dropped from the IP context since the previous Request are
+// deleted. On failure the previously applied neighbors are kept. If there is the reconciler in the chain, the
+// neighbors are only described in its state (see desiredstate.State).
+func apply(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	desired, err := desiredState(conn)
+	if err != nil {
+		return err
+	}
+	if state := desiredstate.State(ctx); state != nil {
+		state.Merge(desired)
+		return nil
+	}
+	applied, _ := load(ctx, isClient)
+	if applied == nil && desired == nil {
+		return nil
+	}
+
+	applied, err = desiredstate.Reconcile(ctx, applied, desired)
+	if err != nil {
+		return err
+	}
+	store(ctx, isClient, applied)
+	return nil
+}
+
+// remove deletes the applied IP context neighbors. Without metadata chain element in the chain there is no applied
+// state, so the IP context neighbors of the connection are deleted. The ones described in the reconciler state are
+// deleted by the reconciler.
+func remove(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	if desiredstate.State(ctx) != nil {
+		return nil
+	}
+	applied, ok := loadAndDelete(ctx, isClient)
+	if !ok {
+		var err error
+		if applied, err = desiredState(conn); err != nil || applied == nil {
+			return err
+		}
+	}
+	_, err := desiredstate.Reconcile(ctx, applied, nil)
+	return err
+}
+
+// desiredState returns the IP context neighbors of the connection kernel interface in its net NS, it returns nil if
+// there are no ones
+func desiredState(conn *networkservice.Connection) (*desiredstate.KernelDesiredState, error) {
+	mech := kernelmech.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil, nil
+	}
+	ipNeighbors := conn.GetContext().GetIpContext().GetIpNeighbors()
+	if len(ipNeighbors) == 0 {
+		return nil, nil
+	}
+
+	neighs, err := toNeighs(ipNeighbors)
+	if err != nil {
+		return nil, err
+	}
+
+	state := desiredstate.New(mech.GetNetNSURL())
+	state.Link(mech.GetInterfaceName(conn)).Neighbors = neighs
+	return state, nil
+}
+
+func toNeighs(ipNeighbors []*networkservice.IpNeighbor) ([]*netlink.Neigh, error) {
+	neighs := make([]*netlink.Neigh, 0, len(ipNeighbors))
+	for _, ipNeighbor := range ipNeighbors {
+		ip := net.ParseIP(ipNeighbor.GetIp())
+		if ip == nil {
+			return nil, errors.Errorf("invalid neighbor IP address: %v", ipNeighbor.GetIp())
+		}
+		macAddr, err := net.ParseMAC(ipNeighbor.GetHardwareAddress())
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid neighbor MAC address: %v", ipNeighbor.GetHardwareAddress())
+		}
+		neighs = append(neighs, &netlink.Neigh{
+			State:        kernel.NudPermanent,
+			IP:           ip,
+			HardwareAddr: macAddr,
+		})
+	}
+	return neighs, nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package neighbors
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/desiredstate"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/metamap"
+)
+
+type keyType struct{}
+
+// store stores the applied IP context neighbors, so the dropped ones are deleted on refresh. Without metadata
+// chain element in the chain nothing is stored.
+func store(ctx context.Context, isClient bool, applied *desiredstate.KernelDesiredState) {
+	if m, ok := metamap.Load(ctx, isClient); ok {
+		m.Store(keyType{}, applied)
+	}
+}
+
+func load(ctx context.Context, isClient bool) (*desiredstate.KernelDesiredState, bool) {
+	m, ok := metamap.Load(ctx, isClient)
+	if !ok {
+		return nil, false
+	}
+	if raw, ok := m.Load(keyType{}); ok {
+		return raw.(*desiredstate.KernelDesiredState), true
+	}
+	return nil, false
+}
+
+func loadAndDelete(ctx context.Context, isClient bool) (*desiredstate.KernelDesiredState, bool) {
+	m, ok := metamap.Load(ctx, isClient)
+	if !ok {
+		return nil, false
+	}
+	if raw, ok := m.LoadAndDelete(keyType{}); ok {
+		return raw.(*desiredstate.KernelDesiredState), true
+	}
+	return nil, false
+}
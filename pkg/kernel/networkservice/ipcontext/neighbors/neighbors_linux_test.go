@@ -0,0 +1,152 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package neighbors_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	kernelconst "github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext/neighbors"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName   = "neighs-1"
+	peerName = "neighs-2"
+)
+
+func newConn(netNSURL string) *networkservice.Connection {
+	return &networkservice.Connection{
+		Id: "conn-1",
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.NetNSURL:         netNSURL,
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+		Context: &networkservice.ConnectionContext{
+			IpContext: &networkservice.IPContext{
+				IpNeighbors: []*networkservice.IpNeighbor{
+					{Ip: "10.0.17.2", HardwareAddress: "0a:00:00:00:11:02"},
+					{Ip: "fd00:17::2", HardwareAddress: "0a:00:00:00:11:02"},
+				},
+			},
+		},
+	}
+}
+
+func permanentNeighbors(t *testing.T) []string {
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+
+	list, err := netlink.NeighList(link.Attrs().Index, kernelconst.FamilyAll)
+	require.NoError(t, err)
+
+	var result []string
+	for i := range list {
+		if list[i].State == kernelconst.NudPermanent {
+			result = append(result, list[i].IP.String()+" "+list[i].HardwareAddr.String())
+		}
+	}
+	return result
+}
+
+func TestNeighborsServer(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	server := neighbors.NewServer()
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: newConn("")})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"10.0.17.2 0a:00:00:00:11:02", "fd00:17::2 0a:00:00:00:11:02"}, permanentNeighbors(t))
+
+	// refresh
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Len(t, permanentNeighbors(t), 2)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, permanentNeighbors(t))
+}
+
+func TestNeighborsClient(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	client := neighbors.NewClient()
+
+	conn, err := client.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: newConn("file:///proc/self/ns/net")})
+	require.NoError(t, err)
+	require.Len(t, permanentNeighbors(t), 2)
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, permanentNeighbors(t))
+
+	invalid := newConn("file:///proc/self/ns/net")
+	invalid.GetContext().GetIpContext().GetIpNeighbors()[0].HardwareAddress = "invalid"
+	_, err = client.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: invalid})
+	require.Error(t, err)
+}
+
+func TestNeighborsServer_Refresh(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		neighbors.NewServer(),
+	)
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: newConn("")})
+	require.NoError(t, err)
+	require.Len(t, permanentNeighbors(t), 2)
+
+	// neighbors dropped on refresh are deleted
+	conn.GetContext().GetIpContext().IpNeighbors = conn.GetContext().GetIpContext().GetIpNeighbors()[:1]
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.17.2 0a:00:00:00:11:02"}, permanentNeighbors(t))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, permanentNeighbors(t))
+}
+
+func TestNeighborsServer_Rollback(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		neighbors.NewServer(),
+		injecterror.NewServer(),
+	)
+
+	_, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: newConn("")})
+	require.Error(t, err)
+	require.Empty(t, permanentNeighbors(t))
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipcontext
+
+import (
+	"time"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ifindex"
+)
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(c *ipContext)
+
+// WithDAD enables IPv6 duplicate address detection for the added IPv6 addresses, by default they are added with
+// IFA_F_NODAD. Request waits for DAD to complete, so the IPv6 addresses are usable when the chain completes, but no
+// longer than the timeout and ctx deadline. Request fails if some IPv6 address is a duplicate or DAD doesn't complete
+// in time, such IPv6 addresses are deleted, so DAD is run again on the next Request.
+func WithDAD(timeout time.Duration) Option {
+	return func(c *ipContext) {
+		c.dad = true
+		c.dadTimeout = timeout
+	}
+}
+
+// WithoutRoutes disables applying the IP context routes, by default they are applied with the routes chain element
+// with the default options. It is used to chain the routes element with the custom options separately.
+func WithoutRoutes() Option {
+	return func(c *ipContext) {
+		c.withoutRoutes = true
+	}
+}
+
+// WithoutNeighbors disables adding the IP context neighbors, by default they are added with the neighbors chain
+// element. It is used to chain the neighbors element separately.
+func WithoutNeighbors() Option {
+	return func(c *ipContext) {
+		c.withoutNeighbors = true
+	}
+}
+
+// WithIfIndexCache sets the net interface index cache used to look up the connection kernel interface on each
+// Request, by default it is looked up by name. The cache can be shared with the other chain elements and warmed up
+// after the Forwarder restart (see warmup).
+func WithIfIndexCache(cache *ifindex.Cache) Option {
+	return func(c *ipContext) {
+		c.ifIndexCache = cache
+	}
+}
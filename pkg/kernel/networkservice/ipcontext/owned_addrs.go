@@ -0,0 +1,92 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipcontext
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/optime"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/owned"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/strict"
+)
+
+// setIPAddrs makes the net interface have the given IP addresses. Owned IP addresses not in the list are deleted,
+// already existing not owned ones are kept not owned.
+func setIPAddrs(ctx context.Context, handle *netlink.Handle, ipAddrs []*netlink.Addr, link netlink.Link) error {
+	current, err := listAddrs(handle, link)
+	if err != nil {
+		return err
+	}
+	ownedAddrs, canOwn := owned.Addrs(link)
+
+	var newOwned []*netlink.Addr
+	for _, ipAddr := range ownedAddrs {
+		if !containsAddr(ipAddrs, ipAddr) && containsAddr(current, ipAddr) {
+			if err := optime.Time(ctx, "AddrDel", ipAddr, func() error { return handle.AddrDel(link, ipAddr) }); err != nil {
+				return errors.Wrapf(err, "failed to delete IP address from the net interface: %v %v", link.Attrs().Name, ipAddr)
+			}
+		}
+	}
+	for _, ipAddr := range ipAddrs {
+		if containsAddr(current, ipAddr) {
+			if containsAddr(ownedAddrs, ipAddr) {
+				newOwned = append(newOwned, ipAddr)
+			}
+			continue
+		}
+		if err := optime.Time(ctx, "AddrAdd", ipAddr, func() error { return handle.AddrAdd(link, ipAddr) }); err != nil {
+			return errors.Wrapf(err, "failed to add IP address to the net interface: %v %v", link.Attrs().Name, ipAddr)
+		}
+		if err := strict.Verify(ctx, "AddrAdd", ipAddr, func() (bool, error) {
+			list, err := listAddrs(handle, link)
+			return containsAddr(list, ipAddr), err
+		}); err != nil {
+			return err
+		}
+		newOwned = append(newOwned, ipAddr)
+	}
+
+	if !canOwn {
+		return nil
+	}
+	return owned.SetAddrsAt(handle, link, newOwned)
+}
+
+func listAddrs(handle *netlink.Handle, link netlink.Link) ([]*netlink.Addr, error) {
+	addrs, err := handle.AddrList(link, kernel.FamilyAll)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the net interface IP addresses: %v", link.Attrs().Name)
+	}
+	addrPtrs := make([]*netlink.Addr, 0, len(addrs))
+	for i := range addrs {
+		addrPtrs = append(addrPtrs, &addrs[i])
+	}
+	return addrPtrs, nil
+}
+
+func containsAddr(addrs []*netlink.Addr, addr *netlink.Addr) bool {
+	for _, a := range addrs {
+		if addr.Equal(*a) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type routesClient struct {
+	*applier
+}
+
+// NewClient returns a new routes client chain element applying Dst routes and routes to the extra prefixes via Src IP
+// address to the Endpoint's net interface. If Src IP address field lists several addresses of the extra prefix family
+// (redundant Endpoints), the extra prefix route is the multipath one via all of them. It should precede the ipcontext
+// client in the chain, so the routes are applied after the IP addresses.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &routesClient{
+		applier: newApplier(options),
+	}
+}
+
+func (c *routesClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.apply(ctx, conn, metadata.IsClient(c)); err != nil {
+		// the connection is closed, so the routes applied by the previous Request are deleted as well
+		_ = c.remove(ctx, conn, metadata.IsClient(c))
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c *routesClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	removeErr := c.remove(ctx, conn, metadata.IsClient(c))
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,322 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/desiredstate"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ipaddrs"
+)
+
+// DriftMetric is a path segment metric key with the number of the connection routes and rules found missing in the
+// kernel on the last refresh verification (see WithVerifyOnRefresh)
+const DriftMetric = "routesDrift"
+
+// TableLabel is a connection label with the route table ID the connection routes are added into, it overrides
+// WithTable. The main route table (254) is used if it is not set.
+const TableLabel = "routesTable"
+
+const defaultRulePriority = 200
+
+// applier is the common part of the routes client and server
+type applier struct {
+	verifyOnRefresh bool
+	preferredSrc    bool
+	table           int
+	rulePriority    int
+}
+
+func newApplier(options []Option) *applier {
+	a := &applier{
+		preferredSrc: true,
+		rulePriority: defaultRulePriority,
+	}
+	for _, opt := range options {
+		opt(a)
+	}
+	return a
+}
+
+// apply reconciles the connection routes and rules in the connection kernel interface net NS with the applied ones
+// stored in metadata: the missing ones are added, the ones dropped from the connection since the previous Request
+// (e.g. on the route table change) are deleted, so there are no mutations on refresh if the kernel state is as
+// expected. On failure the previously applied state is kept. If there is the reconciler in the chain, the routes and
+// rules are only described in its state (see desiredstate.State).
+func (a *applier) apply(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	desired, err := a.desiredState(conn, isClient)
+	if err != nil {
+		return err
+	}
+	if state := desiredstate.State(ctx); state != nil {
+		state.Merge(desired)
+		return nil
+	}
+	applied, _ := load(ctx, isClient)
+	if applied == nil && desired == nil {
+		return nil
+	}
+
+	if a.verifyOnRefresh && applied != nil {
+		drift, err := desiredstate.Drift(applied)
+		if err != nil {
+			return err
+		}
+		setDriftMetric(conn, drift)
+		if drift != 0 {
+			log.Entry(ctx).WithField("routes", "verify").Warnf("%d routes and rules are missing for connection %s, adding them",
+				drift, conn.GetId())
+		}
+	}
+
+	applied, err = desiredstate.Reconcile(ctx, applied, desired)
+	if err != nil {
+		return err
+	}
+	store(ctx, isClient, applied)
+	return nil
+}
+
+// remove deletes the applied connection routes and rules. Without metadata chain element in the chain there is no
+// applied state, so the ones of the connection are deleted. The ones described in the reconciler state are deleted by
+// the reconciler.
+func (a *applier) remove(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	if desiredstate.State(ctx) != nil {
+		return nil
+	}
+	applied, ok := loadAndDelete(ctx, isClient)
+	if !ok {
+		var err error
+		if applied, err = a.desiredState(conn, isClient); err != nil || applied == nil {
+			return err
+		}
+	}
+	_, err := desiredstate.Reconcile(ctx, applied, nil)
+	return err
+}
+
+// desiredState returns the connection routes and rules in the connection kernel interface net NS, it returns nil if
+// there are no ones
+func (a *applier) desiredState(conn *networkservice.Connection, isClient bool) (*desiredstate.KernelDesiredState, error) {
+	mech := kernelmech.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil, nil
+	}
+	routes, err := a.connRoutes(conn, isClient)
+	if err != nil || len(routes) == 0 {
+		return nil, err
+	}
+	rules, err := a.connRules(conn, isClient)
+	if err != nil {
+		return nil, err
+	}
+
+	state := desiredstate.New(mech.GetNetNSURL())
+	state.Link(mech.GetInterfaceName(conn)).Routes = routes
+	state.Rules = rules
+	return state, nil
+}
+
+// connRoutes returns the connection routes. Server side interface gets Src routes, Client side interface gets Dst
+// routes and routes to the extra prefixes via Src IP address. The net interface IP address of the same family is the
+// preferred source of the routes, so the Client having multiple net interfaces sources the connection traffic from the
+// NSM assigned IP address.
+func (a *applier) connRoutes(conn *networkservice.Connection, isClient bool) ([]*netlink.Route, error) {
+	ipContext := conn.GetContext().GetIpContext()
+	ipAddrString, routes := ipContext.GetSrcIpAddr(), ipContext.GetSrcRoutes()
+	var extraPrefixes []string
+	if isClient {
+		ipAddrString, routes = ipContext.GetDstIpAddr(), ipContext.GetDstRoutes()
+		extraPrefixes = ipContext.GetExtraPrefixes()
+	}
+	if len(routes) == 0 && len(extraPrefixes) == 0 {
+		return nil, nil
+	}
+
+	table, err := a.connTable(conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var srcIPNets []*net.IPNet
+	if a.preferredSrc {
+		if srcIPNets, err = ipaddrs.Parse(ipAddrString); err != nil {
+			return nil, err
+		}
+	}
+	gwIPNets, err := ipaddrs.Parse(ipContext.GetSrcIpAddr())
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*netlink.Route
+	for _, route := range routes {
+		dst, err := parsePrefix(route.GetPrefix())
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, newRoute(dst, sameFamily(srcIPNets, dst.IP), nil, table))
+	}
+	for _, prefix := range extraPrefixes {
+		dst, err := parsePrefix(prefix)
+		if err != nil {
+			return nil, err
+		}
+		// if there is no gateway for the extra prefix family, the extra prefix is routed via the net interface only,
+		// if there are several ones, the extra prefix is routed via all of them (ECMP)
+		result = append(result, newRoute(dst, sameFamily(srcIPNets, dst.IP), allSameFamily(gwIPNets, dst.IP), table))
+	}
+	return result, nil
+}
+
+// sameFamily returns the first IP address of the same family with the given one, it returns nil if there is no such
+// address
+func sameFamily(ipNets []*net.IPNet, ip net.IP) net.IP {
+	for _, ipNet := range ipNets {
+		if ipaddrs.IsIPv4(ipNet.IP) == ipaddrs.IsIPv4(ip) {
+			return ipNet.IP
+		}
+	}
+	return nil
+}
+
+// allSameFamily returns all the IP addresses of the same family with the given one
+func allSameFamily(ipNets []*net.IPNet, ip net.IP) []net.IP {
+	var result []net.IP
+	for _, ipNet := range ipNets {
+		if ipaddrs.IsIPv4(ipNet.IP) == ipaddrs.IsIPv4(ip) {
+			result = append(result, ipNet.IP)
+		}
+	}
+	return result
+}
+
+// newRoute returns the route via the net interface, the net interface is set by the reconciler. If there are no gws it
+// is the device route (same as `ip route add $dst dev $link`) with the link scope, so the kernel doesn't require a
+// nexthop gateway for it. If there are several gws it is the multipath route with a nexthop per gateway (same as
+// `ip route add $dst nexthop via $gw1 dev $link onlink nexthop via $gw2 dev $link onlink`).
+func newRoute(dst *net.IPNet, src net.IP, gws []net.IP, table int) *netlink.Route {
+	route := &netlink.Route{
+		Dst:      dst,
+		Table:    table,
+		Protocol: kernel.RouteProtoNSM,
+	}
+	if src != nil {
+		route.Src = src
+	}
+	switch len(gws) {
+	case 0:
+		route.Scope = kernel.RouteScopeLink
+	case 1:
+		// Src IP address can be out of the net interface subnet, e.g. /32
+		route.Gw = gws[0]
+		route.Flags = kernel.RouteFlagOnlink
+	default:
+		for _, gw := range gws {
+			route.MultiPath = append(route.MultiPath, &netlink.NexthopInfo{
+				Gw:    gw,
+				Flags: kernel.RouteFlagOnlink,
+			})
+		}
+	}
+	return route
+}
+
+// connTable returns the connection route table ID or 0 for the main route table
+func (a *applier) connTable(conn *networkservice.Connection) (int, error) {
+	table := a.table
+	if tableString, ok := conn.GetLabels()[TableLabel]; ok {
+		id, err := strconv.ParseUint(tableString, 10, 32)
+		if err != nil || id == 0 {
+			return 0, errors.Errorf("invalid %s label value: %v", TableLabel, tableString)
+		}
+		table = int(id)
+	}
+	if table == kernel.RouteTableMain {
+		return 0, nil
+	}
+	return table, nil
+}
+
+// connRules returns the rules looking up the connection route table for the traffic from the net interface IP
+// addresses, there are no rules for the main route table
+func (a *applier) connRules(conn *networkservice.Connection, isClient bool) ([]*netlink.Rule, error) {
+	table, err := a.connTable(conn)
+	if err != nil || table == 0 {
+		return nil, err
+	}
+
+	ipContext := conn.GetContext().GetIpContext()
+	ipAddrString, hasRoutes := ipContext.GetSrcIpAddr(), len(ipContext.GetSrcRoutes()) != 0
+	if isClient {
+		ipAddrString = ipContext.GetDstIpAddr()
+		hasRoutes = len(ipContext.GetDstRoutes()) != 0 || len(ipContext.GetExtraPrefixes()) != 0
+	}
+	if !hasRoutes {
+		return nil, nil
+	}
+
+	ipNets, err := ipaddrs.Parse(ipAddrString)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*netlink.Rule
+	for _, ipNet := range ipNets {
+		rule := netlink.NewRule()
+		rule.Family = kernel.FamilyV4
+		if !ipaddrs.IsIPv4(ipNet.IP) {
+			rule.Family = kernel.FamilyV6
+		}
+		rule.Src = &net.IPNet{IP: ipNet.IP, Mask: ipaddrs.HostMask(ipNet.IP)}
+		rule.Priority = a.rulePriority
+		rule.Table = table
+		result = append(result, rule)
+	}
+	return result, nil
+}
+
+func parsePrefix(prefix string) (*net.IPNet, error) {
+	_, dst, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid route CIDR: %v", prefix)
+	}
+	return dst, nil
+}
+
+func setDriftMetric(conn *networkservice.Connection, drift int) {
+	path := conn.GetPath()
+	if path == nil || int(path.GetIndex()) >= len(path.GetPathSegments()) {
+		return
+	}
+
+	segment := path.GetPathSegments()[path.GetIndex()]
+	if segment.Metrics == nil {
+		segment.Metrics = make(map[string]string)
+	}
+	segment.Metrics[DriftMetric] = strconv.Itoa(drift)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(a *applier)
+
+// WithVerifyOnRefresh makes refresh Requests report the number of the applied connection routes and rules found
+// missing in the kernel in the path segment metrics (see DriftMetric), the missing ones are added anyway. It requires
+// metadata chain element.
+func WithVerifyOnRefresh() Option {
+	return func(a *applier) {
+		a.verifyOnRefresh = true
+	}
+}
+
+// WithoutPreferredSrc disables setting the net interface IP address as the preferred source ("src") of the connection
+// routes, the kernel selects the source address itself then
+func WithoutPreferredSrc() Option {
+	return func(a *applier) {
+		a.preferredSrc = false
+	}
+}
+
+// WithTable sets the route table ID the connection routes are added into, the connection TableLabel overrides it. For
+// a non-main route table, the rules looking it up for the traffic from the net interface IP addresses are added too.
+func WithTable(table int) Option {
+	return func(a *applier) {
+		a.table = table
+	}
+}
+
+// WithRulePriority sets the priority of the rules added for the non-main route table, it should be less than the
+// priority of the rules looking up the main route table (32766)
+func WithRulePriority(priority int) Option {
+	return func(a *applier) {
+		a.rulePriority = priority
+	}
+}
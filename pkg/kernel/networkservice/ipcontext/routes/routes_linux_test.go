@@ -0,0 +1,468 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	kernelconst "github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext/routes"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/owned"
+)
+
+const (
+	ifName   = "routes-1"
+	peerName = "routes-2"
+)
+
+func newConn() *networkservice.Connection {
+	return &networkservice.Connection{
+		Id: "conn-1",
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.NetNSURL:         "file:///proc/self/ns/net",
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+		Context: &networkservice.ConnectionContext{
+			IpContext: &networkservice.IPContext{
+				SrcIpAddr: "10.0.13.1/32",
+				DstIpAddr: "10.0.13.2/32",
+				SrcRoutes: []*networkservice.Route{
+					{Prefix: "10.0.13.2/32"},
+					{Prefix: "10.0.14.0/24"},
+				},
+				DstRoutes: []*networkservice.Route{
+					{Prefix: "10.0.13.1/32"},
+				},
+				ExtraPrefixes: []string{"10.0.15.0/24"},
+			},
+		},
+	}
+}
+
+func routeDsts(t *testing.T) []string {
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+
+	list, err := owned.Routes(link)
+	require.NoError(t, err)
+
+	var result []string
+	for i := range list {
+		result = append(result, list[i].Dst.String())
+	}
+	return result
+}
+
+func TestRoutesServer(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	server := chain.NewNetworkServiceServer(
+		ipcontext.NewServer(ipcontext.WithoutRoutes()),
+		routes.NewServer(),
+	)
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: newConn()})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"10.0.13.2/32", "10.0.14.0/24"}, routeDsts(t))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, routeDsts(t))
+}
+
+func TestRoutesClient(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	// client chain elements apply the IP context after the Request, so routes should follow the IP addresses
+	client := chain.NewNetworkServiceClient(
+		routes.NewClient(),
+		ipcontext.NewClient(ipcontext.WithoutRoutes()),
+	)
+
+	conn, err := client.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: newConn()})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"10.0.13.1/32", "10.0.15.0/24"}, routeDsts(t))
+
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	list, err := owned.Routes(link)
+	require.NoError(t, err)
+	for i := range list {
+		if list[i].Dst.String() == "10.0.15.0/24" {
+			require.Equal(t, "10.0.13.1", list[i].Gw.String())
+		}
+		require.Equal(t, kernelconst.RouteProtoNSM, list[i].Protocol)
+	}
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, routeDsts(t))
+}
+
+func TestRoutesServer_VerifyOnRefresh(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ipcontext.NewServer(ipcontext.WithoutRoutes()),
+		routes.NewServer(routes.WithVerifyOnRefresh()),
+	)
+
+	conn := newConn()
+	conn.Path = &networkservice.Path{
+		PathSegments: []*networkservice.PathSegment{{Name: "forwarder"}},
+	}
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"10.0.13.2/32", "10.0.14.0/24"}, routeDsts(t))
+	require.NotContains(t, conn.GetPath().GetPathSegments()[0].GetMetrics(), routes.DriftMetric)
+
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Equal(t, "0", conn.GetPath().GetPathSegments()[0].GetMetrics()[routes.DriftMetric])
+
+	// drift the kernel state
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	list, err := owned.Routes(link)
+	require.NoError(t, err)
+	for i := range list {
+		if list[i].Dst.String() == "10.0.14.0/24" {
+			require.NoError(t, netlink.RouteDel(&list[i]))
+		}
+	}
+	require.ElementsMatch(t, []string{"10.0.13.2/32"}, routeDsts(t))
+
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Equal(t, "1", conn.GetPath().GetPathSegments()[0].GetMetrics()[routes.DriftMetric])
+	require.ElementsMatch(t, []string{"10.0.13.2/32", "10.0.14.0/24"}, routeDsts(t))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, routeDsts(t))
+}
+
+func TestRoutesClient_DeviceRoutes(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	client := chain.NewNetworkServiceClient(
+		routes.NewClient(),
+		ipcontext.NewClient(ipcontext.WithoutRoutes()),
+	)
+
+	// there is no IPv6 gateway for the IPv6 extra prefix
+	conn := newConn()
+	conn.GetContext().GetIpContext().ExtraPrefixes = append(conn.GetContext().GetIpContext().ExtraPrefixes, "fd00:15::/64")
+
+	conn, err := client.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"10.0.13.1/32", "10.0.15.0/24", "fd00:15::/64"}, routeDsts(t))
+
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	list, err := owned.Routes(link)
+	require.NoError(t, err)
+	for i := range list {
+		switch list[i].Dst.String() {
+		case "10.0.15.0/24":
+			require.Equal(t, netlink.SCOPE_UNIVERSE, list[i].Scope)
+		case "10.0.13.1/32":
+			require.Nil(t, list[i].Gw)
+			require.Equal(t, netlink.SCOPE_LINK, list[i].Scope)
+		case "fd00:15::/64":
+			require.Nil(t, list[i].Gw)
+		}
+	}
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, routeDsts(t))
+}
+
+func TestRoutesClient_PreferredSrc(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	for _, tc := range []struct {
+		options []routes.Option
+		src     string
+	}{
+		{src: "10.0.13.2"},
+		{options: []routes.Option{routes.WithoutPreferredSrc()}, src: "<nil>"},
+	} {
+		client := chain.NewNetworkServiceClient(
+			routes.NewClient(tc.options...),
+			ipcontext.NewClient(ipcontext.WithoutRoutes()),
+		)
+
+		conn, err := client.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: newConn()})
+		require.NoError(t, err)
+
+		link, err := netlink.LinkByName(ifName)
+		require.NoError(t, err)
+		list, err := owned.Routes(link)
+		require.NoError(t, err)
+		require.NotEmpty(t, list)
+		for i := range list {
+			require.Equal(t, tc.src, list[i].Src.String(), list[i].Dst.String())
+		}
+
+		_, err = client.Close(context.TODO(), conn)
+		require.NoError(t, err)
+	}
+}
+
+func tableRoutes(t *testing.T, table int) []string {
+	list, err := netlink.RouteListFiltered(netlink.FAMILY_ALL, &netlink.Route{Table: table}, netlink.RT_FILTER_TABLE)
+	require.NoError(t, err)
+
+	var result []string
+	for i := range list {
+		result = append(result, list[i].Dst.String())
+	}
+	return result
+}
+
+func tableRuleSrcs(t *testing.T, table int) []string {
+	list, err := netlink.RuleList(netlink.FAMILY_V4)
+	require.NoError(t, err)
+
+	var result []string
+	for i := range list {
+		if list[i].Table == table {
+			result = append(result, list[i].Src.String())
+		}
+	}
+	return result
+}
+
+func TestRoutesServer_Table(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ipcontext.NewServer(ipcontext.WithoutRoutes()),
+		routes.NewServer(routes.WithTable(1000), routes.WithVerifyOnRefresh()),
+	)
+
+	conn := newConn()
+	conn.Labels = map[string]string{
+		routes.TableLabel: "1001",
+	}
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Empty(t, tableRoutes(t, 1000))
+	require.ElementsMatch(t, []string{"10.0.13.2/32", "10.0.14.0/24"}, tableRoutes(t, 1001))
+	require.Equal(t, []string{"10.0.13.1/32"}, tableRuleSrcs(t, 1001))
+
+	// refresh verifies the route table and doesn't duplicate the rules
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Len(t, tableRuleSrcs(t, 1001), 1)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, tableRoutes(t, 1001))
+	require.Empty(t, tableRuleSrcs(t, 1001))
+}
+
+func TestRoutesClient_Table(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	client := chain.NewNetworkServiceClient(
+		routes.NewClient(routes.WithTable(1000)),
+		ipcontext.NewClient(ipcontext.WithoutRoutes()),
+	)
+
+	conn, err := client.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: newConn()})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"10.0.13.1/32", "10.0.15.0/24"}, tableRoutes(t, 1000))
+	require.Equal(t, []string{"10.0.13.2/32"}, tableRuleSrcs(t, 1000))
+
+	// the net interface is deleted with its routes, but the rules are still deleted
+	_ = netlink.LinkDel(&netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: ifName}})
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, tableRuleSrcs(t, 1000))
+}
+
+func TestRoutesServer_InvalidTable(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	conn := newConn()
+	conn.Labels = map[string]string{
+		routes.TableLabel: "main",
+	}
+
+	_, err := routes.NewServer().Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.Error(t, err)
+}
+
+func TestRoutesClient_Multipath(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+	require.NoError(t, netlink.LinkSetUp(link))
+
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		routes.NewClient(routes.WithVerifyOnRefresh()),
+		ipcontext.NewClient(ipcontext.WithoutRoutes()),
+	)
+
+	// there are 2 redundant Endpoints gateways for the extra prefix
+	conn := newConn()
+	conn.GetContext().GetIpContext().SrcIpAddr = "10.0.13.1/32,10.0.13.3/32"
+	conn.Path = &networkservice.Path{
+		PathSegments: []*networkservice.PathSegment{{Name: "forwarder"}},
+	}
+
+	conn, err := client.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	list, err := netlink.RouteListFiltered(netlink.FAMILY_V4, &netlink.Route{Dst: &net.IPNet{
+		IP:   net.IPv4(10, 0, 15, 0).To4(),
+		Mask: net.CIDRMask(24, 32),
+	}}, netlink.RT_FILTER_DST)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Len(t, list[0].MultiPath, 2)
+	var gws []string
+	for _, nh := range list[0].MultiPath {
+		require.Equal(t, link.Attrs().Index, nh.LinkIndex)
+		gws = append(gws, nh.Gw.String())
+	}
+	require.ElementsMatch(t, []string{"10.0.13.1", "10.0.13.3"}, gws)
+
+	conn, err = client.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Equal(t, "0", conn.GetPath().GetPathSegments()[0].GetMetrics()[routes.DriftMetric])
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	list, err = netlink.RouteListFiltered(netlink.FAMILY_V4, &netlink.Route{Table: kernelconst.RouteTableMain}, netlink.RT_FILTER_TABLE)
+	require.NoError(t, err)
+	for i := range list {
+		require.NotEqual(t, "10.0.15.0/24", list[i].Dst.String())
+	}
+}
+
+func TestRoutesServer_Refresh(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ipcontext.NewServer(ipcontext.WithoutRoutes()),
+		routes.NewServer(),
+	)
+
+	conn := newConn()
+	conn.Labels = map[string]string{
+		routes.TableLabel: "1001",
+	}
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"10.0.13.2/32", "10.0.14.0/24"}, tableRoutes(t, 1001))
+	require.Equal(t, []string{"10.0.13.1/32"}, tableRuleSrcs(t, 1001))
+
+	// routes and rules dropped on refresh are deleted
+	conn.GetContext().GetIpContext().SrcRoutes = conn.GetContext().GetIpContext().SrcRoutes[:1]
+	conn.Labels[routes.TableLabel] = "1002"
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Empty(t, tableRoutes(t, 1001))
+	require.Empty(t, tableRuleSrcs(t, 1001))
+	require.Equal(t, []string{"10.0.13.2/32"}, tableRoutes(t, 1002))
+	require.Equal(t, []string{"10.0.13.1/32"}, tableRuleSrcs(t, 1002))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, tableRoutes(t, 1002))
+	require.Empty(t, tableRuleSrcs(t, 1002))
+}
+
+func TestRoutesServer_Rollback(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ipcontext.NewServer(ipcontext.WithoutRoutes()),
+		routes.NewServer(),
+		injecterror.NewServer(),
+	)
+
+	_, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: newConn()})
+	require.Error(t, err)
+	require.Empty(t, routeDsts(t))
+}
+
+func TestRoutesServer_NetNS(t *testing.T) {
+	netNS := kerneltest.NewNetNS(t)
+
+	link := kerneltest.AddVeth(t, ifName, peerName)
+	require.NoError(t, netlink.LinkSetNsFd(link, int(netNS)))
+
+	handle, err := netlink.NewHandleAt(netNS)
+	require.NoError(t, err)
+	defer handle.Delete()
+	link, err = handle.LinkByName(ifName)
+	require.NoError(t, err)
+	require.NoError(t, handle.LinkSetUp(link))
+
+	// the server programs the Client's net interface net NS same as the client programs the Endpoint's one
+	conn := newConn()
+	conn.GetMechanism().GetParameters()[kernel.NetNSURL] = fmt.Sprintf("file:///proc/%d/fd/%d", os.Getpid(), int(netNS))
+
+	server := routes.NewServer(routes.WithoutPreferredSrc())
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	list, err := handle.RouteList(link, netlink.FAMILY_V4)
+	require.NoError(t, err)
+	var dsts []string
+	for i := range list {
+		dsts = append(dsts, list[i].Dst.String())
+	}
+	require.ElementsMatch(t, []string{"10.0.13.2/32", "10.0.14.0/24"}, dsts)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	list, err = handle.RouteList(link, netlink.FAMILY_V4)
+	require.NoError(t, err)
+	require.Empty(t, list)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package routes provides chain elements applying IP context routes to the connection kernel interfaces
+package routes
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type routesServer struct {
+	*applier
+}
+
+// NewServer returns a new routes server chain element applying Src routes to the Client's net interface in its net NS
+// (or in the current net NS if there is no net NS URL) on Request and deleting them on Close. Routes and rules dropped
+// on refresh are deleted too, it requires metadata chain element for that.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &routesServer{
+		applier: newApplier(options),
+	}
+}
+
+func (s *routesServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if err := s.apply(ctx, request.GetConnection(), metadata.IsClient(s)); err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if removeErr := s.remove(ctx, request.GetConnection(), metadata.IsClient(s)); removeErr != nil {
+			log.Entry(ctx).WithField("routesServer", "Request").Warnf("failed to delete routes: %s", removeErr.Error())
+		}
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (s *routesServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	removeErr := s.remove(ctx, conn, metadata.IsClient(s))
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
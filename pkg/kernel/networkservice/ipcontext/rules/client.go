@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+type rulesClient struct {
+	*applier
+}
+
+// NewClient returns a new rules client chain element adding Dst routes into the Table(conn.GetId()) route table and
+// the rules looking it up for the traffic from the Dst IP addresses and the traffic marked with FwmarkLabel in the
+// Endpoint's net NS.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &rulesClient{
+		applier: newApplier(options),
+	}
+}
+
+func (c *rulesClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
+		if err := nshandle.RunInURL(mech.GetNetNSURL(), func() error { return c.create(ctx, conn, metadata.IsClient(c)) }); err != nil {
+			_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+func (c *rulesClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var removeErr error
+	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
+		removeErr = nshandle.RunInURL(mech.GetNetNSURL(), func() error { return c.remove(ctx, conn, metadata.IsClient(c)) })
+	}
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
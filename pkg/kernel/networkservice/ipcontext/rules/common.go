@@ -0,0 +1,322 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"context"
+	"hash/fnv"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ipaddrs"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nodelock"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/optime"
+)
+
+// FwmarkLabel is a connection label with the firewall mark (decimal or 0x prefixed hex) of the traffic routed via the
+// connection route table in addition to the traffic sourced from the connection IP addresses
+const FwmarkLabel = "policyRoutingFwmark"
+
+const (
+	tablePrefix         = 0x4d000000
+	defaultRulePriority = 200
+)
+
+// Table returns the route table ID of the connection. The high byte differs from the steering marks and tables, so
+// a connection can be both steered and policy routed.
+func Table(connID string) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(connID))
+	return tablePrefix | int(hash.Sum32()&0x00ffffff)
+}
+
+type applier struct {
+	rulePriority int
+	locker       *nodelock.Locker
+
+	mu    sync.Mutex
+	locks map[string]*nodelock.Lock
+}
+
+func newApplier(options []Option) *applier {
+	a := &applier{
+		rulePriority: defaultRulePriority,
+		locks:        make(map[string]*nodelock.Lock),
+	}
+	for _, opt := range options {
+		opt(a)
+	}
+	return a
+}
+
+func (a *applier) create(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	mech := kernelmech.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil
+	}
+
+	ifName := mech.GetInterfaceName(conn)
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+
+	routes, err := connRoutes(link, conn, isClient)
+	if err != nil || len(routes) == 0 {
+		return err
+	}
+	rules, err := a.connRules(conn, isClient)
+	if err != nil {
+		return err
+	}
+
+	locked, err := a.lock(conn)
+	if err != nil {
+		return err
+	}
+
+	for _, route := range routes {
+		if err := optime.Time(ctx, "RouteAdd", route, func() error { return netlink.RouteAdd(route) }); err != nil && !os.IsExist(err) {
+			return a.unlockFailed(conn, locked, errors.Wrapf(err, "failed to add route: %v table %v", route.Dst, route.Table))
+		}
+	}
+	for _, rule := range rules {
+		if err := optime.Time(ctx, "RuleAdd", rule, func() error { return ruleAdd(rule) }); err != nil && !os.IsExist(err) {
+			return a.unlockFailed(conn, locked, errors.Wrapf(err, "failed to add rule: %v", rule))
+		}
+	}
+	return nil
+}
+
+func (a *applier) remove(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	if kernelmech.ToMechanism(conn.GetMechanism()) == nil {
+		return nil
+	}
+
+	err := a.removeTable(ctx, conn, isClient)
+
+	if unlockErr := a.unlock(conn); unlockErr != nil {
+		if err != nil {
+			return errors.Wrap(err, unlockErr.Error())
+		}
+		return unlockErr
+	}
+	return err
+}
+
+func (a *applier) removeTable(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	// routes are deleted with the net interface, but rules are not bound to the net interface and so are always deleted
+	rules, err := a.connRules(conn, isClient)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if err := optime.Time(ctx, "RuleDel", rule, func() error { return ruleDel(rule) }); err != nil &&
+			!os.IsNotExist(err) && err != syscall.ESRCH {
+			return errors.Wrapf(err, "failed to delete rule: %v", rule)
+		}
+	}
+
+	table := Table(conn.GetId())
+	routes, err := tableRoutes(table)
+	if err != nil {
+		return err
+	}
+	for i := range routes {
+		route := &routes[i]
+		if err := optime.Time(ctx, "RouteDel", route, func() error { return netlink.RouteDel(route) }); err != nil && err != syscall.ESRCH {
+			return errors.Wrapf(err, "failed to delete route: %v table %v", route.Dst, table)
+		}
+	}
+	return nil
+}
+
+// lock locks the connection route table in the current net NS on the node, so the connections of the Forwarder
+// instances on the node with the colliding Table IDs don't share the route table. It returns true if the lock is taken
+// by this call, the connection already holding the lock keeps it.
+func (a *applier) lock(conn *networkservice.Connection) (bool, error) {
+	if a.locker == nil {
+		return false, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if _, ok := a.locks[conn.GetId()]; ok {
+		return false, nil
+	}
+
+	curNetNS, err := nshandle.Current()
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = curNetNS.Close() }()
+
+	table := Table(conn.GetId())
+	lock, err := a.locker.TryLock(nodelock.KindTableID, curNetNS.UniqueId()+"-"+strconv.Itoa(table))
+	if nodelock.IsLocked(err) {
+		return false, errors.Wrapf(err, "route table is already used by another connection on the node: %v", table)
+	}
+	if err != nil {
+		return false, err
+	}
+	a.locks[conn.GetId()] = lock
+
+	return true, nil
+}
+
+func (a *applier) unlock(conn *networkservice.Connection) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	lock, ok := a.locks[conn.GetId()]
+	if !ok {
+		return nil
+	}
+	delete(a.locks, conn.GetId())
+
+	return lock.Unlock()
+}
+
+// unlockFailed releases the lock taken by the failed create, it returns the create error
+func (a *applier) unlockFailed(conn *networkservice.Connection, locked bool, createErr error) error {
+	if locked {
+		_ = a.unlock(conn)
+	}
+	return createErr
+}
+
+// connRules returns a rule per the connection local IP address and a rule per the routed family for the fwmark
+func (a *applier) connRules(conn *networkservice.Connection, isClient bool) ([]*netlink.Rule, error) {
+	ipContext := conn.GetContext().GetIpContext()
+	ipAddrString, routes := ipContext.GetSrcIpAddr(), ipContext.GetSrcRoutes()
+	if isClient {
+		ipAddrString, routes = ipContext.GetDstIpAddr(), ipContext.GetDstRoutes()
+	}
+
+	ipNets, err := ipaddrs.Parse(ipAddrString)
+	if err != nil {
+		return nil, err
+	}
+
+	table := Table(conn.GetId())
+	var result []*netlink.Rule
+	for _, ipNet := range ipNets {
+		rule := a.newRule(ipNet.IP, table)
+		rule.Src = &net.IPNet{IP: ipNet.IP, Mask: ipaddrs.HostMask(ipNet.IP)}
+		result = append(result, rule)
+	}
+
+	markString, ok := conn.GetLabels()[FwmarkLabel]
+	if !ok {
+		return result, nil
+	}
+	mark, err := strconv.ParseUint(markString, 0, 32)
+	if err != nil || mark == 0 {
+		return nil, errors.Errorf("invalid %s label value: %v", FwmarkLabel, markString)
+	}
+
+	var v4, v6 bool
+	for _, route := range routes {
+		dst, err := parsePrefix(route.GetPrefix())
+		if err != nil {
+			return nil, err
+		}
+		if ipaddrs.IsIPv4(dst.IP) && !v4 {
+			v4 = true
+			result = append(result, a.newMarkRule(dst.IP, int(mark), table))
+		} else if !ipaddrs.IsIPv4(dst.IP) && !v6 {
+			v6 = true
+			result = append(result, a.newMarkRule(dst.IP, int(mark), table))
+		}
+	}
+	return result, nil
+}
+
+func (a *applier) newRule(ip net.IP, table int) *netlink.Rule {
+	rule := netlink.NewRule()
+	rule.Family = kernel.FamilyV4
+	if !ipaddrs.IsIPv4(ip) {
+		rule.Family = kernel.FamilyV6
+	}
+	rule.Priority = a.rulePriority
+	rule.Table = table
+	return rule
+}
+
+func (a *applier) newMarkRule(ip net.IP, mark, table int) *netlink.Rule {
+	rule := a.newRule(ip, table)
+	rule.Mark = mark
+	return rule
+}
+
+// connRoutes returns the connection routes via the net interface in the connection route table
+func connRoutes(link netlink.Link, conn *networkservice.Connection, isClient bool) ([]*netlink.Route, error) {
+	ipContext := conn.GetContext().GetIpContext()
+	ipAddrString, routes := ipContext.GetSrcIpAddr(), ipContext.GetSrcRoutes()
+	if isClient {
+		ipAddrString, routes = ipContext.GetDstIpAddr(), ipContext.GetDstRoutes()
+	}
+
+	srcIPNets, err := ipaddrs.Parse(ipAddrString)
+	if err != nil {
+		return nil, err
+	}
+
+	table := Table(conn.GetId())
+	var result []*netlink.Route
+	for _, route := range routes {
+		dst, err := parsePrefix(route.GetPrefix())
+		if err != nil {
+			return nil, err
+		}
+		r := &netlink.Route{
+			LinkIndex: link.Attrs().Index,
+			Dst:       dst,
+			Scope:     kernel.RouteScopeLink,
+			Table:     table,
+			Protocol:  kernel.RouteProtoNSM,
+		}
+		for _, ipNet := range srcIPNets {
+			if ipaddrs.IsIPv4(ipNet.IP) == ipaddrs.IsIPv4(dst.IP) {
+				r.Src = ipNet.IP
+				break
+			}
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func parsePrefix(prefix string) (*net.IPNet, error) {
+	_, dst, err := net.ParseCIDR(prefix)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid route CIDR: %v", prefix)
+	}
+	return dst, nil
+}
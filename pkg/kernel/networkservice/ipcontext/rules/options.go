@@ -0,0 +1,41 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nodelock"
+)
+
+// Option is an option pattern for NewClient, NewServer
+type Option func(a *applier)
+
+// WithRulePriority sets the priority of the policy routing rules, it should be less than the priority of the rules
+// looking up the main route table (32766)
+func WithRulePriority(priority int) Option {
+	return func(a *applier) {
+		a.rulePriority = priority
+	}
+}
+
+// WithNodeLock enables node level conflict detection with the given Locker: the connection route table is locked in
+// the net NS until Close, so Request fails instead of sharing the route table if the Table IDs of the connections on
+// the node collide
+func WithNodeLock(locker *nodelock.Locker) Option {
+	return func(a *applier) {
+		a.locker = locker
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build !linux
+
+package rules
+
+import (
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+)
+
+func ruleAdd(_ *netlink.Rule) error {
+	return errors.New("policy routing is supported only on linux")
+}
+
+func ruleDel(_ *netlink.Rule) error {
+	return errors.New("policy routing is supported only on linux")
+}
+
+func tableRoutes(_ int) ([]netlink.Route, error) {
+	return nil, errors.New("policy routing is supported only on linux")
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules
+
+import (
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+)
+
+func ruleAdd(rule *netlink.Rule) error {
+	return netlink.RuleAdd(rule)
+}
+
+func ruleDel(rule *netlink.Rule) error {
+	return netlink.RuleDel(rule)
+}
+
+// tableRoutes returns all the routes in the route table
+func tableRoutes(table int) ([]netlink.Route, error) {
+	routes, err := netlink.RouteListFiltered(kernel.FamilyAll, &netlink.Route{Table: table}, netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get routes: table %v", table)
+	}
+	return routes, nil
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package rules provides chain elements routing the connection traffic via the connection route table with the
+// policy routing rules, so the connections with the overlapping destination prefixes can coexist in one net NS
+package rules
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type rulesServer struct {
+	*applier
+}
+
+// NewServer returns a new rules server chain element adding Src routes into the Table(conn.GetId()) route table and
+// the rules looking it up for the traffic from the Src IP addresses and the traffic marked with FwmarkLabel. It
+// programs the current net NS, so it should follow the netns and ipcontext chain elements. The routes are not added
+// into the main route table, so the routes server chain element should not be used for the same connection.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &rulesServer{
+		applier: newApplier(options),
+	}
+}
+
+func (s *rulesServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if err := s.create(ctx, request.GetConnection(), metadata.IsClient(s)); err != nil {
+		return nil, err
+	}
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *rulesServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	removeErr := s.remove(ctx, conn, metadata.IsClient(s))
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rules_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext/rules"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nodelock"
+)
+
+func newConn(id, ifName, srcIPAddr string) *networkservice.Connection {
+	return &networkservice.Connection{
+		Id: id,
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+		Context: &networkservice.ConnectionContext{
+			IpContext: &networkservice.IPContext{
+				SrcIpAddr: srcIPAddr,
+				SrcRoutes: []*networkservice.Route{
+					{Prefix: "10.0.30.0/24"},
+				},
+			},
+		},
+	}
+}
+
+func addVeth(t *testing.T, name string) netlink.Link {
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: name},
+		PeerName:  name + "p",
+	}))
+	link, err := netlink.LinkByName(name)
+	require.NoError(t, err)
+	require.NoError(t, netlink.LinkSetUp(link))
+	return link
+}
+
+func tableRules(t *testing.T, table int) []netlink.Rule {
+	list, err := netlink.RuleList(netlink.FAMILY_V4)
+	require.NoError(t, err)
+
+	var result []netlink.Rule
+	for i := range list {
+		if list[i].Table == table {
+			result = append(result, list[i])
+		}
+	}
+	return result
+}
+
+func tableRoutes(t *testing.T, table int) []netlink.Route {
+	list, err := netlink.RouteListFiltered(netlink.FAMILY_V4, &netlink.Route{Table: table}, netlink.RT_FILTER_TABLE)
+	require.NoError(t, err)
+	return list
+}
+
+func TestRulesServer_OverlappingPrefixes(t *testing.T) {
+	var conns []*networkservice.Connection
+	for i := 1; i <= 2; i++ {
+		ifName := fmt.Sprintf("rules-%d", i)
+		link := addVeth(t, ifName)
+		defer func() { _ = netlink.LinkDel(link) }()
+
+		// the route source addresses should be assigned by the ipcontext chain element
+		addr, err := netlink.ParseAddr(fmt.Sprintf("10.0.29.%d/32", i))
+		require.NoError(t, err)
+		require.NoError(t, netlink.AddrAdd(link, addr))
+
+		conns = append(conns, newConn(fmt.Sprintf("conn-%d", i), ifName, fmt.Sprintf("10.0.29.%d/32", i)))
+	}
+	conns[1].Labels = map[string]string{
+		rules.FwmarkLabel: "0x29",
+	}
+
+	server := rules.NewServer(rules.WithRulePriority(1000))
+	for _, conn := range conns {
+		_, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+		require.NoError(t, err)
+
+		// refresh doesn't duplicate the rules
+		_, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+		require.NoError(t, err)
+	}
+
+	for i, conn := range conns {
+		table := rules.Table(conn.GetId())
+
+		routes := tableRoutes(t, table)
+		require.Len(t, routes, 1)
+		require.Equal(t, "10.0.30.0/24", routes[0].Dst.String())
+
+		list := tableRules(t, table)
+		require.Len(t, list, i+1)
+		require.Equal(t, 1000, list[0].Priority)
+		require.Equal(t, fmt.Sprintf("10.0.29.%d/32", i+1), list[0].Src.String())
+	}
+	require.Equal(t, 0x29, tableRules(t, rules.Table(conns[1].GetId()))[1].Mark)
+
+	for _, conn := range conns {
+		_, err := server.Close(context.TODO(), conn)
+		require.NoError(t, err)
+
+		require.Empty(t, tableRules(t, rules.Table(conn.GetId())))
+		require.Empty(t, tableRoutes(t, rules.Table(conn.GetId())))
+	}
+}
+
+func TestRulesServer_InvalidFwmark(t *testing.T) {
+	link := addVeth(t, "rules-1")
+	defer func() { _ = netlink.LinkDel(link) }()
+
+	conn := newConn("conn-1", "rules-1", "10.0.29.1/32")
+	conn.Labels = map[string]string{
+		rules.FwmarkLabel: "mark",
+	}
+
+	_, err := rules.NewServer().Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.Error(t, err)
+	require.Empty(t, tableRoutes(t, rules.Table(conn.GetId())))
+}
+
+func TestRulesServer_NodeLock(t *testing.T) {
+	link := addVeth(t, "rules-1")
+	defer func() { _ = netlink.LinkDel(link) }()
+
+	addr, err := netlink.ParseAddr("10.0.29.1/32")
+	require.NoError(t, err)
+	require.NoError(t, netlink.AddrAdd(link, addr))
+
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// servers in the different Forwarder instances with the same connection ID, so the Table IDs collide
+	newServer := func() networkservice.NetworkServiceServer {
+		locker, lockerErr := nodelock.NewLocker(dir)
+		require.NoError(t, lockerErr)
+		return rules.NewServer(rules.WithRulePriority(1000), rules.WithNodeLock(locker))
+	}
+	server1, server2 := newServer(), newServer()
+
+	conn := newConn("conn-1", "rules-1", "10.0.29.1/32")
+	_, err = server1.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn.Clone()})
+	require.NoError(t, err)
+
+	_, err = server2.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn.Clone()})
+	require.True(t, nodelock.IsLocked(err))
+
+	// refresh keeps the lock
+	_, err = server1.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn.Clone()})
+	require.NoError(t, err)
+
+	_, err = server1.Close(context.TODO(), conn.Clone())
+	require.NoError(t, err)
+
+	_, err = server2.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn.Clone()})
+	require.NoError(t, err)
+	require.Len(t, tableRoutes(t, rules.Table(conn.GetId())), 1)
+
+	_, err = server2.Close(context.TODO(), conn.Clone())
+	require.NoError(t, err)
+	require.Empty(t, tableRules(t, rules.Table(conn.GetId())))
+}
@@ -19,117 +19,74 @@ package ipcontext
 
 import (
 	"context"
-	"net"
-	"os"
 
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/pkg/errors"
-	"github.com/vishvananda/netlink"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
-	kernelmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
 
-	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext/neighbors"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext/routes"
 )
 
-type ipContextServer struct{}
-
-// NewServer returns a new ip context server chain element
-func NewServer() networkservice.NetworkServiceServer {
-	return &ipContextServer{}
+type ipContextServer struct {
+	*ipContext
 }
 
-func (s *ipContextServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
-	if mech := kernelmech.ToMechanism(request.GetConnection().GetMechanism()); mech != nil {
-		ifName := mech.GetInterfaceName(request.GetConnection())
-		link, err := netlink.LinkByName(ifName)
-		if err != nil {
-			return nil, errors.Wrapf(err, "failed to get net interface: %v", ifName)
-		}
-
-		ipContext := request.GetConnection().GetContext().GetIpContext()
-		ipAddr, err := netlink.ParseAddr(ipContext.GetSrcIpAddr())
-		if err != nil {
-			return nil, errors.Wrapf(err, "invalid IP address: %v", ipContext.GetSrcIpAddr())
-		}
-
-		if link.Attrs().OperState != netlink.OperUp {
-			if err = netlink.LinkSetUp(link); err != nil {
-				return nil, errors.Wrapf(err, "failed to set up net interface: %v", ifName)
-			}
-		}
-
-		if err := setIPAddr(ipAddr, link); err != nil {
-			return nil, err
-		}
-		if err := setRoutes(ipContext.GetSrcRoutes(), ipAddr, link); err != nil {
-			return nil, err
-		}
-		if err := setIPNeighbors(ipContext.GetIpNeighbors(), link); err != nil {
-			return nil, err
-		}
+// NewServer returns a new ip context server chain element applying Src IP context to the Client's net interface in its
+// net NS (or in the current net NS if there is no net NS URL) on Request and deleting added IP addresses on Close.
+// IPv6 enabled for the IPv6 addresses is disabled back on Close. Src routes and IP context neighbors are applied with
+// the routes and neighbors server chain elements following it, unless WithoutRoutes and WithoutNeighbors are set.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	c := newIPContext(options)
+	var server networkservice.NetworkServiceServer = &ipContextServer{
+		ipContext: c,
 	}
-	return next.Server(ctx).Request(ctx, request)
-}
-
-func setIPAddr(ipAddr *netlink.Addr, link netlink.Link) error {
-	ipAddrs, err := netlink.AddrList(link, kernel.FamilyAll)
-	if err != nil {
-		return errors.Wrapf(err, "failed to get the net interface IP addresses: %v", link.Attrs().Name)
+	if c.withoutRoutes && c.withoutNeighbors {
+		return server
 	}
 
-	for i := range ipAddrs {
-		if ipAddr.Equal(ipAddrs[i]) {
-			return nil
-		}
+	// routes are applied after the IP addresses, so the routes preferred sources exist
+	elements := []networkservice.NetworkServiceServer{server}
+	if !c.withoutRoutes {
+		elements = append(elements, routes.NewServer())
 	}
-
-	if err := netlink.AddrAdd(link, ipAddr); err != nil {
-		return errors.Wrapf(err, "failed to add IP address to the net interface: %v %v", link.Attrs().Name, ipAddr)
+	if !c.withoutNeighbors {
+		elements = append(elements, neighbors.NewServer())
 	}
-
-	return nil
+	return chain.NewNetworkServiceServer(elements...)
 }
 
-func setRoutes(routes []*networkservice.Route, ipAddr *netlink.Addr, link netlink.Link) error {
-	for _, route := range routes {
-		_, routeNet, err := net.ParseCIDR(route.GetPrefix())
-		if err != nil {
-			return errors.Wrapf(err, "invalid route CIDR: %v", route.GetPrefix())
-		}
-		if err = netlink.RouteAdd(&netlink.Route{
-			LinkIndex: link.Attrs().Index,
-			Dst: &net.IPNet{
-				IP:   routeNet.IP,
-				Mask: routeNet.Mask,
-			},
-			Src: ipAddr.IP,
-		}); err != nil && !os.IsExist(err) {
-			return errors.Wrapf(err, "failed to add route: %v", route.GetPrefix())
-		}
+func (s *ipContextServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if err := s.create(ctx, request.GetConnection(), metadata.IsClient(s)); err != nil {
+		return nil, err
 	}
-	return nil
-}
 
-func setIPNeighbors(ipNeighbours []*networkservice.IpNeighbor, link netlink.Link) error {
-	for _, ipNeighbor := range ipNeighbours {
-		macAddr, err := net.ParseMAC(ipNeighbor.HardwareAddress)
-		if err != nil {
-			return errors.Wrapf(err, "invalid neighbor MAC address: %v", ipNeighbor.HardwareAddress)
-		}
-		if err := netlink.NeighAdd(&netlink.Neigh{
-			LinkIndex:    link.Attrs().Index,
-			State:        kernel.NudReachable,
-			IP:           net.ParseIP(ipNeighbor.Ip),
-			HardwareAddr: macAddr,
-		}); err != nil && !os.IsExist(err) {
-			return errors.Wrapf(err, "failed to add IP neighbor: %v", ipNeighbor)
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if removeErr := remove(ctx, request.GetConnection(), metadata.IsClient(s)); removeErr != nil {
+			log.Entry(ctx).WithField("ipContextServer", "Request").Warnf("failed to delete IP addresses: %s", removeErr.Error())
 		}
+		return nil, err
 	}
-	return nil
+
+	return conn, nil
 }
 
 func (s *ipContextServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
-	return next.Server(ctx).Close(ctx, conn)
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	removeErr := remove(ctx, conn, metadata.IsClient(s))
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
 }
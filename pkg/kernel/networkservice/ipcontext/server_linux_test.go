@@ -0,0 +1,469 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipcontext_test
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	kernelconst "github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ifindex"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+const (
+	ifName   = "ipctx-1"
+	peerName = "ipctx-2"
+)
+
+func request(srcIPAddr string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.InterfaceNameKey: ifName,
+				},
+			},
+			Context: &networkservice.ConnectionContext{
+				IpContext: &networkservice.IPContext{
+					SrcIpAddr: srcIPAddr,
+				},
+			},
+		},
+	}
+}
+
+func addrs(t *testing.T, link netlink.Link) []string {
+	list, err := netlink.AddrList(link, kernelconst.FamilyV4)
+	require.NoError(t, err)
+
+	var result []string
+	for i := range list {
+		result = append(result, list[i].IPNet.String())
+	}
+	return result
+}
+
+func TestIPContextServer_OwnedAddrs(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	// pre-existing address, e.g. assigned by CNI
+	preExisting, err := netlink.ParseAddr("10.0.0.1/24")
+	require.NoError(t, err)
+	require.NoError(t, netlink.AddrAdd(link, preExisting))
+
+	server := ipcontext.NewServer()
+
+	conn, err := server.Request(context.TODO(), request("10.0.0.1/24"))
+	require.NoError(t, err)
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.1/24"}, addrs(t, link))
+
+	_, err = server.Request(context.TODO(), request("10.0.1.1/24"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"10.0.0.1/24", "10.0.1.1/24"}, addrs(t, link))
+
+	// refresh with the changed address
+	conn, err = server.Request(context.TODO(), request("10.0.2.1/24"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"10.0.0.1/24", "10.0.2.1/24"}, addrs(t, link))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.1/24"}, addrs(t, link))
+}
+
+func TestIPContextServer_DualStack(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	require.NoError(t, sysctl.Set("net/ipv6/conf/"+ifName+"/disable_ipv6", "1"))
+
+	v6Addrs := func() []string {
+		list, err := netlink.AddrList(link, kernelconst.FamilyV6)
+		require.NoError(t, err)
+
+		var result []string
+		for i := range list {
+			if list[i].Scope == unix.RT_SCOPE_UNIVERSE {
+				require.Zero(t, list[i].Flags&unix.IFA_F_TENTATIVE, "address is not usable until DAD completes")
+				result = append(result, list[i].IPNet.String())
+			}
+		}
+		return result
+	}
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ipcontext.NewServer(),
+	)
+
+	conn, err := server.Request(context.TODO(), request("10.0.3.1/24, fd00:3::1/64"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.3.1/24"}, addrs(t, link))
+	require.Equal(t, []string{"fd00:3::1/64"}, v6Addrs())
+
+	// refresh changes only the IPv6 address
+	conn, err = server.Request(context.TODO(), request("10.0.3.1/24,fd00:4::1/64"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.3.1/24"}, addrs(t, link))
+	require.Equal(t, []string{"fd00:4::1/64"}, v6Addrs())
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, addrs(t, link))
+	require.Empty(t, v6Addrs())
+
+	// IPv6 is disabled back on Close
+	disabled, err := sysctl.GetInt("net/ipv6/conf/" + ifName + "/disable_ipv6")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), disabled)
+
+	_, err = server.Request(context.TODO(), request("10.0.3.1/24,fd00:3::1/129"))
+	require.Error(t, err)
+}
+
+func TestIPContextServer_NoNetNSSwitch(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	targetName, otherName := uuid.New().String(), uuid.New().String()
+	targetNetNS, err := netns.NewNamed(targetName)
+	require.NoError(t, err)
+	defer func() { _ = netns.DeleteNamed(targetName) }()
+	defer func() { _ = targetNetNS.Close() }()
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: ifName},
+		PeerName:  peerName,
+	}))
+	otherNetNS, err := netns.NewNamed(otherName)
+	require.NoError(t, err)
+	defer func() { _ = netns.DeleteNamed(otherName) }()
+	defer func() { _ = otherNetNS.Close() }()
+	require.NoError(t, netns.Set(curNetNS))
+
+	handle, err := netlink.NewHandleAt(targetNetNS)
+	require.NoError(t, err)
+	defer handle.Delete()
+	link, err := handle.LinkByName(ifName)
+	require.NoError(t, err)
+
+	// another goroutine keeps switching its OS thread net NS during the Requests
+	stop := make(chan struct{})
+	switched := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		// the thread is left locked, so it is terminated instead of being reused with the unexpected net NS
+		runtime.LockOSThread()
+		for i := 0; ; i++ {
+			if err := netns.Set(otherNetNS); err != nil {
+				done <- err
+				return
+			}
+			if i == 0 {
+				close(switched)
+			}
+			time.Sleep(time.Millisecond)
+			if err := netns.Set(curNetNS); err != nil {
+				done <- err
+				return
+			}
+			select {
+			case <-stop:
+				done <- nil
+				return
+			default:
+			}
+		}
+	}()
+	<-switched
+
+	server := ipcontext.NewServer()
+	for i := 0; i < 20; i++ {
+		req := request("10.0.5.1/24")
+		req.GetConnection().GetMechanism().GetParameters()[kernel.NetNSURL] =
+			(&url.URL{Scheme: "file", Path: filepath.Join("/run/netns", targetName)}).String()
+
+		conn, err := server.Request(context.TODO(), req)
+		require.NoError(t, err)
+
+		list, err := handle.AddrList(link, kernelconst.FamilyV4)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		require.Equal(t, "10.0.5.1/24", list[0].IPNet.String())
+
+		_, err = server.Close(context.TODO(), conn)
+		require.NoError(t, err)
+
+		list, err = handle.AddrList(link, kernelconst.FamilyV4)
+		require.NoError(t, err)
+		require.Empty(t, list)
+	}
+
+	close(stop)
+	require.NoError(t, <-done)
+
+	netNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = netNS.Close() }()
+	require.True(t, netNS.Equal(curNetNS))
+}
+
+func TestIPContextServer_RequestFailed(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	// the next chain element fails, so the added IP addresses should be rolled back
+	_, err := chain.NewNetworkServiceServer(
+		ipcontext.NewServer(),
+		injecterror.NewServer(),
+	).Request(context.TODO(), request("10.0.5.1/24"))
+	require.Error(t, err)
+	require.Empty(t, addrs(t, link))
+}
+
+func TestIPContextServer_NoIPAddr(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	// the net interface is set up even with no IP address
+	_, err := ipcontext.NewServer().Request(context.TODO(), request(""))
+	require.NoError(t, err)
+
+	link, err = netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	require.NotZero(t, link.Attrs().Flags&net.FlagUp)
+	require.Empty(t, addrs(t, link))
+}
+
+func TestIPContextServer_RefreshIdempotent(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	server := ipcontext.NewServer()
+
+	conn, err := server.Request(context.TODO(), request("10.0.6.1/24"))
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+		require.NoError(t, err)
+		require.Equal(t, []string{"10.0.6.1/24"}, addrs(t, link))
+	}
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, addrs(t, link))
+}
+
+func TestIPContextServer_IfIndexCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	server := ipcontext.NewServer(ipcontext.WithIfIndexCache(ifindex.NewCache(ctx)))
+
+	conn, err := server.Request(ctx, request("10.0.6.1/24"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.6.1/24"}, addrs(t, link))
+
+	// the net interface is recreated with the same name and the new index
+	require.NoError(t, netlink.LinkDel(link))
+	link = kerneltest.AddVeth(t, ifName, peerName)
+
+	conn, err = server.Request(ctx, &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.6.1/24"}, addrs(t, link))
+
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+	require.Empty(t, addrs(t, link))
+}
+
+func TestIPContextServer_NetNSGone(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	targetName := uuid.New().String()
+	targetNetNS, err := netns.NewNamed(targetName)
+	require.NoError(t, err)
+	defer func() { _ = netns.DeleteNamed(targetName) }()
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: ifName},
+		PeerName:  peerName,
+	}))
+	require.NoError(t, netns.Set(curNetNS))
+
+	req := request("10.0.7.1/24")
+	req.GetConnection().GetMechanism().GetParameters()[kernel.NetNSURL] = (&url.URL{
+		Scheme: "file",
+		Path:   filepath.Join("/run/netns", targetName),
+	}).String()
+
+	server := ipcontext.NewServer()
+
+	conn, err := server.Request(context.TODO(), req)
+	require.NoError(t, err)
+
+	// the Client's pod is deleted before the connection Close
+	_ = targetNetNS.Close()
+	require.NoError(t, netns.DeleteNamed(targetName))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
+
+func TestIPContextServer_DAD(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	peer, err := netlink.LinkByName(peerName)
+	require.NoError(t, err)
+	require.NoError(t, netlink.LinkSetUp(peer))
+
+	server := ipcontext.NewServer(ipcontext.WithDAD(5 * time.Second))
+
+	conn, err := server.Request(context.TODO(), request("fd00::1/64"))
+	require.NoError(t, err)
+
+	list, err := netlink.AddrList(link, kernelconst.FamilyV6)
+	require.NoError(t, err)
+	var found bool
+	for i := range list {
+		if list[i].IPNet.String() == "fd00::1/64" {
+			require.Zero(t, list[i].Flags&unix.IFA_F_TENTATIVE, "address should be usable when Request returns")
+			require.Zero(t, list[i].Flags&unix.IFA_F_NODAD)
+			found = true
+		}
+	}
+	require.True(t, found)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	// the peer already has the address
+	duplicate, err := netlink.ParseAddr("fd00::2/64")
+	require.NoError(t, err)
+	duplicate.Flags = unix.IFA_F_NODAD
+	require.NoError(t, netlink.AddrAdd(peer, duplicate))
+
+	_, err = server.Request(context.TODO(), request("fd00::2/64"))
+	require.Error(t, err)
+
+	list, err = netlink.AddrList(link, kernelconst.FamilyV6)
+	require.NoError(t, err)
+	for i := range list {
+		require.NotEqual(t, "fd00::2/64", list[i].IPNet.String())
+	}
+}
+
+func TestIPContextServer_Routes(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	routeDsts := func() []string {
+		list, err := netlink.RouteListFiltered(kernelconst.FamilyV4, &netlink.Route{
+			LinkIndex: link.Attrs().Index,
+			Protocol:  kernelconst.RouteProtoNSM,
+		}, netlink.RT_FILTER_OIF|netlink.RT_FILTER_PROTOCOL)
+		require.NoError(t, err)
+
+		var result []string
+		for i := range list {
+			result = append(result, list[i].Dst.String())
+		}
+		return result
+	}
+
+	req := request("10.0.6.1/32")
+	req.GetConnection().GetContext().GetIpContext().SrcRoutes = []*networkservice.Route{{Prefix: "10.0.6.2/32"}}
+
+	// routes are applied by default
+	conn, err := ipcontext.NewServer().Request(context.TODO(), req.Clone())
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.6.2/32"}, routeDsts())
+	_, err = ipcontext.NewServer().Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, routeDsts())
+
+	conn, err = ipcontext.NewServer(ipcontext.WithoutRoutes()).Request(context.TODO(), req.Clone())
+	require.NoError(t, err)
+	require.Empty(t, routeDsts())
+	_, err = ipcontext.NewServer(ipcontext.WithoutRoutes()).Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
+
+func TestIPContextServer_Neighbors(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	permanentNeighbors := func() int {
+		list, err := netlink.NeighList(link.Attrs().Index, kernelconst.FamilyV4)
+		require.NoError(t, err)
+
+		var result int
+		for i := range list {
+			if list[i].State == kernelconst.NudPermanent {
+				result++
+			}
+		}
+		return result
+	}
+
+	req := request("10.0.7.1/32")
+	req.GetConnection().GetContext().GetIpContext().IpNeighbors = []*networkservice.IpNeighbor{
+		{Ip: "10.0.7.2", HardwareAddress: "0a:00:00:00:07:02"},
+	}
+
+	// neighbors are added by default
+	conn, err := ipcontext.NewServer().Request(context.TODO(), req.Clone())
+	require.NoError(t, err)
+	require.Equal(t, 1, permanentNeighbors())
+	_, err = ipcontext.NewServer().Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Zero(t, permanentNeighbors())
+
+	conn, err = ipcontext.NewServer(ipcontext.WithoutNeighbors()).Request(context.TODO(), req.Clone())
+	require.NoError(t, err)
+	require.Zero(t, permanentNeighbors())
+	_, err = ipcontext.NewServer(ipcontext.WithoutNeighbors()).Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
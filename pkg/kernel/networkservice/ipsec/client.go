@@ -0,0 +1,115 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/xfrmi"
+)
+
+type ipsecClient struct{}
+
+// NewClient returns a new client chain element programming the xfrm states and policies in the current net NS for the
+// connections with the selected IPsec mechanism. SPI and key of the SA from the Endpoint are generated and set to the
+// requested mechanism Src parameters before the Request, the SA to the Endpoint is taken from the selected mechanism
+// Dst parameters. If the xfrmi client chain element follows it, the SAs are bound to the connection xfrm net interface.
+// States and policies are deleted on Close.
+func NewClient() networkservice.NetworkServiceClient {
+	return &ipsecClient{}
+}
+
+func (c *ipsecClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	for _, mech := range ipsecMechanisms(request) {
+		if err := generate(mech, SrcSPI, SrcKey); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil || conn.GetMechanism().GetType() != MECHANISM {
+		return conn, err
+	}
+
+	ifID, _ := xfrmi.IfID(ctx, metadata.IsClient(c))
+	if err := c.apply(ctx, conn, ifID); err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	store(ctx, metadata.IsClient(c), ifID)
+
+	return conn, nil
+}
+
+func (c *ipsecClient) apply(ctx context.Context, conn *networkservice.Connection, ifID uint32) error {
+	out, in, err := sas(conn.GetMechanism(), metadata.IsClient(c))
+	if err != nil {
+		return err
+	}
+	if err = apply(out, in, ifID); err != nil {
+		// the states and policies are still used by the connection on the failed refresh
+		if !load(ctx, metadata.IsClient(c)) {
+			if removeErr := remove(out, in, ifID); removeErr != nil {
+				log.Entry(ctx).WithField("ipsecClient", "Request").Warnf("failed to delete xfrm states and policies: %s", removeErr.Error())
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// ipsecMechanisms returns the requested IPsec mechanisms to publish the Src parameters to: the mechanism preferences
+// and the already selected mechanism on refresh
+func ipsecMechanisms(request *networkservice.NetworkServiceRequest) []*networkservice.Mechanism {
+	var mechs []*networkservice.Mechanism
+	if mech := request.GetConnection().GetMechanism(); mech.GetType() == MECHANISM {
+		mechs = append(mechs, mech)
+	}
+	for _, mech := range request.GetMechanismPreferences() {
+		if mech.GetType() == MECHANISM {
+			mechs = append(mechs, mech)
+		}
+	}
+	return mechs
+}
+
+func (c *ipsecClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var removeErr error
+	if ifID, ok := loadAndDelete(ctx, metadata.IsClient(c)); ok {
+		removeErr = removeSAs(conn.GetMechanism(), metadata.IsClient(c), ifID)
+	}
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
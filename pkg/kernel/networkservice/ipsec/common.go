@@ -0,0 +1,193 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/common"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/xfrmi"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ipaddrs"
+)
+
+// IPsec mechanism parameters. Each side generates SPI and key of the SA it receives the traffic with.
+const (
+	// MECHANISM is the IPsec mechanism type
+	MECHANISM = xfrmi.MECHANISM
+
+	// SrcIP is the Client side tunnel IP address parameter key
+	SrcIP = common.SrcIP
+	// DstIP is the Endpoint side tunnel IP address parameter key
+	DstIP = common.DstIP
+	// SrcSPI is the SPI parameter key of the SA from the Endpoint to the Client
+	SrcSPI = "srcSpi"
+	// SrcKey is the hex encoded key parameter key of the SA from the Endpoint to the Client
+	SrcKey = "srcKey"
+	// DstSPI is the SPI parameter key of the SA from the Client to the Endpoint
+	DstSPI = "dstSpi"
+	// DstKey is the hex encoded key parameter key of the SA from the Client to the Endpoint
+	DstKey = "dstKey"
+
+	// AEADName is the ESP AEAD algorithm: AES-GCM with 16 bytes ICV
+	AEADName = "rfc4106(gcm(aes))"
+	// KeyLen is the AEAD key length: 32 bytes AES-256 key and 4 bytes salt
+	KeyLen = 36
+
+	icvLen = 128
+	minSPI = 0x100
+)
+
+// generate sets a new random SPI and key to the mechanism parameters if they are not set yet, so the SA is kept on
+// refresh
+func generate(mech *networkservice.Mechanism, spiKey, keyKey string) error {
+	if mech.GetParameters()[spiKey] != "" && mech.GetParameters()[keyKey] != "" {
+		return nil
+	}
+
+	buf := make([]byte, 4+KeyLen)
+	if _, err := rand.Read(buf); err != nil {
+		return errors.Wrap(err, "failed to generate IPsec key")
+	}
+	spi := binary.BigEndian.Uint32(buf) | minSPI
+
+	if mech.Parameters == nil {
+		mech.Parameters = make(map[string]string)
+	}
+	mech.GetParameters()[spiKey] = strconv.FormatUint(uint64(spi), 10)
+	mech.GetParameters()[keyKey] = hex.EncodeToString(buf[4:])
+	return nil
+}
+
+// sa is the IPsec security association parameters of one direction
+type sa struct {
+	src, dst net.IP
+	spi      int
+	key      []byte
+}
+
+// sas returns the outbound and inbound SAs of the connection. Server side is the Endpoint of the mechanism, so its
+// local IP is the mechanism Dst IP.
+func sas(mech *networkservice.Mechanism, isClient bool) (out, in *sa, err error) {
+	toDst, err := parseSA(mech, SrcIP, DstIP, DstSPI, DstKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	toSrc, err := parseSA(mech, DstIP, SrcIP, SrcSPI, SrcKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if isClient {
+		return toDst, toSrc, nil
+	}
+	return toSrc, toDst, nil
+}
+
+func parseSA(mech *networkservice.Mechanism, srcKey, dstKey, spiKey, keyKey string) (*sa, error) {
+	params := mech.GetParameters()
+	src, dst := net.ParseIP(params[srcKey]), net.ParseIP(params[dstKey])
+	if src == nil || dst == nil || ipaddrs.IsIPv4(src) != ipaddrs.IsIPv4(dst) {
+		return nil, errors.Errorf("IPsec mechanism has no local or remote IP: %v", params)
+	}
+	spi, err := strconv.ParseUint(params[spiKey], 10, 32)
+	if err != nil || spi < minSPI {
+		return nil, errors.Errorf("invalid IPsec SPI: %v", params[spiKey])
+	}
+	key, err := hex.DecodeString(params[keyKey])
+	if err != nil || len(key) != KeyLen {
+		return nil, errors.Errorf("invalid IPsec key, %d bytes hex string is expected", KeyLen)
+	}
+	return &sa{src: src, dst: dst, spi: int(spi), key: key}, nil
+}
+
+// removeSAs deletes the xfrm states and policies of the connection mechanism from the current net NS
+func removeSAs(mech *networkservice.Mechanism, isClient bool, ifID uint32) error {
+	out, in, err := sas(mech, isClient)
+	if err != nil {
+		return err
+	}
+	return remove(out, in, ifID)
+}
+
+func newState(s *sa, ifID uint32) *netlink.XfrmState {
+	mode := netlink.XFRM_MODE_TRANSPORT
+	if ifID != 0 {
+		mode = netlink.XFRM_MODE_TUNNEL
+	}
+	return &netlink.XfrmState{
+		Src:   s.src,
+		Dst:   s.dst,
+		Proto: netlink.XFRM_PROTO_ESP,
+		Mode:  mode,
+		Spi:   s.spi,
+		Reqid: s.spi,
+		Ifid:  int(ifID),
+		Aead: &netlink.XfrmStateAlgo{
+			Name:   AEADName,
+			Key:    s.key,
+			ICVLen: icvLen,
+		},
+	}
+}
+
+func newPolicies(out, in *sa, ifID uint32) []*netlink.XfrmPolicy {
+	mode := netlink.XFRM_MODE_TRANSPORT
+	local := &net.IPNet{IP: out.src, Mask: ipaddrs.HostMask(out.src)}
+	remote := &net.IPNet{IP: out.dst, Mask: ipaddrs.HostMask(out.dst)}
+	if ifID != 0 {
+		mode = netlink.XFRM_MODE_TUNNEL
+		local = &net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, net.IPv4len*8)}
+		if !ipaddrs.IsIPv4(out.src) {
+			local = &net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, net.IPv6len*8)}
+		}
+		remote = local
+	}
+
+	newPolicy := func(src, dst *net.IPNet, dir netlink.Dir, s *sa) *netlink.XfrmPolicy {
+		return &netlink.XfrmPolicy{
+			Src:  src,
+			Dst:  dst,
+			Dir:  dir,
+			Ifid: int(ifID),
+			Tmpls: []netlink.XfrmPolicyTmpl{{
+				Src:   s.src,
+				Dst:   s.dst,
+				Proto: netlink.XFRM_PROTO_ESP,
+				Mode:  mode,
+				Reqid: s.spi,
+			}},
+		}
+	}
+
+	policies := []*netlink.XfrmPolicy{
+		newPolicy(local, remote, netlink.XFRM_DIR_OUT, out),
+		newPolicy(remote, local, netlink.XFRM_DIR_IN, in),
+	}
+	if ifID != 0 {
+		// the traffic routed via the xfrm net interface is the forwarded one
+		policies = append(policies, newPolicy(remote, local, netlink.XFRM_DIR_FWD, in))
+	}
+	return policies
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ipsec provides chain elements programming kernel xfrm states and policies for the remote connections, so
+// the kernel Forwarder has a native ESP encrypted remote mechanism. Combined with the xfrmi chain elements, the IPsec
+// protected traffic is routed via the xfrm net interface.
+package ipsec
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/xfrmi"
+)
+
+type ipsecServer struct{}
+
+// NewServer returns a new server chain element programming the xfrm states and policies in the current net NS for the
+// connections with the IPsec mechanism. SPI and key of the SA from the Client are generated and set to the mechanism
+// Dst parameters, the SA to the Client is taken from the mechanism Src parameters. If the xfrmi server chain element
+// precedes it, the SAs are bound to the connection xfrm net interface. States and policies are deleted on Close.
+func NewServer() networkservice.NetworkServiceServer {
+	return &ipsecServer{}
+}
+
+func (s *ipsecServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	mech := request.GetConnection().GetMechanism()
+	if mech.GetType() != MECHANISM {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	if err := generate(mech, DstSPI, DstKey); err != nil {
+		return nil, err
+	}
+	out, in, err := sas(mech, metadata.IsClient(s))
+	if err != nil {
+		return nil, err
+	}
+	ifID, _ := xfrmi.IfID(ctx, metadata.IsClient(s))
+	if err = apply(out, in, ifID); err != nil {
+		s.removeOnFailure(ctx, out, in, ifID)
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		s.removeOnFailure(ctx, out, in, ifID)
+		return nil, err
+	}
+
+	store(ctx, metadata.IsClient(s), ifID)
+
+	return conn, nil
+}
+
+// removeOnFailure deletes the states and policies on the failed first Request, they are still used by the connection
+// on the failed refresh
+func (s *ipsecServer) removeOnFailure(ctx context.Context, out, in *sa, ifID uint32) {
+	if load(ctx, metadata.IsClient(s)) {
+		return
+	}
+	if err := remove(out, in, ifID); err != nil {
+		log.Entry(ctx).WithField("ipsecServer", "Request").Warnf("failed to delete xfrm states and policies: %s", err.Error())
+	}
+}
+
+func (s *ipsecServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var removeErr error
+	if ifID, ok := loadAndDelete(ctx, metadata.IsClient(s)); ok {
+		removeErr = removeSAs(conn.GetMechanism(), metadata.IsClient(s), ifID)
+	}
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec_test
+
+import (
+	"context"
+	"encoding/hex"
+	"syscall"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipsec"
+)
+
+const (
+	srcIP = "172.16.5.1"
+	dstIP = "172.16.5.2"
+)
+
+func skipUnsupported(t *testing.T, err error) {
+	if cause := errors.Cause(err); cause == syscall.ENOSYS || cause == syscall.EPROTONOSUPPORT {
+		t.Skip("xfrm ESP is not supported by the kernel")
+	}
+}
+
+func request() *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "ipsec-conn",
+			Mechanism: &networkservice.Mechanism{
+				Type: ipsec.MECHANISM,
+				Parameters: map[string]string{
+					ipsec.SrcIP:  srcIP,
+					ipsec.DstIP:  dstIP,
+					ipsec.SrcSPI: "4096",
+					ipsec.SrcKey: hex.EncodeToString(make([]byte, ipsec.KeyLen)),
+				},
+			},
+		},
+	}
+}
+
+func TestIPsecServer(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ipsec.NewServer(),
+	)
+
+	req := request()
+	conn, err := server.Request(context.TODO(), req)
+	skipUnsupported(t, err)
+	require.NoError(t, err)
+
+	dstSPI := conn.GetMechanism().GetParameters()[ipsec.DstSPI]
+	require.NotEmpty(t, dstSPI)
+
+	states, err := netlink.XfrmStateList(netlink.FAMILY_V4)
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+	policies, err := netlink.XfrmPolicyList(netlink.FAMILY_V4)
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+
+	// refresh keeps the SAs
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Equal(t, dstSPI, conn.GetMechanism().GetParameters()[ipsec.DstSPI])
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	states, err = netlink.XfrmStateList(netlink.FAMILY_V4)
+	require.NoError(t, err)
+	require.Empty(t, states)
+}
+
+func TestIPsecServer_InvalidKey(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ipsec.NewServer(),
+	)
+
+	req := request()
+	req.GetConnection().GetMechanism().GetParameters()[ipsec.SrcKey] = "abcd"
+	_, err := server.Request(context.TODO(), req)
+	require.Error(t, err)
+
+	req = request()
+	req.GetConnection().GetMechanism().GetParameters()[ipsec.SrcSPI] = "1"
+	_, err = server.Request(context.TODO(), req)
+	require.Error(t, err)
+}
+
+// selectKernel is the next chain element selecting the kernel mechanism
+type selectKernel struct {
+	preferences []*networkservice.Mechanism
+}
+
+func (s *selectKernel) Request(_ context.Context, request *networkservice.NetworkServiceRequest, _ ...grpc.CallOption) (*networkservice.Connection, error) {
+	s.preferences = request.GetMechanismPreferences()
+	conn := request.GetConnection().Clone()
+	conn.Mechanism = &networkservice.Mechanism{Type: kernel.MECHANISM}
+	return conn, nil
+}
+
+func (s *selectKernel) Close(context.Context, *networkservice.Connection, ...grpc.CallOption) (*empty.Empty, error) {
+	return new(empty.Empty), nil
+}
+
+func TestIPsecClient_Parameters(t *testing.T) {
+	next := new(selectKernel)
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		ipsec.NewClient(),
+		next,
+	)
+
+	conn, err := client.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "ipsec-conn"},
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Type: ipsec.MECHANISM},
+			{Type: kernel.MECHANISM},
+		},
+	})
+	require.NoError(t, err)
+
+	params := next.preferences[0].GetParameters()
+	require.NotEmpty(t, params[ipsec.SrcSPI])
+	key, err := hex.DecodeString(params[ipsec.SrcKey])
+	require.NoError(t, err)
+	require.Len(t, key, ipsec.KeyLen)
+	require.Empty(t, next.preferences[1].GetParameters())
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+)
+
+// apply programs the connection xfrm states and policies in the current net NS. If the connection has the xfrm
+// interface ID, the states are the tunnel mode ones and policies match any traffic routed via the xfrm net interface,
+// otherwise the states are the transport mode ones and policies match the traffic between the local and remote IPs.
+func apply(out, in *sa, ifID uint32) error {
+	for _, s := range []*sa{out, in} {
+		state := newState(s, ifID)
+		if err := netlink.XfrmStateUpdate(state); err != nil {
+			return errors.Wrapf(err, "failed to add xfrm state: %v -> %v 0x%x", s.src, s.dst, s.spi)
+		}
+	}
+	for _, policy := range newPolicies(out, in, ifID) {
+		if err := netlink.XfrmPolicyUpdate(policy); err != nil {
+			return errors.Wrapf(err, "failed to add xfrm policy: %v", policy)
+		}
+	}
+	return nil
+}
+
+// remove deletes the connection xfrm states and policies from the current net NS, already deleted ones are skipped
+func remove(out, in *sa, ifID uint32) error {
+	for _, policy := range newPolicies(out, in, ifID) {
+		if err := netlink.XfrmPolicyDel(policy); err != nil && err != syscall.ENOENT {
+			return errors.Wrapf(err, "failed to delete xfrm policy: %v", policy)
+		}
+	}
+	for _, s := range []*sa{out, in} {
+		if err := netlink.XfrmStateDel(newState(s, ifID)); err != nil && err != syscall.ESRCH {
+			return errors.Wrapf(err, "failed to delete xfrm state: %v -> %v 0x%x", s.src, s.dst, s.spi)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iptun
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/common"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/tunnellink"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/tunnel"
+)
+
+// IP tunnel mechanism. There is no IPIP mechanism in the used API version, so it is defined here with the common
+// Src/Dst IP parameters.
+const (
+	// MECHANISM is the IP tunnel mechanism type
+	MECHANISM = "IPIP"
+
+	// SrcIP is the Client side tunnel IPv4 address parameter key
+	SrcIP = common.SrcIP
+	// DstIP is the Endpoint side tunnel IPv4 address parameter key
+	DstIP = common.DstIP
+	// ModeKey is the tunnel mode parameter key: ModeIPIP or ModeSIT, default is ModeIPIP
+	ModeKey = "tunnelMode"
+
+	// ModeIPIP is the IPv4-in-IPv4 tunnel mode
+	ModeIPIP = "ipip"
+	// ModeSIT is the IPv6-in-IPv4 tunnel mode
+	ModeSIT = "sit"
+
+	linkPrefix = "nsmip"
+)
+
+// LinkName returns the connection IP tunnel net interface name in the Forwarder's net NS
+func LinkName(conn *networkservice.Connection) string {
+	return linkprovider.LinkName(linkPrefix, conn)
+}
+
+// linker is the common part of the iptun client and server
+type linker struct {
+	tunnelOptions []tunnel.Option
+}
+
+func newLinker(options []Option) *linker {
+	l := &linker{}
+	for _, opt := range options {
+		opt(l)
+	}
+	return l
+}
+
+// newLink returns the IP tunnel net interface description for the connection, it returns nil if the connection has no
+// IP tunnel mechanism. Server side is the Endpoint of the mechanism, so its local IP is the mechanism Dst IP.
+func (l *linker) newLink(conn *networkservice.Connection, isClient bool) (netlink.Link, error) {
+	mech := conn.GetMechanism()
+	if mech.GetType() != MECHANISM {
+		return nil, nil
+	}
+
+	local, remote := net.ParseIP(mech.GetParameters()[DstIP]).To4(), net.ParseIP(mech.GetParameters()[SrcIP]).To4()
+	if isClient {
+		local, remote = remote, local
+	}
+	if local == nil || remote == nil {
+		return nil, errors.Errorf("IP tunnel mechanism has no local or remote IPv4 address: %v", mech.GetParameters())
+	}
+
+	attrs := netlink.LinkAttrs{Name: LinkName(conn)}
+	var link netlink.Link
+	switch mode := mech.GetParameters()[ModeKey]; mode {
+	case "", ModeIPIP:
+		link = &netlink.Iptun{LinkAttrs: attrs, Local: local, Remote: remote}
+	case ModeSIT:
+		link = &netlink.Sittun{LinkAttrs: attrs, Local: local, Remote: remote}
+	default:
+		return nil, errors.Errorf("invalid IP tunnel mode: %v", mode)
+	}
+
+	if err := tunnel.Apply(link, l.tunnelOptions...); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// kind returns the IP tunnel part of the tunnel chain elements
+func (l *linker) kind() *tunnellink.Kind {
+	return &tunnellink.Kind{
+		Name:     "IP tunnel",
+		LinkName: LinkName,
+		NewLink:  l.newLink,
+		SameLink: sameLink,
+	}
+}
+
+func sameLink(existing, expected netlink.Link) bool {
+	switch expected := expected.(type) {
+	case *netlink.Iptun:
+		existing, ok := existing.(*netlink.Iptun)
+		return ok && existing.Local.Equal(expected.Local) && existing.Remote.Equal(expected.Remote)
+	case *netlink.Sittun:
+		existing, ok := existing.(*netlink.Sittun)
+		return ok && existing.Local.Equal(expected.Local) && existing.Remote.Equal(expected.Remote)
+	}
+	return false
+}
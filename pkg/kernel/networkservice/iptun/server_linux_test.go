@@ -0,0 +1,117 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iptun_test
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/iptun"
+)
+
+var (
+	srcIP = net.ParseIP("172.16.4.1").To4()
+	dstIP = net.ParseIP("172.16.4.2").To4()
+)
+
+func request(mode string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "iptun-conn",
+			Mechanism: &networkservice.Mechanism{
+				Type: iptun.MECHANISM,
+				Parameters: map[string]string{
+					iptun.SrcIP:   srcIP.String(),
+					iptun.DstIP:   dstIP.String(),
+					iptun.ModeKey: mode,
+				},
+			},
+		},
+	}
+}
+
+func TestIPTunClient(t *testing.T) {
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		iptun.NewClient(),
+	)
+
+	conn, err := client.Request(context.TODO(), request(iptun.ModeIPIP))
+	if errors.Cause(err) == syscall.EOPNOTSUPP {
+		t.Skip("ipip is not supported by the kernel")
+	}
+	require.NoError(t, err)
+
+	link, err := netlink.LinkByName(iptun.LinkName(conn))
+	require.NoError(t, err)
+	require.IsType(t, new(netlink.Iptun), link)
+	require.True(t, link.(*netlink.Iptun).Local.Equal(srcIP))
+	require.True(t, link.(*netlink.Iptun).Remote.Equal(dstIP))
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	_, err = netlink.LinkByName(iptun.LinkName(conn))
+	require.Error(t, err)
+}
+
+func TestIPTunServer_SIT(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		iptun.NewServer(),
+	)
+
+	conn, err := server.Request(context.TODO(), request(iptun.ModeSIT))
+	if errors.Cause(err) == syscall.EOPNOTSUPP {
+		t.Skip("sit is not supported by the kernel")
+	}
+	require.NoError(t, err)
+
+	link, err := netlink.LinkByName(iptun.LinkName(conn))
+	require.NoError(t, err)
+	require.IsType(t, new(netlink.Sittun), link)
+	require.True(t, link.(*netlink.Sittun).Local.Equal(dstIP))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	_, err = netlink.LinkByName(iptun.LinkName(conn))
+	require.Error(t, err)
+}
+
+func TestIPTunServer_InvalidMechanism(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		iptun.NewServer(),
+	)
+
+	_, err := server.Request(context.TODO(), request("gre"))
+	require.Error(t, err)
+
+	req := request(iptun.ModeIPIP)
+	req.GetConnection().GetMechanism().GetParameters()[iptun.SrcIP] = "fe80::1"
+	_, err = server.Request(context.TODO(), req)
+	require.Error(t, err)
+}
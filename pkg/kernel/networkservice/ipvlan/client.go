@@ -0,0 +1,30 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipvlan
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/inject"
+)
+
+// NewClient returns a new ipvlan client chain element creating ipvlan net interface on the given parent net interface
+// for the connection and moving it into the net NS selected by the returned connection mechanism on Request, the net
+// interface is deleted on Close
+func NewClient(parentName string, options ...Option) networkservice.NetworkServiceClient {
+	return inject.NewClient(injectOptions(parentName, options)...)
+}
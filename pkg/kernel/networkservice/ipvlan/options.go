@@ -0,0 +1,64 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipvlan
+
+import (
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/inject"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+)
+
+type ipvlanOptions struct {
+	mode          netlink.IPVlanMode
+	injectOptions []inject.Option
+}
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(o *ipvlanOptions)
+
+// WithMode sets ipvlan mode: netlink.IPVLAN_MODE_L2, netlink.IPVLAN_MODE_L3 or netlink.IPVLAN_MODE_L3S. Default is
+// netlink.IPVLAN_MODE_L3S.
+func WithMode(mode netlink.IPVlanMode) Option {
+	return func(o *ipvlanOptions) {
+		o.mode = mode
+	}
+}
+
+// WithInjectOptions sets options for the underlying inject chain element, e.g. inject.WithPreemptionPolicy. The link
+// provider is always the ipvlan one.
+func WithInjectOptions(options ...inject.Option) Option {
+	return func(o *ipvlanOptions) {
+		o.injectOptions = options
+	}
+}
+
+func newOptions(options []Option) *ipvlanOptions {
+	o := &ipvlanOptions{
+		mode: netlink.IPVLAN_MODE_L3S,
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
+
+func injectOptions(parentName string, options []Option) []inject.Option {
+	o := newOptions(options)
+	return append(append([]inject.Option{}, o.injectOptions...),
+		inject.WithLinkProvider(linkprovider.NewIPVlan(parentName, o.mode)))
+}
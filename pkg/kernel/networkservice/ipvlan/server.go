@@ -0,0 +1,32 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ipvlan provides chain elements creating ipvlan net interfaces on the parent net interface and moving them
+// into the Client's net NS. In the default L3S mode the Client traffic goes through the parent net NS netfilter, so
+// the host policy and conntrack still apply to it.
+package ipvlan
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/inject"
+)
+
+// NewServer returns a new ipvlan server chain element creating ipvlan net interface on the given parent net interface
+// for the connection and moving it into the Client's net NS on Request, the net interface is deleted on Close
+func NewServer(parentName string, options ...Option) networkservice.NetworkServiceServer {
+	return inject.NewServer(injectOptions(parentName, options)...)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipvlan_test
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipvlan"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+const (
+	netNSPath  = "/run/netns"
+	ifName     = "nsm-1"
+	parentName = "ipvlan-parent"
+)
+
+func TestIPVlanServer(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	kerneltest.AddVeth(t, parentName, parentName + "p")
+
+	netNSName := uuid.New().String()
+	clientNetNS, err := netns.NewNamed(netNSName)
+	require.NoError(t, err)
+	require.NoError(t, netns.Set(curNetNS))
+	defer func() {
+		_ = clientNetNS.Close()
+		_ = netns.DeleteNamed(netNSName)
+	}()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ipvlan.NewServer(parentName),
+	)
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: uuid.New().String(),
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.NetNSURL:         (&url.URL{Scheme: "file", Path: path.Join(netNSPath, netNSName)}).String(),
+					kernel.InterfaceNameKey: ifName,
+				},
+			},
+		},
+	})
+	if errors.Cause(err) == syscall.EOPNOTSUPP {
+		t.Skip("ipvlan net interfaces are not supported by the kernel")
+	}
+	require.NoError(t, err)
+
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		link, linkErr := netlink.LinkByName(ifName)
+		if linkErr != nil {
+			return linkErr
+		}
+		ipvlanLink, ok := link.(*netlink.IPVlan)
+		require.True(t, ok)
+		require.Equal(t, netlink.IPVLAN_MODE_L3S, ipvlanLink.Mode)
+		return nil
+	}))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	require.Error(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		_, linkErr := netlink.LinkByName(ifName)
+		return linkErr
+	}))
+}
+
+func TestIPVlanServer_NoParent(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		ipvlan.NewServer("not-exists", ipvlan.WithMode(netlink.IPVLAN_MODE_L2)),
+	)
+
+	_, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: uuid.New().String(),
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.NetNSURL:         "file:///proc/self/ns/net",
+					kernel.InterfaceNameKey: ifName,
+				},
+			},
+		},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keepalive
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type keepaliveClient struct {
+	*generator
+}
+
+// NewClient returns a new keepalive client chain element. For the connections with the selected VXLAN or GRE mechanism
+// it sends the keepalive packets from the local to the remote tunnel IP each interval via the raw socket opened in the
+// current net NS. Keepalive is stopped on Close.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &keepaliveClient{
+		generator: newGenerator(options),
+	}
+}
+
+func (c *keepaliveClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := c.newPacket(conn, metadata.IsClient(c))
+	if err == nil && p == nil {
+		return conn, nil
+	}
+	var sock socket
+	if err == nil {
+		sock, err = openSocket(p)
+	}
+	if err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	store(ctx, metadata.IsClient(c), c.run(ctx, sock, p, "keepaliveClient"))
+
+	return conn, nil
+}
+
+func (c *keepaliveClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	stop(ctx, metadata.IsClient(c))
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
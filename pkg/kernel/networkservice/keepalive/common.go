@@ -0,0 +1,184 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keepalive
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/common"
+	vxlanmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/vxlan"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/gre"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vxlan"
+)
+
+const (
+	defaultInterval = 25 * time.Second
+
+	protoUDP = 17
+	protoGRE = 47
+
+	vxlanFlagVNI     = 0x08
+	greFlagKey       = 0x2000
+	greProtoIPv4     = 0x0800
+	greProtoEthernet = 0x6558
+)
+
+// packet is a keepalive packet of the connection tunnel, payload is sent in the IP packet with proto protocol
+type packet struct {
+	local, remote net.IP
+	proto         int
+	payload       []byte
+}
+
+// generator is the common part of the keepalive client and server
+type generator struct {
+	interval time.Duration
+	srcPort  int
+}
+
+func newGenerator(options []Option) *generator {
+	g := &generator{
+		interval: defaultInterval,
+	}
+	for _, opt := range options {
+		opt(g)
+	}
+	return g
+}
+
+// newPacket returns the keepalive packet for the connection tunnel, it returns nil if the connection has no VXLAN or
+// GRE mechanism. Keepalive packets look like the tunnel packets with no inner packet, so they create and refresh the
+// same NAT mappings as the tunnel traffic, but are dropped by the remote side.
+func (g *generator) newPacket(conn *networkservice.Connection, isClient bool) (*packet, error) {
+	mech := conn.GetMechanism()
+	var local, remote net.IP
+	switch mech.GetType() {
+	case vxlanmech.MECHANISM, gre.MECHANISM:
+		local, remote = net.ParseIP(mech.GetParameters()[common.DstIP]), net.ParseIP(mech.GetParameters()[common.SrcIP])
+	default:
+		return nil, nil
+	}
+	// server side is the Endpoint of the mechanism, so its local IP is the mechanism Dst IP
+	if isClient {
+		local, remote = remote, local
+	}
+	if local == nil || remote == nil {
+		return nil, errors.Errorf("%s mechanism has no local or remote IP: %v", mech.GetType(), mech.GetParameters())
+	}
+
+	if mech.GetType() == vxlanmech.MECHANISM {
+		payload, err := g.vxlanPayload(vxlanmech.ToMechanism(mech))
+		if err != nil {
+			return nil, err
+		}
+		return &packet{local: local, remote: remote, proto: protoUDP, payload: payload}, nil
+	}
+	payload, err := grePayload(mech)
+	if err != nil {
+		return nil, err
+	}
+	return &packet{local: local, remote: remote, proto: protoGRE, payload: payload}, nil
+}
+
+// vxlanPayload returns UDP header with VXLAN header. UDP checksum is optional for IPv4 and is computed by the kernel
+// for IPv6.
+func (g *generator) vxlanPayload(mech *vxlanmech.Mechanism) ([]byte, error) {
+	dstPort := vxlan.DefaultDstPort
+	if value := mech.GetParameters()[vxlan.DstPortKey]; value != "" {
+		parsed, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid VXLAN destination port: %v", value)
+		}
+		dstPort = int(parsed)
+	}
+	srcPort := g.srcPort
+	if srcPort == 0 {
+		srcPort = dstPort
+	}
+
+	payload := make([]byte, 16)
+	binary.BigEndian.PutUint16(payload[0:], uint16(srcPort))
+	binary.BigEndian.PutUint16(payload[2:], uint16(dstPort))
+	binary.BigEndian.PutUint16(payload[4:], uint16(len(payload)))
+	payload[8] = vxlanFlagVNI
+	binary.BigEndian.PutUint32(payload[12:], mech.VNI()<<8)
+	return payload, nil
+}
+
+// grePayload returns GRE header with the mechanism GRE key
+func grePayload(mech *networkservice.Mechanism) ([]byte, error) {
+	proto := uint16(greProtoIPv4)
+	if mech.GetParameters()[gre.ModeKey] == gre.ModeGRETAP {
+		proto = greProtoEthernet
+	}
+
+	value := mech.GetParameters()[gre.KeyKey]
+	if value == "" {
+		payload := make([]byte, 4)
+		binary.BigEndian.PutUint16(payload[2:], proto)
+		return payload, nil
+	}
+
+	key, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid GRE key: %v", value)
+	}
+	payload := make([]byte, 8)
+	binary.BigEndian.PutUint16(payload[0:], greFlagKey)
+	binary.BigEndian.PutUint16(payload[2:], proto)
+	binary.BigEndian.PutUint32(payload[4:], uint32(key))
+	return payload, nil
+}
+
+// run starts sending the keepalive packet via the socket each interval until the returned cancel function is called,
+// the socket is closed then
+func (g *generator) run(ctx context.Context, s socket, p *packet, logField string) context.CancelFunc {
+	logEntry := log.Entry(ctx).WithField(logField, "keepalive")
+	sendCtx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer func() { _ = s.Close() }()
+
+		ticker := time.NewTicker(g.interval)
+		defer ticker.Stop()
+		for {
+			if err := s.Send(p.payload); err != nil {
+				logEntry.Warnf("failed to send keepalive packet: %v -> %v: %s", p.local, p.remote, err.Error())
+			}
+			select {
+			case <-sendCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return cancel
+}
+
+// socket is the raw IP socket connected to the remote side
+type socket interface {
+	Send(payload []byte) error
+	Close() error
+}
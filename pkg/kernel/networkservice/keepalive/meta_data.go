@@ -0,0 +1,40 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keepalive
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type keyType struct{}
+
+// store stores the function stopping the connection keepalive, the previous keepalive is stopped
+func store(ctx context.Context, isClient bool, cancel context.CancelFunc) {
+	if previous, ok := metadata.Map(ctx, isClient).LoadAndDelete(keyType{}); ok {
+		previous.(context.CancelFunc)()
+	}
+	metadata.Map(ctx, isClient).Store(keyType{}, cancel)
+}
+
+// stop stops the connection keepalive
+func stop(ctx context.Context, isClient bool) {
+	if cancel, ok := metadata.Map(ctx, isClient).LoadAndDelete(keyType{}); ok {
+		cancel.(context.CancelFunc)()
+	}
+}
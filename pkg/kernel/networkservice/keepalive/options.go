@@ -0,0 +1,40 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keepalive
+
+import (
+	"time"
+)
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(g *generator)
+
+// WithInterval sets the keepalive packets interval, it should be less than the NAT UDP mapping timeout. Default is
+// 25 seconds.
+func WithInterval(interval time.Duration) Option {
+	return func(g *generator) {
+		g.interval = interval
+	}
+}
+
+// WithSrcPort sets the UDP source port of the VXLAN keepalive packets. Default is the VXLAN destination port, it
+// matches the tunnel traffic if the VXLAN net interfaces source port range is the destination port only.
+func WithSrcPort(port int) Option {
+	return func(g *generator) {
+		g.srcPort = port
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package keepalive provides chain elements sending keepalive packets of the kernel VXLAN and GRE tunnels, so the NAT
+// mappings of the tunnels traversing NATs don't expire when there is no connection traffic. WireGuard has native
+// persistent keepalive and doesn't need it.
+package keepalive
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type keepaliveServer struct {
+	*generator
+}
+
+// NewServer returns a new keepalive server chain element. For the connections with the VXLAN or GRE mechanism it sends
+// the keepalive packets from the local to the remote tunnel IP each interval via the raw socket opened in the current
+// net NS. Keepalive is stopped on Close.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &keepaliveServer{
+		generator: newGenerator(options),
+	}
+}
+
+func (s *keepaliveServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	p, err := s.newPacket(request.GetConnection(), metadata.IsClient(s))
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	// the socket is opened in the current net NS before the next elements may change it
+	sock, err := openSocket(p)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		_ = sock.Close()
+		return nil, err
+	}
+
+	store(ctx, metadata.IsClient(s), s.run(ctx, sock, p, "keepaliveServer"))
+
+	return conn, nil
+}
+
+func (s *keepaliveServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	stop(ctx, metadata.IsClient(s))
+	return next.Server(ctx).Close(ctx, conn)
+}
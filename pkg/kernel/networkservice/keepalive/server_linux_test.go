@@ -0,0 +1,151 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keepalive_test
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/common"
+	vxlanmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/vxlan"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/gre"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/keepalive"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vxlan"
+)
+
+const (
+	interval = 50 * time.Millisecond
+	srcIP    = "127.0.0.2"
+	dstIP    = "127.0.0.1"
+)
+
+func request(mechType string, parameters map[string]string) *networkservice.NetworkServiceRequest {
+	parameters[common.SrcIP] = srcIP
+	parameters[common.DstIP] = dstIP
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "keepalive-conn",
+			Mechanism: &networkservice.Mechanism{
+				Type:       mechType,
+				Parameters: parameters,
+			},
+		},
+	}
+}
+
+func TestKeepaliveServer_VXLAN(t *testing.T) {
+	// server side remote IP is the mechanism Src IP
+	listener, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.ParseIP(srcIP), Port: 47890})
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		keepalive.NewServer(keepalive.WithInterval(interval)),
+	)
+
+	conn, err := server.Request(context.TODO(), request(vxlanmech.MECHANISM, map[string]string{
+		vxlanmech.VNI:    "42",
+		vxlan.DstPortKey: "47890",
+	}))
+	require.NoError(t, err)
+
+	buf := make([]byte, 1500)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, listener.SetReadDeadline(time.Now().Add(time.Second)))
+		n, from, err := listener.ReadFromUDP(buf)
+		require.NoError(t, err)
+		require.Equal(t, 8, n)
+		require.Equal(t, 47890, from.Port)
+		require.True(t, from.IP.Equal(net.ParseIP(dstIP)))
+		require.Equal(t, uint32(42), binary.BigEndian.Uint32(buf[4:])>>8)
+	}
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	// drain the packets sent before Close
+	time.Sleep(interval)
+	require.NoError(t, listener.SetReadDeadline(time.Now().Add(interval)))
+	for err == nil {
+		_, _, err = listener.ReadFromUDP(buf)
+	}
+	require.NoError(t, listener.SetReadDeadline(time.Now().Add(3*interval)))
+	_, _, err = listener.ReadFromUDP(buf)
+	require.Error(t, err)
+}
+
+func TestKeepaliveClient_GRE(t *testing.T) {
+	// client side remote IP is the mechanism Dst IP
+	listener, err := net.ListenIP("ip4:gre", &net.IPAddr{IP: net.ParseIP(dstIP)})
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		keepalive.NewClient(keepalive.WithInterval(interval)),
+	)
+
+	conn, err := client.Request(context.TODO(), request(gre.MECHANISM, map[string]string{
+		gre.KeyKey: "7",
+	}))
+	require.NoError(t, err)
+
+	buf := make([]byte, 1500)
+	for {
+		require.NoError(t, listener.SetReadDeadline(time.Now().Add(time.Second)))
+		n, from, err := listener.ReadFromIP(buf)
+		require.NoError(t, err)
+		// raw socket gets all GRE packets on the host
+		if !from.IP.Equal(net.ParseIP(srcIP)) {
+			continue
+		}
+		require.Equal(t, 8, n)
+		require.Equal(t, uint32(7), binary.BigEndian.Uint32(buf[4:]))
+		break
+	}
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
+
+func TestKeepaliveServer_InvalidMechanism(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		keepalive.NewServer(),
+	)
+
+	req := request(gre.MECHANISM, map[string]string{
+		gre.KeyKey: "invalid",
+	})
+	_, err := server.Request(context.TODO(), req)
+	require.Error(t, err)
+
+	req.GetConnection().GetMechanism().GetParameters()[gre.KeyKey] = "7"
+	delete(req.GetConnection().GetMechanism().GetParameters(), common.SrcIP)
+	_, err = server.Request(context.TODO(), req)
+	require.Error(t, err)
+}
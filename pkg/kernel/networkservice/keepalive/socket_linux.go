@@ -0,0 +1,86 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keepalive
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+type rawSocket int
+
+// openSocket opens the raw IP socket bound to the local IP and connected to the remote IP. The socket never receives
+// packets: it is a raw socket of the tunnel protocol, so it gets a copy of every tunnel packet without the filter.
+func openSocket(p *packet) (socket, error) {
+	family := unix.AF_INET
+	if p.local.To4() == nil {
+		family = unix.AF_INET6
+	}
+
+	fd, err := unix.Socket(family, unix.SOCK_RAW|unix.SOCK_CLOEXEC, p.proto)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open keepalive socket")
+	}
+	s := rawSocket(fd)
+
+	if err := unix.SetsockoptSockFprog(fd, unix.SOL_SOCKET, unix.SO_ATTACH_FILTER, &unix.SockFprog{
+		Len:    1,
+		Filter: &unix.SockFilter{Code: unix.BPF_RET | unix.BPF_K, K: 0},
+	}); err != nil {
+		_ = s.Close()
+		return nil, errors.Wrap(err, "failed to set keepalive socket drop filter")
+	}
+	if family == unix.AF_INET6 && p.proto == protoUDP {
+		// kernel computes UDP checksum at the given offset, it is mandatory for IPv6
+		if err := unix.SetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_CHECKSUM, 6); err != nil {
+			_ = s.Close()
+			return nil, errors.Wrap(err, "failed to enable keepalive socket checksum")
+		}
+	}
+	if err := unix.Bind(fd, sockaddr(p.local)); err != nil {
+		_ = s.Close()
+		return nil, errors.Wrapf(err, "failed to bind keepalive socket: %v", p.local)
+	}
+	if err := unix.Connect(fd, sockaddr(p.remote)); err != nil {
+		_ = s.Close()
+		return nil, errors.Wrapf(err, "failed to connect keepalive socket: %v", p.remote)
+	}
+
+	return s, nil
+}
+
+func (s rawSocket) Send(payload []byte) error {
+	_, err := unix.Write(int(s), payload)
+	return err
+}
+
+func (s rawSocket) Close() error {
+	return unix.Close(int(s))
+}
+
+func sockaddr(ip net.IP) unix.Sockaddr {
+	if ip4 := ip.To4(); ip4 != nil {
+		sa := new(unix.SockaddrInet4)
+		copy(sa.Addr[:], ip4)
+		return sa
+	}
+	sa := new(unix.SockaddrInet6)
+	copy(sa.Addr[:], ip.To16())
+	return sa
+}
@@ -0,0 +1,67 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kernelonly
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type kernelOnlyClient struct {
+	client networkservice.NetworkServiceClient
+}
+
+// NewClient returns a new client chain element calling the wrapped client only if the kernel mechanism can be
+// selected for the connection: the requested mechanism is kernel, or there is no requested mechanism and kernel is
+// one of the mechanism preferences. Otherwise the Request/Close goes directly to the next chain element.
+func NewClient(client networkservice.NetworkServiceClient) networkservice.NetworkServiceClient {
+	return &kernelOnlyClient{
+		client: client,
+	}
+}
+
+func (c *kernelOnlyClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	if !isKernelRequest(request) {
+		return next.Client(ctx).Request(ctx, request, opts...)
+	}
+	return c.client.Request(ctx, request, opts...)
+}
+
+func (c *kernelOnlyClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	if kernel.ToMechanism(conn.GetMechanism()) == nil {
+		return next.Client(ctx).Close(ctx, conn, opts...)
+	}
+	return c.client.Close(ctx, conn, opts...)
+}
+
+func isKernelRequest(request *networkservice.NetworkServiceRequest) bool {
+	if mech := request.GetConnection().GetMechanism(); mech != nil {
+		return kernel.ToMechanism(mech) != nil
+	}
+	for _, mech := range request.GetMechanismPreferences() {
+		if kernel.ToMechanism(mech) != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kernelonly_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/memif"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/kernelonly"
+)
+
+type countServer struct {
+	requests, closes int
+}
+
+func (s *countServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.requests++
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *countServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.closes++
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+type countClient struct {
+	requests, closes int
+}
+
+func (c *countClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	c.requests++
+	return next.Client(ctx).Request(ctx, request, opts...)
+}
+
+func (c *countClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	c.closes++
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
+
+func TestKernelOnlyServer(t *testing.T) {
+	for _, sample := range []struct {
+		name     string
+		mechType string
+		expected int
+	}{
+		{name: "kernel", mechType: kernel.MECHANISM, expected: 1},
+		{name: "memif", mechType: memif.MECHANISM, expected: 0},
+	} {
+		mechType, expected := sample.mechType, sample.expected
+		t.Run(sample.name, func(t *testing.T) {
+			wrapped, last := new(countServer), new(countServer)
+			server := chain.NewNetworkServiceServer(kernelonly.New(wrapped), last)
+
+			conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+				Connection: &networkservice.Connection{
+					Id:        "id",
+					Mechanism: &networkservice.Mechanism{Type: mechType},
+				},
+			})
+			require.NoError(t, err)
+
+			_, err = server.Close(context.TODO(), conn)
+			require.NoError(t, err)
+
+			require.Equal(t, expected, wrapped.requests)
+			require.Equal(t, expected, wrapped.closes)
+			require.Equal(t, 1, last.requests)
+			require.Equal(t, 1, last.closes)
+		})
+	}
+}
+
+func TestKernelOnlyClient(t *testing.T) {
+	wrapped, last := new(countClient), new(countClient)
+	client := chain.NewNetworkServiceClient(kernelonly.NewClient(wrapped), last)
+
+	_, err := client.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id"},
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Type: memif.MECHANISM},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, wrapped.requests)
+
+	_, err = client.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "id"},
+		MechanismPreferences: []*networkservice.Mechanism{
+			{Type: memif.MECHANISM},
+			{Type: kernel.MECHANISM},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, wrapped.requests)
+	require.Equal(t, 2, last.requests)
+}
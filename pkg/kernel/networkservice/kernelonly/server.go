@@ -0,0 +1,55 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package kernelonly provides chain element decorators bypassing the wrapped kernel chain elements if the connection
+// mechanism is not kernel
+package kernelonly
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type kernelOnlyServer struct {
+	server networkservice.NetworkServiceServer
+}
+
+// New returns a new server chain element calling the wrapped server only if the connection mechanism is kernel,
+// otherwise the Request/Close goes directly to the next chain element
+func New(server networkservice.NetworkServiceServer) networkservice.NetworkServiceServer {
+	return &kernelOnlyServer{
+		server: server,
+	}
+}
+
+func (s *kernelOnlyServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if kernel.ToMechanism(request.GetConnection().GetMechanism()) == nil {
+		return next.Server(ctx).Request(ctx, request)
+	}
+	return s.server.Request(ctx, request)
+}
+
+func (s *kernelOnlyServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if kernel.ToMechanism(conn.GetMechanism()) == nil {
+		return next.Server(ctx).Close(ctx, conn)
+	}
+	return s.server.Close(ctx, conn)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechanismguard
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type mechanismGuardClient struct{}
+
+// NewClient returns a new client chain element returning UnsupportedMechanismError if there is no kernel mechanism in
+// the Request mechanism preferences or if the mechanism selected by the Endpoint is not kernel
+func NewClient() networkservice.NetworkServiceClient {
+	return &mechanismGuardClient{}
+}
+
+func (c *mechanismGuardClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	if preferences := request.GetMechanismPreferences(); len(preferences) != 0 && !hasKernel(preferences) {
+		return nil, &UnsupportedMechanismError{Type: preferences[0].GetType()}
+	}
+
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := check(conn.GetMechanism()); err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c *mechanismGuardClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	// Close is never blocked: the connection resources should be freed whatever the mechanism is, the kernel chain
+	// elements skip the connections with non kernel mechanisms themselves
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
+
+func hasKernel(mechanisms []*networkservice.Mechanism) bool {
+	for _, mechanism := range mechanisms {
+		if mechanism.GetType() == kernel.MECHANISM {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mechanismguard provides chain elements refusing connections with not kernel mechanisms before they reach the
+// kernel chain elements
+package mechanismguard
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+)
+
+// UnsupportedMechanismError is returned when the connection mechanism is not supported by the kernel chain elements
+type UnsupportedMechanismError struct {
+	// Type is the unsupported mechanism type, it is empty if there is no mechanism selected
+	Type string
+}
+
+func (e *UnsupportedMechanismError) Error() string {
+	if e.Type == "" {
+		return "no mechanism selected, kernel mechanism is expected"
+	}
+	return "mechanism is not supported by the kernel chain elements: " + e.Type
+}
+
+// IsUnsupportedMechanism returns true if err is caused by UnsupportedMechanismError
+func IsUnsupportedMechanism(err error) bool {
+	for err != nil {
+		if _, ok := err.(*UnsupportedMechanismError); ok {
+			return true
+		}
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			return false
+		}
+		err = cause.Cause()
+	}
+	return false
+}
+
+func check(mechanism *networkservice.Mechanism) error {
+	if mechanism.GetType() != kernel.MECHANISM {
+		return &UnsupportedMechanismError{Type: mechanism.GetType()}
+	}
+	return nil
+}
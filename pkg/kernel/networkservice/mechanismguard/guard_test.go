@@ -0,0 +1,74 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechanismguard_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/memif"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/mechanismguard"
+)
+
+func TestMechanismGuardServer(t *testing.T) {
+	server := mechanismguard.NewServer()
+
+	_, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Mechanism: &networkservice.Mechanism{Type: memif.MECHANISM},
+		},
+	})
+	require.True(t, mechanismguard.IsUnsupportedMechanism(errors.Wrap(err, "wrapped")))
+	require.Contains(t, err.Error(), memif.MECHANISM)
+
+	_, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{})
+	require.True(t, mechanismguard.IsUnsupportedMechanism(err))
+
+	_, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Mechanism: &networkservice.Mechanism{Type: kernel.MECHANISM},
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestMechanismGuardClient(t *testing.T) {
+	client := mechanismguard.NewClient()
+
+	_, err := client.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		MechanismPreferences: []*networkservice.Mechanism{{Type: memif.MECHANISM}},
+	})
+	require.True(t, mechanismguard.IsUnsupportedMechanism(err))
+}
+
+func TestMechanismGuard_Close(t *testing.T) {
+	conn := &networkservice.Connection{
+		Mechanism: &networkservice.Mechanism{Type: memif.MECHANISM},
+	}
+
+	_, err := mechanismguard.NewServer().Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	_, err = mechanismguard.NewClient().Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
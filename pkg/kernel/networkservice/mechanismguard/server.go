@@ -0,0 +1,47 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechanismguard
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type mechanismGuardServer struct{}
+
+// NewServer returns a new server chain element returning UnsupportedMechanismError if the selected connection
+// mechanism is not kernel
+func NewServer() networkservice.NetworkServiceServer {
+	return &mechanismGuardServer{}
+}
+
+func (s *mechanismGuardServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if err := check(request.GetConnection().GetMechanism()); err != nil {
+		return nil, err
+	}
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *mechanismGuardServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	// Close is never blocked: the connection resources should be freed whatever the mechanism is, the kernel chain
+	// elements skip the connections with non kernel mechanisms themselves
+	return next.Server(ctx).Close(ctx, conn)
+}
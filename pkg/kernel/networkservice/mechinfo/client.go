@@ -0,0 +1,62 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechinfo
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type mechInfoClient struct{}
+
+// NewClient returns a new client chain element setting the net NS inodes of both ends, the final interface name and
+// index (see the keys) to the returned Connection kernel mechanism parameters and the ConnectionKernelInfo to the
+// metadata (see Load). Client chain elements program the kernel after the Request, so it should precede them in the
+// chain.
+func NewClient() networkservice.NetworkServiceClient {
+	return &mechInfoClient{}
+}
+
+func (c *mechInfoClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := newKernelInfo(conn)
+	if err != nil {
+		log.Entry(ctx).WithField("mechInfoClient", "Request").Warnf("failed to get kernel state for connection %s: %s",
+			conn.GetId(), err.Error())
+	}
+	if info != nil {
+		info.setParams(conn)
+		store(ctx, metadata.IsClient(c), info)
+	}
+
+	return conn, nil
+}
+
+func (c *mechInfoClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechinfo
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// Kernel mechanism parameter keys
+const (
+	// NetNSInodeKey is the inode of the connection kernel interface net NS
+	NetNSInodeKey = "netnsInode"
+	// IfIndexKey is the connection kernel interface index in its net NS
+	IfIndexKey = "ifIndex"
+	// PeerNetNSInodeKey is the inode of the Forwarder's net NS, where the other end of the connection is
+	PeerNetNSInodeKey = "peerNetnsInode"
+	// PeerIfNameKey is the Forwarder's end of the veth pair name, it is set only for the veth connections
+	PeerIfNameKey = "peerIfName"
+	// PeerIfIndexKey is the Forwarder's end of the veth pair index, it is set only for the veth connections
+	PeerIfIndexKey = "peerIfIndex"
+)
+
+// ConnectionKernelInfo is the concrete kernel state of the connection kernel interface and the Forwarder's end
+// collected after the kernel programming. It is stored into the per connection metadata (see Load), so the chain
+// elements preceding mechinfo (stats, monitoring, probes) get it on the Request return without querying netlink and
+// parsing the mechanism parameters.
+type ConnectionKernelInfo struct {
+	// NetNSInode is the inode of the connection kernel interface net NS
+	NetNSInode uint64
+	// IfName is the connection kernel interface name
+	IfName string
+	// IfIndex is the connection kernel interface index in its net NS
+	IfIndex int
+	// MTU is the connection kernel interface MTU
+	MTU int
+	// HardwareAddr is the connection kernel interface MAC address, it is empty for L3 net interfaces
+	HardwareAddr net.HardwareAddr
+	// PeerNetNSInode is the inode of the Forwarder's net NS
+	PeerNetNSInode uint64
+	// PeerIfName is the Forwarder's end of the veth pair name, it is empty for the non-veth connections
+	PeerIfName string
+	// PeerIfIndex is the Forwarder's end of the veth pair index, it is 0 for the non-veth connections
+	PeerIfIndex int
+}
+
+// newKernelInfo collects the concrete kernel state of the connection kernel interface and the Forwarder's end, it
+// returns nil if the connection is not a kernel one
+func newKernelInfo(conn *networkservice.Connection) (*ConnectionKernelInfo, error) {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil, nil
+	}
+
+	current, err := nshandle.Current()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = current.Close() }()
+
+	info := &ConnectionKernelInfo{
+		IfName: mech.GetInterfaceName(conn),
+	}
+	if err := info.setIfInfo(mech.GetNetNSURL(), current); err != nil {
+		return nil, err
+	}
+
+	if info.PeerNetNSInode, err = nshandle.Inode(current); err != nil {
+		return nil, err
+	}
+
+	if peer, err := netlink.LinkByName(linkprovider.VethPeerName(conn)); err == nil {
+		info.PeerIfName = peer.Attrs().Name
+		info.PeerIfIndex = peer.Attrs().Index
+	}
+
+	return info, nil
+}
+
+// setIfInfo sets the kernel interface net NS inode, index, MTU and MAC address, empty net NS URL means the current
+// net NS
+func (info *ConnectionKernelInfo) setIfInfo(netNSURL string, current netns.NsHandle) error {
+	netNS := current
+	if netNSURL != "" {
+		var err error
+		if netNS, err = nshandle.FromURL(netNSURL); err != nil {
+			return err
+		}
+		defer func() { _ = netNS.Close() }()
+	}
+
+	inode, err := nshandle.Inode(netNS)
+	if err != nil {
+		return err
+	}
+
+	handle, err := netlink.NewHandleAt(netNS)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create netlink handle in net NS: %v", netNSURL)
+	}
+	defer handle.Delete()
+
+	link, err := handle.LinkByName(info.IfName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get net interface: %v", info.IfName)
+	}
+
+	info.NetNSInode = inode
+	info.IfIndex = link.Attrs().Index
+	info.MTU = link.Attrs().MTU
+	info.HardwareAddr = link.Attrs().HardwareAddr
+	return nil
+}
+
+// setParams sets the kernel info to the mechanism parameters
+func (info *ConnectionKernelInfo) setParams(conn *networkservice.Connection) {
+	if conn.GetMechanism().Parameters == nil {
+		conn.GetMechanism().Parameters = make(map[string]string)
+	}
+	params := conn.GetMechanism().GetParameters()
+
+	params[NetNSInodeKey] = strconv.FormatUint(info.NetNSInode, 10)
+	params[IfIndexKey] = strconv.Itoa(info.IfIndex)
+	params[PeerNetNSInodeKey] = strconv.FormatUint(info.PeerNetNSInode, 10)
+
+	delete(params, PeerIfNameKey)
+	delete(params, PeerIfIndexKey)
+	if info.PeerIfName != "" {
+		params[PeerIfNameKey] = info.PeerIfName
+		params[PeerIfIndexKey] = strconv.Itoa(info.PeerIfIndex)
+	}
+}
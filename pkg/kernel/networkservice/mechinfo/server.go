@@ -0,0 +1,63 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mechinfo provides chain elements exposing the concrete kernel state of the connection in the kernel
+// mechanism parameters, so upper layers and monitoring can correlate the control plane and the kernel state
+package mechinfo
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type mechInfoServer struct{}
+
+// NewServer returns a new server chain element setting the net NS inodes of both ends, the final interface name and
+// index (see the keys) to the returned Connection kernel mechanism parameters and the ConnectionKernelInfo to the
+// metadata (see Load). It should precede the elements programming the kernel in the chain, so the parameters are set
+// after the programming.
+func NewServer() networkservice.NetworkServiceServer {
+	return &mechInfoServer{}
+}
+
+func (s *mechInfoServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := newKernelInfo(conn)
+	if err != nil {
+		log.Entry(ctx).WithField("mechInfoServer", "Request").Warnf("failed to get kernel state for connection %s: %s",
+			conn.GetId(), err.Error())
+	}
+	if info != nil {
+		info.setParams(conn)
+		store(ctx, metadata.IsClient(s), info)
+	}
+
+	return conn, nil
+}
+
+func (s *mechInfoServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
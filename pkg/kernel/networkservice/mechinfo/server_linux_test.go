@@ -0,0 +1,127 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechinfo_test
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/checks/checkcontext"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/inject"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/mechinfo"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+const (
+	netNSPath = "/run/netns"
+	ifName    = "nsm-1"
+)
+
+func TestMechInfoServer(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	netNSName := uuid.New().String()
+	clientNetNS, err := netns.NewNamed(netNSName)
+	require.NoError(t, err)
+	defer func() {
+		_ = clientNetNS.Close()
+		_ = netns.DeleteNamed(netNSName)
+	}()
+	require.NoError(t, netns.Set(curNetNS))
+
+	var info *mechinfo.ConnectionKernelInfo
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		mechinfo.NewServer(),
+		// the kernel info of the previous Request is visible on refresh
+		checkcontext.NewServer(t, func(_ *testing.T, ctx context.Context) {
+			info, _ = mechinfo.Load(ctx, false)
+		}),
+		inject.NewServer(inject.WithLinkProvider(linkprovider.NewVeth())),
+	)
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: uuid.New().String(),
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.NetNSURL:         (&url.URL{Scheme: "file", Path: path.Join(netNSPath, netNSName)}).String(),
+					kernel.InterfaceNameKey: ifName,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer func() { _, _ = server.Close(context.TODO(), conn) }()
+
+	params := conn.GetMechanism().GetParameters()
+
+	clientInode, err := nshandle.Inode(clientNetNS)
+	require.NoError(t, err)
+	require.Equal(t, strconv.FormatUint(clientInode, 10), params[mechinfo.NetNSInodeKey])
+
+	var link netlink.Link
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() (linkErr error) {
+		link, linkErr = netlink.LinkByName(ifName)
+		return linkErr
+	}))
+	require.Equal(t, strconv.Itoa(link.Attrs().Index), params[mechinfo.IfIndexKey])
+
+	curInode, err := nshandle.Inode(curNetNS)
+	require.NoError(t, err)
+	require.Equal(t, strconv.FormatUint(curInode, 10), params[mechinfo.PeerNetNSInodeKey])
+
+	peer, err := netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.NoError(t, err)
+	require.Equal(t, peer.Attrs().Name, params[mechinfo.PeerIfNameKey])
+	require.Equal(t, strconv.Itoa(peer.Attrs().Index), params[mechinfo.PeerIfIndexKey])
+
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.NotNil(t, info)
+	require.Equal(t, &mechinfo.ConnectionKernelInfo{
+		NetNSInode:     clientInode,
+		IfName:         ifName,
+		IfIndex:        link.Attrs().Index,
+		MTU:            link.Attrs().MTU,
+		HardwareAddr:   link.Attrs().HardwareAddr,
+		PeerNetNSInode: curInode,
+		PeerIfName:     peer.Attrs().Name,
+		PeerIfIndex:    peer.Attrs().Index,
+	}, info)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechplugin
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type mechPluginClient struct {
+	registry *Registry
+}
+
+// NewClient returns a new client chain element applying the kernel mechanism variant selected by the VariantKey
+// parameter of the returned connection mechanism on Request and reverting it on Close. Connections without VariantKey
+// are passed through, connections with the not registered variant fail with UnknownVariantError. It requires metadata
+// chain element.
+func NewClient(registry *Registry) networkservice.NetworkServiceClient {
+	return &mechPluginClient{
+		registry: registry,
+	}
+}
+
+func (c *mechPluginClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apply(ctx, c.registry, conn, metadata.IsClient(c)); err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c *mechPluginClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	revertErr := revert(ctx, c.registry, conn, metadata.IsClient(c))
+
+	if err != nil && revertErr != nil {
+		return nil, errors.Wrap(err, revertErr.Error())
+	}
+	if revertErr != nil {
+		return nil, revertErr
+	}
+	return &empty.Empty{}, err
+}
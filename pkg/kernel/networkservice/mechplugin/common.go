@@ -0,0 +1,74 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechplugin
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+// variant returns the variant selected by the connection kernel mechanism, it is empty for the connections without
+// VariantKey and for the non-kernel ones
+func variant(conn *networkservice.Connection) string {
+	return kernel.ToMechanism(conn.GetMechanism()).GetParameters()[VariantKey]
+}
+
+// apply applies the variant selected by the connection and stores it. If the variant is changed on refresh, the
+// previously applied one is reverted first. If Apply of the same variant fails on refresh, the variant is kept stored,
+// so it is still reverted on Close.
+func apply(ctx context.Context, registry *Registry, conn *networkservice.Connection, isClient bool) error {
+	name := variant(conn)
+	if previous, ok := load(ctx, isClient); ok && previous != name {
+		del(ctx, isClient)
+		if err := revertVariant(ctx, registry, previous, conn); err != nil {
+			log.Entry(ctx).WithField("mechplugin", "apply").Warnf("failed to revert variant %s: %s", previous, err.Error())
+		}
+	}
+	if name == "" {
+		return nil
+	}
+
+	applier, ok := registry.Get(name)
+	if !ok {
+		return &UnknownVariantError{Variant: name}
+	}
+	if err := applier.Apply(ctx, conn); err != nil {
+		return err
+	}
+	store(ctx, isClient, name)
+	return nil
+}
+
+// revert reverts the variant applied for the connection
+func revert(ctx context.Context, registry *Registry, conn *networkservice.Connection, isClient bool) error {
+	name, ok := loadAndDelete(ctx, isClient)
+	if !ok {
+		return nil
+	}
+	return revertVariant(ctx, registry, name, conn)
+}
+
+func revertVariant(ctx context.Context, registry *Registry, name string, conn *networkservice.Connection) error {
+	applier, ok := registry.Get(name)
+	if !ok {
+		return &UnknownVariantError{Variant: name}
+	}
+	return applier.Revert(ctx, conn)
+}
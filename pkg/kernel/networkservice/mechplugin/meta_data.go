@@ -0,0 +1,48 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechplugin
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type keyType struct{}
+
+// store stores the name of the variant applied for the connection
+func store(ctx context.Context, isClient bool, variant string) {
+	metadata.Map(ctx, isClient).Store(keyType{}, variant)
+}
+
+func load(ctx context.Context, isClient bool) (string, bool) {
+	if raw, ok := metadata.Map(ctx, isClient).Load(keyType{}); ok {
+		return raw.(string), true
+	}
+	return "", false
+}
+
+func del(ctx context.Context, isClient bool) {
+	metadata.Map(ctx, isClient).Delete(keyType{})
+}
+
+func loadAndDelete(ctx context.Context, isClient bool) (string, bool) {
+	if raw, ok := metadata.Map(ctx, isClient).LoadAndDelete(keyType{}); ok {
+		return raw.(string), true
+	}
+	return "", false
+}
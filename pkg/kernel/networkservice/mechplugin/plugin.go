@@ -0,0 +1,119 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mechplugin provides the extension point for the third party kernel mechanism variants (e.g. proprietary NIC
+// technologies): the variants implement MechanismApplier, are registered by name in the Registry and are selected per
+// connection by the VariantKey kernel mechanism parameter, so they slot into the sdk-kernel chains without forking
+package mechplugin
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// VariantKey is a kernel mechanism parameter key with the name of the registered MechanismApplier
+const VariantKey = "variant"
+
+// MechanismApplier is a kernel mechanism variant plugin
+type MechanismApplier interface {
+	// Apply programs the kernel for the connection, e.g. creates the net interface and moves it into the Client's net
+	// NS. It is called on each Request including refreshes, so it should be idempotent. If Apply fails, it should
+	// leave no kernel state, Revert is not called.
+	Apply(ctx context.Context, conn *networkservice.Connection) error
+	// Revert removes the kernel state created by Apply for the connection, it is called on Close. It should succeed if
+	// the kernel state has been already removed, e.g. with the Client's net NS.
+	Revert(ctx context.Context, conn *networkservice.Connection) error
+}
+
+// UnknownVariantError is returned when the connection selects the variant not registered in the Registry
+type UnknownVariantError struct {
+	Variant string
+}
+
+func (e *UnknownVariantError) Error() string {
+	return "kernel mechanism variant is not registered: " + e.Variant
+}
+
+// IsUnknownVariant returns true if err is caused by UnknownVariantError
+func IsUnknownVariant(err error) bool {
+	for err != nil {
+		if _, ok := err.(*UnknownVariantError); ok {
+			return true
+		}
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			return false
+		}
+		err = cause.Cause()
+	}
+	return false
+}
+
+// Registry is a set of the MechanismAppliers by variant names, it is safe for concurrent use
+type Registry struct {
+	appliers map[string]MechanismApplier
+	lock     sync.RWMutex
+}
+
+// NewRegistry returns a new empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		appliers: make(map[string]MechanismApplier),
+	}
+}
+
+// Register registers the MechanismApplier for the variant, it fails if the variant is already registered
+func (r *Registry) Register(variant string, applier MechanismApplier) error {
+	if variant == "" {
+		return errors.New("kernel mechanism variant name is empty")
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.appliers[variant]; ok {
+		return errors.Errorf("kernel mechanism variant is already registered: %v", variant)
+	}
+	r.appliers[variant] = applier
+	return nil
+}
+
+// Get returns the MechanismApplier registered for the variant
+func (r *Registry) Get(variant string) (MechanismApplier, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	applier, ok := r.appliers[variant]
+	return applier, ok
+}
+
+// Variants returns the sorted names of the registered variants
+func (r *Registry) Variants() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	variants := make([]string, 0, len(r.appliers))
+	for variant := range r.appliers {
+		variants = append(variants, variant)
+	}
+	sort.Strings(variants)
+	return variants
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechplugin
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type mechPluginServer struct {
+	registry *Registry
+}
+
+// NewServer returns a new server chain element applying the kernel mechanism variant selected by the VariantKey
+// parameter on Request before the next chain elements, so they see the kernel state created by the variant, and
+// reverting it on Close. Connections without VariantKey are passed through, connections with the not registered
+// variant fail with UnknownVariantError. It requires metadata chain element.
+func NewServer(registry *Registry) networkservice.NetworkServiceServer {
+	return &mechPluginServer{
+		registry: registry,
+	}
+}
+
+func (s *mechPluginServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if err := apply(ctx, s.registry, request.GetConnection(), metadata.IsClient(s)); err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if revertErr := revert(ctx, s.registry, request.GetConnection(), metadata.IsClient(s)); revertErr != nil {
+			log.Entry(ctx).WithField("mechPluginServer", "Request").Warnf("failed to revert variant: %s", revertErr.Error())
+		}
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (s *mechPluginServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	revertErr := revert(ctx, s.registry, conn, metadata.IsClient(s))
+
+	if err != nil && revertErr != nil {
+		return nil, errors.Wrap(err, revertErr.Error())
+	}
+	if revertErr != nil {
+		return nil, revertErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mechplugin_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/mechplugin"
+)
+
+type applier struct {
+	applied  map[string]bool
+	applyErr error
+}
+
+func newApplier() *applier {
+	return &applier{
+		applied: make(map[string]bool),
+	}
+}
+
+func (a *applier) Apply(_ context.Context, conn *networkservice.Connection) error {
+	if a.applyErr != nil {
+		return a.applyErr
+	}
+	a.applied[conn.GetId()] = true
+	return nil
+}
+
+func (a *applier) Revert(_ context.Context, conn *networkservice.Connection) error {
+	delete(a.applied, conn.GetId())
+	return nil
+}
+
+func request(variant string) *networkservice.NetworkServiceRequest {
+	parameters := map[string]string{
+		kernel.InterfaceNameKey: "nsm-1",
+	}
+	if variant != "" {
+		parameters[mechplugin.VariantKey] = variant
+	}
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type:       kernel.MECHANISM,
+				Parameters: parameters,
+			},
+		},
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	registry := mechplugin.NewRegistry()
+	require.NoError(t, registry.Register("b", newApplier()))
+	require.NoError(t, registry.Register("a", newApplier()))
+	require.Error(t, registry.Register("a", newApplier()))
+	require.Error(t, registry.Register("", newApplier()))
+
+	_, ok := registry.Get("a")
+	require.True(t, ok)
+	_, ok = registry.Get("c")
+	require.False(t, ok)
+	require.Equal(t, []string{"a", "b"}, registry.Variants())
+}
+
+func TestMechPluginServer(t *testing.T) {
+	a, b := newApplier(), newApplier()
+	registry := mechplugin.NewRegistry()
+	require.NoError(t, registry.Register("a", a))
+	require.NoError(t, registry.Register("b", b))
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		mechplugin.NewServer(registry),
+	)
+
+	conn, err := server.Request(context.Background(), request("a"))
+	require.NoError(t, err)
+	require.True(t, a.applied["conn-1"])
+
+	// refresh with the changed variant reverts the previous one
+	req := request("b")
+	req.Connection = conn.Clone()
+	req.GetConnection().GetMechanism().GetParameters()[mechplugin.VariantKey] = "b"
+	conn, err = server.Request(context.Background(), req)
+	require.NoError(t, err)
+	require.False(t, a.applied["conn-1"])
+	require.True(t, b.applied["conn-1"])
+
+	_, err = server.Close(context.Background(), conn)
+	require.NoError(t, err)
+	require.False(t, b.applied["conn-1"])
+}
+
+func TestMechPluginServer_PassThrough(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		mechplugin.NewServer(mechplugin.NewRegistry()),
+	)
+
+	conn, err := server.Request(context.Background(), request(""))
+	require.NoError(t, err)
+	_, err = server.Close(context.Background(), conn)
+	require.NoError(t, err)
+}
+
+func TestMechPluginServer_UnknownVariant(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		mechplugin.NewServer(mechplugin.NewRegistry()),
+	)
+
+	_, err := server.Request(context.Background(), request("unknown"))
+	require.Error(t, err)
+	require.True(t, mechplugin.IsUnknownVariant(err))
+}
+
+func TestMechPluginServer_Failure(t *testing.T) {
+	a := newApplier()
+	registry := mechplugin.NewRegistry()
+	require.NoError(t, registry.Register("a", a))
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		mechplugin.NewServer(registry),
+		injecterror.NewServer(),
+	)
+
+	// next chain element failure reverts the applied variant
+	_, err := server.Request(context.Background(), request("a"))
+	require.Error(t, err)
+	require.Empty(t, a.applied)
+
+	a.applyErr = errors.New("error")
+	server = chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		mechplugin.NewServer(registry),
+	)
+	_, err = server.Request(context.Background(), request("a"))
+	require.Error(t, err)
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mtu
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/desiredstate"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/strict"
+)
+
+const (
+	// MTUKey is a connection context extra context key with the connection MTU. ConnectionContext has no MTU field
+	// in the used API version, so it is passed as extra context.
+	MTUKey = "mtu"
+
+	minMTU = 68
+)
+
+// MTU returns the connection MTU, it returns 0 if there is no MTU in the connection context
+func MTU(conn *networkservice.Connection) (int, error) {
+	value, ok := conn.GetContext().GetExtraContext()[MTUKey]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	mtu, err := strconv.Atoi(value)
+	if err != nil || mtu < minMTU {
+		return 0, errors.Errorf("invalid MTU: %v", value)
+	}
+	return mtu, nil
+}
+
+// mtuSetter is the common part of the mtu client and server
+type mtuSetter struct {
+	uplink   string
+	overhead int
+}
+
+func newMTUSetter(options []Option) *mtuSetter {
+	m := &mtuSetter{}
+	for _, opt := range options {
+		opt(m)
+	}
+	return m
+}
+
+// validate checks the MTU against the uplink MTU in the current net NS: tunneled payload should fit into the uplink
+// MTU together with the tunnel overhead
+func (m *mtuSetter) validate(mtu int) error {
+	if m.uplink == "" {
+		return nil
+	}
+	uplink, err := netlink.LinkByName(m.uplink)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get uplink net interface: %v", m.uplink)
+	}
+	if maxMTU := uplink.Attrs().MTU - m.overhead; mtu > maxMTU {
+		return errors.Errorf("MTU %d exceeds uplink %s MTU %d minus overhead %d", mtu, m.uplink, uplink.Attrs().MTU, m.overhead)
+	}
+	return nil
+}
+
+// apply sets the connection MTU to the connection kernel interface in its net NS and returns the original MTU, it
+// returns 0 if nothing has been changed. If there is the reconciler in the chain, the MTU is only described in its
+// state (see desiredstate.State), the reconciler restores the original MTU.
+func (m *mtuSetter) apply(ctx context.Context, conn *networkservice.Connection) (int, error) {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return 0, nil
+	}
+
+	mtu, err := MTU(conn)
+	if err != nil || mtu == 0 {
+		return 0, err
+	}
+	if err := m.validate(mtu); err != nil {
+		return 0, err
+	}
+	if state := desiredstate.State(ctx); state != nil {
+		state.Link(mech.GetInterfaceName(conn)).MTU = mtu
+		return 0, nil
+	}
+
+	var original int
+	err = nshandle.RunInURL(mech.GetNetNSURL(), func() error {
+		original, err = setMTU(ctx, mech.GetInterfaceName(conn), mtu)
+		return err
+	})
+	return original, err
+}
+
+// restore sets back the original MTU to the connection kernel interface, already deleted net interface is skipped
+func restore(ctx context.Context, conn *networkservice.Connection, original int) error {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil || original == 0 {
+		return nil
+	}
+
+	return nshandle.RunInURL(mech.GetNetNSURL(), func() error {
+		if _, err := netlink.LinkByName(mech.GetInterfaceName(conn)); err != nil {
+			return nil
+		}
+		_, err := setMTU(ctx, mech.GetInterfaceName(conn), original)
+		return err
+	})
+}
+
+func setMTU(ctx context.Context, ifName string, mtu int) (int, error) {
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+	original := link.Attrs().MTU
+	if original == mtu {
+		return 0, nil
+	}
+	if err := netlink.LinkSetMTU(link, mtu); err != nil {
+		return 0, errors.Wrapf(err, "failed to set MTU for the net interface: %v %v", ifName, mtu)
+	}
+	if err := strict.Verify(ctx, "LinkSetMTU", ifName, func() (bool, error) {
+		actual, err := netlink.LinkByIndex(link.Attrs().Index)
+		if err != nil {
+			return false, err
+		}
+		return actual.Attrs().MTU == mtu, nil
+	}); err != nil {
+		return 0, err
+	}
+	return original, nil
+}
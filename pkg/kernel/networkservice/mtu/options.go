@@ -0,0 +1,29 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mtu
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(m *mtuSetter)
+
+// WithUplink sets the uplink (physical NIC) net interface name in the current net NS to validate the connection MTU
+// against: connection MTU + overhead should not exceed the uplink MTU
+func WithUplink(ifName string, overhead int) Option {
+	return func(m *mtuSetter) {
+		m.uplink = ifName
+		m.overhead = overhead
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mtu provides chain elements setting the connection MTU to the connection kernel interface
+package mtu
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type mtuServer struct {
+	*mtuSetter
+}
+
+// NewServer returns a new server chain element setting the connection MTU (see MTUKey) to the Client's net interface
+// in its net NS on Request and restoring the original MTU on Close. It should be placed in the Forwarder's net NS, so
+// the uplink is visible.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &mtuServer{
+		mtuSetter: newMTUSetter(options),
+	}
+}
+
+func (s *mtuServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	// MTU is applied on each Request, but the original MTU stored on the first change shouldn't be overwritten by
+	// refresh
+	stored, _ := load(ctx, metadata.IsClient(s))
+
+	original, err := s.apply(ctx, request.GetConnection())
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if restoreErr := restore(ctx, request.GetConnection(), original); restoreErr != nil {
+			log.Entry(ctx).WithField("mtuServer", "Request").Warnf("failed to restore MTU: %s", restoreErr.Error())
+		}
+		return nil, err
+	}
+
+	if stored == 0 {
+		store(ctx, metadata.IsClient(s), original)
+	}
+
+	return conn, nil
+}
+
+func (s *mtuServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var restoreErr error
+	if original, ok := loadAndDelete(ctx, metadata.IsClient(s)); ok {
+		restoreErr = restore(ctx, conn, original)
+	}
+
+	if err != nil && restoreErr != nil {
+		return nil, errors.Wrap(err, restoreErr.Error())
+	}
+	if restoreErr != nil {
+		return nil, restoreErr
+	}
+	return &empty.Empty{}, err
+}
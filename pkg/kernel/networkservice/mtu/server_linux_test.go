@@ -0,0 +1,93 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mtu_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/mtu"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName     = "mtu-1"
+	uplinkName = "mtu-2"
+)
+
+func request(mtuValue string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.NetNSURL:         "file:///proc/self/ns/net",
+					kernel.InterfaceNameKey: ifName,
+				},
+			},
+			Context: &networkservice.ConnectionContext{
+				ExtraContext: map[string]string{
+					mtu.MTUKey: mtuValue,
+				},
+			},
+		},
+	}
+}
+
+func linkMTU(t *testing.T) int {
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	return link.Attrs().MTU
+}
+
+func TestMTUServer(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, uplinkName)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		mtu.NewServer(mtu.WithUplink(uplinkName, 50)),
+	)
+
+	conn, err := server.Request(context.TODO(), request("1400"))
+	require.NoError(t, err)
+	require.Equal(t, 1400, linkMTU(t))
+
+	// refresh with the changed MTU
+	conn, err = server.Request(context.TODO(), request("1450"))
+	require.NoError(t, err)
+	require.Equal(t, 1450, linkMTU(t))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Equal(t, 1500, linkMTU(t))
+
+	_, err = server.Request(context.TODO(), request("1451"))
+	require.Error(t, err)
+	require.Equal(t, 1500, linkMTU(t))
+
+	_, err = server.Request(context.TODO(), request("mtu"))
+	require.Error(t, err)
+}
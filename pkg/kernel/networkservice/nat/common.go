@@ -0,0 +1,175 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nat
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ipaddrs"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nft"
+)
+
+// DNATLabel is a connection label with comma separated list of the host ports forwarded to the Client's IP addresses:
+// "protocol:port[:targetPort]", e.g. "tcp:8080:80,udp:53". Protocol is "tcp" or "udp", port is used as the target port
+// if not set.
+const DNATLabel = "natDNAT"
+
+const (
+	srcNATPriority = 100
+	dstNATPriority = -100
+)
+
+// port is a host port forwarded to the Client
+type port struct {
+	protocol   string
+	port       int
+	targetPort int
+}
+
+// tableName returns the nftables table name differing from the other connection tables (e.g. conntrack helpers)
+func tableName(connID string) string {
+	return nft.TableName(connID + "/nat")
+}
+
+// newTable returns nftables table translating the Client's source IP addresses and forwarding the DNATLabel host ports
+// to them, it returns nil if the connection has no source IP addresses
+func (s *natServer) newTable(conn *networkservice.Connection, ifName string) (*nft.Table, error) {
+	srcIPNets, err := ipaddrs.Parse(conn.GetContext().GetIpContext().GetSrcIpAddr())
+	if err != nil {
+		return nil, err
+	}
+	if len(srcIPNets) == 0 {
+		return nil, nil
+	}
+
+	table := &nft.Table{
+		Family: nft.FamilyInet,
+		Name:   tableName(conn.GetId()),
+	}
+	postrouting := table.AddChain(&nft.Chain{
+		Name:     "postrouting",
+		Type:     "nat",
+		Hook:     "postrouting",
+		Priority: srcNATPriority,
+	})
+	dnatPorts, err := parsePorts(conn.GetLabels()[DNATLabel])
+	if err != nil {
+		return nil, err
+	}
+	var prerouting *nft.Chain
+	if len(dnatPorts) > 0 {
+		prerouting = table.AddChain(&nft.Chain{
+			Name:     "prerouting",
+			Type:     "nat",
+			Hook:     "prerouting",
+			Priority: dstNATPriority,
+		})
+	}
+
+	oifMatch := ""
+	if s.outIfName != "" {
+		oifMatch = fmt.Sprintf(`oifname "%s" `, s.outIfName)
+	}
+
+	for _, srcIPNet := range srcIPNets {
+		family, nfproto := "ip", "ipv4"
+		if !ipaddrs.IsIPv4(srcIPNet.IP) {
+			family, nfproto = "ip6", "ipv6"
+		}
+		prefix := &net.IPNet{IP: srcIPNet.IP.Mask(srcIPNet.Mask), Mask: srcIPNet.Mask}
+
+		if snatIP := s.snatIP(srcIPNet.IP); snatIP != nil {
+			postrouting.AddRule(`iifname "%s" %s%s saddr %s snat %s to %s`, ifName, oifMatch, family, prefix, family, snatIP)
+		} else {
+			postrouting.AddRule(`iifname "%s" %s%s saddr %s masquerade`, ifName, oifMatch, family, prefix)
+		}
+
+		for _, p := range dnatPorts {
+			prerouting.AddRule(`iifname != "%s" meta nfproto %s %s dport %d dnat %s to %s`, ifName, nfproto, p.protocol,
+				p.port, family, net.JoinHostPort(srcIPNet.IP.String(), strconv.Itoa(p.targetPort)))
+		}
+	}
+
+	return table, nil
+}
+
+// parsePorts parses DNATLabel value
+func parsePorts(value string) ([]port, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var result []port
+	for _, item := range strings.Split(value, ",") {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, errors.Errorf("invalid %s label value: %v", DNATLabel, value)
+		}
+		if parts[0] != "tcp" && parts[0] != "udp" {
+			return nil, errors.Errorf("unsupported DNAT protocol: %v", parts[0])
+		}
+
+		p := port{protocol: parts[0]}
+		var err error
+		if p.port, err = parsePort(parts[1]); err != nil {
+			return nil, err
+		}
+		p.targetPort = p.port
+		if len(parts) == 3 {
+			if p.targetPort, err = parsePort(parts[2]); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+func parsePort(value string) (int, error) {
+	p, err := strconv.ParseUint(value, 10, 16)
+	if err != nil || p == 0 {
+		return 0, errors.Errorf("invalid DNAT port: %v", value)
+	}
+	return int(p), nil
+}
+
+// deleteTable deletes the connection table applied on the previous Request, e.g. on refresh if the source IP addresses
+// have been dropped from the connection
+func deleteTable(ctx context.Context, isClient bool) error {
+	if table, ok := loadAndDelete(ctx, isClient); ok {
+		return nft.Delete(ctx, table.Family, table.Name)
+	}
+	return nil
+}
+
+// snatIP returns the source address of the same family as the given IP address
+func (s *natServer) snatIP(ip net.IP) net.IP {
+	for _, snatIP := range s.snatIPs {
+		if ipaddrs.IsIPv4(snatIP) == ipaddrs.IsIPv4(ip) {
+			return snatIP
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nat
+
+import (
+	"net"
+)
+
+// Option is an option pattern for NewServer
+type Option func(s *natServer)
+
+// WithOutInterface limits the translation to the traffic leaving the host via the given net interface
+func WithOutInterface(ifName string) Option {
+	return func(s *natServer) {
+		s.outIfName = ifName
+	}
+}
+
+// WithSNAT sets the source addresses to translate the Client's addresses to, at most one per IP family. The Client's
+// addresses of the family without the source address set are still masqueraded.
+func WithSNAT(ips ...net.IP) Option {
+	return func(s *natServer) {
+		s.snatIPs = append(s.snatIPs, ips...)
+	}
+}
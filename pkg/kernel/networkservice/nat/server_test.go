@@ -0,0 +1,186 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nat_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/nat"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nft"
+)
+
+const ifName = "nsm-1"
+
+// fakeNFT saves the last applied script: `nft -f -`
+const fakeNFT = `#!/bin/sh
+cat > "$(dirname "$0")/script"
+`
+
+func withFakeNFT(t *testing.T) (script func() string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "nat")
+	require.NoError(t, err)
+
+	binary := filepath.Join(dir, "nft")
+	require.NoError(t, ioutil.WriteFile(binary, []byte(fakeNFT), 0700))
+
+	original := nft.Binary
+	nft.Binary = binary
+
+	return func() string {
+			data, _ := ioutil.ReadFile(filepath.Join(dir, "script"))
+			return string(data)
+		}, func() {
+			nft.Binary = original
+			_ = os.RemoveAll(dir)
+		}
+}
+
+func newConn(srcIPAddr string) *networkservice.Connection {
+	return &networkservice.Connection{
+		Id: "conn-1",
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+		Context: &networkservice.ConnectionContext{
+			IpContext: &networkservice.IPContext{
+				SrcIpAddr: srcIPAddr,
+			},
+		},
+	}
+}
+
+func TestNATServer(t *testing.T) {
+	script, cleanup := withFakeNFT(t)
+	defer cleanup()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		nat.NewServer(
+			nat.WithOutInterface("eth0"),
+			nat.WithSNAT(net.ParseIP("192.168.0.1")),
+		),
+	)
+
+	conn := newConn("10.0.0.1/24,fd00::1/64")
+	conn.Labels = map[string]string{
+		nat.DNATLabel: "tcp:8080:80, udp:53",
+	}
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	require.Contains(t, script(), "\t\ttype nat hook postrouting priority 100;\n"+
+		"\t\tiifname \"nsm-1\" oifname \"eth0\" ip saddr 10.0.0.0/24 snat ip to 192.168.0.1\n"+
+		"\t\tiifname \"nsm-1\" oifname \"eth0\" ip6 saddr fd00::/64 masquerade\n")
+	require.Contains(t, script(), "\t\ttype nat hook prerouting priority -100;\n"+
+		"\t\tiifname != \"nsm-1\" meta nfproto ipv4 tcp dport 8080 dnat ip to 10.0.0.1:80\n"+
+		"\t\tiifname != \"nsm-1\" meta nfproto ipv4 udp dport 53 dnat ip to 10.0.0.1:53\n"+
+		"\t\tiifname != \"nsm-1\" meta nfproto ipv6 tcp dport 8080 dnat ip6 to [fd00::1]:80\n"+
+		"\t\tiifname != \"nsm-1\" meta nfproto ipv6 udp dport 53 dnat ip6 to [fd00::1]:53\n")
+
+	// refresh replaces the whole table
+	conn.GetContext().GetIpContext().SrcIpAddr = "10.0.1.1/24"
+	delete(conn.Labels, nat.DNATLabel)
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Contains(t, script(), "ip saddr 10.0.1.0/24 snat ip to 192.168.0.1\n")
+	require.NotContains(t, script(), "ip6 saddr")
+	require.NotContains(t, script(), "dnat")
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.NotContains(t, script(), "saddr")
+	require.Contains(t, script(), "delete table inet ")
+}
+
+func TestNATServer_SrcIPAddrDropped(t *testing.T) {
+	script, cleanup := withFakeNFT(t)
+	defer cleanup()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		nat.NewServer(),
+	)
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: newConn("10.0.0.1/24"),
+	})
+	require.NoError(t, err)
+	require.Contains(t, script(), "masquerade")
+
+	// refresh with no source IP addresses deletes the table
+	conn.GetContext().GetIpContext().SrcIpAddr = ""
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.NotContains(t, script(), "masquerade")
+	require.Contains(t, script(), "delete table inet ")
+
+	// nothing to delete on Close
+	require.NoError(t, ioutil.WriteFile(filepath.Join(filepath.Dir(nft.Binary), "script"), nil, 0600))
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, script())
+}
+
+func TestNATServer_RequestFailed(t *testing.T) {
+	script, cleanup := withFakeNFT(t)
+	defer cleanup()
+
+	_, err := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		nat.NewServer(),
+		injecterror.NewServer(),
+	).Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: newConn("10.0.0.1/24"),
+	})
+	require.Error(t, err)
+	require.NotContains(t, script(), "masquerade")
+}
+
+func TestNATServer_InvalidDNAT(t *testing.T) {
+	_, cleanup := withFakeNFT(t)
+	defer cleanup()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		nat.NewServer(),
+	)
+
+	for _, value := range []string{"sctp:80", "tcp", "tcp:0", "tcp:80:65536", "tcp:80:80:80"} {
+		conn := newConn("10.0.0.1/24")
+		conn.Labels = map[string]string{
+			nat.DNATLabel: value,
+		}
+		_, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+		require.Error(t, err, value)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netem
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+)
+
+// qdiscMajor differs from the qos root qdisc one, so Close doesn't delete the root qdisc installed by qos
+const qdiscMajor = 0x4e01
+
+func setNetem(link netlink.Link, values *impairments) error {
+	qdisc := netlink.NewNetem(netlink.QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    netlink.MakeHandle(qdiscMajor, 0),
+		Parent:    netlink.HANDLE_ROOT,
+	}, netlink.NetemQdiscAttrs{
+		Latency:     uint32(values.delay.Microseconds()),
+		Jitter:      uint32(values.jitter.Microseconds()),
+		Loss:        values.loss,
+		ReorderProb: values.reorder,
+	})
+	if err := netlink.QdiscReplace(qdisc); err != nil {
+		return errors.Wrapf(err, "failed to set netem qdisc: %v", link.Attrs().Name)
+	}
+	return nil
+}
+
+func delNetem(link netlink.Link) error {
+	qdisc := &netlink.GenericQdisc{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(qdiscMajor, 0),
+			Parent:    netlink.HANDLE_ROOT,
+		},
+	}
+	// the root qdisc has another handle if it is already replaced
+	if err := netlink.QdiscDel(qdisc); err != nil && !os.IsNotExist(err) && err != syscall.EINVAL {
+		return errors.Wrapf(err, "failed to delete netem qdisc: %v", link.Attrs().Name)
+	}
+	return nil
+}
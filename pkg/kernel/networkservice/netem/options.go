@@ -0,0 +1,47 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netem
+
+import "time"
+
+// Option is an option for the netem server
+type Option func(s *netemServer)
+
+// WithDelay sets the connection traffic delay with jitter. It can be overridden per connection with the DelayLabel
+// and JitterLabel labels.
+func WithDelay(delay, jitter time.Duration) Option {
+	return func(s *netemServer) {
+		s.delay = delay
+		s.jitter = jitter
+	}
+}
+
+// WithLoss sets the connection traffic loss in percents. It can be overridden per connection with the LossLabel
+// label.
+func WithLoss(loss float32) Option {
+	return func(s *netemServer) {
+		s.loss = loss
+	}
+}
+
+// WithReorder sets the percent of the connection traffic sent without delay. It can be overridden per connection
+// with the ReorderLabel label.
+func WithReorder(reorder float32) Option {
+	return func(s *netemServer) {
+		s.reorder = reorder
+	}
+}
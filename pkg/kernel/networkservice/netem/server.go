@@ -0,0 +1,149 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package netem provides chain element injecting datapath impairments into the connection traffic for testing
+package netem
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const (
+	// DelayLabel is a connection label setting the connection traffic delay: "100ms"
+	DelayLabel = "netemDelay"
+	// JitterLabel is a connection label setting the connection traffic delay jitter: "10ms"
+	JitterLabel = "netemJitter"
+	// LossLabel is a connection label setting the connection traffic loss in percents: "0.5"
+	LossLabel = "netemLoss"
+	// ReorderLabel is a connection label setting the percent of the connection traffic sent immediately, so it is
+	// reordered with the delayed one: "25"
+	ReorderLabel = "netemReorder"
+)
+
+// impairments are netem qdisc parameters, zero value means "no impairment"
+type impairments struct {
+	delay   time.Duration
+	jitter  time.Duration
+	loss    float32
+	reorder float32
+}
+
+type netemServer struct {
+	impairments
+}
+
+// NewServer returns a new netem server chain element. It replaces the root qdisc of the connection kernel interface
+// with netem qdisc delaying, dropping and reordering the connection egress traffic as set with the options and labels,
+// the qdisc is deleted on Close. It should be used in the test environments only and should be placed after the netns
+// chain element. It replaces the qos root qdisc, so they shouldn't be used for the same connection.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	s := &netemServer{}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+func (s *netemServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	mech := kernel.ToMechanism(request.GetConnection().GetMechanism())
+	if mech == nil {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	values, err := s.withLabels(request.GetConnection().GetLabels())
+	if err != nil {
+		return nil, err
+	}
+	if *values == (impairments{}) {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	ifName := mech.GetInterfaceName(request.GetConnection())
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+	if err = setNetem(link, values); err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if delErr := delNetem(link); delErr != nil {
+			log.Entry(ctx).Warnf("failed to delete netem qdisc: %s", delErr.Error())
+		}
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (s *netemServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
+		if values, err := s.withLabels(conn.GetLabels()); err == nil && *values != (impairments{}) {
+			if link, err := netlink.LinkByName(mech.GetInterfaceName(conn)); err == nil {
+				if err := delNetem(link); err != nil {
+					log.Entry(ctx).Warnf("failed to delete netem qdisc: %s", err.Error())
+				}
+			}
+		}
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+// withLabels returns a copy of impairments overridden with the values from connection labels
+func (s *netemServer) withLabels(labels map[string]string) (*impairments, error) {
+	values := s.impairments
+	for label, value := range map[string]*time.Duration{
+		DelayLabel:  &values.delay,
+		JitterLabel: &values.jitter,
+	} {
+		if labelValue, ok := labels[label]; ok {
+			duration, err := time.ParseDuration(labelValue)
+			if err != nil || duration < 0 {
+				return nil, errors.Errorf("invalid %s label value: %v", label, labelValue)
+			}
+			*value = duration
+		}
+	}
+	for label, value := range map[string]*float32{
+		LossLabel:    &values.loss,
+		ReorderLabel: &values.reorder,
+	} {
+		if labelValue, ok := labels[label]; ok {
+			percent, err := strconv.ParseFloat(labelValue, 32)
+			if err != nil || percent < 0 || percent > 100 {
+				return nil, errors.Errorf("invalid %s label value: %v", label, labelValue)
+			}
+			*value = float32(percent)
+		}
+	}
+	if values.reorder != 0 && values.delay == 0 {
+		return nil, errors.New("netem reordering requires delay")
+	}
+	return &values, nil
+}
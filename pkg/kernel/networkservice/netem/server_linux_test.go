@@ -0,0 +1,114 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netem_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/netem"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName   = "netem-1"
+	peerName = "netem-2"
+)
+
+func request(labels map[string]string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.InterfaceNameKey: ifName,
+				},
+			},
+			Labels: labels,
+		},
+	}
+}
+
+func netemQdisc(t *testing.T, link netlink.Link) *netlink.Netem {
+	qdiscs, err := netlink.QdiscList(link)
+	require.NoError(t, err)
+	for _, qdisc := range qdiscs {
+		if netemQdisc, ok := qdisc.(*netlink.Netem); ok {
+			return netemQdisc
+		}
+	}
+	return nil
+}
+
+func TestNetemServer(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	server := netem.NewServer(netem.WithDelay(100*time.Millisecond, 10*time.Millisecond))
+
+	conn, err := server.Request(context.TODO(), request(nil))
+	if errors.Cause(err) == syscall.ENOENT {
+		t.Skip("netem qdisc is not supported by the kernel")
+	}
+	require.NoError(t, err)
+	qdisc := netemQdisc(t, link)
+	require.NotNil(t, qdisc)
+	require.Zero(t, qdisc.Loss)
+
+	// labels override the options
+	conn, err = server.Request(context.TODO(), request(map[string]string{
+		netem.LossLabel:    "10",
+		netem.ReorderLabel: "25",
+	}))
+	require.NoError(t, err)
+	qdisc = netemQdisc(t, link)
+	require.NotNil(t, qdisc)
+	require.Equal(t, netlink.Percentage2u32(10), qdisc.Loss)
+	require.Equal(t, netlink.Percentage2u32(25), qdisc.ReorderProb)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Nil(t, netemQdisc(t, link))
+}
+
+func TestNetemServer_InvalidLabels(t *testing.T) {
+	server := netem.NewServer()
+
+	for _, labels := range []map[string]string{
+		{netem.DelayLabel: "fast"},
+		{netem.LossLabel: "101"},
+		{netem.ReorderLabel: "25"},
+	} {
+		_, err := server.Request(context.TODO(), request(labels))
+		require.Error(t, err, labels)
+	}
+}
+
+func TestNetemServer_NoImpairments(t *testing.T) {
+	// there is no net interface, but nothing should be applied
+	_, err := netem.NewServer().Request(context.TODO(), request(nil))
+	require.NoError(t, err)
+}
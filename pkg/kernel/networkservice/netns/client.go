@@ -0,0 +1,66 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netns
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type netNSClient struct{}
+
+// NewClient returns a new net NS client chain element running the rest of the chain in the kernel mechanism net NS.
+// The connection has no mechanism before the first Request returns, so the first preferred kernel mechanism is used
+// then.
+func NewClient() networkservice.NetworkServiceClient {
+	return &netNSClient{}
+}
+
+func (c *netNSClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (conn *networkservice.Connection, err error) {
+	err = runIn(requestMechanism(request), func() error {
+		conn, err = next.Client(ctx).Request(ctx, request, opts...)
+		return err
+	})
+	return conn, err
+}
+
+func (c *netNSClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (_ *empty.Empty, err error) {
+	err = runIn(kernel.ToMechanism(conn.GetMechanism()), func() error {
+		_, err = next.Client(ctx).Close(ctx, conn, opts...)
+		return err
+	})
+	return &empty.Empty{}, err
+}
+
+// requestMechanism returns the kernel mechanism of the refreshed connection or the first preferred kernel mechanism
+func requestMechanism(request *networkservice.NetworkServiceRequest) *kernel.Mechanism {
+	if mech := kernel.ToMechanism(request.GetConnection().GetMechanism()); mech != nil {
+		return mech
+	}
+	for _, mechanism := range request.GetMechanismPreferences() {
+		if mech := kernel.ToMechanism(mechanism); mech != nil {
+			return mech
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netns
+
+import (
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// runIn runs the runner in the kernel mechanism net NS and switches back to the current net NS, it runs the runner in
+// the current net NS if there is no kernel mechanism
+func runIn(mech *kernel.Mechanism, runner func() error) error {
+	if mech == nil {
+		return runner()
+	}
+
+	curNetNS, err := nshandle.Current()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = curNetNS.Close() }()
+
+	mechNetNS, err := nshandle.FromURL(mech.GetNetNSURL())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = mechNetNS.Close() }()
+
+	return nshandle.RunIn(curNetNS, mechNetNS, runner)
+}
@@ -21,70 +21,31 @@ import (
 	"context"
 
 	"github.com/golang/protobuf/ptypes/empty"
-	"github.com/vishvananda/netns"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
-
-	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
 )
 
 type netNSServer struct{}
 
-// NewServer returns a new net NS server chain element
+// NewServer returns a new net NS server chain element running the rest of the chain in the kernel mechanism net NS
 func NewServer() networkservice.NetworkServiceServer {
 	return &netNSServer{}
 }
 
 func (s *netNSServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (conn *networkservice.Connection, err error) {
-	if mech := kernel.ToMechanism(request.GetConnection().GetMechanism()); mech != nil {
-		var forwarderNetNS netns.NsHandle
-		forwarderNetNS, err = nshandle.Current()
-		if err != nil {
-			return nil, err
-		}
-		defer func() { _ = forwarderNetNS.Close() }()
-
-		var clientNetNS netns.NsHandle
-		clientNetNS, err = nshandle.FromURL(mech.GetNetNSURL())
-		if err != nil {
-			return nil, err
-		}
-		defer func() { _ = clientNetNS.Close() }()
-
-		err = nshandle.RunIn(forwarderNetNS, clientNetNS, func() error {
-			conn, err = next.Server(ctx).Request(ctx, request)
-			return err
-		})
-	} else {
+	err = runIn(kernel.ToMechanism(request.GetConnection().GetMechanism()), func() error {
 		conn, err = next.Server(ctx).Request(ctx, request)
-	}
+		return err
+	})
 	return conn, err
 }
 
 func (s *netNSServer) Close(ctx context.Context, conn *networkservice.Connection) (_ *empty.Empty, err error) {
-	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
-		var forwarderNetNS netns.NsHandle
-		forwarderNetNS, err = nshandle.Current()
-		if err != nil {
-			return nil, err
-		}
-		defer func() { _ = forwarderNetNS.Close() }()
-
-		var clientNetNS netns.NsHandle
-		clientNetNS, err = nshandle.FromURL(mech.GetNetNSURL())
-		if err != nil {
-			return nil, err
-		}
-		defer func() { _ = clientNetNS.Close() }()
-
-		err = nshandle.RunIn(forwarderNetNS, clientNetNS, func() error {
-			_, err = next.Server(ctx).Close(ctx, conn)
-			return err
-		})
-	} else {
+	err = runIn(kernel.ToMechanism(conn.GetMechanism()), func() error {
 		_, err = next.Server(ctx).Close(ctx, conn)
-	}
+		return err
+	})
 	return &empty.Empty{}, err
 }
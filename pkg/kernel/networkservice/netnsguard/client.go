@@ -0,0 +1,84 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netnsguard
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type netNSGuardClient struct{}
+
+// NewClient returns a new client chain element recording the net NS inode selected by the returned connection
+// mechanism on Request and returning NetNSChangedError on refresh and Close if the net NS referenced by the process
+// PID is changed. Close is still passed down the chain with the Client's net NS handled as deleted, so the resources
+// are freed without mutating the unrelated net NS.
+func NewClient() networkservice.NetworkServiceClient {
+	return &netNSGuardClient{}
+}
+
+func (c *netNSGuardClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	isClient := metadata.IsClient(c)
+
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if expected, ok := load(ctx, isClient); ok {
+		if err := validate(conn, expected); err != nil {
+			return nil, err
+		}
+		return conn, nil
+	}
+
+	ino, ok, err := inode(conn)
+	if err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+	if ok {
+		store(ctx, isClient, ino)
+	}
+
+	return conn, nil
+}
+
+func (c *netNSGuardClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	expected, ok := load(ctx, metadata.IsClient(c))
+	if !ok {
+		return next.Client(ctx).Close(ctx, conn, opts...)
+	}
+
+	changedErr := validate(conn, expected)
+	if changedErr == nil {
+		return next.Client(ctx).Close(ctx, conn, opts...)
+	}
+
+	// the rest of the chain is closed, but the unrelated net NS is not mutated
+	if _, err := next.Client(ctx).Close(ctx, withDeletedNetNS(conn), opts...); err != nil {
+		return nil, errors.Wrap(changedErr, err.Error())
+	}
+	return nil, changedErr
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package netnsguard provides chain elements protecting the Client's net NS referenced by the process PID
+// (/proc/<pid>/ns/net) from the PID reuse: the net NS inode is recorded on Request and validated on refresh and Close,
+// so the kernel chain elements never mutate an unrelated pod having the same PID
+package netnsguard
+
+import (
+	"net/url"
+	"regexp"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// deletedNetNSURL is the net NS URL which never exists: PID 0 has no /proc entry
+const deletedNetNSURL = "file:///proc/0/ns/net"
+
+var pidNetNSPath = regexp.MustCompile(`^/proc/[0-9]+/ns/net$`)
+
+// NetNSChangedError is returned when the Client's net NS referenced by the process PID is not the one recorded on
+// Request, so the PID is reused by another process
+type NetNSChangedError struct {
+	// NetNSURL is the Client's net NS URL
+	NetNSURL string
+	// Expected is the net NS inode recorded on Request
+	Expected uint64
+	// Actual is the current net NS inode
+	Actual uint64
+}
+
+func (e *NetNSChangedError) Error() string {
+	return "net NS is changed, the PID is probably reused: " + e.NetNSURL
+}
+
+// IsNetNSChanged returns true if err is caused by NetNSChangedError
+func IsNetNSChanged(err error) bool {
+	for err != nil {
+		if _, ok := err.(*NetNSChangedError); ok {
+			return true
+		}
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			return false
+		}
+		err = cause.Cause()
+	}
+	return false
+}
+
+// inode returns the inode of the Client's net NS, it returns false if the connection mechanism is not kernel or the
+// net NS is not referenced by the process PID
+func inode(conn *networkservice.Connection) (uint64, bool, error) {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return 0, false, nil
+	}
+
+	netNSURL, err := url.Parse(mech.GetNetNSURL())
+	if err != nil || !pidNetNSPath.MatchString(netNSURL.Path) {
+		return 0, false, nil
+	}
+
+	handle, err := nshandle.FromURL(mech.GetNetNSURL())
+	if err != nil {
+		return 0, true, err
+	}
+	defer func() { _ = handle.Close() }()
+
+	ino, err := nshandle.Inode(handle)
+	if err != nil {
+		return 0, true, err
+	}
+	return ino, true, nil
+}
+
+// validate returns NetNSChangedError if the Client's net NS inode is not the expected one. If the net NS cannot be
+// opened, the process is gone and there is nothing to protect.
+func validate(conn *networkservice.Connection, expected uint64) error {
+	actual, ok, err := inode(conn)
+	if !ok || err != nil {
+		return nil
+	}
+	if actual != expected {
+		return &NetNSChangedError{
+			NetNSURL: kernel.ToMechanism(conn.GetMechanism()).GetNetNSURL(),
+			Expected: expected,
+			Actual:   actual,
+		}
+	}
+	return nil
+}
+
+// withDeletedNetNS returns the connection copy with the Client's net NS URL replaced by the never existing one. The
+// Client's net NS recorded on Request is deleted when the PID is reused, so the kernel chain elements handle the
+// copy as the connection with the deleted Client's net NS: they free the resources outside of it and don't mutate the
+// unrelated net NS referenced by the reused PID.
+func withDeletedNetNS(conn *networkservice.Connection) *networkservice.Connection {
+	conn = conn.Clone()
+	kernel.ToMechanism(conn.GetMechanism()).GetParameters()[kernel.NetNSURL] = deletedNetNSURL
+	return conn
+}
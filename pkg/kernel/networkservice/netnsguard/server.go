@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netnsguard
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type netNSGuardServer struct{}
+
+// NewServer returns a new server chain element recording the Client's net NS inode on Request and returning
+// NetNSChangedError on refresh and Close if the net NS referenced by the process PID is changed. Close is still passed
+// down the chain with the Client's net NS handled as deleted, so the resources are freed without mutating the
+// unrelated net NS.
+func NewServer() networkservice.NetworkServiceServer {
+	return &netNSGuardServer{}
+}
+
+func (s *netNSGuardServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	isClient := metadata.IsClient(s)
+
+	if expected, ok := load(ctx, isClient); ok {
+		if err := validate(request.GetConnection(), expected); err != nil {
+			return nil, err
+		}
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	ino, ok, err := inode(request.GetConnection())
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		store(ctx, isClient, ino)
+	}
+
+	return conn, nil
+}
+
+func (s *netNSGuardServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	expected, ok := load(ctx, metadata.IsClient(s))
+	if !ok {
+		return next.Server(ctx).Close(ctx, conn)
+	}
+
+	changedErr := validate(conn, expected)
+	if changedErr == nil {
+		return next.Server(ctx).Close(ctx, conn)
+	}
+
+	// the rest of the chain is closed, but the unrelated net NS is not mutated
+	if _, err := next.Server(ctx).Close(ctx, withDeletedNetNS(conn)); err != nil {
+		return nil, errors.Wrap(changedErr, err.Error())
+	}
+	return nil, changedErr
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netnsguard_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/netnsguard"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+func TestNetNSGuardServer_PIDReuse(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	// the thread ID path points to the net NS of the locked thread
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.NetNSURL: fmt.Sprintf("file:///proc/%d/ns/net", unix.Gettid()),
+				},
+			},
+		},
+	}
+
+	closed := new(closeRecorder)
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		netnsguard.NewServer(),
+		closed,
+	)
+
+	conn, err := server.Request(context.TODO(), request.Clone())
+	require.NoError(t, err)
+
+	// refresh in the same net NS
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	request.GetConnection().Id = "another-conn"
+	anotherConn, err := server.Request(context.TODO(), request)
+	require.NoError(t, err)
+
+	// the process with the same PID is in another net NS now
+	netNSName := uuid.New().String()
+	newNetNS, err := netns.NewNamed(netNSName)
+	require.NoError(t, err)
+	defer func() {
+		_ = netns.Set(curNetNS)
+		_ = newNetNS.Close()
+		_ = netns.DeleteNamed(netNSName)
+	}()
+
+	_, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.True(t, netnsguard.IsNetNSChanged(err))
+
+	_, err = server.Close(context.TODO(), anotherConn)
+	require.True(t, netnsguard.IsNetNSChanged(errors.Wrap(err, "wrapped")))
+
+	// Close is passed down, but not into the unrelated net NS
+	require.NotNil(t, closed.conn)
+	require.NotEqual(t, request.GetConnection().GetMechanism().GetParameters()[kernel.NetNSURL],
+		closed.conn.GetMechanism().GetParameters()[kernel.NetNSURL])
+	_, err = nshandle.FromURL(closed.conn.GetMechanism().GetParameters()[kernel.NetNSURL])
+	require.True(t, os.IsNotExist(errors.Cause(err)))
+}
+
+type closeRecorder struct {
+	conn *networkservice.Connection
+}
+
+func (r *closeRecorder) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (r *closeRecorder) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	r.conn = conn
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func TestNetNSGuardServer_NamedNetNS(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		netnsguard.NewServer(),
+	)
+
+	// net NS not referenced by the process PID is not validated
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.NetNSURL: "file:///run/netns/not-exists",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
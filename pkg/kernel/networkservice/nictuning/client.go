@@ -0,0 +1,79 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nictuning
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type nicTuningClient struct {
+	*tuner
+}
+
+// NewClient returns a new client chain element tuning the Endpoint's net interface in its net NS via ethtool netlink
+// on Request and restoring the original settings on Close. Only physical NICs and VFs are tuned, virtual net
+// interfaces are skipped.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &nicTuningClient{
+		tuner: newTuner(options),
+	}
+}
+
+func (c *nicTuningClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	orig, err := c.apply(conn)
+	if err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	// the original settings stored on the first change shouldn't be overwritten by refresh
+	if stored, _ := load(ctx, metadata.IsClient(c)); stored == nil && orig != nil {
+		store(ctx, metadata.IsClient(c), orig)
+	}
+
+	return conn, nil
+}
+
+func (c *nicTuningClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var restoreErr error
+	if orig, ok := loadAndDelete(ctx, metadata.IsClient(c)); ok {
+		restoreErr = restore(conn, orig)
+	}
+
+	if err != nil && restoreErr != nil {
+		return nil, errors.Wrap(err, restoreErr.Error())
+	}
+	if restoreErr != nil {
+		return nil, restoreErr
+	}
+	return &empty.Empty{}, err
+}
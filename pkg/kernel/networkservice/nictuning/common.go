@@ -0,0 +1,158 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nictuning
+
+import (
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ethtool"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// deviceLinkType is the netlink link type of the physical NICs and SR-IOV VFs
+const deviceLinkType = "device"
+
+// original is the device settings before the tuner has changed them, only the fields set in the masks have been
+// changed, so only they are restored
+type original struct {
+	rings          *ethtool.Rings
+	ringsFields    ethtool.RingsFields
+	coalesce       *ethtool.Coalesce
+	coalesceFields ethtool.CoalesceFields
+}
+
+// tuner is the common part of the nictuning client and server
+type tuner struct {
+	rings    *ethtool.Rings
+	coalesce *ethtool.Coalesce
+}
+
+func newTuner(options []Option) *tuner {
+	t := &tuner{}
+	for _, opt := range options {
+		opt(t)
+	}
+	return t
+}
+
+// apply tunes the connection kernel interface in its net NS if it is a physical NIC or VF and returns the original
+// settings, it returns nil if nothing has been changed
+func (t *tuner) apply(conn *networkservice.Connection) (*original, error) {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil || (t.rings == nil && t.coalesce == nil) {
+		return nil, nil
+	}
+
+	var orig *original
+	err := nshandle.RunInURL(mech.GetNetNSURL(), func() error {
+		ifName := mech.GetInterfaceName(conn)
+		link, err := netlink.LinkByName(ifName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+		}
+		if link.Type() != deviceLinkType {
+			return nil
+		}
+
+		orig = new(original)
+		if err = t.setRings(ifName, orig); err != nil {
+			return err
+		}
+		if err = t.setCoalesce(ifName, orig); err != nil {
+			if orig.rings != nil {
+				_ = ethtool.SetRingsFields(ifName, orig.rings, orig.ringsFields)
+			}
+			return err
+		}
+		return nil
+	})
+	return orig, err
+}
+
+// setRings sets the ring buffer sizes and records the original values with the mask of the changed ones to orig
+func (t *tuner) setRings(ifName string, orig *original) error {
+	if t.rings == nil {
+		return nil
+	}
+	current, err := ethtool.GetRings(ifName)
+	if err != nil {
+		return err
+	}
+	if (current.RXMax != 0 && t.rings.RX > current.RXMax) || (current.TXMax != 0 && t.rings.TX > current.TXMax) {
+		return errors.Errorf("ring buffer sizes %+v exceed the device max sizes %+v: %v", *t.rings, *current, ifName)
+	}
+
+	if err := ethtool.SetRings(ifName, t.rings); err != nil {
+		return err
+	}
+	orig.rings, orig.ringsFields = current, t.rings.Fields()
+	return nil
+}
+
+// setCoalesce sets the interrupt coalescing parameters and records the original values with the mask of the changed
+// ones to orig
+func (t *tuner) setCoalesce(ifName string, orig *original) error {
+	if t.coalesce == nil {
+		return nil
+	}
+	current, err := ethtool.GetCoalesce(ifName)
+	if err != nil {
+		return err
+	}
+
+	if err := ethtool.SetCoalesce(ifName, t.coalesce); err != nil {
+		return err
+	}
+	orig.coalesce, orig.coalesceFields = current, t.coalesce.Fields()
+	return nil
+}
+
+// restore sets back the original settings to the connection kernel interface, already deleted net interface is
+// skipped
+func restore(conn *networkservice.Connection, orig *original) error {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil || orig == nil {
+		return nil
+	}
+
+	return nshandle.RunInURL(mech.GetNetNSURL(), func() error {
+		ifName := mech.GetInterfaceName(conn)
+		if _, err := netlink.LinkByName(ifName); err != nil {
+			return nil
+		}
+
+		var ringsErr, coalesceErr error
+		if orig.rings != nil {
+			ringsErr = ethtool.SetRingsFields(ifName, orig.rings, orig.ringsFields)
+		}
+		if orig.coalesce != nil {
+			coalesceErr = ethtool.SetCoalesceFields(ifName, orig.coalesce, orig.coalesceFields)
+		}
+
+		if ringsErr != nil && coalesceErr != nil {
+			return errors.Wrap(ringsErr, coalesceErr.Error())
+		}
+		if ringsErr != nil {
+			return ringsErr
+		}
+		return coalesceErr
+	})
+}
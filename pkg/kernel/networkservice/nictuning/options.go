@@ -0,0 +1,38 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nictuning
+
+import (
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ethtool"
+)
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(t *tuner)
+
+// WithRings sets the RX and TX ring buffer sizes, 0 keeps the current size
+func WithRings(rx, tx uint32) Option {
+	return func(t *tuner) {
+		t.rings = &ethtool.Rings{RX: rx, TX: tx}
+	}
+}
+
+// WithCoalesce sets the interrupt coalescing parameters, 0 fields keep the current values
+func WithCoalesce(coalesce ethtool.Coalesce) Option {
+	return func(t *tuner) {
+		t.coalesce = &coalesce
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nictuning provides chain elements tuning ring buffer sizes and interrupt coalescing of the physical NIC and
+// SR-IOV VF connection kernel interfaces for the high throughput
+package nictuning
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type nicTuningServer struct {
+	*tuner
+}
+
+// NewServer returns a new server chain element tuning the Client's net interface in its net NS via ethtool netlink on
+// Request and restoring the original settings on Close. Only physical NICs and VFs are tuned, virtual net interfaces
+// are skipped. It should follow the inject server in the chain, so the settings are restored before the device is
+// returned to the Forwarder's net NS.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &nicTuningServer{
+		tuner: newTuner(options),
+	}
+}
+
+func (s *nicTuningServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	// settings are applied on each Request, but the original ones stored on the first change shouldn't be
+	// overwritten by refresh
+	stored, _ := load(ctx, metadata.IsClient(s))
+
+	orig, err := s.apply(request.GetConnection())
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if restoreErr := restore(request.GetConnection(), orig); restoreErr != nil {
+			log.Entry(ctx).WithField("nicTuningServer", "Request").Warnf("failed to restore NIC settings: %s", restoreErr.Error())
+		}
+		return nil, err
+	}
+
+	if stored == nil && orig != nil {
+		store(ctx, metadata.IsClient(s), orig)
+	}
+
+	return conn, nil
+}
+
+func (s *nicTuningServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var restoreErr error
+	if orig, ok := loadAndDelete(ctx, metadata.IsClient(s)); ok {
+		restoreErr = restore(conn, orig)
+	}
+
+	if err != nil && restoreErr != nil {
+		return nil, errors.Wrap(err, restoreErr.Error())
+	}
+	if restoreErr != nil {
+		return nil, restoreErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nictuning_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/nictuning"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ethtool"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName   = "nictuning-1"
+	peerName = "nictuning-2"
+)
+
+func request() *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.NetNSURL:         "file:///proc/self/ns/net",
+					kernel.InterfaceNameKey: ifName,
+				},
+			},
+		},
+	}
+}
+
+func TestNICTuningServer_VirtualSkipped(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		nictuning.NewServer(
+			nictuning.WithRings(4096, 4096),
+			nictuning.WithCoalesce(ethtool.Coalesce{RXUsecs: 50}),
+		),
+	)
+
+	// veth doesn't support ring buffer tuning, so the error means it hasn't been skipped
+	conn, err := server.Request(context.TODO(), request())
+	require.NoError(t, err)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
+
+func TestNICTuningServer_NoNetInterface(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		nictuning.NewServer(nictuning.WithRings(4096, 4096)),
+	)
+
+	_, err := server.Request(context.TODO(), request())
+	require.Error(t, err)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsbreaker
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type nsBreakerClient struct {
+	*breaker
+}
+
+// NewClient returns a new circuit breaker client chain element, see NewServer. The net NS URL is taken from the
+// kernel mechanism preference until the connection mechanism is selected.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &nsBreakerClient{
+		breaker: newBreaker(options),
+	}
+}
+
+func (c *nsBreakerClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	url := netNSURL(request)
+	if err := c.allow(url); err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	c.done(ctx, url, err)
+	return conn, err
+}
+
+func (c *nsBreakerClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsbreaker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+)
+
+const (
+	defaultThreshold = 5
+	defaultCooldown  = 30 * time.Second
+)
+
+// OpenError is returned without calling the next chain elements while the circuit breaker of the Client's net NS is
+// open
+type OpenError struct {
+	// NetNSURL is the Client's net NS URL
+	NetNSURL string
+	// Failures is the number of the consecutive failures opened the circuit breaker
+	Failures int
+	// Until is the time the next Request is let through to probe the net NS again
+	Until time.Time
+}
+
+func (e *OpenError) Error() string {
+	return "net NS keeps failing, requests are rejected until " + e.Until.Format(time.RFC3339) + ": " + e.NetNSURL
+}
+
+// IsOpen returns true if err is caused by OpenError
+func IsOpen(err error) bool {
+	for err != nil {
+		if _, ok := err.(*OpenError); ok {
+			return true
+		}
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			return false
+		}
+		err = cause.Cause()
+	}
+	return false
+}
+
+type state struct {
+	failures    int
+	lastFailure time.Time
+	openUntil   time.Time
+	probing     bool
+}
+
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu     sync.Mutex
+	states map[string]*state
+}
+
+func newBreaker(options []Option) *breaker {
+	b := &breaker{
+		threshold: defaultThreshold,
+		cooldown:  defaultCooldown,
+		states:    make(map[string]*state),
+	}
+	for _, opt := range options {
+		opt(b)
+	}
+	return b
+}
+
+// netNSURL returns the Client's net NS URL from the connection mechanism or, if the mechanism is not selected yet,
+// from the kernel mechanism preference. It returns "" if there is no kernel mechanism.
+func netNSURL(request *networkservice.NetworkServiceRequest) string {
+	if mech := kernel.ToMechanism(request.GetConnection().GetMechanism()); mech != nil {
+		return mech.GetNetNSURL()
+	}
+	for _, mechanism := range request.GetMechanismPreferences() {
+		if mech := kernel.ToMechanism(mechanism); mech != nil {
+			return mech.GetNetNSURL()
+		}
+	}
+	return ""
+}
+
+// allow returns OpenError if the circuit breaker is open. Once the cooldown passes, a single Request is let through
+// (half-open state), its result closes the circuit breaker or opens it for one more cooldown.
+func (b *breaker) allow(url string) error {
+	if url == "" {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.states[url]
+	if !ok || s.failures < b.threshold {
+		return nil
+	}
+	if s.probing || time.Now().Before(s.openUntil) {
+		return &OpenError{
+			NetNSURL: url,
+			Failures: s.failures,
+			Until:    s.openUntil,
+		}
+	}
+	s.probing = true
+	return nil
+}
+
+// done records the Request result
+func (b *breaker) done(ctx context.Context, url string, err error) {
+	if url == "" {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		delete(b.states, url)
+		return
+	}
+
+	s, ok := b.states[url]
+	// context errors are caused by the caller, not by the net NS
+	if ctx.Err() != nil || errors.Cause(err) == context.Canceled || errors.Cause(err) == context.DeadlineExceeded {
+		if ok {
+			s.probing = false
+		}
+		return
+	}
+
+	now := time.Now()
+	b.prune(now)
+	if !ok {
+		s = new(state)
+		b.states[url] = s
+	}
+	s.failures++
+	s.lastFailure = now
+	s.probing = false
+	if s.failures >= b.threshold {
+		s.openUntil = now.Add(b.cooldown)
+	}
+}
+
+// prune deletes the states of the net NSes not failed for a cooldown, so the states of the deleted net NSes don't
+// pile up
+func (b *breaker) prune(now time.Time) {
+	for url, s := range b.states {
+		if !s.probing && now.Sub(s.lastFailure) > b.cooldown && now.After(s.openUntil) {
+			delete(b.states, url)
+		}
+	}
+}
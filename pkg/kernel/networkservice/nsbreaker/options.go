@@ -0,0 +1,36 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsbreaker
+
+import "time"
+
+// Option is an option pattern for NewClient, NewServer
+type Option func(b *breaker)
+
+// WithThreshold sets the number of the consecutive failures opening the circuit breaker
+func WithThreshold(threshold int) Option {
+	return func(b *breaker) {
+		b.threshold = threshold
+	}
+}
+
+// WithCooldown sets the time the circuit breaker stays open before the next Request is let through
+func WithCooldown(cooldown time.Duration) Option {
+	return func(b *breaker) {
+		b.cooldown = cooldown
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nsbreaker provides chain elements failing fast with OpenError for the Client's net NS failing the
+// Requests persistently, e.g. a pod sandbox being torn down, instead of hammering netlink with the doomed Requests
+package nsbreaker
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+)
+
+type nsBreakerServer struct {
+	*breaker
+}
+
+// NewServer returns a new circuit breaker server chain element. After WithThreshold consecutive Request failures for
+// the same kernel mechanism net NS URL it returns OpenError for the net NS Requests for WithCooldown. Close is always
+// passed through, so the resources are still released.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &nsBreakerServer{
+		breaker: newBreaker(options),
+	}
+}
+
+func (s *nsBreakerServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	url := netNSURL(request)
+	if err := s.allow(url); err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	s.done(ctx, url, err)
+	return conn, err
+}
+
+func (s *nsBreakerServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
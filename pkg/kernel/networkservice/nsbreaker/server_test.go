@@ -0,0 +1,125 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsbreaker_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/nsbreaker"
+)
+
+type countServer struct {
+	err   error
+	count int
+}
+
+func (s *countServer) Request(_ context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.count++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return request.GetConnection(), nil
+}
+
+func (s *countServer) Close(_ context.Context, _ *networkservice.Connection) (*empty.Empty, error) {
+	return &empty.Empty{}, nil
+}
+
+func newRequest(netNSURL string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.NetNSURL: netNSURL,
+				},
+			},
+		},
+	}
+}
+
+func TestNSBreakerServer(t *testing.T) {
+	const cooldown = 100 * time.Millisecond
+
+	counter := &countServer{err: errors.New("no such file or directory")}
+	server := chain.NewNetworkServiceServer(
+		nsbreaker.NewServer(nsbreaker.WithThreshold(3), nsbreaker.WithCooldown(cooldown)),
+		counter,
+	)
+
+	for i := 0; i < 3; i++ {
+		_, err := server.Request(context.TODO(), newRequest("file:///proc/1000/ns/net"))
+		require.Error(t, err)
+		require.False(t, nsbreaker.IsOpen(err))
+	}
+
+	// the circuit breaker is open
+	_, err := server.Request(context.TODO(), newRequest("file:///proc/1000/ns/net"))
+	require.True(t, nsbreaker.IsOpen(err))
+	require.Equal(t, 3, counter.count)
+
+	// Close is passed through
+	_, err = server.Close(context.TODO(), newRequest("file:///proc/1000/ns/net").GetConnection())
+	require.NoError(t, err)
+
+	// another net NS is not affected
+	_, err = server.Request(context.TODO(), newRequest("file:///proc/2000/ns/net"))
+	require.False(t, nsbreaker.IsOpen(err))
+	require.Equal(t, 4, counter.count)
+
+	// the failed probe opens the circuit breaker for one more cooldown
+	time.Sleep(cooldown)
+	_, err = server.Request(context.TODO(), newRequest("file:///proc/1000/ns/net"))
+	require.False(t, nsbreaker.IsOpen(err))
+	_, err = server.Request(context.TODO(), newRequest("file:///proc/1000/ns/net"))
+	require.True(t, nsbreaker.IsOpen(err))
+	require.Equal(t, 5, counter.count)
+
+	// the successful probe closes the circuit breaker
+	time.Sleep(cooldown)
+	counter.err = nil
+	for i := 0; i < 2; i++ {
+		_, err = server.Request(context.TODO(), newRequest("file:///proc/1000/ns/net"))
+		require.NoError(t, err)
+	}
+	require.Equal(t, 7, counter.count)
+}
+
+func TestNSBreakerServer_ContextCanceled(t *testing.T) {
+	counter := &countServer{err: context.Canceled}
+	server := chain.NewNetworkServiceServer(
+		nsbreaker.NewServer(nsbreaker.WithThreshold(1)),
+		counter,
+	)
+
+	for i := 0; i < 2; i++ {
+		_, err := server.Request(context.TODO(), newRequest("file:///proc/1000/ns/net"))
+		require.False(t, nsbreaker.IsOpen(err))
+	}
+	require.Equal(t, 2, counter.count)
+}
@@ -0,0 +1,319 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package orphangc provides opt-in garbage collection of the orphan NSM kernel objects: routes with the NSM routing
+// protocol and IP addresses listed in the net interface alias (see owned) not belonging to any live connection.
+// Orphans are left after Forwarder crashes or failed Close. The Forwarder's net NS and the kernel mechanism net NSs
+// of the connections passed through the Collector chain element are scanned, net NSs of the connections not
+// requested since the Collector start (e.g. not refreshed yet after the Forwarder restart) are not. The net
+// interfaces are identified by the net NS inode and the net interface name, so the same named net interfaces in the
+// different net NSs don't hide each other.
+package orphangc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/owned"
+)
+
+const (
+	defaultInterval    = time.Minute
+	defaultGracePeriod = 5 * time.Minute
+)
+
+// Stats is the garbage collection statistics
+type Stats struct {
+	// Scans is a number of completed scans
+	Scans uint64
+	// Routes is a number of collected routes
+	Routes uint64
+	// Addrs is a number of net interfaces with collected IP addresses
+	Addrs uint64
+	// Errors is a number of objects failed to be collected
+	Errors uint64
+}
+
+// Collector collects the orphan NSM kernel objects in the current net NS and in the connections net NSs. Live
+// connections are the ones passed through the Collector chain element, so the grace period should be greater than the
+// connection refresh period: after the Forwarder restart connections become live again only on refresh.
+type Collector struct {
+	interval    time.Duration
+	gracePeriod time.Duration
+
+	lock     sync.Mutex
+	live     map[string][]liveLink
+	netNSs   map[string]bool
+	orphans  map[string]time.Time
+	stats    Stats
+	scanLock sync.Mutex
+}
+
+// liveLink is the live connection net interface in the net NS given by URL, empty URL means the current net NS
+type liveLink struct {
+	netNSURL string
+	ifName   string
+}
+
+// linkKey identifies the net interface on the node
+type linkKey struct {
+	netNS  uint64
+	ifName string
+}
+
+// New returns a new Collector
+func New(options ...Option) *Collector {
+	c := &Collector{
+		interval:    defaultInterval,
+		gracePeriod: defaultGracePeriod,
+		live:        make(map[string][]liveLink),
+		netNSs:      make(map[string]bool),
+		orphans:     make(map[string]time.Time),
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// Start starts collecting orphans each interval, it stops when ctx is done
+func (c *Collector) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := c.Collect(ctx); err != nil {
+				log.Entry(ctx).Warnf("failed to collect orphan NSM kernel objects: %s", err.Error())
+			}
+		}
+	}()
+}
+
+// Stats returns the garbage collection statistics
+func (c *Collector) Stats() Stats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.stats
+}
+
+// Collect scans the net NSs once and deletes the objects being orphan for more than the grace period. The net NSs
+// already deleted are forgotten.
+func (c *Collector) Collect(ctx context.Context) error {
+	c.scanLock.Lock()
+	defer c.scanLock.Unlock()
+
+	current, err := nshandle.Current()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = current.Close() }()
+
+	c.lock.Lock()
+	netNSURLs := []string{""}
+	for netNSURL := range c.netNSs {
+		netNSURLs = append(netNSURLs, netNSURL)
+	}
+	liveLinks := make(map[string][]string)
+	for _, links := range c.live {
+		for _, link := range links {
+			liveLinks[link.netNSURL] = append(liveLinks[link.netNSURL], link.ifName)
+		}
+	}
+	c.lock.Unlock()
+
+	// the net NSs are scanned once even if they are given by different URLs
+	handles := make(map[uint64]netns.NsHandle)
+	defer func() {
+		for _, handle := range handles {
+			if handle != current {
+				_ = handle.Close()
+			}
+		}
+	}()
+	live := make(map[linkKey]bool)
+	for _, netNSURL := range netNSURLs {
+		handle := current
+		if netNSURL != "" {
+			if handle, err = nshandle.FromURL(netNSURL); err != nil {
+				c.forget(netNSURL)
+				continue
+			}
+		}
+		inode, err := nshandle.Inode(handle)
+		if _, ok := handles[inode]; ok || err != nil {
+			if handle != current {
+				_ = handle.Close()
+			}
+			if err != nil {
+				return err
+			}
+		} else {
+			handles[inode] = handle
+		}
+		for _, ifName := range liveLinks[netNSURL] {
+			live[linkKey{netNS: inode, ifName: ifName}] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	var collected Stats
+	for inode, handle := range handles {
+		inode := inode
+		if err := nshandle.RunIn(current, handle, func() error {
+			return c.scan(ctx, inode, live, seen, &collected)
+		}); err != nil {
+			return err
+		}
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for key := range c.orphans {
+		if !seen[key] {
+			delete(c.orphans, key)
+		}
+	}
+	c.stats.Scans++
+	c.stats.Routes += collected.Routes
+	c.stats.Addrs += collected.Addrs
+	c.stats.Errors += collected.Errors
+
+	return nil
+}
+
+// scan scans the current net NS with the given inode and deletes the objects being orphan for more than the grace
+// period
+func (c *Collector) scan(ctx context.Context, inode uint64, live map[linkKey]bool, seen map[string]bool, collected *Stats) error {
+	links, err := netlink.LinkList()
+	if err != nil {
+		return errors.Wrap(err, "failed to list net interfaces")
+	}
+	routes, err := owned.Routes(nil)
+	if err != nil {
+		return err
+	}
+
+	linkNames := make(map[int]string, len(links))
+	for _, link := range links {
+		linkNames[link.Attrs().Index] = link.Attrs().Name
+	}
+
+	for i := range routes {
+		ifName, ok := linkNames[routes[i].LinkIndex]
+		if !ok || live[linkKey{netNS: inode, ifName: ifName}] {
+			continue
+		}
+		key := fmt.Sprintf("route %d/%s %s", inode, ifName, routeDst(&routes[i]))
+		if !c.expired(key, seen) {
+			continue
+		}
+		if err := netlink.RouteDel(&routes[i]); err != nil {
+			log.Entry(ctx).Warnf("failed to delete orphan route: %s: %s", key, err.Error())
+			collected.Errors++
+			continue
+		}
+		log.Entry(ctx).Infof("deleted orphan %s", key)
+		collected.Routes++
+	}
+	for _, link := range links {
+		if live[linkKey{netNS: inode, ifName: link.Attrs().Name}] {
+			continue
+		}
+		if addrs, _ := owned.Addrs(link); len(addrs) == 0 {
+			continue
+		}
+		key := fmt.Sprintf("addrs %d/%s %s", inode, link.Attrs().Name, link.Attrs().Alias)
+		if !c.expired(key, seen) {
+			continue
+		}
+		if err := owned.DeleteAddrs(link); err != nil {
+			log.Entry(ctx).Warnf("failed to delete orphan IP addresses: %s: %s", key, err.Error())
+			collected.Errors++
+			continue
+		}
+		log.Entry(ctx).Infof("deleted orphan %s", key)
+		collected.Addrs++
+	}
+	return nil
+}
+
+// expired marks the object orphan and returns true if it has been orphan for more than the grace period
+func (c *Collector) expired(key string, seen map[string]bool) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	seen[key] = true
+	since, ok := c.orphans[key]
+	if !ok {
+		since = time.Now()
+		c.orphans[key] = since
+	}
+	if time.Now().Sub(since) < c.gracePeriod {
+		return false
+	}
+	delete(c.orphans, key)
+	return true
+}
+
+func (c *Collector) setLive(connID string, links []liveLink) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.live[connID] = links
+	for _, link := range links {
+		if link.netNSURL != "" {
+			c.netNSs[link.netNSURL] = true
+		}
+	}
+}
+
+// forget stops scanning the deleted net NS
+func (c *Collector) forget(netNSURL string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.netNSs, netNSURL)
+}
+
+func (c *Collector) deleteLive(connID string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.live, connID)
+}
+
+func routeDst(route *netlink.Route) string {
+	if route.Dst == nil {
+		return "default"
+	}
+	return route.Dst.String()
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orphangc_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	kernelconst "github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/orphangc"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/owned"
+)
+
+const (
+	orphanName = "orphangc-1"
+	liveName   = "orphangc-2"
+	sharedName = "orphangc-3"
+)
+
+func addLink(t *testing.T, name, ipAddr string) {
+	kerneltest.AddVeth(t, name, name+"p")
+	setOwned(t, name, ipAddr)
+}
+
+// setOwned adds the owned IP address and route to the net interface in the current net NS
+func setOwned(t *testing.T, name, ipAddr string) {
+	kerneltest.AddAddr(t, name, ipAddr)
+	link, err := netlink.LinkByName(name)
+	require.NoError(t, err)
+
+	addr, err := netlink.ParseAddr(ipAddr)
+	require.NoError(t, err)
+	require.NoError(t, owned.SetAddrs(link, []*netlink.Addr{addr}))
+
+	require.NoError(t, netlink.RouteAdd(&netlink.Route{
+		LinkIndex: link.Attrs().Index,
+		Dst: &net.IPNet{
+			IP:   addr.IP.Mask(net.CIDRMask(16, 32)),
+			Mask: net.CIDRMask(16, 32),
+		},
+		Protocol: kernelconst.RouteProtoNSM,
+	}))
+}
+
+func ownedState(t *testing.T, name string) (routes int, addrs int) {
+	link, err := netlink.LinkByName(name)
+	require.NoError(t, err)
+
+	routeList, err := owned.Routes(link)
+	require.NoError(t, err)
+	addrList, _ := owned.Addrs(link)
+
+	return len(routeList), len(addrList)
+}
+
+func TestCollector(t *testing.T) {
+	addLink(t, orphanName, "10.70.0.1/24")
+	addLink(t, liveName, "10.71.0.1/24")
+
+	collector := orphangc.New(orphangc.WithGracePeriod(100 * time.Millisecond))
+	server := collector.NewServer()
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.InterfaceNameKey: liveName,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	// orphans are not collected during the grace period
+	require.NoError(t, collector.Collect(context.TODO()))
+	routes, addrs := ownedState(t, orphanName)
+	require.Equal(t, 1, routes)
+	require.Equal(t, 1, addrs)
+
+	time.Sleep(150 * time.Millisecond)
+	require.NoError(t, collector.Collect(context.TODO()))
+	routes, addrs = ownedState(t, orphanName)
+	require.Zero(t, routes)
+	require.Zero(t, addrs)
+
+	routes, addrs = ownedState(t, liveName)
+	require.Equal(t, 1, routes)
+	require.Equal(t, 1, addrs)
+
+	require.Equal(t, orphangc.Stats{Scans: 2, Routes: 1, Addrs: 1}, collector.Stats())
+
+	// closed connection objects become orphan
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	require.NoError(t, collector.Collect(context.TODO()))
+	time.Sleep(150 * time.Millisecond)
+	require.NoError(t, collector.Collect(context.TODO()))
+	routes, addrs = ownedState(t, liveName)
+	require.Zero(t, routes)
+	require.Zero(t, addrs)
+
+	require.Equal(t, orphangc.Stats{Scans: 4, Routes: 2, Addrs: 2}, collector.Stats())
+}
+
+func TestCollector_NetNSs(t *testing.T) {
+	// the same named net interfaces: the orphan one in the current net NS and the live one in the Client's net NS
+	netNS := kerneltest.AddVethPeer(t, "orphangc-4", "", sharedName, "")
+	kerneltest.RunIn(t, netNS, func() {
+		setOwned(t, sharedName, "10.73.0.1/24")
+	})
+	addLink(t, sharedName, "10.72.0.1/24")
+
+	collector := orphangc.New(orphangc.WithGracePeriod(100 * time.Millisecond))
+	server := collector.NewServer()
+
+	_, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.NetNSURL:         fmt.Sprintf("file:///proc/self/fd/%d", int(netNS)),
+					kernel.InterfaceNameKey: sharedName,
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, collector.Collect(context.TODO()))
+	time.Sleep(150 * time.Millisecond)
+	require.NoError(t, collector.Collect(context.TODO()))
+
+	routes, addrs := ownedState(t, sharedName)
+	require.Zero(t, routes)
+	require.Zero(t, addrs)
+
+	kerneltest.RunIn(t, netNS, func() {
+		routes, addrs = ownedState(t, sharedName)
+	})
+	require.Equal(t, 1, routes)
+	require.Equal(t, 1, addrs)
+
+	require.Equal(t, orphangc.Stats{Scans: 2, Routes: 1, Addrs: 1}, collector.Stats())
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package orphangc
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+)
+
+type orphanGCServer struct {
+	collector *Collector
+}
+
+// NewServer returns a new server chain element marking the connection net interfaces live for the Collector: the
+// kernel interface itself in the kernel mechanism net NS and its veth peer in the current net NS. The kernel mechanism
+// net NS is scanned by the Collector since then.
+func (c *Collector) NewServer() networkservice.NetworkServiceServer {
+	return &orphanGCServer{
+		collector: c,
+	}
+}
+
+func (s *orphanGCServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
+		s.collector.setLive(conn.GetId(), []liveLink{
+			{netNSURL: mech.GetNetNSURL(), ifName: mech.GetInterfaceName(conn)},
+			{ifName: linkprovider.VethPeerName(conn)},
+		})
+	}
+
+	return conn, nil
+}
+
+func (s *orphanGCServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	defer s.collector.deleteLive(conn.GetId())
+	return next.Server(ctx).Close(ctx, conn)
+}
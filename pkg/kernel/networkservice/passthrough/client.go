@@ -0,0 +1,89 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package passthrough
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type passThroughClient struct{}
+
+// NewClient returns a new pass-through client chain element. When the first Request returns it moves network
+// interface from the current network namespace into the returned mechanism network namespace. On subsequent Requests
+// of the same connection with the changed mechanism network namespace it moves network interface from the previous
+// pod into the new one. On Close network interface is moved back into the current network namespace.
+func NewClient() networkservice.NetworkServiceClient {
+	return &passThroughClient{}
+}
+
+func (c *passThroughClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return conn, nil
+	}
+
+	toURL := mech.GetNetNSURL()
+	fromURL, ok := loadLocation(ctx, metadata.IsClient(c))
+	if ok && fromURL == toURL {
+		return conn, nil
+	}
+
+	ifName := mech.GetInterfaceName(conn)
+	if err := move(ifName, fromURL, toURL); err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+	log.Entry(ctx).WithField("passThroughClient", "Request").Infof("moved network interface %s: %s -> %s", ifName, fromURL, toURL)
+
+	storeLocation(ctx, metadata.IsClient(c), toURL)
+
+	return conn, nil
+}
+
+func (c *passThroughClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var moveErr error
+	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
+		if fromURL, ok := loadAndDeleteLocation(ctx, metadata.IsClient(c)); ok {
+			moveErr = move(mech.GetInterfaceName(conn), fromURL, "")
+		}
+	}
+
+	if err != nil && moveErr != nil {
+		return nil, errors.Wrap(err, moveErr.Error())
+	}
+	if moveErr != nil {
+		return nil, moveErr
+	}
+	return &empty.Empty{}, err
+}
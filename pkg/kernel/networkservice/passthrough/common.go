@@ -0,0 +1,70 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package passthrough
+
+import (
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// move moves the net interface between the net NSs, empty URL stands for the Forwarder's net NS
+func move(ifName, fromURL, toURL string) error {
+	curNetNS, err := nshandle.Current()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = curNetNS.Close() }()
+
+	fromNetNS, err := netNSFromURL(fromURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = fromNetNS.Close() }()
+
+	toNetNS, err := netNSFromURL(toURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = toNetNS.Close() }()
+
+	if fromNetNS.Equal(toNetNS) {
+		return nil
+	}
+
+	return nshandle.RunIn(curNetNS, fromNetNS, func() error {
+		link, err := netlink.LinkByName(ifName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+		}
+
+		if err := netlink.LinkSetNsFd(link, int(toNetNS)); err != nil {
+			return errors.Wrapf(err, "failed to move net interface to net NS: %v %v", ifName, toNetNS)
+		}
+
+		return nil
+	})
+}
+
+func netNSFromURL(netNSURL string) (netns.NsHandle, error) {
+	if netNSURL == "" {
+		return nshandle.Current()
+	}
+	return nshandle.FromURL(netNSURL)
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package passthrough provides chain element passing network interface through a chain of pods for the service
+// function chaining
+package passthrough
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type passThroughServer struct{}
+
+// NewServer returns a new pass-through server chain element. On the first Request it moves network interface from the
+// Forwarder's network namespace into the mechanism network namespace. On subsequent Requests of the same connection
+// with the changed mechanism network namespace it moves network interface from the previous pod into the new one. On
+// Close network interface is moved back into the Forwarder's network namespace.
+func NewServer() networkservice.NetworkServiceServer {
+	return &passThroughServer{}
+}
+
+func (s *passThroughServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	logEntry := log.Entry(ctx).WithField("passThroughServer", "Request")
+
+	mech := kernel.ToMechanism(request.GetConnection().GetMechanism())
+	if mech == nil {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	toURL := mech.GetNetNSURL()
+	fromURL, ok := loadLocation(ctx, metadata.IsClient(s))
+	if ok && fromURL == toURL {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	ifName := mech.GetInterfaceName(request.GetConnection())
+	if err := move(ifName, fromURL, toURL); err != nil {
+		return nil, err
+	}
+	logEntry.Infof("moved network interface %s: %s -> %s", ifName, fromURL, toURL)
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if moveErr := move(ifName, toURL, fromURL); moveErr != nil {
+			logEntry.Warnf("failed to move network interface %s back: %s", ifName, moveErr.Error())
+		}
+		return nil, err
+	}
+
+	storeLocation(ctx, metadata.IsClient(s), toURL)
+
+	return conn, nil
+}
+
+func (s *passThroughServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var moveErr error
+	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
+		if fromURL, ok := loadAndDeleteLocation(ctx, metadata.IsClient(s)); ok {
+			moveErr = move(mech.GetInterfaceName(conn), fromURL, "")
+		}
+	}
+
+	if err != nil && moveErr != nil {
+		return nil, errors.Wrap(err, moveErr.Error())
+	}
+	if moveErr != nil {
+		return nil, moveErr
+	}
+	return &empty.Empty{}, err
+}
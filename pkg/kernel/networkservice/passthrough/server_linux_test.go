@@ -0,0 +1,113 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package passthrough_test
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"runtime"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/passthrough"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+const (
+	netNSPath = "/run/netns"
+	ifName    = "sfc-1"
+	peerName  = "sfc-2"
+)
+
+func newNetNS(t *testing.T, curNetNS netns.NsHandle) (handle netns.NsHandle, netNSURL string, cleanup func()) {
+	name := uuid.New().String()
+	handle, err := netns.NewNamed(name)
+	require.NoError(t, err)
+	require.NoError(t, netns.Set(curNetNS))
+
+	return handle, (&url.URL{Scheme: "file", Path: path.Join(netNSPath, name)}).String(), func() {
+		_ = handle.Close()
+		_ = netns.DeleteNamed(name)
+	}
+}
+
+func hasLink(curNetNS, handle netns.NsHandle) bool {
+	return nshandle.RunIn(curNetNS, handle, func() error {
+		_, err := netlink.LinkByName(ifName)
+		return err
+	}) == nil
+}
+
+func TestPassThroughServer(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	podA, podAURL, cleanupA := newNetNS(t, curNetNS)
+	defer cleanupA()
+	podB, podBURL, cleanupB := newNetNS(t, curNetNS)
+	defer cleanupB()
+
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		passthrough.NewServer(),
+	)
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.InterfaceNameKey: ifName,
+					kernel.NetNSURL:         podAURL,
+				},
+			},
+		},
+	}
+
+	conn, err := server.Request(context.TODO(), request)
+	require.NoError(t, err)
+	require.True(t, hasLink(curNetNS, podA))
+
+	conn.GetMechanism().GetParameters()[kernel.NetNSURL] = podBURL
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.False(t, hasLink(curNetNS, podA))
+	require.True(t, hasLink(curNetNS, podB))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.False(t, hasLink(curNetNS, podB))
+	require.True(t, hasLink(curNetNS, curNetNS))
+}
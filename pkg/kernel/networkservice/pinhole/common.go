@@ -0,0 +1,99 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pinhole
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// IPTablesBinary is a path to the iptables binary used to program IPv4 rules. Set it to "iptables-nft" to program
+	// the rules on the nftables based hosts.
+	IPTablesBinary = "iptables"
+	// IP6TablesBinary is a path to the ip6tables binary used to program IPv6 rules, "ip6tables-nft" for nftables
+	IP6TablesBinary = "ip6tables"
+)
+
+// Comment returns the comment marking the firewall rules owned by the connection
+func Comment(connID string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(connID))
+	return fmt.Sprintf("nsm:%08x", hash.Sum32())
+}
+
+// open inserts the missing connection rules, so refresh doesn't duplicate them
+func (s *pinholeServer) open(ctx context.Context, connID string) error {
+	for _, binary := range s.binaries {
+		for _, port := range s.ports {
+			rule := s.rule(port, connID)
+			if run(ctx, binary, "-C", rule...) == nil {
+				continue
+			}
+			if err := run(ctx, binary, "-I", rule...); err != nil {
+				return errors.Wrapf(err, "failed to open %s port %d", port.Protocol, port.Port)
+			}
+		}
+	}
+	return nil
+}
+
+// close deletes the connection rules, the rules of the other connections don't match the connection comment
+func (s *pinholeServer) close(ctx context.Context, connID string) error {
+	for _, binary := range s.binaries {
+		for _, port := range s.ports {
+			rule := s.rule(port, connID)
+			if run(ctx, binary, "-C", rule...) != nil {
+				continue
+			}
+			if err := run(ctx, binary, "-D", rule...); err != nil {
+				return errors.Wrapf(err, "failed to close %s port %d", port.Protocol, port.Port)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *pinholeServer) rule(port Port, connID string) []string {
+	return []string{
+		s.chain,
+		"-p", port.Protocol,
+		"--dport", strconv.Itoa(port.Port),
+		"-m", "comment", "--comment", Comment(connID),
+		"-j", "ACCEPT",
+	}
+}
+
+// run runs the iptables command waiting for the xtables lock held by the other firewall users
+func run(ctx context.Context, binary, command string, rule ...string) error {
+	cmd := exec.CommandContext(ctx, binary, append([]string{"-w", command}, rule...)...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pinhole
+
+// Option is an option for the pinhole server
+type Option func(s *pinholeServer)
+
+// WithPorts sets the ports to open for the connections
+func WithPorts(ports ...Port) Option {
+	return func(s *pinholeServer) {
+		s.ports = append(s.ports, ports...)
+	}
+}
+
+// WithChain sets the filter table chain to insert the rules into. Default is INPUT.
+func WithChain(chain string) Option {
+	return func(s *pinholeServer) {
+		s.chain = chain
+	}
+}
+
+// WithIPv6 makes the ports to be opened for IPv6 with ip6tables too
+func WithIPv6() Option {
+	return func(s *pinholeServer) {
+		s.binaries = []string{IPTablesBinary, IP6TablesBinary}
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pinhole provides chain element opening the connection ports in the host firewall
+package pinhole
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const defaultChain = "INPUT"
+
+// Port is a port to open in the host firewall
+type Port struct {
+	// Protocol is "udp" or "tcp"
+	Protocol string
+	// Port is the destination port number
+	Port int
+}
+
+type pinholeServer struct {
+	ports    []Port
+	chain    string
+	binaries []string
+}
+
+// NewServer returns a new pinhole server chain element. It inserts iptables rules accepting the traffic to the ports
+// set with WithPorts (e.g. WireGuard or VXLAN UDP ports) into the host firewall in the current net NS on Request and
+// deletes them on Close. Every connection owns its own rules marked with the connection comment, so the connections
+// using the same port don't delete each other's rules.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	s := &pinholeServer{
+		chain:    defaultChain,
+		binaries: []string{IPTablesBinary},
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+func (s *pinholeServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if len(s.ports) == 0 {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	connID := request.GetConnection().GetId()
+	if err := s.open(ctx, connID); err != nil {
+		s.closeLogged(ctx, connID)
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		s.closeLogged(ctx, connID)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (s *pinholeServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var closeErr error
+	if len(s.ports) != 0 {
+		closeErr = s.close(ctx, conn.GetId())
+	}
+
+	if err != nil && closeErr != nil {
+		return nil, errors.Wrap(err, closeErr.Error())
+	}
+	if closeErr != nil {
+		return nil, closeErr
+	}
+	return &empty.Empty{}, err
+}
+
+func (s *pinholeServer) closeLogged(ctx context.Context, connID string) {
+	if err := s.close(ctx, connID); err != nil {
+		log.Entry(ctx).WithField("pinholeServer", "Request").Warnf("failed to delete firewall rules: %s", err.Error())
+	}
+}
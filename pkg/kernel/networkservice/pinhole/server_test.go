@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pinhole_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/inject/injecterror"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/pinhole"
+)
+
+// fakeIPTables keeps the rules in the state file: `iptables -w <command> <rule...>`
+const fakeIPTables = `#!/bin/sh
+state="$(dirname "$0")/rules"
+touch "$state"
+command=$2
+shift 2
+case $command in
+-C) grep -qxF -- "$*" "$state" ;;
+-I) echo "$*" >> "$state" ;;
+-D) grep -vxF -- "$*" "$state" > "$state.tmp"; mv "$state.tmp" "$state" ;;
+*) exit 2 ;;
+esac
+`
+
+func withFakeIPTables(t *testing.T) (rules func() []string, cleanup func()) {
+	dir, err := ioutil.TempDir("", "pinhole")
+	require.NoError(t, err)
+
+	binary := filepath.Join(dir, "iptables")
+	require.NoError(t, ioutil.WriteFile(binary, []byte(fakeIPTables), 0700))
+
+	original := pinhole.IPTablesBinary
+	pinhole.IPTablesBinary = binary
+
+	return func() []string {
+			data, _ := ioutil.ReadFile(filepath.Join(dir, "rules"))
+			return strings.Fields(strings.ReplaceAll(string(data), " ", "_"))
+		}, func() {
+			pinhole.IPTablesBinary = original
+			_ = os.RemoveAll(dir)
+		}
+}
+
+func rule(connID string) string {
+	return "INPUT_-p_udp_--dport_51820_-m_comment_--comment_" + pinhole.Comment(connID) + "_-j_ACCEPT"
+}
+
+func TestPinholeServer(t *testing.T) {
+	rules, cleanup := withFakeIPTables(t)
+	defer cleanup()
+
+	server := pinhole.NewServer(pinhole.WithPorts(pinhole.Port{Protocol: "udp", Port: 51820}))
+
+	conn1, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-1"},
+	})
+	require.NoError(t, err)
+	conn2, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-2"},
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{rule("conn-1"), rule("conn-2")}, rules())
+
+	// refresh doesn't duplicate the rules
+	conn2, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn2})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{rule("conn-1"), rule("conn-2")}, rules())
+
+	// the same port is still open for the other connection
+	_, err = server.Close(context.TODO(), conn1)
+	require.NoError(t, err)
+	require.Equal(t, []string{rule("conn-2")}, rules())
+
+	_, err = server.Close(context.TODO(), conn2)
+	require.NoError(t, err)
+	require.Empty(t, rules())
+}
+
+func TestPinholeServer_RequestFailed(t *testing.T) {
+	rules, cleanup := withFakeIPTables(t)
+	defer cleanup()
+
+	_, err := chain.NewNetworkServiceServer(
+		pinhole.NewServer(pinhole.WithPorts(pinhole.Port{Protocol: "udp", Port: 51820})),
+		injecterror.NewServer(),
+	).Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-1"},
+	})
+	require.Error(t, err)
+	require.Empty(t, rules())
+}
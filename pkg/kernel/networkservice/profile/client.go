@@ -0,0 +1,57 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+type profileClient struct {
+	profiler
+	client networkservice.NetworkServiceClient
+}
+
+// NewClient returns a new client chain element wrapping the given client with pprof labels
+func NewClient(name string, client networkservice.NetworkServiceClient, options ...Option) networkservice.NetworkServiceClient {
+	c := &profileClient{
+		profiler: profiler{name: name},
+		client:   client,
+	}
+	for _, opt := range options {
+		opt(&c.profiler)
+	}
+	return c
+}
+
+func (c *profileClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (conn *networkservice.Connection, err error) {
+	c.do(ctx, requestMethod, request.GetConnection().GetId(), func(ctx context.Context) {
+		conn, err = c.client.Request(ctx, request, opts...)
+	})
+	return conn, err
+}
+
+func (c *profileClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (e *empty.Empty, err error) {
+	c.do(ctx, closeMethod, conn.GetId(), func(ctx context.Context) {
+		e, err = c.client.Close(ctx, conn, opts...)
+	})
+	return e, err
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Histogram is an Observer collecting execution time histograms per chain element method
+type Histogram struct {
+	buckets []time.Duration
+	mu      sync.Mutex
+	counts  map[string][]uint64
+}
+
+// NewHistogram returns a new Histogram with the given bucket upper bounds, the last implicit bucket counts all the
+// longer executions
+func NewHistogram(buckets ...time.Duration) *Histogram {
+	buckets = append([]time.Duration(nil), buckets...)
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i] < buckets[j] })
+	return &Histogram{
+		buckets: buckets,
+		counts:  make(map[string][]uint64),
+	}
+}
+
+// Observe implements Observer
+func (h *Histogram) Observe(element, method string, duration time.Duration) {
+	i := sort.Search(len(h.buckets), func(i int) bool { return duration <= h.buckets[i] })
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := element + "." + method
+	if _, ok := h.counts[key]; !ok {
+		h.counts[key] = make([]uint64, len(h.buckets)+1)
+	}
+	h.counts[key][i]++
+}
+
+// Buckets returns the bucket upper bounds
+func (h *Histogram) Buckets() []time.Duration {
+	return append([]time.Duration(nil), h.buckets...)
+}
+
+// Counts returns a copy of the bucket counts for the chain element method
+func (h *Histogram) Counts(element, method string) []uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if counts, ok := h.counts[element+"."+method]; ok {
+		return append([]uint64(nil), counts...)
+	}
+	return make([]uint64, len(h.buckets)+1)
+}
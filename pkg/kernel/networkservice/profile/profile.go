@@ -0,0 +1,56 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package profile provides chain element wrappers attributing CPU profiles and execution time to the wrapped chain
+// elements
+package profile
+
+import (
+	"context"
+	"runtime/pprof"
+	"time"
+)
+
+const (
+	// ElementLabel is a pprof label with the wrapped chain element name
+	ElementLabel = "element"
+	// ConnectionIDLabel is a pprof label with the connection ID
+	ConnectionIDLabel = "connID"
+
+	requestMethod = "Request"
+	closeMethod   = "Close"
+)
+
+// Observer observes chain element method execution time
+type Observer interface {
+	Observe(element, method string, duration time.Duration)
+}
+
+type profiler struct {
+	name     string
+	observer Observer
+}
+
+// do runs f with pprof labels set and observes its execution time. The labels are inherited by all the subsequent
+// chain elements called from f, until they are overridden by some other wrapper, so the execution time includes the
+// time spent in the rest of the chain.
+func (p *profiler) do(ctx context.Context, method, connID string, f func(ctx context.Context)) {
+	start := time.Now()
+	pprof.Do(ctx, pprof.Labels(ElementLabel, p.name, ConnectionIDLabel, connID), f)
+	if p.observer != nil {
+		p.observer.Observe(p.name, method, time.Since(start))
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+type profileServer struct {
+	profiler
+	server networkservice.NetworkServiceServer
+}
+
+// NewServer returns a new server chain element wrapping the given server with pprof labels
+func NewServer(name string, server networkservice.NetworkServiceServer, options ...Option) networkservice.NetworkServiceServer {
+	s := &profileServer{
+		profiler: profiler{name: name},
+		server:   server,
+	}
+	for _, opt := range options {
+		opt(&s.profiler)
+	}
+	return s
+}
+
+func (s *profileServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (conn *networkservice.Connection, err error) {
+	s.do(ctx, requestMethod, request.GetConnection().GetId(), func(ctx context.Context) {
+		conn, err = s.server.Request(ctx, request)
+	})
+	return conn, err
+}
+
+func (s *profileServer) Close(ctx context.Context, conn *networkservice.Connection) (e *empty.Empty, err error) {
+	s.do(ctx, closeMethod, conn.GetId(), func(ctx context.Context) {
+		e, err = s.server.Close(ctx, conn)
+	})
+	return e, err
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package profile_test
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/profile"
+)
+
+func TestProfileServer(t *testing.T) {
+	histogram := profile.NewHistogram(time.Hour)
+	server := chain.NewNetworkServiceServer(
+		profile.NewServer("check", &checkLabelsServer{t: t}, profile.WithObserver(histogram)),
+	)
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-1"},
+	})
+	require.NoError(t, err)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	require.Equal(t, []uint64{1, 0}, histogram.Counts("check", "Request"))
+	require.Equal(t, []uint64{1, 0}, histogram.Counts("check", "Close"))
+}
+
+type checkLabelsServer struct {
+	t *testing.T
+}
+
+func (s *checkLabelsServer) check(ctx context.Context, connID string) {
+	element, _ := pprof.Label(ctx, profile.ElementLabel)
+	require.Equal(s.t, "check", element)
+	id, _ := pprof.Label(ctx, profile.ConnectionIDLabel)
+	require.Equal(s.t, connID, id)
+}
+
+func (s *checkLabelsServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	s.check(ctx, request.GetConnection().GetId())
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *checkLabelsServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	s.check(ctx, conn.GetId())
+	return next.Server(ctx).Close(ctx, conn)
+}
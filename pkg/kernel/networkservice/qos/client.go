@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qos
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type qosClient struct {
+	*shaper
+}
+
+// NewClient returns a new client chain element limiting the egress rate (see RateKey, WithRate) of the Endpoint's net
+// interface in its net NS with tbf or htb root qdisc on Request and deleting the qdisc on Close
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &qosClient{
+		shaper: newShaper(options),
+	}
+}
+
+func (c *qosClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := c.apply(conn)
+	if err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	// the rate can be removed from the connection context on refresh
+	if applied {
+		store(ctx, metadata.IsClient(c))
+	} else if loadAndDelete(ctx, metadata.IsClient(c)) {
+		if err := remove(conn); err != nil {
+			log.Entry(ctx).WithField("qosClient", "Request").Warnf("failed to delete qdisc: %s", err.Error())
+		}
+	}
+
+	return conn, nil
+}
+
+func (c *qosClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var removeErr error
+	if loadAndDelete(ctx, metadata.IsClient(c)) {
+		removeErr = remove(conn)
+	}
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
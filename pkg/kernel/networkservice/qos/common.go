@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qos
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// RateKey is a connection context extra context key with the connection egress rate limit in kbit/s
+const RateKey = "rate"
+
+// Rate returns the connection rate limit in kbit/s, it returns 0 if there is no rate in the connection context
+func Rate(conn *networkservice.Connection) (uint64, error) {
+	value, ok := conn.GetContext().GetExtraContext()[RateKey]
+	if !ok || value == "" {
+		return 0, nil
+	}
+	rate, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || rate == 0 {
+		return 0, errors.Errorf("invalid rate: %v", value)
+	}
+	return rate, nil
+}
+
+// shaper is the common part of the qos client and server
+type shaper struct {
+	rate  uint64
+	burst uint32
+	htb   bool
+}
+
+func newShaper(options []Option) *shaper {
+	s := &shaper{}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+// apply installs the root qdisc limiting the egress rate of the connection kernel interface in its net NS, the rate
+// from the connection context overrides the WithRate one. It returns false if there is no rate for the connection.
+func (s *shaper) apply(conn *networkservice.Connection) (bool, error) {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return false, nil
+	}
+
+	rate, err := Rate(conn)
+	if err != nil {
+		return false, err
+	}
+	if rate == 0 {
+		rate = s.rate
+	}
+	if rate == 0 {
+		return false, nil
+	}
+
+	ifName := mech.GetInterfaceName(conn)
+	err = nshandle.RunInURL(mech.GetNetNSURL(), func() error {
+		link, err := netlink.LinkByName(ifName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+		}
+		return s.setQdisc(link, rate*1000)
+	})
+	return err == nil, err
+}
+
+// remove deletes the root qdisc from the connection kernel interface, so the kernel restores the default one. Already
+// deleted net interface is skipped.
+func remove(conn *networkservice.Connection) error {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil
+	}
+
+	return nshandle.RunInURL(mech.GetNetNSURL(), func() error {
+		link, err := netlink.LinkByName(mech.GetInterfaceName(conn))
+		if err != nil {
+			return nil
+		}
+		return delQdisc(link)
+	})
+}
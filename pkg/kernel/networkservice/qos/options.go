@@ -0,0 +1,42 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qos
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(s *shaper)
+
+// WithRate sets the default egress rate limit in kbit/s for the connections with no rate in the connection context
+func WithRate(rate uint64) Option {
+	return func(s *shaper) {
+		s.rate = rate
+	}
+}
+
+// WithBurst sets the max burst size in bytes, by default it is the rate per kernel timer tick plus MTU
+func WithBurst(burst uint32) Option {
+	return func(s *shaper) {
+		s.burst = burst
+	}
+}
+
+// WithHTB makes the rate to be limited with htb qdisc having a single default class instead of tbf qdisc, so other
+// classes can be added under it
+func WithHTB() Option {
+	return func(s *shaper) {
+		s.htb = true
+	}
+}
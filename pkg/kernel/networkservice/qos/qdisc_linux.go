@@ -0,0 +1,94 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qos
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// qdiscMajor is the NSM routing protocol number, so the NSM qdisc is recognizable with `tc qdisc show`
+	qdiscMajor = 0x4e
+	classMinor = 1
+
+	// latency is the max time in ms a packet can wait in the tbf queue
+	latency  = 50
+	minBurst = 1600
+)
+
+// setQdisc replaces the root qdisc of the net interface with the tbf or htb one limiting the rate in bit/s
+func (s *shaper) setQdisc(link netlink.Link, rate uint64) error {
+	attrs := netlink.QdiscAttrs{
+		LinkIndex: link.Attrs().Index,
+		Handle:    netlink.MakeHandle(qdiscMajor, 0),
+		Parent:    netlink.HANDLE_ROOT,
+	}
+
+	if s.htb {
+		qdisc := netlink.NewHtb(attrs)
+		qdisc.Defcls = classMinor
+		if err := netlink.QdiscReplace(qdisc); err != nil {
+			return errors.Wrapf(err, "failed to set htb qdisc: %v", link.Attrs().Name)
+		}
+		class := netlink.NewHtbClass(netlink.ClassAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(qdiscMajor, classMinor),
+			Parent:    netlink.MakeHandle(qdiscMajor, 0),
+		}, netlink.HtbClassAttrs{
+			Rate:   rate,
+			Buffer: s.burst,
+		})
+		if err := netlink.ClassReplace(class); err != nil {
+			return errors.Wrapf(err, "failed to set htb class: %v", link.Attrs().Name)
+		}
+		return nil
+	}
+
+	// tbf rate is in bytes/s, buffer is the burst transmission time in ticks
+	byteRate := rate / 8
+	burst := s.burst
+	if burst == 0 {
+		burst = uint32(float64(byteRate)/netlink.Hz()) + minBurst
+	}
+	qdisc := &netlink.Tbf{
+		QdiscAttrs: attrs,
+		Rate:       byteRate,
+		Limit:      uint32(byteRate*latency/1000) + burst,
+		Buffer:     uint32(netlink.Xmittime(byteRate, burst)),
+	}
+	if err := netlink.QdiscReplace(qdisc); err != nil {
+		return errors.Wrapf(err, "failed to set tbf qdisc: %v", link.Attrs().Name)
+	}
+	return nil
+}
+
+func delQdisc(link netlink.Link) error {
+	qdisc := &netlink.GenericQdisc{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(qdiscMajor, 0),
+			Parent:    netlink.HANDLE_ROOT,
+		},
+	}
+	if err := netlink.QdiscDel(qdisc); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to delete qdisc: %v", link.Attrs().Name)
+	}
+	return nil
+}
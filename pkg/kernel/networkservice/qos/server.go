@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package qos provides chain elements limiting the connection kernel interface egress rate
+package qos
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type qosServer struct {
+	*shaper
+}
+
+// NewServer returns a new server chain element limiting the egress rate (see RateKey, WithRate) of the Client's net
+// interface in its net NS with tbf or htb root qdisc on Request and deleting the qdisc on Close
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &qosServer{
+		shaper: newShaper(options),
+	}
+}
+
+func (s *qosServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	applied, err := s.apply(request.GetConnection())
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if applied {
+			if removeErr := remove(request.GetConnection()); removeErr != nil {
+				log.Entry(ctx).WithField("qosServer", "Request").Warnf("failed to delete qdisc: %s", removeErr.Error())
+			}
+		}
+		return nil, err
+	}
+
+	// the rate can be removed from the connection context on refresh
+	if applied {
+		store(ctx, metadata.IsClient(s))
+	} else if loadAndDelete(ctx, metadata.IsClient(s)) {
+		if err := remove(conn); err != nil {
+			log.Entry(ctx).WithField("qosServer", "Request").Warnf("failed to delete qdisc: %s", err.Error())
+		}
+	}
+
+	return conn, nil
+}
+
+func (s *qosServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var removeErr error
+	if loadAndDelete(ctx, metadata.IsClient(s)) {
+		removeErr = remove(conn)
+	}
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package qos_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/qos"
+)
+
+const (
+	ifName   = "qos-1"
+	peerName = "qos-2"
+)
+
+func request(rate string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.NetNSURL:         "file:///proc/self/ns/net",
+					kernel.InterfaceNameKey: ifName,
+				},
+			},
+			Context: &networkservice.ConnectionContext{
+				ExtraContext: map[string]string{
+					qos.RateKey: rate,
+				},
+			},
+		},
+	}
+}
+
+func addLink(t *testing.T) (netlink.Link, func()) {
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: ifName},
+		PeerName:  peerName,
+	}))
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	return link, func() { _ = netlink.LinkDel(link) }
+}
+
+func rootQdisc(t *testing.T, link netlink.Link) netlink.Qdisc {
+	qdiscs, err := netlink.QdiscList(link)
+	require.NoError(t, err)
+	for _, qdisc := range qdiscs {
+		if qdisc.Attrs().Parent == netlink.HANDLE_ROOT {
+			return qdisc
+		}
+	}
+	return nil
+}
+
+func TestQoSServer_Tbf(t *testing.T) {
+	link, del := addLink(t)
+	defer del()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		qos.NewServer(),
+	)
+
+	conn, err := server.Request(context.TODO(), request("1000"))
+	require.NoError(t, err)
+	tbf, ok := rootQdisc(t, link).(*netlink.Tbf)
+	require.True(t, ok)
+	require.Equal(t, uint64(125000), tbf.Rate)
+
+	// refresh with the changed rate
+	conn, err = server.Request(context.TODO(), request("2000"))
+	require.NoError(t, err)
+	tbf, ok = rootQdisc(t, link).(*netlink.Tbf)
+	require.True(t, ok)
+	require.Equal(t, uint64(250000), tbf.Rate)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	_, ok = rootQdisc(t, link).(*netlink.Tbf)
+	require.False(t, ok)
+
+	_, err = server.Request(context.TODO(), request("fast"))
+	require.Error(t, err)
+}
+
+func TestQoSClient_Htb(t *testing.T) {
+	link, del := addLink(t)
+	defer del()
+
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		qos.NewClient(qos.WithHTB(), qos.WithRate(1000)),
+	)
+
+	// no rate in the connection context, the default one is used
+	conn, err := client.Request(context.TODO(), request(""))
+	require.NoError(t, err)
+	_, ok := rootQdisc(t, link).(*netlink.Htb)
+	require.True(t, ok)
+
+	classes, err := netlink.ClassList(link, netlink.MakeHandle(0x4e, 0))
+	require.NoError(t, err)
+	require.Len(t, classes, 1)
+	require.Equal(t, uint64(125000), classes[0].(*netlink.HtbClass).Rate)
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	_, ok = rootQdisc(t, link).(*netlink.Htb)
+	require.False(t, ok)
+}
+
+func TestQoSServer_RateRemoved(t *testing.T) {
+	link, del := addLink(t)
+	defer del()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		qos.NewServer(),
+	)
+
+	_, err := server.Request(context.TODO(), request("1000"))
+	require.NoError(t, err)
+
+	conn, err := server.Request(context.TODO(), request(""))
+	require.NoError(t, err)
+	_, ok := rootQdisc(t, link).(*netlink.Tbf)
+	require.False(t, ok)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/desiredstate"
+)
+
+type reconcileClient struct{}
+
+// NewClient returns a new reconcile client chain element, it is the same as NewServer, but the state is applied in the
+// net NS of the kernel mechanism returned by the next chain elements
+func NewClient() networkservice.NetworkServiceClient {
+	return &reconcileClient{}
+}
+
+func (c *reconcileClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	desired := desiredstate.New("")
+
+	conn, err := next.Client(ctx).Request(desiredstate.WithState(ctx, desired), request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apply(ctx, conn, desired, metadata.IsClient(c)); err != nil {
+		if _, refresh := load(ctx, metadata.IsClient(c)); !refresh {
+			_, _ = next.Client(ctx).Close(withEmptyState(ctx), conn.Clone(), opts...)
+		}
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c *reconcileClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(withEmptyState(ctx), conn, opts...)
+
+	removeErr := remove(ctx, metadata.IsClient(c))
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/desiredstate"
+)
+
+// apply reconciles the kernel state built by the chain elements for the connection with the previously applied one
+// and stores the new applied state. On failure the kernel state is rolled back, and the previously applied state is
+// kept.
+func apply(ctx context.Context, conn *networkservice.Connection, desired *desiredstate.KernelDesiredState, isClient bool) error {
+	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
+		desired.NetNSURL = mech.GetNetNSURL()
+	}
+
+	applied, _ := load(ctx, isClient)
+	if applied == nil && len(desired.Links) == 0 && len(desired.Sysctls) == 0 {
+		return nil
+	}
+
+	applied, err := desiredstate.Reconcile(ctx, applied, desired)
+	if err != nil {
+		return err
+	}
+	store(ctx, isClient, applied)
+	return nil
+}
+
+// withEmptyState returns a new context with the empty state for Close, so the state builders leave the applied kernel
+// objects to the reconciler
+func withEmptyState(ctx context.Context) context.Context {
+	return desiredstate.WithState(ctx, desiredstate.New(""))
+}
+
+// remove removes the applied kernel state of the connection
+func remove(ctx context.Context, isClient bool) error {
+	applied, ok := loadAndDelete(ctx, isClient)
+	if !ok {
+		return nil
+	}
+	_, err := desiredstate.Reconcile(ctx, applied, nil)
+	return err
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package reconcile provides chain elements applying the declarative kernel state of the connection built by the
+// next chain elements (see desiredstate)
+package reconcile
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/desiredstate"
+)
+
+type reconcileServer struct{}
+
+// NewServer returns a new reconcile server chain element. On each Request it passes a new empty KernelDesiredState
+// to the next chain elements in the context (see desiredstate.State), they describe the kernel objects they need, and
+// the state is applied in the kernel mechanism net NS after the next Request returns. Kernel objects dropped from the
+// state on refresh are deleted, all the applied ones are deleted on Close. The failed refresh keeps the previously
+// applied state. The ipcontext and mtu chain elements are the state builders (see desiredstate). It requires metadata
+// chain element.
+func NewServer() networkservice.NetworkServiceServer {
+	return &reconcileServer{}
+}
+
+func (s *reconcileServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	desired := desiredstate.New("")
+
+	conn, err := next.Server(ctx).Request(desiredstate.WithState(ctx, desired), request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apply(ctx, conn, desired, metadata.IsClient(s)); err != nil {
+		if _, refresh := load(ctx, metadata.IsClient(s)); !refresh {
+			_, _ = next.Server(ctx).Close(withEmptyState(ctx), conn.Clone())
+		}
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (s *reconcileServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(withEmptyState(ctx), conn)
+
+	removeErr := remove(ctx, metadata.IsClient(s))
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconcile_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	kernelconst "github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/mtu"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/reconcile"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName   = "reconcile-1"
+	peerName = "reconcile-2"
+)
+
+func newServer() networkservice.NetworkServiceServer {
+	return chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		reconcile.NewServer(),
+		mtu.NewServer(),
+		ipcontext.NewServer(),
+	)
+}
+
+func newRequest() *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.InterfaceNameKey: ifName,
+				},
+			},
+			Context: &networkservice.ConnectionContext{
+				IpContext: &networkservice.IPContext{
+					SrcIpAddr: "172.16.1.1/32",
+					SrcRoutes: []*networkservice.Route{{Prefix: "172.16.10.0/24"}},
+					IpNeighbors: []*networkservice.IpNeighbor{{
+						Ip:              "172.16.1.2",
+						HardwareAddress: "02:00:00:00:00:02",
+					}},
+				},
+				ExtraContext: map[string]string{mtu.MTUKey: "1400"},
+			},
+		},
+	}
+}
+
+func requireLink(t *testing.T, mtu int, addrs ...string) {
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	require.Equal(t, mtu, link.Attrs().MTU)
+
+	list, err := netlink.AddrList(link, netlink.FAMILY_V4)
+	require.NoError(t, err)
+	var actual []string
+	for i := range list {
+		actual = append(actual, list[i].IPNet.String())
+	}
+	require.ElementsMatch(t, addrs, actual)
+}
+
+func requireRoutesAndNeighbors(t *testing.T, expected int) {
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_V4, &netlink.Route{
+		LinkIndex: link.Attrs().Index,
+		Protocol:  kernelconst.RouteProtoNSM,
+	}, netlink.RT_FILTER_OIF|netlink.RT_FILTER_PROTOCOL)
+	require.NoError(t, err)
+	require.Len(t, routes, expected)
+
+	neighs, err := netlink.NeighList(link.Attrs().Index, netlink.FAMILY_V4)
+	require.NoError(t, err)
+	var permanent int
+	for i := range neighs {
+		if neighs[i].State == kernelconst.NudPermanent {
+			permanent++
+		}
+	}
+	require.Equal(t, expected, permanent)
+}
+
+func TestReconcileServer(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	server := newServer()
+	request := newRequest()
+
+	conn, err := server.Request(context.Background(), request.Clone())
+	require.NoError(t, err)
+	requireLink(t, 1400, "172.16.1.1/32")
+	requireRoutesAndNeighbors(t, 1)
+
+	// refresh with the changed IP context replaces the IP address
+	request.Connection = conn.Clone()
+	request.GetConnection().GetContext().GetIpContext().SrcIpAddr = "172.16.1.3/32"
+	conn, err = server.Request(context.Background(), request.Clone())
+	require.NoError(t, err)
+	requireLink(t, 1400, "172.16.1.3/32")
+	requireRoutesAndNeighbors(t, 1)
+
+	_, err = server.Close(context.Background(), conn)
+	require.NoError(t, err)
+	requireLink(t, 1500)
+	requireRoutesAndNeighbors(t, 0)
+}
+
+func TestReconcileServer_RefreshFailed(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	server := newServer()
+	request := newRequest()
+
+	conn, err := server.Request(context.Background(), request.Clone())
+	require.NoError(t, err)
+
+	// the failed refresh keeps the established connection state: the new net interface doesn't exist
+	request.Connection = conn.Clone()
+	request.GetConnection().GetContext().GetIpContext().SrcIpAddr = "172.16.1.3/32"
+	request.GetConnection().GetMechanism().GetParameters()[kernel.InterfaceNameKey] = "reconcile-3"
+	_, err = server.Request(context.Background(), request.Clone())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "net interface not found")
+	requireLink(t, 1400, "172.16.1.1/32")
+	requireRoutesAndNeighbors(t, 1)
+
+	_, err = server.Close(context.Background(), conn)
+	require.NoError(t, err)
+	requireLink(t, 1500)
+	requireRoutesAndNeighbors(t, 0)
+}
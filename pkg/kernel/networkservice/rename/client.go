@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rename
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+)
+
+type renameClient struct {
+	// If we have 2 rename clients in chain, they should manage their own mappings.
+	id string
+}
+
+// NewClient returns a new link rename client chain element renaming the VF net interface after the interface name of
+// the returned kernel mechanism
+func NewClient() networkservice.NetworkServiceClient {
+	return &renameClient{
+		id: uuid.New().String(),
+	}
+}
+
+func (c *renameClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return conn, nil
+	}
+	ifName := mech.GetInterfaceName(conn)
+
+	vfConfig, ok := vfconfig.Load(ctx, metadata.IsClient(c))
+	if !ok || vfConfig.VFInterfaceName == ifName {
+		return conn, nil
+	}
+
+	if err := renameLink(vfConfig.VFInterfaceName, ifName); err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+	oldIfName := vfConfig.VFInterfaceName
+	vfConfig.VFInterfaceName = ifName
+
+	if _, renamed := loadOldIfName(ctx, metadata.IsClient(c), c.id); !renamed {
+		storeOldIfName(ctx, metadata.IsClient(c), c.id, oldIfName)
+	}
+
+	return conn, nil
+}
+
+func (c *renameClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var renameErr error
+	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
+		ifName := mech.GetInterfaceName(conn)
+		if oldIfName, renamed := loadOldIfName(ctx, metadata.IsClient(c), c.id); renamed {
+			renameErr = renameLink(ifName, oldIfName)
+		}
+	}
+
+	if err != nil && renameErr != nil {
+		return nil, errors.Wrap(err, renameErr.Error())
+	}
+	if renameErr != nil {
+		return nil, renameErr
+	}
+	return &empty.Empty{}, err
+}
@@ -19,17 +19,25 @@ package rename
 import (
 	"context"
 
-	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/metamap"
 )
 
 type keyType string
 
-func storeOldIfName(ctx context.Context, id, oldIfName string) {
-	metadata.Map(ctx, false).Store(keyType(id), oldIfName)
+// storeOldIfName stores the VF net interface name before the rename, so it is renamed back on Close. Without metadata
+// chain element in the chain nothing is stored and the VF net interface is not renamed back.
+func storeOldIfName(ctx context.Context, isClient bool, id, oldIfName string) {
+	if m, ok := metamap.Load(ctx, isClient); ok {
+		m.Store(keyType(id), oldIfName)
+	}
 }
 
-func loadOldIfName(ctx context.Context, id string) (string, bool) {
-	if raw, ok := metadata.Map(ctx, false).Load(keyType(id)); ok {
+func loadOldIfName(ctx context.Context, isClient bool, id string) (string, bool) {
+	m, ok := metamap.Load(ctx, isClient)
+	if !ok {
+		return "", false
+	}
+	if raw, ok := m.Load(keyType(id)); ok {
 		return raw.(string), true
 	}
 	return "", false
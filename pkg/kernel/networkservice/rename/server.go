@@ -23,11 +23,11 @@ import (
 	"github.com/golang/protobuf/ptypes/empty"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
-	"github.com/vishvananda/netlink"
 
 	"github.com/networkservicemesh/api/pkg/api/networkservice"
 	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
 	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
 
 	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vfconfig"
 )
@@ -51,8 +51,8 @@ func (s *renameServer) Request(ctx context.Context, request *networkservice.Netw
 	}
 	ifName := mech.GetInterfaceName(request.GetConnection())
 
-	vfConfig := vfconfig.Config(ctx)
-	if vfConfig == nil || vfConfig.VFInterfaceName == ifName {
+	vfConfig, ok := vfconfig.Load(ctx, metadata.IsClient(s))
+	if !ok || vfConfig.VFInterfaceName == ifName {
 		return next.Server(ctx).Request(ctx, request)
 	}
 
@@ -70,8 +70,8 @@ func (s *renameServer) Request(ctx context.Context, request *networkservice.Netw
 		return nil, err
 	}
 
-	if _, renamed := loadOldIfName(ctx, s.id); !renamed {
-		storeOldIfName(ctx, s.id, oldIfName)
+	if _, renamed := loadOldIfName(ctx, metadata.IsClient(s), s.id); !renamed {
+		storeOldIfName(ctx, metadata.IsClient(s), s.id, oldIfName)
 	}
 
 	return conn, nil
@@ -83,7 +83,7 @@ func (s *renameServer) Close(ctx context.Context, conn *networkservice.Connectio
 	var renameErr error
 	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
 		ifName := mech.GetInterfaceName(conn)
-		if oldIfName, renamed := loadOldIfName(ctx, s.id); renamed {
+		if oldIfName, renamed := loadOldIfName(ctx, metadata.IsClient(s), s.id); renamed {
 			renameErr = renameLink(ifName, oldIfName)
 		}
 	}
@@ -96,16 +96,3 @@ func (s *renameServer) Close(ctx context.Context, conn *networkservice.Connectio
 	}
 	return &empty.Empty{}, err
 }
-
-func renameLink(oldName, newName string) error {
-	link, err := netlink.LinkByName(oldName)
-	if err != nil {
-		return errors.Wrapf(err, "failed to get the net interface: %v", oldName)
-	}
-
-	if err = netlink.LinkSetName(link, newName); err != nil {
-		return errors.Wrapf(err, "failed to rename net interface: %v -> %v", oldName, newName)
-	}
-
-	return nil
-}
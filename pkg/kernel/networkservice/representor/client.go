@@ -0,0 +1,84 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package representor
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+)
+
+type representorClient struct {
+	*representor
+}
+
+// NewClient returns a new representor client chain element. For the switchdev mode PFs it finds the VF representor
+// when Request returns, optionally renames it, sets it up, prepares it as a tc offload target and stores its name into
+// the VFConfig. The representor is set down and gets its original name back on Close.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &representorClient{
+		representor: newRepresentor(options),
+	}
+}
+
+func (c *representorClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	isClient := metadata.IsClient(c)
+
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	vfConfig, ok := vfconfig.Load(ctx, isClient)
+	if !ok {
+		return conn, nil
+	}
+
+	repName, err := c.up(conn, vfConfig)
+	if err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	if _, loaded := loadOldName(ctx, isClient); !loaded && repName != "" {
+		storeOldName(ctx, isClient, repName)
+	}
+
+	return conn, nil
+}
+
+func (c *representorClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	downErr := down(ctx, metadata.IsClient(c))
+
+	if err != nil && downErr != nil {
+		return nil, errors.Wrap(err, downErr.Error())
+	}
+	if downErr != nil {
+		return nil, downErr
+	}
+	return &empty.Empty{}, err
+}
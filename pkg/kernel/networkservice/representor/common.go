@@ -0,0 +1,123 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package representor
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/switchdev"
+)
+
+// representor is the common part of the representor client and server
+type representor struct {
+	nameFunc func(conn *networkservice.Connection) string
+}
+
+func newRepresentor(options []Option) *representor {
+	r := &representor{}
+	for _, opt := range options {
+		opt(r)
+	}
+	return r
+}
+
+// up finds the VF representor for the switchdev mode PF, optionally renames it and sets it up. It returns the
+// representor original name, empty if the PF is not in the switchdev mode.
+func (r *representor) up(conn *networkservice.Connection, vfConfig *vfconfig.VFConfig) (string, error) {
+	if isSwitchdev, err := switchdev.IsSwitchdev(vfConfig.PFInterfaceName); err != nil || !isSwitchdev {
+		return "", nil
+	}
+
+	repName, err := switchdev.FindVFRepresentor(vfConfig.PFInterfaceName, vfConfig.VFNum)
+	if err != nil {
+		return "", err
+	}
+
+	name := repName
+	if r.nameFunc != nil {
+		name = r.nameFunc(conn)
+	}
+	if err = setUp(repName, name); err != nil {
+		return "", err
+	}
+	vfConfig.VFRepresentorName = name
+
+	return repName, nil
+}
+
+// down sets the VF representor down and gives it the original name back
+func down(ctx context.Context, isClient bool) error {
+	vfConfig, ok := vfconfig.Load(ctx, isClient)
+	if !ok || vfConfig.VFRepresentorName == "" {
+		return nil
+	}
+
+	oldName, loaded := loadOldName(ctx, isClient)
+	if !loaded {
+		oldName = vfConfig.VFRepresentorName
+	}
+	deleteOldName(ctx, isClient)
+
+	return setDown(vfConfig.VFRepresentorName, oldName)
+}
+
+func setUp(repName, name string) error {
+	link, err := netlink.LinkByName(repName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the VF representor: %v", repName)
+	}
+
+	if name != repName {
+		if err = netlink.LinkSetDown(link); err != nil {
+			return errors.Wrapf(err, "failed to set down the VF representor: %v", repName)
+		}
+		if err = netlink.LinkSetName(link, name); err != nil {
+			return errors.Wrapf(err, "failed to rename the VF representor: %v -> %v", repName, name)
+		}
+	}
+
+	if err = netlink.LinkSetUp(link); err != nil {
+		return errors.Wrapf(err, "failed to set up the VF representor: %v", name)
+	}
+
+	return switchdev.SetupOffloadTarget(link)
+}
+
+func setDown(name, oldName string) error {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the VF representor: %v", name)
+	}
+
+	if err = netlink.LinkSetDown(link); err != nil {
+		return errors.Wrapf(err, "failed to set down the VF representor: %v", name)
+	}
+
+	if name != oldName {
+		if err = netlink.LinkSetName(link, oldName); err != nil {
+			return errors.Wrapf(err, "failed to rename the VF representor: %v -> %v", name, oldName)
+		}
+	}
+
+	return nil
+}
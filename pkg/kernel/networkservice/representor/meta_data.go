@@ -0,0 +1,50 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package representor
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/metamap"
+)
+
+type keyType struct{}
+
+// storeOldName stores the VF representor original name. Without metadata chain element in the chain nothing is stored
+// and the VF representor keeps the new name on Close.
+func storeOldName(ctx context.Context, isClient bool, oldName string) {
+	if m, ok := metamap.Load(ctx, isClient); ok {
+		m.Store(keyType{}, oldName)
+	}
+}
+
+func loadOldName(ctx context.Context, isClient bool) (string, bool) {
+	m, ok := metamap.Load(ctx, isClient)
+	if !ok {
+		return "", false
+	}
+	if raw, ok := m.Load(keyType{}); ok {
+		return raw.(string), true
+	}
+	return "", false
+}
+
+func deleteOldName(ctx context.Context, isClient bool) {
+	if m, ok := metamap.Load(ctx, isClient); ok {
+		m.Delete(keyType{})
+	}
+}
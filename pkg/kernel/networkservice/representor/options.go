@@ -0,0 +1,30 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package representor
+
+import "github.com/networkservicemesh/api/pkg/api/networkservice"
+
+// Option is an option for the representor server and client
+type Option func(r *representor)
+
+// WithNameFunc sets function returning representor net interface name for the connection, by default representor
+// keeps the name given by the kernel
+func WithNameFunc(nameFunc func(conn *networkservice.Connection) string) Option {
+	return func(r *representor) {
+		r.nameFunc = nameFunc
+	}
+}
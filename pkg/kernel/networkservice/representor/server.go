@@ -0,0 +1,91 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package representor provides chain element managing VF representor net interfaces for the switchdev mode PFs
+package representor
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+)
+
+type representorServer struct {
+	*representor
+}
+
+// NewServer returns a new representor server chain element. For the switchdev mode PFs it finds the VF representor,
+// optionally renames it, sets it up, prepares it as a tc offload target and stores its name into the VFConfig. The
+// representor is set down and gets its original name back on Close.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &representorServer{
+		representor: newRepresentor(options),
+	}
+}
+
+func (s *representorServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	isClient := metadata.IsClient(s)
+
+	vfConfig, ok := vfconfig.Load(ctx, isClient)
+	if !ok {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	repName, err := s.up(request.GetConnection(), vfConfig)
+	if err != nil {
+		return nil, err
+	}
+	if repName == "" {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if downErr := setDown(vfConfig.VFRepresentorName, repName); downErr != nil {
+			log.Entry(ctx).Warnf("failed to set down the VF representor: %s", downErr.Error())
+		}
+		vfConfig.VFRepresentorName = ""
+		return nil, err
+	}
+
+	if _, loaded := loadOldName(ctx, isClient); !loaded {
+		storeOldName(ctx, isClient, repName)
+	}
+
+	return conn, nil
+}
+
+func (s *representorServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	downErr := down(ctx, metadata.IsClient(s))
+
+	if err != nil && downErr != nil {
+		return nil, errors.Wrap(err, downErr.Error())
+	}
+	if downErr != nil {
+		return nil, downErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package representor_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/representor"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	pfName   = "rep-1"
+	peerName = "rep-2"
+)
+
+func request() *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+			},
+		},
+	}
+}
+
+func TestRepresentorServer_NotSwitchdev(t *testing.T) {
+	kerneltest.AddVeth(t, pfName, peerName)
+
+	vfConfig := &vfconfig.VFConfig{PFInterfaceName: pfName}
+	ctx := vfconfig.WithConfig(context.TODO(), vfConfig)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		representor.NewServer(representor.WithNameFunc(func(*networkservice.Connection) string {
+			return "rep-renamed"
+		})),
+	)
+
+	conn, err := server.Request(ctx, request())
+	require.NoError(t, err)
+
+	// the PF not in the switchdev mode has no representors, so nothing is changed
+	require.Empty(t, vfConfig.VFRepresentorName)
+	_, err = netlink.LinkByName("rep-renamed")
+	require.Error(t, err)
+
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+}
+
+func TestRepresentorServer_NoVFConfig(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		representor.NewServer(),
+	)
+
+	conn, err := server.Request(context.TODO(), request())
+	require.NoError(t, err)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
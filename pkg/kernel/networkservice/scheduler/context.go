@@ -0,0 +1,43 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// cancelFreeContext is a context with the parent values but without its deadline and cancellation
+type cancelFreeContext struct {
+	context.Context
+}
+
+func withoutCancel(parent context.Context) context.Context {
+	return cancelFreeContext{Context: parent}
+}
+
+func (cancelFreeContext) Deadline() (deadline time.Time, ok bool) {
+	return time.Time{}, false
+}
+
+func (cancelFreeContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (cancelFreeContext) Err() error {
+	return nil
+}
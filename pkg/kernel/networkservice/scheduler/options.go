@@ -0,0 +1,37 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import "time"
+
+// Option is an option pattern for New
+type Option func(s *Scheduler)
+
+// WithLimit sets the max number of concurrent kernel mutations, default is 16
+func WithLimit(limit int) Option {
+	return func(s *Scheduler) {
+		s.limit = limit
+	}
+}
+
+// WithCloseTimeout sets the max time Close waits for the mutation slot, Close waits for it even if its context is
+// already done and runs without the slot on the timeout, default is 15s
+func WithCloseTimeout(closeTimeout time.Duration) Option {
+	return func(s *Scheduler) {
+		s.closeTimeout = closeTimeout
+	}
+}
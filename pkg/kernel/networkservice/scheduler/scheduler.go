@@ -0,0 +1,161 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scheduler provides a global scheduler bounding concurrent kernel mutations: under mass pod churn it protects
+// the node from netlink socket buffer exhaustion and prioritizes Close and heal over the new Requests
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Priority is a mutation priority class, mutations with the higher priority are started first
+type Priority int
+
+const (
+	// PriorityRequest is a priority of the new connection Request
+	PriorityRequest Priority = iota
+	// PriorityRefresh is a priority of the established connection Request: refresh or heal
+	PriorityRefresh
+	// PriorityClose is a priority of the connection Close
+	PriorityClose
+
+	priorityCount = int(PriorityClose) + 1
+
+	defaultLimit        = 16
+	defaultCloseTimeout = 15 * time.Second
+)
+
+// Scheduler bounds concurrent kernel mutations, waiting mutations are started in the priority order and in the FIFO
+// order inside the same priority class
+type Scheduler struct {
+	limit        int
+	closeTimeout time.Duration
+
+	mu      sync.Mutex
+	active  int
+	waiters [priorityCount][]chan struct{}
+}
+
+// New creates a new Scheduler
+func New(options ...Option) *Scheduler {
+	s := &Scheduler{
+		limit:        defaultLimit,
+		closeTimeout: defaultCloseTimeout,
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	if s.limit < 1 {
+		s.limit = 1
+	}
+	return s
+}
+
+// Acquire waits for a free mutation slot for the given priority and returns a function releasing it. It returns an
+// error if ctx is done before the slot is acquired.
+func (s *Scheduler) Acquire(ctx context.Context, priority Priority) (release func(), err error) {
+	if priority < PriorityRequest || int(priority) >= priorityCount {
+		return nil, errors.Errorf("invalid priority: %d", priority)
+	}
+
+	s.mu.Lock()
+	if s.active < s.limit && s.queued() == 0 {
+		s.active++
+		s.mu.Unlock()
+		return s.releaser(), nil
+	}
+	ready := make(chan struct{})
+	s.waiters[priority] = append(s.waiters[priority], ready)
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return s.releaser(), nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		select {
+		case <-ready:
+			// the slot has been handed over concurrently with ctx cancel, so pass it further
+			s.release()
+		default:
+			s.removeWaiter(priority, ready)
+		}
+		return nil, errors.Wrap(ctx.Err(), "failed to wait for the kernel mutation slot")
+	}
+}
+
+// Active returns the number of the running mutations
+func (s *Scheduler) Active() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active
+}
+
+// Queued returns the number of the waiting mutations with the given priority
+func (s *Scheduler) Queued(priority Priority) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if priority < PriorityRequest || int(priority) >= priorityCount {
+		return 0
+	}
+	return len(s.waiters[priority])
+}
+
+func (s *Scheduler) releaser() func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.release()
+		})
+	}
+}
+
+// release hands the slot over to the first waiter with the highest priority or frees it, s.mu should be locked
+func (s *Scheduler) release() {
+	for priority := priorityCount - 1; priority >= 0; priority-- {
+		if waiters := s.waiters[priority]; len(waiters) > 0 {
+			s.waiters[priority] = waiters[1:]
+			close(waiters[0])
+			return
+		}
+	}
+	s.active--
+}
+
+func (s *Scheduler) removeWaiter(priority Priority, ready chan struct{}) {
+	waiters := s.waiters[priority]
+	for i := range waiters {
+		if waiters[i] == ready {
+			s.waiters[priority] = append(waiters[:i:i], waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Scheduler) queued() (count int) {
+	for i := range s.waiters {
+		count += len(s.waiters[i])
+	}
+	return count
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/scheduler"
+)
+
+const timeout = time.Second
+
+func acquireAsync(s *scheduler.Scheduler, priority scheduler.Priority, started chan<- scheduler.Priority) {
+	go func() {
+		release, err := s.Acquire(context.Background(), priority)
+		if err != nil {
+			return
+		}
+		started <- priority
+		release()
+	}()
+}
+
+func TestScheduler_Priority(t *testing.T) {
+	s := scheduler.New(scheduler.WithLimit(1))
+
+	release, err := s.Acquire(context.Background(), scheduler.PriorityRequest)
+	require.NoError(t, err)
+	require.Equal(t, 1, s.Active())
+
+	started := make(chan scheduler.Priority, 3)
+	for _, priority := range []scheduler.Priority{scheduler.PriorityRequest, scheduler.PriorityRefresh, scheduler.PriorityClose} {
+		acquireAsync(s, priority, started)
+		require.Eventually(t, func() bool { return s.Queued(priority) == 1 }, timeout, time.Millisecond)
+	}
+
+	release()
+	// second release is a no-op
+	release()
+
+	for _, priority := range []scheduler.Priority{scheduler.PriorityClose, scheduler.PriorityRefresh, scheduler.PriorityRequest} {
+		select {
+		case actual := <-started:
+			require.Equal(t, priority, actual)
+		case <-time.After(timeout):
+			require.FailNow(t, "mutation is not started")
+		}
+	}
+	require.Eventually(t, func() bool { return s.Active() == 0 }, timeout, time.Millisecond)
+}
+
+func TestScheduler_Cancel(t *testing.T) {
+	s := scheduler.New(scheduler.WithLimit(1))
+
+	release, err := s.Acquire(context.Background(), scheduler.PriorityClose)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = s.Acquire(ctx, scheduler.PriorityRequest)
+	require.Error(t, err)
+	require.Equal(t, 0, s.Queued(scheduler.PriorityRequest))
+
+	release()
+	require.Equal(t, 0, s.Active())
+
+	release, err = s.Acquire(context.Background(), scheduler.PriorityRequest)
+	require.NoError(t, err)
+	release()
+}
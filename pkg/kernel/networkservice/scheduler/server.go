@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type schedulerServer struct {
+	scheduler *Scheduler
+}
+
+// NewServer returns a new server chain element running the rest of the chain in the Scheduler mutation slot: new
+// connection Requests get PriorityRequest, refresh and heal Requests get PriorityRefresh, Close gets PriorityClose. It
+// should be placed right before the kernel elements, so the slot is not held over the remote calls. The same Scheduler
+// can be shared between several chains to bound the mutations globally. Close always reaches the rest of the chain:
+// if the slot is not acquired in the close timeout, Close is run without the slot.
+func (s *Scheduler) NewServer() networkservice.NetworkServiceServer {
+	return &schedulerServer{
+		scheduler: s,
+	}
+}
+
+func (s *schedulerServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	priority := PriorityRequest
+	if load(ctx, metadata.IsClient(s)) {
+		priority = PriorityRefresh
+	}
+
+	release, err := s.scheduler.Acquire(ctx, priority)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	store(ctx, metadata.IsClient(s))
+
+	return conn, nil
+}
+
+func (s *schedulerServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	del(ctx, metadata.IsClient(s))
+
+	// Close should always reach the rest of the chain to free the kernel resources, so the slot is waited detached from
+	// ctx cancellation bounded by the close timeout, if it is not acquired in time Close is run without the slot
+	acquireCtx, cancel := context.WithTimeout(withoutCancel(ctx), s.scheduler.closeTimeout)
+	defer cancel()
+	if release, err := s.scheduler.Acquire(acquireCtx, PriorityClose); err == nil {
+		defer release()
+	}
+
+	return next.Server(ctx).Close(ctx, conn)
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scheduler_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/scheduler"
+)
+
+type blockingServer struct {
+	block chan struct{}
+}
+
+func (s *blockingServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	<-s.block
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *blockingServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	<-s.block
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func TestSchedulerServer(t *testing.T) {
+	s := scheduler.New(scheduler.WithLimit(1))
+	block := make(chan struct{}, 10)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		s.NewServer(),
+		&blockingServer{block: block},
+	)
+
+	request := func(id string) *networkservice.NetworkServiceRequest {
+		return &networkservice.NetworkServiceRequest{
+			Connection: &networkservice.Connection{Id: id},
+		}
+	}
+
+	// establish conn-1
+	block <- struct{}{}
+	conn, err := server.Request(context.Background(), request("conn-1"))
+	require.NoError(t, err)
+
+	// conn-2 holds the slot
+	go func() { _, _ = server.Request(context.Background(), request("conn-2")) }()
+	require.Eventually(t, func() bool { return s.Active() == 1 }, timeout, time.Millisecond)
+
+	go func() { _, _ = server.Request(context.Background(), request("conn-3")) }()
+	require.Eventually(t, func() bool { return s.Queued(scheduler.PriorityRequest) == 1 }, timeout, time.Millisecond)
+
+	go func() { _, _ = server.Request(context.Background(), request("conn-1")) }()
+	require.Eventually(t, func() bool { return s.Queued(scheduler.PriorityRefresh) == 1 }, timeout, time.Millisecond)
+
+	go func() { _, _ = server.Close(context.Background(), conn) }()
+	require.Eventually(t, func() bool { return s.Queued(scheduler.PriorityClose) == 1 }, timeout, time.Millisecond)
+
+	for i := 0; i < 4; i++ {
+		block <- struct{}{}
+	}
+	require.Eventually(t, func() bool { return s.Active() == 0 }, timeout, time.Millisecond)
+}
+
+type closeCountServer struct {
+	closed int32
+}
+
+func (s *closeCountServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return next.Server(ctx).Request(ctx, request)
+}
+
+func (s *closeCountServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	atomic.AddInt32(&s.closed, 1)
+	return next.Server(ctx).Close(ctx, conn)
+}
+
+func TestSchedulerServer_CloseCanceled(t *testing.T) {
+	s := scheduler.New(scheduler.WithLimit(1), scheduler.WithCloseTimeout(10*time.Millisecond))
+	counter := new(closeCountServer)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		s.NewServer(),
+		counter,
+	)
+
+	conn, err := server.Request(context.Background(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{Id: "conn-1"},
+	})
+	require.NoError(t, err)
+
+	// the slot is held by someone else and the Close context is already canceled
+	release, err := s.Acquire(context.Background(), scheduler.PriorityRequest)
+	require.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = server.Close(ctx, conn)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), atomic.LoadInt32(&counter.closed))
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sriov
+
+import (
+	"time"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/inject"
+)
+
+const defaultRebindTimeout = 5 * time.Second
+
+type sriovOptions struct {
+	rebindTimeout time.Duration
+	injectOptions []inject.Option
+}
+
+// Option is an option pattern for NewServer
+type Option func(o *sriovOptions)
+
+// WithRebindTimeout sets how long to wait for the VF net interface to appear while the VF driver is being rebound.
+// Default is 5 seconds.
+func WithRebindTimeout(rebindTimeout time.Duration) Option {
+	return func(o *sriovOptions) {
+		o.rebindTimeout = rebindTimeout
+	}
+}
+
+// WithInjectOptions sets options for the underlying inject chain element, e.g. inject.WithPreemptionPolicy. The link
+// provider is always linkprovider.NewSRIOV().
+func WithInjectOptions(options ...inject.Option) Option {
+	return func(o *sriovOptions) {
+		o.injectOptions = options
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sriov provides chain element injecting the SR-IOV VF selected by the PCI address in the kernel mechanism
+// parameters into the Client's net NS
+package sriov
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ethernetcontext"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/inject"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+)
+
+// PCIAddressKey is a kernel mechanism parameter key with the PCI address of the VF to inject, e.g. "0000:01:10.0"
+const PCIAddressKey = "pciAddress"
+
+type sriovServer struct {
+	rebindTimeout time.Duration
+	vfServer      networkservice.NetworkServiceServer
+}
+
+// NewServer returns a new SR-IOV server chain element. On Request it resolves the VF net interface by PCI address from
+// the PCIAddressKey kernel mechanism parameter into VFConfig, sets the VF MAC address and VLAN via the PF, moves the VF
+// into the Client's net NS and applies the IP context to it. On Close the VF is returned into the current net NS with
+// its original name. Connections without PCIAddressKey are passed through.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	o := &sriovOptions{
+		rebindTimeout: defaultRebindTimeout,
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+
+	return &sriovServer{
+		rebindTimeout: o.rebindTimeout,
+		// the VF connections continue to the next chain element after the VF chain
+		vfServer: chain.NewNetworkServiceServer(
+			ethernetcontext.NewVFServer(),
+			inject.NewServer(append(append([]inject.Option{}, o.injectOptions...),
+				inject.WithLinkProvider(linkprovider.NewSRIOV()))...),
+			ipcontext.NewServer(),
+		),
+	}
+}
+
+func (s *sriovServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	pciAddress := kernel.ToMechanism(request.GetConnection().GetMechanism()).GetParameters()[PCIAddressKey]
+	if pciAddress == "" {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	vfConfig, loaded := vfconfig.Load(ctx, false)
+	if !loaded {
+		var err error
+		if vfConfig, err = resolveVF(ctx, pciAddress, s.rebindTimeout); err != nil {
+			return nil, err
+		}
+		vfconfig.Store(ctx, vfConfig)
+	}
+
+	conn, err := s.vfServer.Request(vfconfig.WithConfig(ctx, vfConfig), request)
+	if err != nil {
+		if !loaded {
+			vfconfig.Delete(ctx)
+		}
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (s *sriovServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	vfConfig, ok := vfconfig.Load(ctx, false)
+	if !ok || kernel.ToMechanism(conn.GetMechanism()).GetParameters()[PCIAddressKey] == "" {
+		return next.Server(ctx).Close(ctx, conn)
+	}
+
+	_, err := s.vfServer.Close(vfconfig.WithConfig(ctx, vfConfig), conn)
+	vfconfig.Delete(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+	return &empty.Empty{}, nil
+}
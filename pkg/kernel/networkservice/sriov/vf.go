@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sriov
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+)
+
+const (
+	pciDevicesPath   = "/sys/bus/pci/devices"
+	virtfnPrefix     = "virtfn"
+	rebindPollPeriod = 100 * time.Millisecond
+)
+
+// resolveVF returns VFConfig for the VF with the given PCI address, it waits for the VF net interface to appear for
+// the given timeout, because the VF driver can be rebound at the moment (e.g. after returning the VF to the host)
+func resolveVF(ctx context.Context, pciAddress string, timeout time.Duration) (*vfconfig.VFConfig, error) {
+	timeoutCh := time.After(timeout)
+	for {
+		vfConfig, err := readVF(pciAddress)
+		if err == nil {
+			if _, err = netlink.LinkByName(vfConfig.VFInterfaceName); err == nil {
+				return vfConfig, nil
+			}
+			err = errors.Wrapf(err, "failed to get VF net interface: %v", vfConfig.VFInterfaceName)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrap(err, ctx.Err().Error())
+		case <-timeoutCh:
+			return nil, errors.Wrapf(err, "VF net interface is not ready, probably the driver is being rebound: %v", pciAddress)
+		case <-time.After(rebindPollPeriod):
+		}
+	}
+}
+
+func readVF(pciAddress string) (*vfconfig.VFConfig, error) {
+	devicePath := filepath.Join(pciDevicesPath, filepath.Base(pciAddress))
+
+	vfName, err := netName(devicePath)
+	if err != nil {
+		return nil, err
+	}
+
+	pfPath := filepath.Join(devicePath, "physfn")
+	pfName, err := netName(pfPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get PF for the VF: %v", pciAddress)
+	}
+
+	vfNum, err := vfNum(pfPath, pciAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vfconfig.VFConfig{
+		PFInterfaceName: pfName,
+		VFInterfaceName: vfName,
+		VFNum:           vfNum,
+	}, nil
+}
+
+// netName returns name of the PCI device net interface
+func netName(devicePath string) (string, error) {
+	infos, err := ioutil.ReadDir(filepath.Join(devicePath, "net"))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read PCI device net interfaces: %v", devicePath)
+	}
+	if len(infos) == 0 {
+		return "", errors.Errorf("no net interfaces for the PCI device: %v", devicePath)
+	}
+	return infos[0].Name(), nil
+}
+
+// vfNum returns the VF number for the PF: N of the PF virtfnN link pointing to the VF
+func vfNum(pfPath, pciAddress string) (int, error) {
+	links, err := filepath.Glob(filepath.Join(pfPath, virtfnPrefix+"*"))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to list PF VFs: %v", pfPath)
+	}
+	for _, link := range links {
+		target, err := filepath.EvalSymlinks(link)
+		if err != nil || filepath.Base(target) != pciAddress {
+			continue
+		}
+		if num, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(link), virtfnPrefix)); err == nil {
+			return num, nil
+		}
+	}
+	return 0, errors.Errorf("failed to find VF number for the VF: %v", pciAddress)
+}
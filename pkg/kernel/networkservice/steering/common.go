@@ -0,0 +1,64 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steering
+
+import (
+	"hash/fnv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nft"
+)
+
+const markPrefix = 0x4e000000
+
+// Mark returns the firewall mark and the route table ID of the connection steered traffic. The high byte is the NSM
+// routing protocol number, so the marks don't clash with the small marks and route table IDs used by CNIs.
+func Mark(connID string) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(connID))
+	return markPrefix | int(hash.Sum32()&0x00ffffff)
+}
+
+// tableName returns the nftables table name differing from the other connection tables (e.g. conntrack helpers)
+func tableName(connID string) string {
+	return nft.TableName(connID + "/steering")
+}
+
+// newTable returns nftables table marking the packets of the sockets owned by the cgroup. The route type chain makes
+// the kernel reroute the locally generated packets after the mark is set.
+func newTable(connID, cgroup string, mark int) (*nft.Table, error) {
+	cgroup = strings.Trim(cgroup, "/")
+	if cgroup == "" || strings.ContainsAny(cgroup, "\"\n") {
+		return nil, errors.Errorf("invalid %s label value: %v", CgroupLabel, cgroup)
+	}
+
+	table := &nft.Table{
+		Family: nft.FamilyInet,
+		Name:   tableName(connID),
+	}
+	output := table.AddChain(&nft.Chain{
+		Name:     "output",
+		Type:     "route",
+		Hook:     "output",
+		Priority: -150,
+	})
+	output.AddRule(`socket cgroupv2 level %d "%s" meta mark set 0x%08x`, len(strings.Split(cgroup, "/")), cgroup, mark)
+
+	return table, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steering
+
+import (
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ipaddrs"
+)
+
+// addPolicy adds the default route via the net interface into the connection route table and the rule looking up
+// the table for the marked packets, for every IP family of the connection
+func (s *steeringServer) addPolicy(conn *networkservice.Connection, ifName string, mark int) error {
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+
+	dsts, err := defaultDsts(conn)
+	if err != nil {
+		return err
+	}
+	for _, dst := range dsts {
+		route := &netlink.Route{
+			LinkIndex: link.Attrs().Index,
+			Dst:       dst,
+			Scope:     netlink.SCOPE_LINK,
+			Table:     mark,
+			Protocol:  kernel.RouteProtoNSM,
+		}
+		if err := netlink.RouteAdd(route); err != nil && !os.IsExist(err) {
+			return errors.Wrapf(err, "failed to add route: %v table %v", dst, mark)
+		}
+		if err := netlink.RuleAdd(s.newRule(dst, mark)); err != nil && !os.IsExist(err) {
+			return errors.Wrapf(err, "failed to add rule: fwmark %v lookup %v", mark, mark)
+		}
+	}
+	return nil
+}
+
+// delPolicy deletes the rules, the routes are deleted with the net interface
+func (s *steeringServer) delPolicy(conn *networkservice.Connection, mark int) error {
+	dsts, err := defaultDsts(conn)
+	if err != nil {
+		return err
+	}
+	for _, dst := range dsts {
+		if err := netlink.RuleDel(s.newRule(dst, mark)); err != nil && !os.IsNotExist(err) && err != syscall.ESRCH {
+			return errors.Wrapf(err, "failed to delete rule: fwmark %v lookup %v", mark, mark)
+		}
+	}
+	return nil
+}
+
+func (s *steeringServer) newRule(dst *net.IPNet, mark int) *netlink.Rule {
+	rule := netlink.NewRule()
+	rule.Family = netlink.FAMILY_V4
+	if !ipaddrs.IsIPv4(dst.IP) {
+		rule.Family = netlink.FAMILY_V6
+	}
+	rule.Priority = s.rulePriority
+	rule.Mark = mark
+	rule.Table = mark
+	return rule
+}
+
+// defaultDsts returns the default route destinations for the IP families of the Client's IP addresses, both families
+// are steered if there are no IP addresses
+func defaultDsts(conn *networkservice.Connection) ([]*net.IPNet, error) {
+	ipNets, err := ipaddrs.Parse(conn.GetContext().GetIpContext().GetSrcIpAddr())
+	if err != nil {
+		return nil, err
+	}
+
+	var v4, v6 bool
+	for _, ipNet := range ipNets {
+		if ipaddrs.IsIPv4(ipNet.IP) {
+			v4 = true
+		} else {
+			v6 = true
+		}
+	}
+	if !v4 && !v6 {
+		v4, v6 = true, true
+	}
+
+	var dsts []*net.IPNet
+	if v4 {
+		dsts = append(dsts, &net.IPNet{IP: net.IPv4zero.To4(), Mask: net.CIDRMask(0, net.IPv4len*8)})
+	}
+	if v6 {
+		dsts = append(dsts, &net.IPNet{IP: net.IPv6zero, Mask: net.CIDRMask(0, net.IPv6len*8)})
+	}
+	return dsts, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package steering provides chain element steering only a single workload traffic into the Client's net interface
+package steering
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nft"
+)
+
+// CgroupLabel is a connection label with the cgroup v2 path of the workload relative to the cgroup v2 root, e.g.
+// "kubepods.slice/kubepods-pod1234.slice/cri-containerd-5678.scope". Only the traffic of the sockets owned by the
+// workload is steered into the connection, the other traffic in the same net NS (e.g. sidecars) is untouched.
+const CgroupLabel = "steeringCgroup"
+
+const defaultRulePriority = 100
+
+type steeringServer struct {
+	rulePriority int
+}
+
+// NewServer returns a new traffic steering server chain element. If the connection has CgroupLabel, it marks the
+// traffic of the workload sockets with the nftables `socket cgroupv2` match and routes the marked traffic via the
+// Client's net interface with the policy routing rule and the connection route table. Both mark and route table are
+// Mark(conn.GetId()). It programs the current net NS, so it should follow the netns chain element.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	s := &steeringServer{
+		rulePriority: defaultRulePriority,
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+func (s *steeringServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	mech := kernel.ToMechanism(request.GetConnection().GetMechanism())
+	cgroup := request.GetConnection().GetLabels()[CgroupLabel]
+	if mech == nil || cgroup == "" {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	mark := Mark(request.GetConnection().GetId())
+	table, err := newTable(request.GetConnection().GetId(), cgroup, mark)
+	if err != nil {
+		return nil, err
+	}
+	if err = nft.Apply(ctx, table); err != nil {
+		return nil, err
+	}
+
+	if err = s.addPolicy(request.GetConnection(), mech.GetInterfaceName(request.GetConnection()), mark); err != nil {
+		s.cleanup(ctx, request.GetConnection(), mark)
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		s.cleanup(ctx, request.GetConnection(), mark)
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (s *steeringServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var deleteErr error
+	if kernel.ToMechanism(conn.GetMechanism()) != nil && conn.GetLabels()[CgroupLabel] != "" {
+		mark := Mark(conn.GetId())
+		if deleteErr = s.delPolicy(conn, mark); deleteErr == nil {
+			deleteErr = nft.Delete(ctx, nft.FamilyInet, tableName(conn.GetId()))
+		}
+	}
+
+	if err != nil && deleteErr != nil {
+		return nil, errors.Wrap(err, deleteErr.Error())
+	}
+	if deleteErr != nil {
+		return nil, deleteErr
+	}
+	return &empty.Empty{}, err
+}
+
+func (s *steeringServer) cleanup(ctx context.Context, conn *networkservice.Connection, mark int) {
+	logEntry := log.Entry(ctx).WithField("steeringServer", "Request")
+	if err := s.delPolicy(conn, mark); err != nil {
+		logEntry.Warnf("failed to delete policy routing: %s", err.Error())
+	}
+	if err := nft.Delete(ctx, nft.FamilyInet, tableName(conn.GetId())); err != nil {
+		logEntry.Warnf("failed to delete socket marking: %s", err.Error())
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package steering_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/steering"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nft"
+)
+
+const (
+	ifName   = "steering-1"
+	peerName = "steering-2"
+)
+
+func rules(t *testing.T, mark int) []netlink.Rule {
+	list, err := netlink.RuleList(netlink.FAMILY_V4)
+	require.NoError(t, err)
+
+	var result []netlink.Rule
+	for i := range list {
+		if list[i].Mark == mark {
+			result = append(result, list[i])
+		}
+	}
+	return result
+}
+
+func TestSteeringServer(t *testing.T) {
+	// nftables rules are not programmed, they are covered with the nft package tests
+	binary := nft.Binary
+	nft.Binary = "true"
+	defer func() { nft.Binary = binary }()
+
+	link := kerneltest.AddVeth(t, ifName, peerName)
+	require.NoError(t, netlink.LinkSetUp(link))
+
+	conn := &networkservice.Connection{
+		Id: "conn-1",
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+		Context: &networkservice.ConnectionContext{
+			IpContext: &networkservice.IPContext{
+				SrcIpAddr: "10.0.16.1/32",
+			},
+		},
+		Labels: map[string]string{
+			steering.CgroupLabel: "/kubepods/pod-1/container-1",
+		},
+	}
+	mark := steering.Mark(conn.GetId())
+
+	server := steering.NewServer(steering.WithRulePriority(1000))
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	list := rules(t, mark)
+	require.Len(t, list, 1)
+	require.Equal(t, mark, list[0].Table)
+	require.Equal(t, 1000, list[0].Priority)
+
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_V4, &netlink.Route{Table: mark}, netlink.RT_FILTER_TABLE)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	require.Equal(t, link.Attrs().Index, routes[0].LinkIndex)
+
+	// refresh doesn't duplicate the rules
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Len(t, rules(t, mark), 1)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.Empty(t, rules(t, mark))
+}
+
+func TestSteeringServer_InvalidCgroup(t *testing.T) {
+	conn := &networkservice.Connection{
+		Id: "conn-1",
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+		Labels: map[string]string{
+			steering.CgroupLabel: "/",
+		},
+	}
+
+	_, err := steering.NewServer().Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.Error(t, err)
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package strict provides chain elements enabling the strict verification mode (see tools/strict) for the following
+// chain elements: the mutations made by ipcontext, routes, mtu and veth chain elements are read back and the
+// mismatches fail the Request with strict.MismatchError
+package strict
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/strict"
+)
+
+type strictServer struct{}
+
+// NewServer returns a new server chain element enabling the strict verification mode for the following chain
+// elements
+func NewServer() networkservice.NetworkServiceServer {
+	return &strictServer{}
+}
+
+func (s *strictServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return next.Server(ctx).Request(strict.WithStrict(ctx), request)
+}
+
+func (s *strictServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(strict.WithStrict(ctx), conn)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strict_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext/routes"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/mtu"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/strict"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName   = "strict-1"
+	peerName = "strict-2"
+)
+
+func TestStrictServer(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		strict.NewServer(),
+		mtu.NewServer(),
+		ipcontext.NewServer(ipcontext.WithoutRoutes()),
+		routes.NewServer(routes.WithTable(1000)),
+	)
+
+	conn := &networkservice.Connection{
+		Id: "conn-1",
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.NetNSURL:         "file:///proc/self/ns/net",
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+		Context: &networkservice.ConnectionContext{
+			IpContext: &networkservice.IPContext{
+				SrcIpAddr: "10.0.31.1/32",
+				SrcRoutes: []*networkservice.Route{
+					{Prefix: "10.0.31.2/32"},
+				},
+			},
+			ExtraContext: map[string]string{
+				mtu.MTUKey: "1400",
+			},
+		},
+	}
+
+	// the read-backs match the kernel state
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	require.Equal(t, 1400, link.Attrs().MTU)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
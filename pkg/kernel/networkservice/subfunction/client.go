@@ -0,0 +1,88 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subfunction
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type subfunctionClient struct {
+	*subfunctions
+}
+
+// NewClient returns a new subfunction client chain element. When the first Request returns it creates and activates a
+// new SF on the given PCI device PF and names the SF net interface after the returned kernel mechanism interface name,
+// so it can be moved into the target net NS by inject chain element. The SF is deleted on Close.
+func NewClient(pciAddress string, options ...Option) networkservice.NetworkServiceClient {
+	return &subfunctionClient{
+		subfunctions: newSubfunctions(pciAddress, options),
+	}
+}
+
+func (c *subfunctionClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	isClient := metadata.IsClient(c)
+
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return conn, nil
+	}
+	if _, ok := loadPort(ctx, isClient); ok {
+		return conn, nil
+	}
+
+	port, err := c.createSF(ctx, conn, mech.GetInterfaceName(conn), isClient)
+	if err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	storePort(ctx, isClient, port)
+
+	return conn, nil
+}
+
+func (c *subfunctionClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var deleteErr error
+	if port, ok := loadPort(ctx, metadata.IsClient(c)); ok {
+		deleteErr = c.deleteSF(port)
+		deletePort(ctx, metadata.IsClient(c))
+	}
+
+	if err != nil && deleteErr != nil {
+		return nil, errors.Wrap(err, deleteErr.Error())
+	}
+	if deleteErr != nil {
+		return nil, deleteErr
+	}
+	return &empty.Empty{}, err
+}
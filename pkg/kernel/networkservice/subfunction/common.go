@@ -0,0 +1,147 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subfunction
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/devlink"
+)
+
+const (
+	defaultMinSFNumber = 1
+	defaultMaxSFNumber = 1024
+	defaultTimeout     = 10 * time.Second
+)
+
+// subfunctions is the common part of the subfunction client and server
+type subfunctions struct {
+	pciAddress  string
+	pfNumber    uint16
+	minSFNumber uint32
+	maxSFNumber uint32
+	timeout     time.Duration
+
+	usedSFNumbers map[uint32]struct{}
+	lock          sync.Mutex
+}
+
+func newSubfunctions(pciAddress string, options []Option) *subfunctions {
+	s := &subfunctions{
+		pciAddress:    pciAddress,
+		minSFNumber:   defaultMinSFNumber,
+		maxSFNumber:   defaultMaxSFNumber,
+		timeout:       defaultTimeout,
+		usedSFNumbers: make(map[uint32]struct{}),
+	}
+	for _, opt := range options {
+		opt(s)
+	}
+	return s
+}
+
+// createSF creates and activates a new SF and names the SF net interface after the given name. Server side SF gets Src
+// MAC address, Client side SF gets Dst one.
+func (s *subfunctions) createSF(ctx context.Context, conn *networkservice.Connection, ifName string, isClient bool) (port *devlink.Port, err error) {
+	sfNumber, err := s.allocateSFNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	if port, err = devlink.NewSFPort(s.pciAddress, s.pfNumber, sfNumber); err != nil {
+		s.releaseSFNumber(sfNumber)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			_ = s.deleteSF(port)
+		}
+	}()
+
+	mac := conn.GetContext().GetEthernetContext().GetSrcMac()
+	if isClient {
+		mac = conn.GetContext().GetEthernetContext().GetDstMac()
+	}
+	if mac != "" {
+		var hwAddr net.HardwareAddr
+		if hwAddr, err = net.ParseMAC(mac); err != nil {
+			return nil, errors.Wrapf(err, "invalid MAC address: %v", mac)
+		}
+		if err = devlink.SetPortFunctionHwAddr(port, hwAddr); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = devlink.SetPortFunctionState(port, true); err != nil {
+		return nil, err
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var sfName string
+	if sfName, err = devlink.SFNetdevName(timeoutCtx, s.pciAddress, sfNumber); err != nil {
+		return nil, err
+	}
+
+	var link netlink.Link
+	if link, err = netlink.LinkByName(sfName); err != nil {
+		return nil, errors.Wrapf(err, "failed to get SF net interface: %v", sfName)
+	}
+	if err = netlink.LinkSetName(link, ifName); err != nil {
+		return nil, errors.Wrapf(err, "failed to rename SF net interface: %v -> %v", sfName, ifName)
+	}
+
+	return port, nil
+}
+
+func (s *subfunctions) deleteSF(port *devlink.Port) error {
+	defer s.releaseSFNumber(port.SFNumber)
+
+	if err := devlink.SetPortFunctionState(port, false); err != nil {
+		return err
+	}
+	return devlink.DeletePort(port)
+}
+
+func (s *subfunctions) allocateSFNumber() (uint32, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for sfNumber := s.minSFNumber; sfNumber <= s.maxSFNumber; sfNumber++ {
+		if _, ok := s.usedSFNumbers[sfNumber]; !ok {
+			s.usedSFNumbers[sfNumber] = struct{}{}
+			return sfNumber, nil
+		}
+	}
+	return 0, errors.Errorf("no free SF numbers left: %v-%v", s.minSFNumber, s.maxSFNumber)
+}
+
+func (s *subfunctions) releaseSFNumber(sfNumber uint32) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.usedSFNumbers, sfNumber)
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subfunction
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/devlink"
+)
+
+type keyType struct{}
+
+func storePort(ctx context.Context, isClient bool, port *devlink.Port) {
+	metadata.Map(ctx, isClient).Store(keyType{}, port)
+}
+
+func loadPort(ctx context.Context, isClient bool) (*devlink.Port, bool) {
+	if raw, ok := metadata.Map(ctx, isClient).Load(keyType{}); ok {
+		return raw.(*devlink.Port), true
+	}
+	return nil, false
+}
+
+func deletePort(ctx context.Context, isClient bool) {
+	metadata.Map(ctx, isClient).Delete(keyType{})
+}
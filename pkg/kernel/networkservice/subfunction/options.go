@@ -0,0 +1,44 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subfunction
+
+import "time"
+
+// Option is an option for the subfunction server and client
+type Option func(s *subfunctions)
+
+// WithPFNumber sets PF number of the PCI device to create SFs on, default 0
+func WithPFNumber(pfNumber uint16) Option {
+	return func(s *subfunctions) {
+		s.pfNumber = pfNumber
+	}
+}
+
+// WithSFNumberRange sets range of SF numbers available for the allocation
+func WithSFNumberRange(minSFNumber, maxSFNumber uint32) Option {
+	return func(s *subfunctions) {
+		s.minSFNumber = minSFNumber
+		s.maxSFNumber = maxSFNumber
+	}
+}
+
+// WithTimeout sets timeout for waiting the SF net interface to appear after the SF activation
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *subfunctions) {
+		s.timeout = timeout
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package subfunction provides chain element creating subfunction (SF) net interfaces for the Clients
+package subfunction
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type subfunctionServer struct {
+	*subfunctions
+}
+
+// NewServer returns a new subfunction server chain element. On Request it creates and activates a new SF on the
+// given PCI device PF and names the SF net interface after the kernel mechanism interface name, so it can be moved into
+// the Client's net NS by inject chain element. The SF is deleted on Close.
+func NewServer(pciAddress string, options ...Option) networkservice.NetworkServiceServer {
+	return &subfunctionServer{
+		subfunctions: newSubfunctions(pciAddress, options),
+	}
+}
+
+func (s *subfunctionServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	isClient := metadata.IsClient(s)
+
+	mech := kernel.ToMechanism(request.GetConnection().GetMechanism())
+	if mech == nil {
+		return next.Server(ctx).Request(ctx, request)
+	}
+	if _, ok := loadPort(ctx, isClient); ok {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	port, err := s.createSF(ctx, request.GetConnection(), mech.GetInterfaceName(request.GetConnection()), isClient)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if deleteErr := s.deleteSF(port); deleteErr != nil {
+			log.Entry(ctx).Warnf("failed to delete SF: %s", deleteErr.Error())
+		}
+		return nil, err
+	}
+
+	storePort(ctx, isClient, port)
+
+	return conn, nil
+}
+
+func (s *subfunctionServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var deleteErr error
+	if port, ok := loadPort(ctx, metadata.IsClient(s)); ok {
+		deleteErr = s.deleteSF(port)
+		deletePort(ctx, metadata.IsClient(s))
+	}
+
+	if err != nil && deleteErr != nil {
+		return nil, errors.Wrap(err, deleteErr.Error())
+	}
+	if deleteErr != nil {
+		return nil, deleteErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package subfunction_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/memif"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/subfunction"
+)
+
+// pciAddress is a PCI address with no devlink device
+const pciAddress = "0000:ff:1f.7"
+
+func request(mechanismType string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: mechanismType,
+				Parameters: map[string]string{
+					kernel.InterfaceNameKey: "sf-1",
+				},
+			},
+		},
+	}
+}
+
+func TestSubfunctionServer_CreateFailed(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		subfunction.NewServer(pciAddress, subfunction.WithSFNumberRange(1, 1)),
+	)
+
+	_, err := server.Request(context.TODO(), request(kernel.MECHANISM))
+	require.Error(t, err)
+
+	// the SF number is released on failure, so the only one is available again
+	_, err = server.Request(context.TODO(), request(kernel.MECHANISM))
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "no free SF numbers")
+}
+
+func TestSubfunctionServer_NotKernel(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		subfunction.NewServer(pciAddress),
+	)
+
+	conn, err := server.Request(context.TODO(), request(memif.MECHANISM))
+	require.NoError(t, err)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
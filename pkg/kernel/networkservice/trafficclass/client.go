@@ -0,0 +1,84 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafficclass
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type trafficClassClient struct {
+	*trafficClass
+}
+
+// NewClient returns a new traffic class client chain element. It installs egress tc filter on the net interface
+// selected by the returned kernel mechanism setting skb priority and/or TX queue mapping, so the traffic gets into the
+// chosen NIC traffic class or queue group. The filter and the clsact qdisc added for it are removed on Close. It should
+// be placed after the netns chain element.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &trafficClassClient{
+		trafficClass: newTrafficClass(options),
+	}
+}
+
+func (c *trafficClassClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return conn, nil
+	}
+
+	prev, _ := load(ctx, metadata.IsClient(c))
+	state, err := c.apply(conn, mech, prev)
+	if err == nil && state == nil {
+		// the traffic class dropped on refresh is removed
+		if prev, ok := loadAndDelete(ctx, metadata.IsClient(c)); ok {
+			err = prev.remove()
+		}
+	}
+	if err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	if state != nil {
+		store(ctx, metadata.IsClient(c), state)
+	}
+
+	return conn, nil
+}
+
+func (c *trafficClassClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	if state, ok := loadAndDelete(ctx, metadata.IsClient(c)); ok {
+		if err := state.remove(); err != nil {
+			log.Entry(ctx).Warnf("failed to delete traffic class filter: %s", err.Error())
+		}
+	}
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafficclass
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+)
+
+const (
+	// PriorityLabel is a connection label setting skb priority of the connection traffic, NIC maps priorities to the
+	// traffic classes with mqprio/DCB configuration
+	PriorityLabel = "trafficClassPriority"
+	// QueueLabel is a connection label setting TX queue of the connection traffic
+	QueueLabel = "trafficClassQueue"
+)
+
+// trafficClass is the common part of the traffic class client and server
+type trafficClass struct {
+	priority *uint32
+	queue    *uint16
+}
+
+func newTrafficClass(options []Option) *trafficClass {
+	t := &trafficClass{}
+	for _, opt := range options {
+		opt(t)
+	}
+	return t
+}
+
+func (t *trafficClass) values(labels map[string]string) (priority *uint32, queue *uint16, err error) {
+	priority, queue = t.priority, t.queue
+	if value, ok := labels[PriorityLabel]; ok {
+		intValue, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid %s label value: %v", PriorityLabel, value)
+		}
+		priority = new(uint32)
+		*priority = uint32(intValue)
+	}
+	if value, ok := labels[QueueLabel]; ok {
+		intValue, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid %s label value: %v", QueueLabel, value)
+		}
+		queue = new(uint16)
+		*queue = uint16(intValue)
+	}
+	return priority, queue, nil
+}
+
+// applied is the traffic class filter installed on the net interface, qdiscAdded is true if the clsact qdisc has been
+// added with the filter, so it should be deleted with it
+type applied struct {
+	ifName     string
+	qdiscAdded bool
+}
+
+// apply installs the traffic class filter on the connection kernel interface, it returns nil if the connection has no
+// traffic class. prev is the filter installed on the previous Request, the clsact qdisc added with it is still owned
+// by the connection on refresh.
+func (t *trafficClass) apply(conn *networkservice.Connection, mech *kernel.Mechanism, prev *applied) (*applied, error) {
+	priority, queue, err := t.values(conn.GetLabels())
+	if err != nil {
+		return nil, err
+	}
+	if priority == nil && queue == nil {
+		return nil, nil
+	}
+
+	ifName := mech.GetInterfaceName(conn)
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+	qdiscAdded, err := setTrafficClass(link, priority, queue)
+	if err != nil {
+		return nil, err
+	}
+	return &applied{
+		ifName:     ifName,
+		qdiscAdded: qdiscAdded || (prev != nil && prev.ifName == ifName && prev.qdiscAdded),
+	}, nil
+}
+
+// remove deletes the traffic class filter and the clsact qdisc added with it, already deleted net interface is skipped
+func (a *applied) remove() error {
+	link, err := netlink.LinkByName(a.ifName)
+	if err != nil {
+		return nil
+	}
+	return delTrafficClass(link, a.qdiscAdded)
+}
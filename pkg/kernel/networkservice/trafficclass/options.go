@@ -0,0 +1,35 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafficclass
+
+// Option is an option for the traffic class server and client
+type Option func(t *trafficClass)
+
+// WithPriority sets skb priority for the connection traffic. It can be overridden per connection with the
+// PriorityLabel label.
+func WithPriority(priority uint32) Option {
+	return func(t *trafficClass) {
+		t.priority = &priority
+	}
+}
+
+// WithQueue sets TX queue for the connection traffic. It can be overridden per connection with the QueueLabel label.
+func WithQueue(queue uint16) Option {
+	return func(t *trafficClass) {
+		t.queue = &queue
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trafficclass provides chain element mapping connection traffic to the NIC traffic class and TX queue
+package trafficclass
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type trafficClassServer struct {
+	*trafficClass
+}
+
+// NewServer returns a new traffic class server chain element. It installs egress tc filter on the connection kernel
+// interface setting skb priority and/or TX queue mapping, so the traffic gets into the chosen NIC traffic class or
+// queue group. The filter and the clsact qdisc added for it are removed on Close. It should be placed after the netns
+// chain element.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &trafficClassServer{
+		trafficClass: newTrafficClass(options),
+	}
+}
+
+func (s *trafficClassServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	mech := kernel.ToMechanism(request.GetConnection().GetMechanism())
+	if mech == nil {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	prev, _ := load(ctx, metadata.IsClient(s))
+	state, err := s.apply(request.GetConnection(), mech, prev)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		// the traffic class dropped on refresh is removed
+		if prev, ok := loadAndDelete(ctx, metadata.IsClient(s)); ok {
+			if err := prev.remove(); err != nil {
+				return nil, err
+			}
+		}
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		if prev == nil {
+			if removeErr := state.remove(); removeErr != nil {
+				log.Entry(ctx).Warnf("failed to delete traffic class filter: %s", removeErr.Error())
+			}
+		}
+		return nil, err
+	}
+
+	store(ctx, metadata.IsClient(s), state)
+
+	return conn, nil
+}
+
+func (s *trafficClassServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if state, ok := loadAndDelete(ctx, metadata.IsClient(s)); ok {
+		if err := state.remove(); err != nil {
+			log.Entry(ctx).Warnf("failed to delete traffic class filter: %s", err.Error())
+		}
+	}
+	return next.Server(ctx).Close(ctx, conn)
+}
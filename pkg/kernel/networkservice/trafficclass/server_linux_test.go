@@ -0,0 +1,174 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafficclass_test
+
+import (
+	"context"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/trafficclass"
+)
+
+const (
+	ifName   = "tc-1"
+	peerName = "tc-2"
+)
+
+func addVeth(t *testing.T) netlink.Link {
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: ifName},
+		PeerName:  peerName,
+	}))
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	return link
+}
+
+func request(labels map[string]string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.InterfaceNameKey: ifName,
+				},
+			},
+			Labels: labels,
+		},
+	}
+}
+
+func requestOrSkip(t *testing.T, server networkservice.NetworkServiceServer, request *networkservice.NetworkServiceRequest) *networkservice.Connection {
+	conn, err := server.Request(context.TODO(), request)
+	if errors.Cause(err) == syscall.ENOENT {
+		t.Skip("matchall filter or skbedit action is not supported by the kernel")
+	}
+	require.NoError(t, err)
+	return conn
+}
+
+func hasClsact(t *testing.T, link netlink.Link) bool {
+	qdiscs, err := netlink.QdiscList(link)
+	require.NoError(t, err)
+	for _, qdisc := range qdiscs {
+		if qdisc.Type() == "clsact" {
+			return true
+		}
+	}
+	return false
+}
+
+func egressFilters(t *testing.T, link netlink.Link) []netlink.Filter {
+	filters, err := netlink.FilterList(link, netlink.HANDLE_MIN_EGRESS)
+	require.NoError(t, err)
+	return filters
+}
+
+func TestTrafficClassServer(t *testing.T) {
+	link := addVeth(t)
+	defer func() { _ = netlink.LinkDel(link) }()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		trafficclass.NewServer(trafficclass.WithPriority(3)),
+	)
+
+	conn, err := server.Request(context.TODO(), request(map[string]string{trafficclass.QueueLabel: "1"}))
+	if errors.Cause(err) == syscall.ENOENT {
+		// the qdisc added for the failed filter is deleted
+		require.False(t, hasClsact(t, link))
+		t.Skip("matchall filter or skbedit action is not supported by the kernel")
+	}
+	require.NoError(t, err)
+	require.True(t, hasClsact(t, link))
+	require.Len(t, egressFilters(t, link), 1)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	require.False(t, hasClsact(t, link))
+}
+
+func TestTrafficClassServer_ExistingQdisc(t *testing.T) {
+	link := addVeth(t)
+	defer func() { _ = netlink.LinkDel(link) }()
+
+	require.NoError(t, netlink.QdiscAdd(&netlink.GenericQdisc{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(0xffff, 0),
+			Parent:    netlink.HANDLE_CLSACT,
+		},
+		QdiscType: "clsact",
+	}))
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		trafficclass.NewServer(trafficclass.WithPriority(3)),
+	)
+
+	conn := requestOrSkip(t, server, request(nil))
+	require.Len(t, egressFilters(t, link), 1)
+
+	_, err := server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	// the qdisc added by someone else is kept
+	require.True(t, hasClsact(t, link))
+	require.Empty(t, egressFilters(t, link))
+}
+
+func TestTrafficClassServer_LabelDropped(t *testing.T) {
+	link := addVeth(t)
+	defer func() { _ = netlink.LinkDel(link) }()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		trafficclass.NewServer(),
+	)
+
+	conn := requestOrSkip(t, server, request(map[string]string{trafficclass.PriorityLabel: "3"}))
+	require.True(t, hasClsact(t, link))
+
+	conn.Labels = nil
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.False(t, hasClsact(t, link))
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
+
+func TestTrafficClassServer_InvalidLabel(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		trafficclass.NewServer(),
+	)
+
+	_, err := server.Request(context.TODO(), request(map[string]string{trafficclass.QueueLabel: "65536"}))
+	require.Error(t, err)
+}
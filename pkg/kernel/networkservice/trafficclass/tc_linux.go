@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trafficclass
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const filterPriority = 1
+
+func setTrafficClass(link netlink.Link, priority *uint32, queue *uint16) (qdiscAdded bool, err error) {
+	qdisc := newQdisc(link)
+	if err = netlink.QdiscAdd(qdisc); err != nil && !os.IsExist(err) {
+		return false, errors.Wrapf(err, "failed to add clsact qdisc: %v", link.Attrs().Name)
+	}
+	qdiscAdded = err == nil
+
+	action := netlink.NewSkbEditAction()
+	action.Priority = priority
+	action.QueueMapping = queue
+
+	if err = netlink.FilterReplace(newFilter(link, action)); err != nil {
+		if qdiscAdded {
+			_ = netlink.QdiscDel(qdisc)
+		}
+		return false, errors.Wrapf(err, "failed to add traffic class filter: %v", link.Attrs().Name)
+	}
+	return qdiscAdded, nil
+}
+
+func delTrafficClass(link netlink.Link, delQdisc bool) error {
+	if err := netlink.FilterDel(newFilter(link)); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to delete traffic class filter: %v", link.Attrs().Name)
+	}
+	if delQdisc {
+		if err := netlink.QdiscDel(newQdisc(link)); err != nil && !os.IsNotExist(err) && err != unix.EINVAL {
+			return errors.Wrapf(err, "failed to delete clsact qdisc: %v", link.Attrs().Name)
+		}
+	}
+	return nil
+}
+
+func newQdisc(link netlink.Link) *netlink.GenericQdisc {
+	return &netlink.GenericQdisc{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(0xffff, 0),
+			Parent:    netlink.HANDLE_CLSACT,
+		},
+		QdiscType: "clsact",
+	}
+}
+
+func newFilter(link netlink.Link, actions ...netlink.Action) *netlink.MatchAll {
+	return &netlink.MatchAll{
+		FilterAttrs: netlink.FilterAttrs{
+			LinkIndex: link.Attrs().Index,
+			Parent:    netlink.HANDLE_MIN_EGRESS,
+			Handle:    netlink.MakeHandle(0, 1),
+			Priority:  filterPriority,
+			Protocol:  unix.ETH_P_ALL,
+		},
+		Actions: actions,
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunnellink
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/optime"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/vni"
+)
+
+// linker is the common part of the tunnel client and server
+type linker struct {
+	kind       *Kind
+	bridgeName string
+	allocator  *vni.Allocator
+}
+
+func newLinker(kind *Kind, options []Option) *linker {
+	l := &linker{
+		kind: kind,
+	}
+	for _, opt := range options {
+		opt(l)
+	}
+	return l
+}
+
+// allocate allocates the connection tunnel ID if the mechanism has no one and writes it into the mechanism
+// parameters, already set tunnel ID is reserved
+func (l *linker) allocate(conn *networkservice.Connection) error {
+	mech := conn.GetMechanism()
+	if l.allocator == nil || l.kind.IDKey == "" || mech.GetType() != l.kind.MechanismType {
+		return nil
+	}
+
+	value := mech.GetParameters()[l.kind.IDKey]
+	if value == "" || value == "0" {
+		id, err := l.allocator.Allocate(conn.GetId())
+		if err != nil {
+			return errors.Wrapf(err, "failed to allocate %s tunnel ID", l.kind.Name)
+		}
+		if mech.Parameters == nil {
+			mech.Parameters = make(map[string]string)
+		}
+		mech.Parameters[l.kind.IDKey] = strconv.FormatUint(uint64(id), 10)
+		return nil
+	}
+
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		// invalid tunnel ID is reported by the kind
+		return nil
+	}
+	if current, ok := l.allocator.Lookup(conn.GetId()); ok && current != uint32(id) {
+		if err := l.allocator.Release(conn.GetId()); err != nil {
+			return err
+		}
+	}
+	if err := l.allocator.Reserve(conn.GetId(), uint32(id)); err != nil {
+		return errors.Wrapf(err, "failed to reserve %s tunnel ID: %v", l.kind.Name, id)
+	}
+	return nil
+}
+
+// release releases the connection tunnel ID
+func (l *linker) release(conn *networkservice.Connection) error {
+	if l.allocator == nil || l.kind.IDKey == "" || conn.GetMechanism().GetType() != l.kind.MechanismType {
+		return nil
+	}
+	return l.allocator.Release(conn.GetId())
+}
+
+// create creates the connection tunnel net interface in the current net NS. Already existing net interface with the
+// same configuration is reused on refresh, otherwise it is recreated.
+func (l *linker) create(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	expected, err := l.kind.NewLink(conn, isClient)
+	if err != nil || expected == nil {
+		return err
+	}
+	name := expected.Attrs().Name
+
+	if link, err := netlink.LinkByName(name); err == nil {
+		if l.kind.SameLink(link, expected) {
+			return l.setUp(ctx, link)
+		}
+		if err := l.del(ctx, link); err != nil {
+			return err
+		}
+	}
+
+	if err := optime.Time(ctx, "LinkAdd", expected, func() error { return l.addLink(expected) }); err != nil {
+		return errors.Wrapf(err, "failed to create %s net interface: %v", l.kind.Name, name)
+	}
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get %s net interface: %v", l.kind.Name, name)
+	}
+	if err := l.setUp(ctx, link); err != nil {
+		_ = l.del(ctx, link)
+		return err
+	}
+	return nil
+}
+
+func (l *linker) addLink(link netlink.Link) error {
+	if l.kind.AddLink != nil {
+		return l.kind.AddLink(link)
+	}
+	return netlink.LinkAdd(link)
+}
+
+// setUp attaches the tunnel net interface to the bridge if configured and sets it up
+func (l *linker) setUp(ctx context.Context, link netlink.Link) error {
+	if l.bridgeName != "" && (l.kind.Bridged == nil || l.kind.Bridged(link)) {
+		bridge, err := netlink.LinkByName(l.bridgeName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get bridge: %v", l.bridgeName)
+		}
+		if link.Attrs().MasterIndex != bridge.Attrs().Index {
+			if err := netlink.LinkSetMasterByIndex(link, bridge.Attrs().Index); err != nil {
+				return errors.Wrapf(err, "failed to attach %s net interface to the bridge: %v %v", l.kind.Name, link.Attrs().Name, l.bridgeName)
+			}
+		}
+	}
+	if err := optime.Time(ctx, "LinkSetUp", link, func() error { return netlink.LinkSetUp(link) }); err != nil {
+		return errors.Wrapf(err, "failed to set up %s net interface: %v", l.kind.Name, link.Attrs().Name)
+	}
+	return nil
+}
+
+// remove deletes the connection tunnel net interface and releases the tunnel ID, already deleted net interface is
+// skipped
+func (l *linker) remove(ctx context.Context, conn *networkservice.Connection) error {
+	if link, err := netlink.LinkByName(l.kind.LinkName(conn)); err == nil {
+		if err := l.del(ctx, link); err != nil {
+			return err
+		}
+	}
+	return l.release(conn)
+}
+
+func (l *linker) del(ctx context.Context, link netlink.Link) error {
+	if err := optime.Time(ctx, "LinkDel", link, func() error { return netlink.LinkDel(link) }); err != nil {
+		return errors.Wrapf(err, "failed to delete %s net interface: %v", l.kind.Name, link.Attrs().Name)
+	}
+	return nil
+}
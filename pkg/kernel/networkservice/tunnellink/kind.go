@@ -0,0 +1,49 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tunnellink provides the chain elements lifecycle shared by the tunnel net interface kinds: vxlan, gre,
+// geneve, iptun. The tunnel net interface is created in the current net NS for the connections with the kind mechanism,
+// reused on refresh if it has the same configuration, and deleted on Close.
+package tunnellink
+
+import (
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+// Kind is the tunnel kind specific part of the chain elements
+type Kind struct {
+	// Name is the kind name used in the errors, e.g. "VXLAN"
+	Name string
+	// MechanismType is the kind mechanism type
+	MechanismType string
+	// IDKey is the kind mechanism parameter key of the tunnel ID (VNI, GRE key) managed with the allocator, see
+	// WithAllocator. Empty means the kind has no tunnel ID.
+	IDKey string
+	// LinkName returns the connection tunnel net interface name in the current net NS
+	LinkName func(conn *networkservice.Connection) string
+	// NewLink returns the connection tunnel net interface description, it returns nil if the connection has no
+	// mechanism of the kind. Server side is the Endpoint of the mechanism, so its local IP is the mechanism Dst IP.
+	NewLink func(conn *networkservice.Connection, isClient bool) (netlink.Link, error)
+	// SameLink returns true if the existing net interface has all the configured attributes of the expected one, so it
+	// is reused on refresh
+	SameLink func(existing, expected netlink.Link) bool
+	// AddLink creates the net interface in the current net NS, default is netlink.LinkAdd
+	AddLink func(link netlink.Link) error
+	// Bridged returns true if the net interface can be attached to the bridge, default is true for all net interfaces
+	Bridged func(link netlink.Link) bool
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunnellink
+
+import (
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/vni"
+)
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(l *linker)
+
+// WithBridgeName sets the bridge name in the current net NS to attach the tunnel net interfaces to. The bridge should
+// already exist.
+func WithBridgeName(bridgeName string) Option {
+	return func(l *linker) {
+		l.bridgeName = bridgeName
+	}
+}
+
+// WithAllocator sets the tunnel IDs allocator used by the server: the tunnel ID is allocated for the connections
+// with no tunnel ID in the mechanism parameters and written into them, already set tunnel ID is reserved to detect
+// the conflicts. The tunnel ID is released on Close. Client uses the tunnel ID selected by the server.
+func WithAllocator(allocator *vni.Allocator) Option {
+	return func(l *linker) {
+		l.allocator = allocator
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunnellink
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type tunnelServer struct {
+	*linker
+}
+
+// NewServer returns a new server chain element creating the kind tunnel net interface in the current net NS for the
+// connections with the kind mechanism. The net interface is deleted on Close.
+func NewServer(kind *Kind, options ...Option) networkservice.NetworkServiceServer {
+	return &tunnelServer{
+		linker: newLinker(kind, options),
+	}
+}
+
+func (s *tunnelServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	isClient := metadata.IsClient(s)
+
+	if err := s.allocate(request.GetConnection()); err != nil {
+		return nil, err
+	}
+	if err := s.create(ctx, request.GetConnection(), isClient); err != nil {
+		if !load(ctx, isClient, s.kind.Name) {
+			_ = s.release(request.GetConnection())
+		}
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		// the tunnel net interface is still used by the connection on failed refresh
+		if !load(ctx, isClient, s.kind.Name) {
+			if removeErr := s.remove(ctx, request.GetConnection()); removeErr != nil {
+				log.Entry(ctx).WithField("tunnelServer", "Request").Warnf("failed to delete %s net interface: %s", s.kind.Name, removeErr.Error())
+			}
+		}
+		return nil, err
+	}
+
+	store(ctx, isClient, s.kind.Name)
+
+	return conn, nil
+}
+
+func (s *tunnelServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var removeErr error
+	if loadAndDelete(ctx, metadata.IsClient(s), s.kind.Name) {
+		removeErr = s.remove(ctx, conn)
+	}
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
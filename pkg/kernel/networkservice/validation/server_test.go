@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validation_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/validation"
+)
+
+func kernelConn(ifName, netNSURL string) *networkservice.Connection {
+	return &networkservice.Connection{
+		Id: "conn-1",
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.InterfaceNameKey: ifName,
+				kernel.NetNSURL:         netNSURL,
+			},
+		},
+	}
+}
+
+func TestValidationServer(t *testing.T) {
+	server := validation.NewServer()
+
+	invalidIPContext := kernelConn("nsm-1", "file:///proc/1/ns/net")
+	invalidIPContext.Context = &networkservice.ConnectionContext{
+		IpContext: &networkservice.IPContext{SrcIpAddr: "10.0.0.1"},
+	}
+
+	for _, conn := range []*networkservice.Connection{
+		nil,
+		{},
+		kernelConn("nsm/1", "file:///proc/1/ns/net"),
+		kernelConn("nsm-1", ""),
+		kernelConn("nsm-1", "inode://4/4026531992"),
+		invalidIPContext,
+	} {
+		_, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+		require.Equal(t, codes.InvalidArgument, status.Code(err), conn.String())
+
+		_, err = server.Close(context.TODO(), conn)
+		require.Equal(t, codes.InvalidArgument, status.Code(err), conn.String())
+	}
+
+	_, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: kernelConn("nsm-1", "file:///proc/1/ns/net"),
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package validation provides chain elements validating the connection before it reaches the kernel chain elements
+package validation
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ipaddrs"
+)
+
+// validate returns codes.InvalidArgument status error if the connection is malformed
+func validate(conn *networkservice.Connection) error {
+	if conn == nil {
+		return status.Error(codes.InvalidArgument, "connection is nil")
+	}
+	if conn.GetId() == "" {
+		return status.Error(codes.InvalidArgument, "connection ID is empty")
+	}
+	if mech := kernel.ToMechanism(conn.GetMechanism()); mech != nil {
+		if err := validateMechanism(conn, mech); err != nil {
+			return err
+		}
+	}
+	return validateIPContext(conn.GetContext().GetIpContext())
+}
+
+func validateMechanism(conn *networkservice.Connection, mech *kernel.Mechanism) error {
+	ifName := mech.GetInterfaceName(conn)
+	if ifName == "" || ifName == "." || ifName == ".." || strings.ContainsAny(ifName, "/: \t\n") {
+		return status.Errorf(codes.InvalidArgument, "invalid kernel interface name: %q", ifName)
+	}
+	if netNSURL, err := url.Parse(mech.GetNetNSURL()); err != nil || netNSURL.Scheme != "file" || netNSURL.Path == "" {
+		return status.Errorf(codes.InvalidArgument, "invalid kernel net NS URL: %q", mech.GetNetNSURL())
+	}
+	return nil
+}
+
+func validateIPContext(ipContext *networkservice.IPContext) error {
+	for _, ipAddr := range []string{ipContext.GetSrcIpAddr(), ipContext.GetDstIpAddr()} {
+		if _, err := ipaddrs.Parse(ipAddr); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid IP address: %q", ipAddr)
+		}
+	}
+	for _, routes := range [][]*networkservice.Route{ipContext.GetSrcRoutes(), ipContext.GetDstRoutes()} {
+		for _, route := range routes {
+			if _, _, err := net.ParseCIDR(route.GetPrefix()); err != nil {
+				return status.Errorf(codes.InvalidArgument, "invalid route CIDR: %q", route.GetPrefix())
+			}
+		}
+	}
+	for _, ipNeighbor := range ipContext.GetIpNeighbors() {
+		if net.ParseIP(ipNeighbor.GetIp()) == nil {
+			return status.Errorf(codes.InvalidArgument, "invalid IP neighbor IP address: %q", ipNeighbor.GetIp())
+		}
+		if _, err := net.ParseMAC(ipNeighbor.GetHardwareAddress()); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid IP neighbor MAC address: %q", ipNeighbor.GetHardwareAddress())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type verifyClient struct {
+	*verifier
+}
+
+// NewClient returns a new client chain element probing the Src IP address through the Endpoint's net interface after
+// the Request. The result is stored in the metadata (see Load) and in the path segment metrics, Request fails on
+// probe failure only with WithGate option.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &verifyClient{
+		verifier: newVerifier(options),
+	}
+}
+
+func (c *verifyClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.verify(ctx, conn, metadata.IsClient(c)); err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn.Clone(), opts...)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (c *verifyClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return next.Client(ctx).Close(ctx, conn, opts...)
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ipaddrs"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/probe"
+)
+
+const (
+	// VerifiedMetric is a path segment metric key with the datapath verification result: "true" or "false"
+	VerifiedMetric = "datapathVerified"
+	// RTTMetric is a path segment metric key with the datapath verification round trip time
+	RTTMetric = "datapathRTT"
+
+	defaultTimeout = time.Second
+)
+
+// verifier is the common part of the verify client and server
+type verifier struct {
+	timeout time.Duration
+	udpPort int
+	gate    bool
+}
+
+func newVerifier(options []Option) *verifier {
+	v := &verifier{
+		timeout: defaultTimeout,
+	}
+	for _, opt := range options {
+		opt(v)
+	}
+	return v
+}
+
+// verify probes the peer IP address through the connection net interface and records the result, it returns an error
+// only if the probe fails and the verifier is gating
+func (v *verifier) verify(ctx context.Context, conn *networkservice.Connection, isClient bool) error {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil
+	}
+
+	peerAddr := conn.GetContext().GetIpContext().GetDstIpAddr()
+	if isClient {
+		peerAddr = conn.GetContext().GetIpContext().GetSrcIpAddr()
+	}
+	if peerAddr == "" {
+		return nil
+	}
+
+	result := &Result{}
+	if peer, err := ipaddrs.First(peerAddr, nil); err != nil {
+		result.Err = errors.Wrapf(err, "invalid peer IP address: %v", peerAddr)
+	} else if peer != nil {
+		result.RTT, result.Err = v.probe(ctx, mech.GetNetNSURL(), mech.GetInterfaceName(conn), peer.IP)
+	}
+
+	store(ctx, isClient, result)
+	setMetrics(conn, result)
+
+	if result.Err != nil {
+		log.Entry(ctx).WithField("verifier", "verify").Warnf("datapath verification failed for connection %s: %s",
+			conn.GetId(), result.Err.Error())
+		if v.gate {
+			return result.Err
+		}
+	}
+
+	return nil
+}
+
+func (v *verifier) probe(ctx context.Context, netNSURL, ifName string, peer net.IP) (rtt time.Duration, err error) {
+	curNetNS, err := nshandle.Current()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = curNetNS.Close() }()
+
+	var targetNetNS netns.NsHandle
+	if targetNetNS, err = nshandle.FromURL(netNSURL); err != nil {
+		return 0, err
+	}
+	defer func() { _ = targetNetNS.Close() }()
+
+	err = nshandle.RunIn(curNetNS, targetNetNS, func() error {
+		var probeErr error
+		if rtt, probeErr = probe.ICMPEcho(ctx, ifName, peer, v.timeout); probeErr != nil || v.udpPort == 0 {
+			return probeErr
+		}
+		rtt, probeErr = probe.UDP(ctx, ifName, &net.UDPAddr{IP: peer, Port: v.udpPort}, v.timeout)
+		return probeErr
+	})
+	return rtt, err
+}
+
+func setMetrics(conn *networkservice.Connection, result *Result) {
+	path := conn.GetPath()
+	if path == nil || int(path.GetIndex()) >= len(path.GetPathSegments()) {
+		return
+	}
+
+	segment := path.GetPathSegments()[path.GetIndex()]
+	if segment.Metrics == nil {
+		segment.Metrics = make(map[string]string)
+	}
+	segment.Metrics[VerifiedMetric] = strconv.FormatBool(result.Err == nil)
+	if result.Err == nil {
+		segment.Metrics[RTTMetric] = result.RTT.String()
+	} else {
+		delete(segment.Metrics, RTTMetric)
+	}
+}
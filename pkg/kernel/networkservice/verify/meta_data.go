@@ -0,0 +1,46 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"context"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type keyType struct{}
+
+// Result is the last datapath verification result for the connection
+type Result struct {
+	// RTT is the probe round trip time
+	RTT time.Duration
+	// Err is the probe error, nil means the datapath is verified
+	Err error
+}
+
+func store(ctx context.Context, isClient bool, result *Result) {
+	metadata.Map(ctx, isClient).Store(keyType{}, result)
+}
+
+// Load returns the last datapath verification result for the connection
+func Load(ctx context.Context, isClient bool) (*Result, bool) {
+	if raw, ok := metadata.Map(ctx, isClient).Load(keyType{}); ok {
+		return raw.(*Result), true
+	}
+	return nil, false
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify
+
+import (
+	"time"
+)
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(v *verifier)
+
+// WithTimeout sets the probe timeout, default is 1s
+func WithTimeout(timeout time.Duration) Option {
+	return func(v *verifier) {
+		v.timeout = timeout
+	}
+}
+
+// WithUDPProbe enables additional UDP probe to the given peer port for checking the datapath through the tunnels
+// dropping ICMP, the probe succeeds on any UDP reply or ICMP port unreachable
+func WithUDPProbe(port int) Option {
+	return func(v *verifier) {
+		v.udpPort = port
+	}
+}
+
+// WithGate makes the Request fail if the probe fails
+func WithGate() Option {
+	return func(v *verifier) {
+		v.gate = true
+	}
+}
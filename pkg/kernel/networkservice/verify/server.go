@@ -0,0 +1,60 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package verify provides chain element actively checking the datapath to the peer over the connection net interface
+// after the Request
+package verify
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type verifyServer struct {
+	*verifier
+}
+
+// NewServer returns a new server chain element probing the Dst IP address through the Client's net interface after
+// the Request. The result is stored in the metadata (see Load) and in the path segment metrics, Request fails on
+// probe failure only with WithGate option.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &verifyServer{
+		verifier: newVerifier(options),
+	}
+}
+
+func (s *verifyServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verify(ctx, conn, metadata.IsClient(s)); err != nil {
+		_, _ = next.Server(ctx).Close(ctx, conn.Clone())
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (s *verifyServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	return next.Server(ctx).Close(ctx, conn)
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verify_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/verify"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName   = "verify-1"
+	peerName = "verify-2"
+)
+
+func newRequest(dstIPAddr string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "id",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.NetNSURL:         "file:///proc/self/ns/net",
+					kernel.InterfaceNameKey: ifName,
+				},
+			},
+			Context: &networkservice.ConnectionContext{
+				IpContext: &networkservice.IPContext{
+					SrcIpAddr: "10.0.8.1/30",
+					DstIpAddr: dstIPAddr,
+				},
+			},
+			Path: &networkservice.Path{
+				PathSegments: []*networkservice.PathSegment{{Name: "forwarder"}},
+			},
+		},
+	}
+}
+
+func TestVerifyServer(t *testing.T) {
+	kerneltest.AddVethPeer(t, ifName, "10.0.8.1/30", peerName, "10.0.8.2/30")
+
+	conn, err := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		verify.NewServer(verify.WithUDPProbe(9), verify.WithGate()),
+	).Request(context.TODO(), newRequest("10.0.8.2/30"))
+	require.NoError(t, err)
+	require.Equal(t, "true", conn.GetPath().GetPathSegments()[0].GetMetrics()[verify.VerifiedMetric])
+	require.NotEmpty(t, conn.GetPath().GetPathSegments()[0].GetMetrics()[verify.RTTMetric])
+
+	conn, err = chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		verify.NewServer(verify.WithTimeout(100*time.Millisecond)),
+	).Request(context.TODO(), newRequest("10.0.8.3/30"))
+	require.NoError(t, err)
+	require.Equal(t, "false", conn.GetPath().GetPathSegments()[0].GetMetrics()[verify.VerifiedMetric])
+
+	_, err = chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		verify.NewServer(verify.WithTimeout(100*time.Millisecond), verify.WithGate()),
+	).Request(context.TODO(), newRequest("10.0.8.3/30"))
+	require.Error(t, err)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package veth provides chain elements creating the veth pair for the local kernel connection: one end is injected
+// into the Client's net NS, the peer end stays in the Forwarder's net NS and is set up. The veth pair is named
+// deterministically from the connection ID and deleted on Close.
+package veth
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/inject"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/optime"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/strict"
+)
+
+// PeerName returns name of the Forwarder's end of the veth pair created for the connection
+func PeerName(conn *networkservice.Connection) string {
+	return linkprovider.VethPeerName(conn)
+}
+
+func injectOptions(options []Option) []inject.Option {
+	o := &vethOptions{}
+	for _, opt := range options {
+		opt(o)
+	}
+	return append(append([]inject.Option{}, o.injectOptions...), inject.WithLinkProvider(linkprovider.NewVeth()))
+}
+
+// setPeerUp sets up the Forwarder's end of the veth pair, there is no peer if the existing net interface is adopted by
+// the inject chain element
+func setPeerUp(ctx context.Context, conn *networkservice.Connection) error {
+	if kernel.ToMechanism(conn.GetMechanism()) == nil {
+		return nil
+	}
+
+	peer, err := netlink.LinkByName(PeerName(conn))
+	if err != nil {
+		return nil
+	}
+	if err := optime.Time(ctx, "LinkSetUp", peer, func() error { return netlink.LinkSetUp(peer) }); err != nil {
+		return errors.Wrapf(err, "failed to set up net interface: %v", peer.Attrs().Name)
+	}
+	return strict.Verify(ctx, "LinkSetUp", peer.Attrs().Name, func() (bool, error) {
+		link, err := netlink.LinkByIndex(peer.Attrs().Index)
+		if err != nil {
+			return false, err
+		}
+		return link.Attrs().Flags&net.FlagUp != 0, nil
+	})
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package veth
+
+import (
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/inject"
+)
+
+type vethOptions struct {
+	injectOptions []inject.Option
+}
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(o *vethOptions)
+
+// WithInjectOptions sets options for the underlying inject chain element, e.g. inject.WithPreemptionPolicy. The link
+// provider is always the veth one.
+func WithInjectOptions(options ...inject.Option) Option {
+	return func(o *vethOptions) {
+		o.injectOptions = options
+	}
+}
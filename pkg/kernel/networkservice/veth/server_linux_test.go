@@ -0,0 +1,137 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package veth_test
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"path"
+	"runtime"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/veth"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+const (
+	netNSPath = "/run/netns"
+	ifName    = "nsm-1"
+)
+
+func newClientNetNS(t *testing.T, curNetNS netns.NsHandle) (clientNetNS netns.NsHandle, conn *networkservice.Connection, cleanup func()) {
+	netNSName := uuid.New().String()
+	clientNetNS, err := netns.NewNamed(netNSName)
+	require.NoError(t, err)
+	require.NoError(t, netns.Set(curNetNS))
+
+	conn = &networkservice.Connection{
+		Id: uuid.New().String(),
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.NetNSURL:         (&url.URL{Scheme: "file", Path: path.Join(netNSPath, netNSName)}).String(),
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+	}
+
+	return clientNetNS, conn, func() {
+		_ = clientNetNS.Close()
+		_ = netns.DeleteNamed(netNSName)
+	}
+}
+
+func requirePair(t *testing.T, curNetNS, clientNetNS netns.NsHandle, conn *networkservice.Connection) {
+	peer, err := netlink.LinkByName(veth.PeerName(conn))
+	require.NoError(t, err)
+	require.NotZero(t, peer.Attrs().Flags&net.FlagUp)
+
+	require.NoError(t, nshandle.RunIn(curNetNS, clientNetNS, func() error {
+		_, linkErr := netlink.LinkByName(ifName)
+		return linkErr
+	}))
+}
+
+func TestVethServer(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		veth.NewServer(),
+	)
+
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	requirePair(t, curNetNS, clientNetNS, conn)
+
+	// refresh
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	requirePair(t, curNetNS, clientNetNS, conn)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	_, err = netlink.LinkByName(veth.PeerName(conn))
+	require.Error(t, err)
+}
+
+func TestVethClient(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	clientNetNS, conn, cleanup := newClientNetNS(t, curNetNS)
+	defer cleanup()
+
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		veth.NewClient(),
+	)
+
+	conn, err = client.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	requirePair(t, curNetNS, clientNetNS, conn)
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	_, err = netlink.LinkByName(veth.PeerName(conn))
+	require.Error(t, err)
+}
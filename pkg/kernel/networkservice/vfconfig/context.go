@@ -33,6 +33,8 @@ type VFConfig struct {
 	VFInterfaceName string
 	// VFNum is a VF num for the parent PF
 	VFNum int
+	// VFRepresentorName is a VF representor net interface name, it is set only for the switchdev mode PF
+	VFRepresentorName string
 }
 
 // WithConfig returns new context with VFConfig
@@ -0,0 +1,56 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vfconfig
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/metamap"
+)
+
+type metaDataKey struct{}
+
+// Store stores VFConfig selected by the Server side into the per connection metadata, so it is available on refresh
+// and Close. Without metadata chain element in the chain nothing is stored.
+func Store(ctx context.Context, config *VFConfig) {
+	if m, ok := metamap.Load(ctx, false); ok {
+		m.Store(metaDataKey{}, config)
+	}
+}
+
+// Load returns VFConfig from context or the one stored for the given side of the connection. Without metadata chain
+// element in the chain only the VFConfig from context is returned, so the non-VF connections pass through.
+func Load(ctx context.Context, isClient bool) (*VFConfig, bool) {
+	if config := Config(ctx); config != nil {
+		return config, true
+	}
+	m, ok := metamap.Load(ctx, isClient)
+	if !ok {
+		return nil, false
+	}
+	if raw, ok := m.Load(metaDataKey{}); ok {
+		return raw.(*VFConfig), true
+	}
+	return nil, false
+}
+
+// Delete deletes VFConfig stored by the Server side
+func Delete(ctx context.Context) {
+	if m, ok := metamap.Load(ctx, false); ok {
+		m.Delete(metaDataKey{})
+	}
+}
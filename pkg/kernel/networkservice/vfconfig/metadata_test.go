@@ -0,0 +1,70 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vfconfig_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ethernetcontext"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/rename"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/representor"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+)
+
+func TestLoad_NoMetadata(t *testing.T) {
+	_, ok := vfconfig.Load(context.TODO(), false)
+	require.False(t, ok)
+
+	config := &vfconfig.VFConfig{PFInterfaceName: "pf-1", VFInterfaceName: "vf-1"}
+	vfconfig.Store(context.TODO(), config)
+	vfconfig.Delete(context.TODO())
+
+	loaded, ok := vfconfig.Load(vfconfig.WithConfig(context.TODO(), config), false)
+	require.True(t, ok)
+	require.Equal(t, config, loaded)
+}
+
+func TestVFChain_NoMetadata(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		rename.NewServer(),
+		ethernetcontext.NewVFServer(),
+		representor.NewServer(),
+	)
+
+	conn, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "conn-1",
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.InterfaceNameKey: "nsm-1",
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vxlan
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	vxlanmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/vxlan"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/tunnellink"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/tunnel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/vni"
+)
+
+const (
+	// DstPortKey is a VXLAN mechanism parameter key with the UDP destination port of the remote side. The VXLAN
+	// mechanism has no port parameter in the used API version, so it is passed as a custom parameter.
+	DstPortKey = "dstPort"
+	// DefaultDstPort is the IANA assigned VXLAN UDP port
+	DefaultDstPort = 4789
+
+	linkPrefix = "nsmvx"
+)
+
+// LinkName returns the connection VXLAN net interface name in the Forwarder's net NS
+func LinkName(conn *networkservice.Connection) string {
+	return linkprovider.LinkName(linkPrefix, conn)
+}
+
+// linker is the common part of the vxlan client and server
+type linker struct {
+	allocator     *vni.Allocator
+	dstPort       int
+	bridgeName    string
+	tunnelOptions []tunnel.Option
+}
+
+func newLinker(options []Option) *linker {
+	l := &linker{
+		dstPort: DefaultDstPort,
+	}
+	for _, opt := range options {
+		opt(l)
+	}
+	return l
+}
+
+// newVxlan returns the VXLAN net interface description for the connection, it returns nil if the connection has no
+// VXLAN mechanism. Server side is the Endpoint of the VXLAN mechanism, so its local IP is the mechanism Dst IP.
+func (l *linker) newVxlan(conn *networkservice.Connection, isClient bool) (*netlink.Vxlan, error) {
+	mech := vxlanmech.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil, nil
+	}
+
+	local, remote := mech.DstIP(), mech.SrcIP()
+	if isClient {
+		local, remote = remote, local
+	}
+	if local == nil || remote == nil {
+		return nil, errors.Errorf("VXLAN mechanism has no local or remote IP: %v", mech.GetParameters())
+	}
+
+	vni := mech.VNI()
+	if vni == 0 {
+		return nil, errors.New("VXLAN mechanism has no VNI")
+	}
+
+	dstPort, err := l.port(mech)
+	if err != nil {
+		return nil, err
+	}
+
+	vxlan := &netlink.Vxlan{
+		LinkAttrs: netlink.LinkAttrs{Name: LinkName(conn)},
+		VxlanId:   int(vni),
+		SrcAddr:   local,
+		Group:     remote,
+		Port:      dstPort,
+	}
+	if err := tunnel.Apply(vxlan, l.tunnelOptions...); err != nil {
+		return nil, err
+	}
+	return vxlan, nil
+}
+
+func (l *linker) port(mech *vxlanmech.Mechanism) (int, error) {
+	value, ok := mech.GetParameters()[DstPortKey]
+	if !ok || value == "" {
+		return l.dstPort, nil
+	}
+	dstPort, err := strconv.ParseUint(value, 10, 16)
+	if err != nil || dstPort == 0 {
+		return 0, errors.Errorf("invalid VXLAN destination port: %v", value)
+	}
+	return int(dstPort), nil
+}
+
+// kind returns the VXLAN part of the tunnel chain elements
+func (l *linker) kind() *tunnellink.Kind {
+	return &tunnellink.Kind{
+		Name:          "VXLAN",
+		MechanismType: vxlanmech.MECHANISM,
+		IDKey:         vxlanmech.VNI,
+		LinkName:      LinkName,
+		NewLink: func(conn *networkservice.Connection, isClient bool) (netlink.Link, error) {
+			vxlan, err := l.newVxlan(conn, isClient)
+			if err != nil || vxlan == nil {
+				return nil, err
+			}
+			return vxlan, nil
+		},
+		SameLink: func(existing, expected netlink.Link) bool {
+			existingVxlan, ok := existing.(*netlink.Vxlan)
+			return ok && sameVxlan(existingVxlan, expected.(*netlink.Vxlan))
+		},
+	}
+}
+
+func sameVxlan(existing, expected *netlink.Vxlan) bool {
+	return existing.VxlanId == expected.VxlanId &&
+		existing.Port == expected.Port &&
+		sameIP(existing.SrcAddr, expected.SrcAddr) &&
+		sameIP(existing.Group, expected.Group)
+}
+
+func sameIP(ip1, ip2 net.IP) bool {
+	return ip1 != nil && ip1.Equal(ip2)
+}
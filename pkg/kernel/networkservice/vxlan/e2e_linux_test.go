@@ -0,0 +1,155 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vxlan_test
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	vxlanmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/vxlan"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/inject"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/ipcontext"
+	kernelnetns "github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/netns"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vxlan"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest/fakenode"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/probe"
+)
+
+const (
+	podIfName     = "nsm-1"
+	podBridgeName = "nsm-br"
+	e2eVNI        = 100
+)
+
+// newPodServer returns the Forwarder chain injecting the pod net interface with the veth peer attached to the bridge
+func newPodServer(node *fakenode.Node) networkservice.NetworkServiceServer {
+	return node.NewServer(chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithLinkProvider(linkprovider.NewBridged(podBridgeName))),
+		kernelnetns.NewServer(),
+		ipcontext.NewServer(),
+	))
+}
+
+func podRequest(id string, pod *fakenode.Pod, srcIPAddr string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: id,
+			Mechanism: &networkservice.Mechanism{
+				Type: kernel.MECHANISM,
+				Parameters: map[string]string{
+					kernel.NetNSURL:         pod.NetNSURL(),
+					kernel.InterfaceNameKey: podIfName,
+				},
+			},
+			Context: &networkservice.ConnectionContext{
+				IpContext: &networkservice.IPContext{
+					SrcIpAddr: srcIPAddr,
+				},
+			},
+		},
+	}
+}
+
+func tunnelRequest(src, dst *fakenode.Node) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "vxlan-e2e",
+			Mechanism: &networkservice.Mechanism{
+				Type: vxlanmech.MECHANISM,
+				Parameters: map[string]string{
+					vxlanmech.SrcIP: src.UnderlayIP.String(),
+					vxlanmech.DstIP: dst.UnderlayIP.String(),
+					vxlanmech.VNI:   strconv.Itoa(e2eVNI),
+				},
+			},
+		},
+	}
+}
+
+func TestVxlan_E2E(t *testing.T) {
+	node1, err := fakenode.NewNode("node1")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, node1.Close()) }()
+
+	node2, err := fakenode.NewNode("node2")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, node2.Close()) }()
+
+	require.NoError(t, fakenode.Connect(node1, node2, "172.30.0.1/24", "172.30.0.2/24"))
+
+	client, err := node1.NewPod("client")
+	require.NoError(t, err)
+	endpoint, err := node2.NewPod("endpoint")
+	require.NoError(t, err)
+
+	podServer1, podServer2 := newPodServer(node1), newPodServer(node2)
+	clientConn, err := podServer1.Request(context.TODO(), podRequest("client-conn", client, "10.60.0.1/24"))
+	require.NoError(t, err)
+	endpointConn, err := podServer2.Request(context.TODO(), podRequest("endpoint-conn", endpoint, "10.60.0.2/24"))
+	require.NoError(t, err)
+
+	tunnelClient := node1.NewClient(chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		vxlan.NewClient(vxlan.WithBridgeName(podBridgeName)),
+	))
+	tunnelServer := node2.NewServer(chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		vxlan.NewServer(vxlan.WithBridgeName(podBridgeName)),
+	))
+	tunnelConn, err := tunnelServer.Request(context.TODO(), tunnelRequest(node1, node2))
+	require.NoError(t, err)
+	_, err = tunnelClient.Request(context.TODO(), tunnelRequest(node1, node2))
+	require.NoError(t, err)
+
+	require.NoError(t, client.Run(func() error {
+		_, probeErr := probe.ICMPEcho(context.TODO(), podIfName, net.ParseIP("10.60.0.2"), 3*time.Second)
+		return probeErr
+	}))
+
+	_, err = tunnelClient.Close(context.TODO(), tunnelConn)
+	require.NoError(t, err)
+	_, err = tunnelServer.Close(context.TODO(), tunnelConn)
+	require.NoError(t, err)
+
+	require.Error(t, client.Run(func() error {
+		_, probeErr := probe.ICMPEcho(context.TODO(), podIfName, net.ParseIP("10.60.0.2"), time.Second)
+		return probeErr
+	}))
+
+	_, err = podServer1.Close(context.TODO(), clientConn)
+	require.NoError(t, err)
+	_, err = podServer2.Close(context.TODO(), endpointConn)
+	require.NoError(t, err)
+
+	require.Error(t, client.Run(func() error {
+		_, linkErr := netlink.LinkByName(podIfName)
+		return linkErr
+	}))
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vxlan
+
+import (
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/tunnel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/vni"
+)
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(l *linker)
+
+// WithDstPort sets the default UDP destination port of the remote side, it can be overridden per connection with
+// the DstPortKey mechanism parameter. Default is DefaultDstPort.
+func WithDstPort(dstPort int) Option {
+	return func(l *linker) {
+		l.dstPort = dstPort
+	}
+}
+
+// WithBridgeName sets the bridge name in the current net NS to attach the VXLAN net interfaces to. The bridge should
+// already exist.
+func WithBridgeName(bridgeName string) Option {
+	return func(l *linker) {
+		l.bridgeName = bridgeName
+	}
+}
+
+// WithTunnelOptions sets the QoS options applied to the VXLAN net interfaces on creation
+func WithTunnelOptions(options ...tunnel.Option) Option {
+	return func(l *linker) {
+		l.tunnelOptions = options
+	}
+}
+
+// WithVNIAllocator sets the VNIs allocator used by the server: the VNI is allocated for the connections with
+// no VNI in the mechanism parameters, already set one is reserved to detect the conflicts. It should allocate in
+// [1, vni.MaxVNI] range. Allocator with the node lock should be used if there are multiple Forwarder instances on the
+// node.
+func WithVNIAllocator(allocator *vni.Allocator) Option {
+	return func(l *linker) {
+		l.allocator = allocator
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vxlan_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	vxlanmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/vxlan"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vxlan"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/vni"
+)
+
+const bridgeName = "vxlan-br"
+
+var (
+	srcIP = net.ParseIP("172.16.1.1").To4()
+	dstIP = net.ParseIP("172.16.1.2").To4()
+)
+
+func request(vni string) *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "vxlan-conn",
+			Mechanism: &networkservice.Mechanism{
+				Type: vxlanmech.MECHANISM,
+				Parameters: map[string]string{
+					vxlanmech.SrcIP: srcIP.String(),
+					vxlanmech.DstIP: dstIP.String(),
+					vxlanmech.VNI:   vni,
+				},
+			},
+		},
+	}
+}
+
+func linkVxlan(t *testing.T, conn *networkservice.Connection) *netlink.Vxlan {
+	link, err := netlink.LinkByName(vxlan.LinkName(conn))
+	require.NoError(t, err)
+	require.IsType(t, new(netlink.Vxlan), link)
+	return link.(*netlink.Vxlan)
+}
+
+func TestVxlanServer(t *testing.T) {
+	bridge := &netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: bridgeName}}
+	require.NoError(t, netlink.LinkAdd(bridge))
+	defer func() { _ = netlink.LinkDel(bridge) }()
+	bridgeLink, err := netlink.LinkByName(bridgeName)
+	require.NoError(t, err)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		vxlan.NewServer(vxlan.WithBridgeName(bridgeName)),
+	)
+
+	conn, err := server.Request(context.TODO(), request("10"))
+	require.NoError(t, err)
+
+	link := linkVxlan(t, conn)
+	require.Equal(t, 10, link.VxlanId)
+	require.Equal(t, vxlan.DefaultDstPort, link.Port)
+	require.True(t, link.SrcAddr.Equal(dstIP))
+	require.True(t, link.Group.Equal(srcIP))
+	require.Equal(t, bridgeLink.Attrs().Index, link.MasterIndex)
+	index := link.Index
+
+	// refresh with the same parameters keeps the net interface
+	conn, err = server.Request(context.TODO(), request("10"))
+	require.NoError(t, err)
+	require.Equal(t, index, linkVxlan(t, conn).Index)
+
+	// refresh with the changed VNI recreates the net interface
+	conn, err = server.Request(context.TODO(), request("11"))
+	require.NoError(t, err)
+	require.Equal(t, 11, linkVxlan(t, conn).VxlanId)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	_, err = netlink.LinkByName(vxlan.LinkName(conn))
+	require.Error(t, err)
+}
+
+func TestVxlanClient(t *testing.T) {
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		vxlan.NewClient(vxlan.WithDstPort(8472)),
+	)
+
+	req := request("20")
+	req.GetConnection().GetMechanism().GetParameters()[vxlan.DstPortKey] = "4790"
+
+	conn, err := client.Request(context.TODO(), req)
+	require.NoError(t, err)
+
+	link := linkVxlan(t, conn)
+	require.Equal(t, 20, link.VxlanId)
+	require.Equal(t, 4790, link.Port)
+	require.True(t, link.SrcAddr.Equal(srcIP))
+	require.True(t, link.Group.Equal(dstIP))
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	_, err = netlink.LinkByName(vxlan.LinkName(conn))
+	require.Error(t, err)
+}
+
+func TestVxlanServer_VNIAllocator(t *testing.T) {
+	allocator, err := vni.NewAllocator(vni.WithRange(100, 101))
+	require.NoError(t, err)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		vxlan.NewServer(vxlan.WithVNIAllocator(allocator)),
+	)
+
+	// VNI is allocated for the connection with no VNI
+	conn, err := server.Request(context.TODO(), request(""))
+	require.NoError(t, err)
+	require.Equal(t, "100", conn.GetMechanism().GetParameters()[vxlanmech.VNI])
+	require.Equal(t, 100, linkVxlan(t, conn).VxlanId)
+
+	// refresh keeps the allocated VNI
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	require.Equal(t, 100, linkVxlan(t, conn).VxlanId)
+
+	// VNI already allocated for another connection is a conflict
+	req := request("100")
+	req.GetConnection().Id = "another-vxlan-conn"
+	_, err = server.Request(context.TODO(), req)
+	require.True(t, vni.IsConflict(err))
+	_, err = netlink.LinkByName(vxlan.LinkName(req.GetConnection()))
+	require.Error(t, err)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	_, ok := allocator.Lookup(conn.GetId())
+	require.False(t, ok)
+}
+
+func TestVxlanServer_InvalidMechanism(t *testing.T) {
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		vxlan.NewServer(),
+	)
+
+	_, err := server.Request(context.TODO(), request("0"))
+	require.Error(t, err)
+
+	req := request("30")
+	req.GetConnection().GetMechanism().GetParameters()[vxlan.DstPortKey] = "port"
+	_, err = server.Request(context.TODO(), req)
+	require.Error(t, err)
+
+	_, err = netlink.LinkByName(vxlan.LinkName(req.GetConnection()))
+	require.Error(t, err)
+}
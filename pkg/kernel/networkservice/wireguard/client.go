@@ -0,0 +1,122 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	wgmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/wireguard"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type wireguardClient struct {
+	*configurator
+}
+
+// NewClient returns a new client chain element creating the WireGuard net interface (see LinkName) in the current net
+// NS before the Request if the WireGuard mechanism is requested: its public key and listen port are set to the
+// mechanism Src parameters. After the Request the Endpoint side is configured as the only peer from the selected
+// mechanism Dst parameters. The net interface is deleted on Close or if another mechanism is selected.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &wireguardClient{
+		configurator: newConfigurator(options),
+	}
+}
+
+func (c *wireguardClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	mechs := wireguardMechanisms(request)
+	for _, mech := range mechs {
+		if err := create(ctx, request.GetConnection(), mech, wgmech.SrcPublicKey, wgmech.SrcPort); err != nil {
+			c.removeOnFailure(ctx, request.GetConnection())
+			return nil, err
+		}
+	}
+
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil {
+		c.removeOnFailure(ctx, request.GetConnection())
+		return nil, err
+	}
+
+	mech := wgmech.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		if len(mechs) > 0 {
+			c.removeOnFailure(ctx, conn)
+		}
+		return conn, nil
+	}
+
+	if err := c.setPeer(ctx, conn, mech.DstPublicKey(), mech.DstIP(), mech.DstPort()); err != nil {
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		c.removeOnFailure(ctx, conn)
+		return nil, err
+	}
+
+	store(ctx, metadata.IsClient(c))
+
+	return conn, nil
+}
+
+// wireguardMechanisms returns the requested WireGuard mechanisms to publish the Src parameters to: the mechanism
+// preferences and the already selected mechanism on refresh
+func wireguardMechanisms(request *networkservice.NetworkServiceRequest) []*networkservice.Mechanism {
+	var mechs []*networkservice.Mechanism
+	if mech := request.GetConnection().GetMechanism(); mech.GetType() == wgmech.MECHANISM {
+		mechs = append(mechs, mech)
+	}
+	for _, mech := range request.GetMechanismPreferences() {
+		if mech.GetType() == wgmech.MECHANISM {
+			mechs = append(mechs, mech)
+		}
+	}
+	return mechs
+}
+
+// removeOnFailure deletes the net interface on the failed first Request, it is still used by the connection on the
+// failed refresh
+func (c *wireguardClient) removeOnFailure(ctx context.Context, conn *networkservice.Connection) {
+	if load(ctx, metadata.IsClient(c)) {
+		return
+	}
+	if err := remove(ctx, conn); err != nil {
+		log.Entry(ctx).WithField("wireguardClient", "Request").Warnf("failed to delete WireGuard net interface: %s", err.Error())
+	}
+}
+
+func (c *wireguardClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var removeErr error
+	if loadAndDelete(ctx, metadata.IsClient(c)) {
+		removeErr = remove(ctx, conn)
+	}
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/optime"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/wireguard"
+)
+
+const (
+	linkPrefix = "nsmwg"
+	linkType   = "wireguard"
+)
+
+// LinkName returns the connection WireGuard net interface name in the Forwarder's net NS
+func LinkName(conn *networkservice.Connection) string {
+	return linkprovider.LinkName(linkPrefix, conn)
+}
+
+// configurator is the common part of the wireguard client and server
+type configurator struct {
+	keepalive  time.Duration
+	allowedIPs []*net.IPNet
+}
+
+func newConfigurator(options []Option) *configurator {
+	c := &configurator{
+		allowedIPs: []*net.IPNet{
+			{IP: net.IPv4zero, Mask: net.CIDRMask(0, net.IPv4len*8)},
+			{IP: net.IPv6zero, Mask: net.CIDRMask(0, net.IPv6len*8)},
+		},
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	return c
+}
+
+// create creates the connection WireGuard net interface in the current net NS with a new private key and publishes
+// its public key and listen port to the mechanism parameters. Already existing net interface is reused on refresh.
+func create(ctx context.Context, conn *networkservice.Connection, mech *networkservice.Mechanism, publicKeyKey, portKey string) error {
+	name := LinkName(conn)
+	if _, err := netlink.LinkByName(name); err != nil {
+		if err := createLink(ctx, name, mech.GetParameters()[portKey]); err != nil {
+			return err
+		}
+	}
+
+	device, err := wireguard.GetDevice(name)
+	if err != nil {
+		return err
+	}
+
+	if mech.Parameters == nil {
+		mech.Parameters = make(map[string]string)
+	}
+	mech.GetParameters()[publicKeyKey] = device.PublicKey.String()
+	mech.GetParameters()[portKey] = strconv.Itoa(device.ListenPort)
+
+	return nil
+}
+
+func createLink(ctx context.Context, name, port string) error {
+	var listenPort int
+	if port != "" {
+		value, err := strconv.ParseUint(port, 10, 16)
+		if err != nil {
+			return errors.Errorf("invalid WireGuard listen port: %v", port)
+		}
+		listenPort = int(value)
+	}
+
+	privateKey, err := wireguard.GeneratePrivateKey()
+	if err != nil {
+		return err
+	}
+
+	link := &netlink.GenericLink{
+		LinkAttrs: netlink.LinkAttrs{Name: name},
+		LinkType:  linkType,
+	}
+	if err := optime.Time(ctx, "LinkAdd", link, func() error { return netlink.LinkAdd(link) }); err != nil {
+		return errors.Wrapf(err, "failed to create WireGuard net interface: %v", name)
+	}
+
+	if err := wireguard.Configure(name, &wireguard.Config{
+		PrivateKey: privateKey,
+		ListenPort: listenPort,
+	}); err != nil {
+		_ = netlink.LinkDel(link)
+		return err
+	}
+	return nil
+}
+
+// setPeer sets the remote side as the only peer of the connection WireGuard net interface and sets it up
+func (c *configurator) setPeer(ctx context.Context, conn *networkservice.Connection, publicKey string, ip net.IP, port int) error {
+	key, err := wireguard.ParseKey(publicKey)
+	if err != nil {
+		return err
+	}
+	if ip == nil || port == 0 {
+		return errors.Errorf("WireGuard mechanism has no remote IP or port: %v", conn.GetMechanism().GetParameters())
+	}
+
+	name := LinkName(conn)
+	if err := wireguard.Configure(name, &wireguard.Config{
+		ReplacePeers: true,
+		Peers: []*wireguard.Peer{{
+			PublicKey:           key,
+			Endpoint:            &net.UDPAddr{IP: ip, Port: port},
+			PersistentKeepalive: c.keepalive,
+			AllowedIPs:          c.allowedIPs,
+		}},
+	}); err != nil {
+		return err
+	}
+
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get WireGuard net interface: %v", name)
+	}
+	if err := optime.Time(ctx, "LinkSetUp", link, func() error { return netlink.LinkSetUp(link) }); err != nil {
+		return errors.Wrapf(err, "failed to set up WireGuard net interface: %v", name)
+	}
+	return nil
+}
+
+// remove deletes the connection WireGuard net interface, already deleted net interface is skipped
+func remove(ctx context.Context, conn *networkservice.Connection) error {
+	link, err := netlink.LinkByName(LinkName(conn))
+	if err != nil {
+		return nil
+	}
+	if err := optime.Time(ctx, "LinkDel", link, func() error { return netlink.LinkDel(link) }); err != nil {
+		return errors.Wrapf(err, "failed to delete WireGuard net interface: %v", link.Attrs().Name)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"net"
+	"time"
+)
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(c *configurator)
+
+// WithPersistentKeepalive sets the peer keepalive interval, it keeps NAT mappings open. Keepalives are disabled by
+// default.
+func WithPersistentKeepalive(keepalive time.Duration) Option {
+	return func(c *configurator) {
+		c.keepalive = keepalive
+	}
+}
+
+// WithAllowedIPs sets the inner IP prefixes accepted from and routed to the peer, default is all IPv4 and IPv6
+// addresses: each connection has its own net interface with the only peer
+func WithAllowedIPs(allowedIPs ...*net.IPNet) Option {
+	return func(c *configurator) {
+		c.allowedIPs = allowedIPs
+	}
+}
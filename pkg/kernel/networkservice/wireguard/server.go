@@ -0,0 +1,103 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wireguard provides chain elements creating kernel WireGuard net interfaces for the remote connections, so
+// the kernel Forwarder has an encrypted remote mechanism
+package wireguard
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	wgmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/wireguard"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type wireguardServer struct {
+	*configurator
+}
+
+// NewServer returns a new server chain element creating the WireGuard net interface (see LinkName) in the current net
+// NS for the connections with the WireGuard mechanism. The net interface gets a new private key, its public key and
+// listen port are set to the mechanism Dst parameters, the Client side is configured as the only peer from the
+// mechanism Src parameters. The net interface is deleted on Close.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &wireguardServer{
+		configurator: newConfigurator(options),
+	}
+}
+
+func (s *wireguardServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	mech := wgmech.ToMechanism(request.GetConnection().GetMechanism())
+	if mech == nil {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	if err := s.setUp(ctx, request.GetConnection(), mech); err != nil {
+		s.removeOnFailure(ctx, request.GetConnection())
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		s.removeOnFailure(ctx, request.GetConnection())
+		return nil, err
+	}
+
+	store(ctx, metadata.IsClient(s))
+
+	return conn, nil
+}
+
+func (s *wireguardServer) setUp(ctx context.Context, conn *networkservice.Connection, mech wgmech.Mechanism) error {
+	if err := create(ctx, conn, conn.GetMechanism(), wgmech.DstPublicKey, wgmech.DstPort); err != nil {
+		return err
+	}
+	return s.setPeer(ctx, conn, mech.SrcPublicKey(), mech.SrcIP(), mech.SrcPort())
+}
+
+// removeOnFailure deletes the net interface on the failed first Request, it is still used by the connection on the
+// failed refresh
+func (s *wireguardServer) removeOnFailure(ctx context.Context, conn *networkservice.Connection) {
+	if load(ctx, metadata.IsClient(s)) {
+		return
+	}
+	if err := remove(ctx, conn); err != nil {
+		log.Entry(ctx).WithField("wireguardServer", "Request").Warnf("failed to delete WireGuard net interface: %s", err.Error())
+	}
+}
+
+func (s *wireguardServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var removeErr error
+	if loadAndDelete(ctx, metadata.IsClient(s)) {
+		removeErr = remove(ctx, conn)
+	}
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard_test
+
+import (
+	"context"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	wgmech "github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/wireguard"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/wireguard"
+	wgtool "github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/wireguard"
+)
+
+func skipUnsupported(t *testing.T) {
+	link := &netlink.GenericLink{LinkAttrs: netlink.LinkAttrs{Name: "wg-check"}, LinkType: "wireguard"}
+	if err := netlink.LinkAdd(link); err != nil {
+		if errors.Cause(err) == syscall.EOPNOTSUPP {
+			t.Skip("WireGuard is not supported by the kernel")
+		}
+		require.NoError(t, err)
+	}
+	_ = netlink.LinkDel(link)
+}
+
+func peerKey(t *testing.T) string {
+	// any 32 bytes are a valid Curve25519 public key
+	key, err := wgtool.GeneratePrivateKey()
+	require.NoError(t, err)
+	return key.String()
+}
+
+func TestWireguardServer(t *testing.T) {
+	skipUnsupported(t)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		wireguard.NewServer(),
+	)
+
+	request := &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "wg-conn",
+			Mechanism: &networkservice.Mechanism{
+				Type: wgmech.MECHANISM,
+				Parameters: map[string]string{
+					wgmech.SrcIP:        "172.16.2.1",
+					wgmech.DstIP:        "172.16.2.2",
+					wgmech.SrcPort:      "51821",
+					wgmech.DstPort:      "51822",
+					wgmech.SrcPublicKey: peerKey(t),
+				},
+			},
+		},
+	}
+
+	conn, err := server.Request(context.TODO(), request)
+	require.NoError(t, err)
+
+	device, err := wgtool.GetDevice(wireguard.LinkName(conn))
+	require.NoError(t, err)
+	require.Equal(t, 51822, device.ListenPort)
+
+	params := conn.GetMechanism().GetParameters()
+	require.Equal(t, device.PublicKey.String(), params[wgmech.DstPublicKey])
+	require.Equal(t, strconv.Itoa(device.ListenPort), params[wgmech.DstPort])
+
+	// refresh keeps the net interface and its key
+	conn, err = server.Request(context.TODO(), request)
+	require.NoError(t, err)
+	require.Equal(t, device.PublicKey.String(), conn.GetMechanism().GetParameters()[wgmech.DstPublicKey])
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	_, err = netlink.LinkByName(wireguard.LinkName(conn))
+	require.Error(t, err)
+}
+
+func TestWireguardServer_InvalidPeer(t *testing.T) {
+	skipUnsupported(t)
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		wireguard.NewServer(),
+	)
+
+	conn := &networkservice.Connection{
+		Id: "wg-conn",
+		Mechanism: &networkservice.Mechanism{
+			Type: wgmech.MECHANISM,
+			Parameters: map[string]string{
+				wgmech.SrcIP:        "172.16.2.1",
+				wgmech.SrcPort:      "51821",
+				wgmech.SrcPublicKey: "key",
+			},
+		},
+	}
+
+	_, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.Error(t, err)
+	_, err = netlink.LinkByName(wireguard.LinkName(conn))
+	require.Error(t, err)
+}
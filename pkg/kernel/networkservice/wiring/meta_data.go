@@ -0,0 +1,39 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wiring
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/metamap"
+)
+
+type keyType struct{}
+
+// loadMode returns the connection wiring mode selected on the first Request, so the connection is unwired in the same
+// mode even if the refresh changes its labels. The mode is stored in the server metadata: the Wiring chain elements
+// are the inject and wiring servers. Without metadata chain element in the chain the mode is selected every time.
+func (w *Wiring) loadMode(ctx context.Context, conn *networkservice.Connection) Mode {
+	m, ok := metamap.Load(ctx, false)
+	if !ok {
+		return w.Mode(conn)
+	}
+	raw, _ := m.LoadOrStore(keyType{}, w.Mode(conn))
+	return raw.(Mode)
+}
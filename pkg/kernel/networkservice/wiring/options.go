@@ -0,0 +1,55 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wiring
+
+// Option is an option pattern for New
+type Option func(w *Wiring)
+
+// WithDefaultMode sets the mode for the connections not matching any rule, default is ModeRouted
+func WithDefaultMode(mode Mode) Option {
+	return func(w *Wiring) {
+		w.defaultMode = mode
+	}
+}
+
+// WithBridgeName sets the bridge name for the bridged connections, default is "nsm-br0"
+func WithBridgeName(bridgeName string) Option {
+	return func(w *Wiring) {
+		w.bridgeName = bridgeName
+	}
+}
+
+// WithServiceMode sets the mode for the connections to the given network service
+func WithServiceMode(service string, mode Mode) Option {
+	return func(w *Wiring) {
+		w.rules = append(w.rules, &rule{
+			service: service,
+			mode:    mode,
+		})
+	}
+}
+
+// WithLabelMode sets the mode for the connections with the given label
+func WithLabelMode(key, value string, mode Mode) Option {
+	return func(w *Wiring) {
+		w.rules = append(w.rules, &rule{
+			labelKey:   key,
+			labelValue: value,
+			mode:       mode,
+		})
+	}
+}
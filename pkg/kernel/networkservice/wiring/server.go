@@ -0,0 +1,204 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wiring
+
+import (
+	"context"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+
+	kernelconst "github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ipaddrs"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+// ntfProxy is the kernel NTF_PROXY neighbor flag, netlink defines it only on linux
+const ntfProxy = 0x08
+
+type wiringServer struct {
+	wiring *Wiring
+}
+
+// NewServer returns a new server chain element wiring the routed connections veth peers in the current (Forwarder's)
+// net NS: it sets up host routes to the Client's Src IP address via the veth peer and enables proxy ARP on it. For the
+// IPv6 the proxy NDP is enabled on the veth peer with the proxy entries for the Client's gateways (Dst IP addresses).
+// Bridged connections are wired by the LinkProvider. It should follow the inject chain element. The wiring mode is
+// selected on the first Request and kept in the metadata, so the connection is unwired in the same mode.
+func (w *Wiring) NewServer() networkservice.NetworkServiceServer {
+	return &wiringServer{
+		wiring: w,
+	}
+}
+
+func (s *wiringServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if kernel.ToMechanism(request.GetConnection().GetMechanism()) == nil || s.wiring.loadMode(ctx, request.GetConnection()) != ModeRouted {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	if err := route(request.GetConnection()); err != nil {
+		return nil, err
+	}
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		_ = unroute(request.GetConnection())
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (s *wiringServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var unrouteErr error
+	if kernel.ToMechanism(conn.GetMechanism()) != nil && s.wiring.loadMode(ctx, conn) == ModeRouted {
+		unrouteErr = unroute(conn)
+	}
+
+	if err != nil && unrouteErr != nil {
+		return nil, errors.Wrap(err, unrouteErr.Error())
+	}
+	if unrouteErr != nil {
+		return nil, unrouteErr
+	}
+	return &empty.Empty{}, err
+}
+
+func route(conn *networkservice.Connection) error {
+	peerName := linkprovider.VethPeerName(conn)
+	peer, err := netlink.LinkByName(peerName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get net interface: %v", peerName)
+	}
+	if err = netlink.LinkSetUp(peer); err != nil {
+		return errors.Wrapf(err, "failed to set up net interface: %v", peerName)
+	}
+
+	// the Client resolves its gateway on the veth peer
+	if err = sysctl.Set("net/ipv4/conf/"+peerName+"/proxy_arp", "1"); err != nil {
+		return err
+	}
+
+	hostRoutes, err := newHostRoutes(conn, peer)
+	if err != nil {
+		return err
+	}
+	for _, hostRoute := range hostRoutes {
+		if err := netlink.RouteAdd(hostRoute); err != nil && !os.IsExist(err) {
+			return errors.Wrapf(err, "failed to add route: %v", hostRoute.Dst)
+		}
+	}
+
+	// proxy NDP answers only for the addresses in the proxy entries, so the Client's gateways are added
+	proxyNeighs, err := newProxyNeighs(conn, peer)
+	if err != nil || len(proxyNeighs) == 0 {
+		return err
+	}
+	if err = sysctl.Set("net/ipv6/conf/"+peerName+"/proxy_ndp", "1"); err != nil {
+		return err
+	}
+	for _, proxyNeigh := range proxyNeighs {
+		if err := netlink.NeighAdd(proxyNeigh); err != nil && !os.IsExist(err) {
+			return errors.Wrapf(err, "failed to add proxy neighbor: %v", proxyNeigh.IP)
+		}
+	}
+
+	return nil
+}
+
+func unroute(conn *networkservice.Connection) error {
+	peer, err := netlink.LinkByName(linkprovider.VethPeerName(conn))
+	if err != nil {
+		// the route is deleted with the veth peer
+		return nil
+	}
+
+	hostRoutes, err := newHostRoutes(conn, peer)
+	if err != nil {
+		return err
+	}
+	for _, hostRoute := range hostRoutes {
+		if err := netlink.RouteDel(hostRoute); err != nil && err != syscall.ESRCH {
+			return errors.Wrapf(err, "failed to delete route: %v", hostRoute.Dst)
+		}
+	}
+
+	proxyNeighs, err := newProxyNeighs(conn, peer)
+	if err != nil {
+		return err
+	}
+	for _, proxyNeigh := range proxyNeighs {
+		if err := netlink.NeighDel(proxyNeigh); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to delete proxy neighbor: %v", proxyNeigh.IP)
+		}
+	}
+
+	return nil
+}
+
+func newHostRoutes(conn *networkservice.Connection, peer netlink.Link) ([]*netlink.Route, error) {
+	ipNets, err := ipaddrs.Parse(conn.GetContext().GetIpContext().GetSrcIpAddr())
+	if err != nil {
+		return nil, err
+	}
+
+	hostRoutes := make([]*netlink.Route, 0, len(ipNets))
+	for _, ipNet := range ipNets {
+		hostRoutes = append(hostRoutes, &netlink.Route{
+			LinkIndex: peer.Attrs().Index,
+			Dst: &net.IPNet{
+				IP:   ipNet.IP,
+				Mask: ipaddrs.HostMask(ipNet.IP),
+			},
+			Protocol: kernelconst.RouteProtoNSM,
+		})
+	}
+	return hostRoutes, nil
+}
+
+// newProxyNeighs returns the proxy NDP entries for the Client's IPv6 gateways: Dst IP addresses
+func newProxyNeighs(conn *networkservice.Connection, peer netlink.Link) ([]*netlink.Neigh, error) {
+	ipNets, err := ipaddrs.Parse(conn.GetContext().GetIpContext().GetDstIpAddr())
+	if err != nil {
+		return nil, err
+	}
+
+	var proxyNeighs []*netlink.Neigh
+	for _, ipNet := range ipNets {
+		if ipaddrs.IsIPv4(ipNet.IP) {
+			continue
+		}
+		proxyNeighs = append(proxyNeighs, &netlink.Neigh{
+			LinkIndex: peer.Attrs().Index,
+			Family:    syscall.AF_INET6,
+			Flags:     ntfProxy,
+			IP:        ipNet.IP,
+		})
+	}
+	return proxyNeighs, nil
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wiring provides selection between L2 (bridged) and L3 (routed) wiring of the Client's veth in the
+// Forwarder's net NS per network service or connection label
+package wiring
+
+import (
+	"context"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+)
+
+// Mode is the wiring mode
+type Mode int
+
+const (
+	// ModeRouted wires the veth peer as L3 link: host routes to the Client's IP addresses via the veth peer, proxy ARP
+	// and proxy NDP on it
+	ModeRouted Mode = iota
+	// ModeBridged wires the veth peer as L2 link: the veth peer is attached to the bridge
+	ModeBridged
+)
+
+const defaultBridgeName = "nsm-br0"
+
+type rule struct {
+	service              string
+	labelKey, labelValue string
+	mode                 Mode
+}
+
+// Wiring is a composite option selecting the wiring mode per connection, it provides both the LinkProvider for the
+// inject chain element and the wiring chain element:
+//
+//	w := wiring.New(wiring.WithServiceMode("l2-service", wiring.ModeBridged))
+//	chain.NewNetworkServiceServer(
+//		metadata.NewServer(),
+//		inject.NewServer(inject.WithLinkProvider(w.LinkProvider())),
+//		w.NewServer(),
+//		...
+//	)
+type Wiring struct {
+	defaultMode Mode
+	bridgeName  string
+	rules       []*rule
+}
+
+// New returns a new Wiring, by default all connections are routed
+func New(options ...Option) *Wiring {
+	w := &Wiring{
+		defaultMode: ModeRouted,
+		bridgeName:  defaultBridgeName,
+	}
+	for _, opt := range options {
+		opt(w)
+	}
+	return w
+}
+
+// Mode returns the wiring mode for the connection: mode of the first matching rule or the default one
+func (w *Wiring) Mode(conn *networkservice.Connection) Mode {
+	for _, r := range w.rules {
+		if r.service != "" && r.service == conn.GetNetworkService() {
+			return r.mode
+		}
+		if r.labelKey != "" && conn.GetLabels()[r.labelKey] == r.labelValue {
+			return r.mode
+		}
+	}
+	return w.defaultMode
+}
+
+// LinkProvider returns a new LinkProvider creating veth pairs with the peer end attached to the bridge for the
+// bridged connections
+func (w *Wiring) LinkProvider() linkprovider.LinkProvider {
+	return &modeProvider{
+		wiring:  w,
+		routed:  linkprovider.NewVeth(),
+		bridged: linkprovider.NewBridged(w.bridgeName),
+	}
+}
+
+type modeProvider struct {
+	wiring          *Wiring
+	routed, bridged linkprovider.LinkProvider
+}
+
+func (p *modeProvider) provider(ctx context.Context, conn *networkservice.Connection) linkprovider.LinkProvider {
+	if p.wiring.loadMode(ctx, conn) == ModeBridged {
+		return p.bridged
+	}
+	return p.routed
+}
+
+func (p *modeProvider) CreateLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	return p.provider(ctx, conn).CreateLink(ctx, conn)
+}
+
+func (p *modeProvider) AdoptLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	return p.provider(ctx, conn).AdoptLink(ctx, conn)
+}
+
+func (p *modeProvider) DeleteLink(ctx context.Context, conn *networkservice.Connection, link netlink.Link) error {
+	return p.provider(ctx, conn).DeleteLink(ctx, conn, link)
+}
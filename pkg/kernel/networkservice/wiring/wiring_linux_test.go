@@ -0,0 +1,230 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wiring_test
+
+import (
+	"context"
+	"net/url"
+	"path"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/inject"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/wiring"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/owned"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+const (
+	netNSPath  = "/run/netns"
+	bridgeName = "wiring-br"
+)
+
+func newConn(t *testing.T, curNetNS netns.NsHandle, service string) (*networkservice.Connection, func()) {
+	netNSName := uuid.New().String()
+	clientNetNS, err := netns.NewNamed(netNSName)
+	require.NoError(t, err)
+	require.NoError(t, netns.Set(curNetNS))
+
+	return &networkservice.Connection{
+		Id:             uuid.New().String(),
+		NetworkService: service,
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.NetNSURL:         (&url.URL{Scheme: "file", Path: path.Join(netNSPath, netNSName)}).String(),
+				kernel.InterfaceNameKey: "nsm-1",
+			},
+		},
+		Context: &networkservice.ConnectionContext{
+			IpContext: &networkservice.IPContext{
+				SrcIpAddr: "10.0.16.1/32",
+			},
+		},
+	}, func() {
+		_ = clientNetNS.Close()
+		_ = netns.DeleteNamed(netNSName)
+	}
+}
+
+func TestWiring(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	w := wiring.New(
+		wiring.WithBridgeName(bridgeName),
+		wiring.WithServiceMode("l2", wiring.ModeBridged),
+	)
+	defer func() { _ = netlink.LinkDel(&netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: bridgeName}}) }()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithLinkProvider(w.LinkProvider())),
+		w.NewServer(),
+	)
+
+	bridgedConn, cleanup := newConn(t, curNetNS, "l2")
+	defer cleanup()
+	routedConn, cleanup := newConn(t, curNetNS, "l3")
+	defer cleanup()
+
+	bridgedConn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: bridgedConn})
+	require.NoError(t, err)
+	routedConn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: routedConn})
+	require.NoError(t, err)
+
+	bridge, err := netlink.LinkByName(bridgeName)
+	require.NoError(t, err)
+
+	bridgedPeer, err := netlink.LinkByName(linkprovider.VethPeerName(bridgedConn))
+	require.NoError(t, err)
+	require.Equal(t, bridge.Attrs().Index, bridgedPeer.Attrs().MasterIndex)
+	routes, err := owned.Routes(bridgedPeer)
+	require.NoError(t, err)
+	require.Empty(t, routes)
+
+	routedPeer, err := netlink.LinkByName(linkprovider.VethPeerName(routedConn))
+	require.NoError(t, err)
+	require.Zero(t, routedPeer.Attrs().MasterIndex)
+	routes, err = owned.Routes(routedPeer)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	require.Equal(t, "10.0.16.1/32", routes[0].Dst.String())
+
+	for _, conn := range []*networkservice.Connection{bridgedConn, routedConn} {
+		_, err = server.Close(context.TODO(), conn)
+		require.NoError(t, err)
+
+		_, err = netlink.LinkByName(linkprovider.VethPeerName(conn))
+		require.Error(t, err)
+	}
+}
+
+func TestWiring_ModeKept(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	w := wiring.New(
+		wiring.WithBridgeName(bridgeName),
+		wiring.WithLabelMode("wiring", "l2", wiring.ModeBridged),
+	)
+	defer func() { _ = netlink.LinkDel(&netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: bridgeName}}) }()
+
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithLinkProvider(w.LinkProvider())),
+		w.NewServer(),
+	)
+
+	conn, cleanup := newConn(t, curNetNS, "service")
+	defer cleanup()
+	conn.Labels = map[string]string{"wiring": "l2"}
+
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	// the refresh doesn't rewire the connection as routed
+	conn.Labels = nil
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	bridge, err := netlink.LinkByName(bridgeName)
+	require.NoError(t, err)
+	peer, err := netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.NoError(t, err)
+	require.Equal(t, bridge.Attrs().Index, peer.Attrs().MasterIndex)
+	routes, err := owned.Routes(peer)
+	require.NoError(t, err)
+	require.Empty(t, routes)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	_, err = netlink.LinkByName(linkprovider.VethPeerName(conn))
+	require.Error(t, err)
+}
+
+func TestWiring_ProxyNDP(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	w := wiring.New()
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		inject.NewServer(inject.WithLinkProvider(w.LinkProvider())),
+		w.NewServer(),
+	)
+
+	conn, cleanup := newConn(t, curNetNS, "l3")
+	defer cleanup()
+	conn.Context.IpContext = &networkservice.IPContext{
+		SrcIpAddr: "fd00:16::1/128",
+		DstIpAddr: "fd00:16::2/128",
+	}
+
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+
+	peerName := linkprovider.VethPeerName(conn)
+	peer, err := netlink.LinkByName(peerName)
+	require.NoError(t, err)
+
+	proxyNDP, err := sysctl.Get("net/ipv6/conf/" + peerName + "/proxy_ndp")
+	require.NoError(t, err)
+	require.Equal(t, "1", proxyNDP)
+
+	proxyNeighs, err := netlink.NeighProxyList(peer.Attrs().Index, syscall.AF_INET6)
+	require.NoError(t, err)
+	require.Len(t, proxyNeighs, 1)
+	require.Equal(t, "fd00:16::2", proxyNeighs[0].IP.String())
+
+	routes, err := owned.Routes(peer)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	require.Equal(t, "fd00:16::1/128", routes[0].Dst.String())
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+
+	_, err = netlink.LinkByName(peerName)
+	require.Error(t, err)
+}
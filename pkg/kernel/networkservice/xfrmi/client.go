@@ -0,0 +1,80 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xfrmi
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type xfrmiClient struct {
+	*linker
+}
+
+// NewClient returns a new client chain element creating the xfrm net interface (see LinkName) in the current net NS
+// for the connections with the selected IPsec mechanism. The routes to the Endpoint's IP addresses and Dst routes go
+// via the net interface. The net interface is deleted and the xfrm interface ID is released on Close.
+func NewClient(options ...Option) networkservice.NetworkServiceClient {
+	return &xfrmiClient{
+		linker: newLinker(options),
+	}
+}
+
+func (c *xfrmiClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*networkservice.Connection, error) {
+	conn, err := next.Client(ctx).Request(ctx, request, opts...)
+	if err != nil || conn.GetMechanism().GetType() != MECHANISM {
+		return conn, err
+	}
+
+	ifID, err := c.create(ctx, conn, metadata.IsClient(c))
+	if err != nil {
+		// the xfrm net interface is still used by the connection on failed refresh
+		if _, refresh := IfID(ctx, metadata.IsClient(c)); !refresh {
+			_ = c.remove(ctx, conn)
+		}
+		_, _ = next.Client(ctx).Close(ctx, conn, opts...)
+		return nil, err
+	}
+
+	store(ctx, metadata.IsClient(c), ifID)
+
+	return conn, nil
+}
+
+func (c *xfrmiClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	_, err := next.Client(ctx).Close(ctx, conn, opts...)
+
+	var removeErr error
+	if loadAndDelete(ctx, metadata.IsClient(c)) {
+		removeErr = c.remove(ctx, conn)
+	}
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
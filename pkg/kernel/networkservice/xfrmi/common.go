@@ -0,0 +1,185 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xfrmi
+
+import (
+	"context"
+	"net"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ipaddrs"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/optime"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/vni"
+)
+
+// IPsec mechanism. There is no IPsec mechanism in the used API version, so it is defined here.
+const (
+	// MECHANISM is the IPsec mechanism type
+	MECHANISM = "IPSEC"
+
+	// MaxIfID is the max xfrm interface ID
+	MaxIfID = 1<<32 - 1
+
+	linkPrefix = "nsmxf"
+)
+
+// LinkName returns the connection xfrm net interface name in the Forwarder's net NS
+func LinkName(conn *networkservice.Connection) string {
+	return linkprovider.LinkName(linkPrefix, conn)
+}
+
+// linker is the common part of the xfrmi client and server
+type linker struct {
+	allocator    *vni.Allocator
+	parentIfName string
+}
+
+func newLinker(options []Option) *linker {
+	l := &linker{}
+	for _, opt := range options {
+		opt(l)
+	}
+	if l.allocator == nil {
+		// allocator without persistence and node lock never fails
+		l.allocator, _ = vni.NewAllocator(vni.WithRange(1, MaxIfID))
+	}
+	return l
+}
+
+// create allocates the connection xfrm interface ID and creates the connection xfrm net interface with it in the
+// current net NS. Already existing net interface with the same ID is reused on refresh, otherwise it is recreated.
+// Routes to the remote side IP addresses and routes go via the net interface.
+func (l *linker) create(ctx context.Context, conn *networkservice.Connection, isClient bool) (uint32, error) {
+	ifID, err := l.allocator.Allocate(conn.GetId())
+	if err != nil {
+		return 0, err
+	}
+
+	xfrmi := &netlink.Xfrmi{
+		LinkAttrs: netlink.LinkAttrs{Name: LinkName(conn)},
+		Ifid:      ifID,
+	}
+	if l.parentIfName != "" {
+		parent, err := netlink.LinkByName(l.parentIfName)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to get xfrm parent net interface: %v", l.parentIfName)
+		}
+		xfrmi.ParentIndex = parent.Attrs().Index
+	}
+
+	link, err := netlink.LinkByName(xfrmi.Name)
+	if err == nil && !sameLink(link, xfrmi) {
+		if err = del(ctx, link); err != nil {
+			return 0, err
+		}
+	}
+	if err != nil {
+		if err = optime.Time(ctx, "LinkAdd", xfrmi, func() error { return netlink.LinkAdd(xfrmi) }); err != nil {
+			return 0, errors.Wrapf(err, "failed to create xfrm net interface: %v", xfrmi.Name)
+		}
+		if link, err = netlink.LinkByName(xfrmi.Name); err != nil {
+			return 0, errors.Wrapf(err, "failed to get xfrm net interface: %v", xfrmi.Name)
+		}
+	}
+
+	if err := setUp(ctx, conn, link, isClient); err != nil {
+		_ = del(ctx, link)
+		return 0, err
+	}
+	return ifID, nil
+}
+
+func setUp(ctx context.Context, conn *networkservice.Connection, link netlink.Link, isClient bool) error {
+	if err := optime.Time(ctx, "LinkSetUp", link, func() error { return netlink.LinkSetUp(link) }); err != nil {
+		return errors.Wrapf(err, "failed to set up xfrm net interface: %v", link.Attrs().Name)
+	}
+
+	dsts, err := remotePrefixes(conn, isClient)
+	if err != nil {
+		return err
+	}
+	for _, dst := range dsts {
+		route := &netlink.Route{
+			LinkIndex: link.Attrs().Index,
+			Dst:       dst,
+			Protocol:  kernel.RouteProtoNSM,
+			Scope:     kernel.RouteScopeLink,
+		}
+		if err := optime.Time(ctx, "RouteAdd", route, func() error { return netlink.RouteAdd(route) }); err != nil && !os.IsExist(err) {
+			return errors.Wrapf(err, "failed to add route: %v", route.Dst)
+		}
+	}
+	return nil
+}
+
+// remotePrefixes returns the remote side IP addresses and routes. Server side is the Endpoint of the mechanism, so its
+// remote side is the Client: Src IP addresses and routes.
+func remotePrefixes(conn *networkservice.Connection, isClient bool) ([]*net.IPNet, error) {
+	ipContext := conn.GetContext().GetIpContext()
+	ipAddrs, routes := ipContext.GetSrcIpAddr(), ipContext.GetSrcRoutes()
+	if isClient {
+		ipAddrs, routes = ipContext.GetDstIpAddr(), ipContext.GetDstRoutes()
+	}
+
+	ipNets, err := ipaddrs.Parse(ipAddrs)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*net.IPNet
+	for _, ipNet := range ipNets {
+		result = append(result, &net.IPNet{IP: ipNet.IP, Mask: ipaddrs.HostMask(ipNet.IP)})
+	}
+	for _, route := range routes {
+		_, dst, err := net.ParseCIDR(route.GetPrefix())
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid route CIDR: %v", route.GetPrefix())
+		}
+		result = append(result, dst)
+	}
+	return result, nil
+}
+
+// remove deletes the connection xfrm net interface with its routes and releases the xfrm interface ID, already
+// deleted net interface is skipped
+func (l *linker) remove(ctx context.Context, conn *networkservice.Connection) error {
+	if link, err := netlink.LinkByName(LinkName(conn)); err == nil {
+		if err := del(ctx, link); err != nil {
+			return err
+		}
+	}
+	return l.allocator.Release(conn.GetId())
+}
+
+func del(ctx context.Context, link netlink.Link) error {
+	if err := optime.Time(ctx, "LinkDel", link, func() error { return netlink.LinkDel(link) }); err != nil {
+		return errors.Wrapf(err, "failed to delete xfrm net interface: %v", link.Attrs().Name)
+	}
+	return nil
+}
+
+func sameLink(existing netlink.Link, expected *netlink.Xfrmi) bool {
+	xfrmi, ok := existing.(*netlink.Xfrmi)
+	return ok && xfrmi.Ifid == expected.Ifid && xfrmi.ParentIndex == expected.ParentIndex
+}
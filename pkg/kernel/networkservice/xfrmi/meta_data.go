@@ -0,0 +1,45 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xfrmi
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+type keyType struct{}
+
+// store stores the xfrm interface ID of the connection xfrm net interface created by the element
+func store(ctx context.Context, isClient bool, ifID uint32) {
+	metadata.Map(ctx, isClient).Store(keyType{}, ifID)
+}
+
+// IfID returns the xfrm interface ID of the connection xfrm net interface, the IPsec states and policies of the
+// connection should be created with it to route the connection traffic via the net interface
+func IfID(ctx context.Context, isClient bool) (uint32, bool) {
+	value, ok := metadata.Map(ctx, isClient).Load(keyType{})
+	if !ok {
+		return 0, false
+	}
+	return value.(uint32), true
+}
+
+func loadAndDelete(ctx context.Context, isClient bool) bool {
+	_, ok := metadata.Map(ctx, isClient).LoadAndDelete(keyType{})
+	return ok
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xfrmi
+
+import (
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/vni"
+)
+
+// Option is an option pattern for NewServer, NewClient
+type Option func(l *linker)
+
+// WithIfIDAllocator sets the xfrm interface IDs allocator, it should allocate IDs in [1, MaxIfID] range. Allocator
+// with the node lock should be used if there are multiple Forwarder instances on the node.
+func WithIfIDAllocator(allocator *vni.Allocator) Option {
+	return func(l *linker) {
+		l.allocator = allocator
+	}
+}
+
+// WithParent sets the net interface the xfrm net interfaces are bound to, it is required by the kernels before 5.3
+func WithParent(ifName string) Option {
+	return func(l *linker) {
+		l.parentIfName = ifName
+	}
+}
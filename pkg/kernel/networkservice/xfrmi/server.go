@@ -0,0 +1,89 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package xfrmi provides chain elements creating kernel xfrm net interfaces for the IPsec connections, so the IPsec
+// protected traffic is routed like via the normal net interface instead of being matched by the xfrm policies selectors
+package xfrmi
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/next"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+type xfrmiServer struct {
+	*linker
+}
+
+// NewServer returns a new server chain element creating the xfrm net interface (see LinkName) in the current net NS
+// for the connections with the IPsec mechanism. The xfrm interface ID is allocated per connection and stored in the
+// metadata (see IfID), the routes to the Client's IP addresses and Src routes go via the net interface. The net
+// interface is deleted and the ID is released on Close.
+func NewServer(options ...Option) networkservice.NetworkServiceServer {
+	return &xfrmiServer{
+		linker: newLinker(options),
+	}
+}
+
+func (s *xfrmiServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	if request.GetConnection().GetMechanism().GetType() != MECHANISM {
+		return next.Server(ctx).Request(ctx, request)
+	}
+
+	ifID, err := s.create(ctx, request.GetConnection(), metadata.IsClient(s))
+	if err != nil {
+		return nil, err
+	}
+	_, refresh := IfID(ctx, metadata.IsClient(s))
+	store(ctx, metadata.IsClient(s), ifID)
+
+	conn, err := next.Server(ctx).Request(ctx, request)
+	if err != nil {
+		// the xfrm net interface is still used by the connection on failed refresh
+		if !refresh {
+			loadAndDelete(ctx, metadata.IsClient(s))
+			if removeErr := s.remove(ctx, request.GetConnection()); removeErr != nil {
+				log.Entry(ctx).WithField("xfrmiServer", "Request").Warnf("failed to delete xfrm net interface: %s", removeErr.Error())
+			}
+		}
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+func (s *xfrmiServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	_, err := next.Server(ctx).Close(ctx, conn)
+
+	var removeErr error
+	if loadAndDelete(ctx, metadata.IsClient(s)) {
+		removeErr = s.remove(ctx, conn)
+	}
+
+	if err != nil && removeErr != nil {
+		return nil, errors.Wrap(err, removeErr.Error())
+	}
+	if removeErr != nil {
+		return nil, removeErr
+	}
+	return &empty.Empty{}, err
+}
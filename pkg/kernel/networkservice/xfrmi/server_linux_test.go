@@ -0,0 +1,142 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package xfrmi_test
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/core/chain"
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/xfrmi"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/vni"
+)
+
+func request() *networkservice.NetworkServiceRequest {
+	return &networkservice.NetworkServiceRequest{
+		Connection: &networkservice.Connection{
+			Id: "xfrmi-conn",
+			Mechanism: &networkservice.Mechanism{
+				Type: xfrmi.MECHANISM,
+			},
+			Context: &networkservice.ConnectionContext{
+				IpContext: &networkservice.IPContext{
+					SrcIpAddr: "10.0.20.1/32",
+					DstIpAddr: "10.0.20.2/32",
+					SrcRoutes: []*networkservice.Route{{Prefix: "10.0.21.0/24"}},
+				},
+			},
+		},
+	}
+}
+
+// checkIfID is the next chain element checking the xfrm interface ID stored in the metadata
+type checkIfID struct {
+	t    *testing.T
+	ifID uint32
+}
+
+func (c *checkIfID) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	ifID, ok := xfrmi.IfID(ctx, false)
+	require.True(c.t, ok)
+	c.ifID = ifID
+	return request.GetConnection(), nil
+}
+
+func (c *checkIfID) Close(context.Context, *networkservice.Connection) (*empty.Empty, error) {
+	return new(empty.Empty), nil
+}
+
+func TestXfrmiServer(t *testing.T) {
+	allocator, err := vni.NewAllocator(vni.WithRange(100, xfrmi.MaxIfID))
+	require.NoError(t, err)
+
+	check := &checkIfID{t: t}
+	server := chain.NewNetworkServiceServer(
+		metadata.NewServer(),
+		xfrmi.NewServer(xfrmi.WithIfIDAllocator(allocator)),
+		check,
+	)
+
+	conn, err := server.Request(context.TODO(), request())
+	if errors.Cause(err) == syscall.EOPNOTSUPP {
+		t.Skip("xfrm interfaces are not supported by the kernel")
+	}
+	require.NoError(t, err)
+	require.Equal(t, uint32(100), check.ifID)
+
+	link, err := netlink.LinkByName(xfrmi.LinkName(conn))
+	require.NoError(t, err)
+	require.IsType(t, new(netlink.Xfrmi), link)
+	require.Equal(t, uint32(100), link.(*netlink.Xfrmi).Ifid)
+
+	routes, err := netlink.RouteList(link, netlink.FAMILY_V4)
+	require.NoError(t, err)
+	var dsts []string
+	for i := range routes {
+		dsts = append(dsts, routes[i].Dst.String())
+	}
+	require.ElementsMatch(t, []string{"10.0.20.1/32", "10.0.21.0/24"}, dsts)
+
+	// refresh keeps the net interface and the ID
+	conn, err = server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+	require.NoError(t, err)
+	refreshed, err := netlink.LinkByName(xfrmi.LinkName(conn))
+	require.NoError(t, err)
+	require.Equal(t, link.Attrs().Index, refreshed.Attrs().Index)
+
+	_, err = server.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	_, err = netlink.LinkByName(xfrmi.LinkName(conn))
+	require.Error(t, err)
+	_, ok := allocator.Lookup(conn.GetId())
+	require.False(t, ok)
+}
+
+func TestXfrmiClient(t *testing.T) {
+	client := chain.NewNetworkServiceClient(
+		metadata.NewClient(),
+		xfrmi.NewClient(),
+	)
+
+	conn, err := client.Request(context.TODO(), request())
+	if errors.Cause(err) == syscall.EOPNOTSUPP {
+		t.Skip("xfrm interfaces are not supported by the kernel")
+	}
+	require.NoError(t, err)
+
+	link, err := netlink.LinkByName(xfrmi.LinkName(conn))
+	require.NoError(t, err)
+	routes, err := netlink.RouteList(link, netlink.FAMILY_V4)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	require.True(t, routes[0].Dst.IP.Equal(net.ParseIP("10.0.20.2")))
+
+	_, err = client.Close(context.TODO(), conn)
+	require.NoError(t, err)
+	_, err = netlink.LinkByName(xfrmi.LinkName(conn))
+	require.Error(t, err)
+}
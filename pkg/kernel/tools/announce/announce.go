@@ -0,0 +1,40 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package announce provides gratuitous ARP and unsolicited neighbor advertisements sent for the IP addresses moved to
+// the net interface, so the peers and the switches update their neighbor and FDB entries without waiting for them to
+// expire
+package announce
+
+import (
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+// Addrs sends gratuitous ARP for each IPv4 address and unsolicited neighbor advertisement with the override flag for
+// each IPv6 address through the net interface in the current net NS. Announcements are sent only for the IP addresses
+// assigned to the net interface, the net interface should be up.
+func Addrs(ifName string, ips []net.IP) error {
+	return addrs(ifName, ips)
+}
+
+// AddrsAt sends the announcements like Addrs through the net interface in the given net NS. The sockets are opened in
+// the net NS, so the calling goroutine net NS is switched only for the sockets creation.
+func AddrsAt(netNS netns.NsHandle, link netlink.Link, ips []net.IP) error {
+	return addrsAt(netNS, link, ips)
+}
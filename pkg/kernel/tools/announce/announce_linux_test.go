@@ -0,0 +1,133 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package announce_test
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/announce"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName   = "announce-1"
+	peerName = "announce-2"
+)
+
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
+
+// capture opens a packet socket receiving all the frames on the net interface
+func capture(t *testing.T, link netlink.Link) int {
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_RAW, int(htons(unix.ETH_P_ALL)))
+	require.NoError(t, err)
+	require.NoError(t, unix.Bind(fd, &unix.SockaddrLinklayer{
+		Protocol: htons(unix.ETH_P_ALL),
+		Ifindex:  link.Attrs().Index,
+	}))
+	tv := unix.NsecToTimeval(time.Second.Nanoseconds())
+	require.NoError(t, unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv))
+	return fd
+}
+
+// received returns true if the frame announcing ip has been received
+func received(fd int, ip net.IP) bool {
+	frame := make([]byte, 1500)
+	for {
+		n, _, err := unix.Recvfrom(fd, frame, 0)
+		if err != nil {
+			return false
+		}
+		switch binary.BigEndian.Uint16(frame[12:]) {
+		case unix.ETH_P_ARP:
+			// gratuitous ARP has the same sender and target IP addresses
+			if n >= 42 && net.IP(frame[28:32]).Equal(ip) && net.IP(frame[38:42]).Equal(ip) {
+				return true
+			}
+		case unix.ETH_P_IPV6:
+			// ICMPv6 neighbor advertisement target address
+			if n >= 78 && frame[20] == unix.IPPROTO_ICMPV6 && frame[54] == 136 && net.IP(frame[62:78]).Equal(ip) {
+				return true
+			}
+		}
+	}
+}
+
+func TestAddrs(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+	peer, err := netlink.LinkByName(peerName)
+	require.NoError(t, err)
+
+	ipv4Net, err := netlink.ParseIPNet("172.16.1.1/32")
+	require.NoError(t, err)
+	ipv6Net, err := netlink.ParseIPNet("fd00::1/128")
+	require.NoError(t, err)
+	require.NoError(t, netlink.AddrAdd(link, &netlink.Addr{IPNet: ipv4Net}))
+	require.NoError(t, netlink.AddrAdd(link, &netlink.Addr{IPNet: ipv6Net, Flags: unix.IFA_F_NODAD}))
+
+	require.NoError(t, netlink.LinkSetUp(link))
+	require.NoError(t, netlink.LinkSetUp(peer))
+
+	fd := capture(t, peer)
+	defer func() { _ = unix.Close(fd) }()
+
+	require.NoError(t, announce.Addrs(ifName, []net.IP{ipv4Net.IP}))
+	require.True(t, received(fd, ipv4Net.IP))
+
+	require.NoError(t, announce.Addrs(ifName, []net.IP{ipv6Net.IP}))
+	require.True(t, received(fd, ipv6Net.IP))
+}
+
+func TestAddrs_NoInterface(t *testing.T) {
+	require.Error(t, announce.Addrs("announce-x", []net.IP{net.ParseIP("172.16.1.1")}))
+}
+
+func TestAddrsAt(t *testing.T) {
+	netNS := kerneltest.AddVethPeer(t, peerName, "", ifName, "")
+	peer, err := netlink.LinkByName(peerName)
+	require.NoError(t, err)
+
+	handle, err := netlink.NewHandleAt(netNS)
+	require.NoError(t, err)
+	defer handle.Delete()
+	link, err := handle.LinkByName(ifName)
+	require.NoError(t, err)
+
+	ipv4Net, err := netlink.ParseIPNet("172.16.1.1/32")
+	require.NoError(t, err)
+	ipv6Net, err := netlink.ParseIPNet("fd00::1/128")
+	require.NoError(t, err)
+	require.NoError(t, handle.AddrAdd(link, &netlink.Addr{IPNet: ipv4Net}))
+	require.NoError(t, handle.AddrAdd(link, &netlink.Addr{IPNet: ipv6Net, Flags: unix.IFA_F_NODAD}))
+
+	fd := capture(t, peer)
+	defer func() { _ = unix.Close(fd) }()
+
+	require.NoError(t, announce.AddrsAt(netNS, link, []net.IP{ipv4Net.IP}))
+	require.True(t, received(fd, ipv4Net.IP))
+
+	require.NoError(t, announce.AddrsAt(netNS, link, []net.IP{ipv6Net.IP}))
+	require.True(t, received(fd, ipv6Net.IP))
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package announce
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+const (
+	ethHeaderLen = 14
+	arpLen       = 28
+	arpRequest   = 1
+
+	icmpv6NeighborAdvertisement = 136
+	naFlagOverride              = 0x20000000
+	ndOptTargetLinkLayerAddr    = 2
+	ndHopLimit                  = 255
+)
+
+var allNodes = net.ParseIP("ff02::1")
+
+// socketFunc opens a new socket
+type socketFunc func(domain, typ, proto int) (int, error)
+
+func addrs(ifName string, ips []net.IP) error {
+	iface, err := net.InterfaceByName(ifName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+	return send(unix.Socket, iface, ips)
+}
+
+func addrsAt(netNS netns.NsHandle, link netlink.Link, ips []net.IP) error {
+	curNetNS, err := nshandle.Current()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = curNetNS.Close() }()
+
+	socketAt := func(domain, typ, proto int) (fd int, err error) {
+		err = nshandle.RunIn(curNetNS, netNS, func() (socketErr error) {
+			fd, socketErr = unix.Socket(domain, typ, proto)
+			return socketErr
+		})
+		return fd, err
+	}
+	return send(socketAt, &net.Interface{
+		Index:        link.Attrs().Index,
+		Name:         link.Attrs().Name,
+		HardwareAddr: link.Attrs().HardwareAddr,
+	}, ips)
+}
+
+func send(socket socketFunc, iface *net.Interface, ips []net.IP) error {
+	if len(iface.HardwareAddr) != 6 {
+		// there are no neighbors to notify on the L3 net interfaces
+		return nil
+	}
+
+	var err error
+	for _, ip := range ips {
+		if ip4 := ip.To4(); ip4 != nil {
+			err = sendARP(socket, iface, ip4)
+		} else {
+			err = sendNA(socket, iface, ip)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendARP sends gratuitous ARP in the ARP request form (RFC 5227 ARP announcement), it updates the existing entries
+// on the peers which ignore the gratuitous ARP replies
+func sendARP(socket socketFunc, iface *net.Interface, ip net.IP) error {
+	fd, err := socket(unix.AF_PACKET, unix.SOCK_RAW|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return errors.Wrap(err, "failed to open packet socket")
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	frame := make([]byte, ethHeaderLen+arpLen)
+	for i := 0; i < 6; i++ {
+		frame[i] = 0xff
+	}
+	copy(frame[6:], iface.HardwareAddr)
+	binary.BigEndian.PutUint16(frame[12:], unix.ETH_P_ARP)
+
+	arp := frame[ethHeaderLen:]
+	binary.BigEndian.PutUint16(arp[0:], unix.ARPHRD_ETHER)
+	binary.BigEndian.PutUint16(arp[2:], unix.ETH_P_IP)
+	arp[4], arp[5] = 6, 4
+	binary.BigEndian.PutUint16(arp[6:], arpRequest)
+	copy(arp[8:], iface.HardwareAddr)
+	copy(arp[14:], ip)
+	copy(arp[24:], ip)
+
+	sa := &unix.SockaddrLinklayer{
+		Protocol: htons(unix.ETH_P_ARP),
+		Ifindex:  iface.Index,
+		Halen:    6,
+	}
+	copy(sa.Addr[:], frame[:6])
+	if err := unix.Sendto(fd, frame, 0, sa); err != nil {
+		return errors.Wrapf(err, "failed to send gratuitous ARP: %v %v", iface.Name, ip)
+	}
+	return nil
+}
+
+// sendNA sends unsolicited neighbor advertisement with the override flag to all nodes (RFC 4861 7.2.6), kernel
+// computes the ICMPv6 checksum and selects the source address. The net interface name is resolved in the socket net NS.
+func sendNA(socket socketFunc, iface *net.Interface, ip net.IP) error {
+	fd, err := socket(unix.AF_INET6, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.IPPROTO_ICMPV6)
+	if err != nil {
+		return errors.Wrap(err, "failed to open ICMPv6 socket")
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	if err := unix.BindToDevice(fd, iface.Name); err != nil {
+		return errors.Wrapf(err, "failed to bind socket to net interface: %v", iface.Name)
+	}
+	if err := unix.SetsockoptInt(fd, unix.IPPROTO_IPV6, unix.IPV6_MULTICAST_HOPS, ndHopLimit); err != nil {
+		return errors.Wrap(err, "failed to set socket multicast hop limit")
+	}
+
+	na := make([]byte, 32)
+	na[0] = icmpv6NeighborAdvertisement
+	binary.BigEndian.PutUint32(na[4:], naFlagOverride)
+	copy(na[8:], ip.To16())
+	na[24], na[25] = ndOptTargetLinkLayerAddr, 1
+	copy(na[26:], iface.HardwareAddr)
+
+	sa := &unix.SockaddrInet6{ZoneId: uint32(iface.Index)}
+	copy(sa.Addr[:], allNodes)
+	if err := unix.Sendto(fd, na, 0, sa); err != nil {
+		return errors.Wrapf(err, "failed to send unsolicited neighbor advertisement: %v %v", iface.Name, ip)
+	}
+	return nil
+}
+
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
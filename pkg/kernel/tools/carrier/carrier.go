@@ -0,0 +1,36 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package carrier provides utils for forcing carrier down on the net interfaces, so the consumers can test their
+// healing behavior against the link loss
+package carrier
+
+import (
+	"github.com/vishvananda/netns"
+)
+
+// Down forces carrier down on the net interface in the given net NS:
+//   - veth carrier is put down by setting its peer admin state down, the peer is looked up in the net interface net NS
+//     and in the current net NS;
+//   - other net interfaces carrier is set with IFLA_CARRIER, it is supported only by some drivers (e.g. dummy).
+func Down(netNS netns.NsHandle, ifName string) error {
+	return set(netNS, ifName, false)
+}
+
+// Up reverts Down
+func Up(netNS netns.NsHandle, ifName string) error {
+	return set(netNS, ifName, true)
+}
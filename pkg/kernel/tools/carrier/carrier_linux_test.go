@@ -0,0 +1,50 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carrier_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/carrier"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName   = "carrier-1"
+	peerName = "carrier-2"
+)
+
+func isUp(t *testing.T, name string) bool {
+	link, err := netlink.LinkByName(name)
+	require.NoError(t, err)
+	return link.Attrs().Flags&net.FlagUp != 0
+}
+
+func TestDown_VethPeerInCurrentNetNS(t *testing.T) {
+	// the carrier of the net interface in netNS is its veth peer in the current net NS
+	netNS := kerneltest.AddVethPeer(t, peerName, "", ifName, "")
+
+	require.NoError(t, carrier.Down(netNS, ifName))
+	require.False(t, isUp(t, peerName))
+
+	require.NoError(t, carrier.Up(netNS, ifName))
+	require.True(t, isUp(t, peerName))
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carrier
+
+import (
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+func set(netNS netns.NsHandle, ifName string, up bool) error {
+	curNetNS, err := nshandle.Current()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = curNetNS.Close() }()
+
+	var index, peerIndex int
+	var done bool
+	if err = nshandle.RunIn(curNetNS, netNS, func() error {
+		link, err := netlink.LinkByName(ifName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+		}
+
+		veth, ok := link.(*netlink.Veth)
+		if !ok {
+			done = true
+			return setCarrier(link, up)
+		}
+
+		index = link.Attrs().Index
+		if peerIndex, err = netlink.VethPeerIndex(veth); err != nil {
+			return errors.Wrapf(err, "failed to get veth peer index: %v", ifName)
+		}
+
+		if peer, ok := vethPeer(peerIndex, index); ok {
+			done = true
+			return setAdminState(peer, up)
+		}
+		return nil
+	}); err != nil || done {
+		return err
+	}
+
+	peer, ok := vethPeer(peerIndex, index)
+	if !ok {
+		return errors.Errorf("veth peer is found neither in the net interface net NS nor in the current net NS: %v", ifName)
+	}
+	return setAdminState(peer, up)
+}
+
+// vethPeer returns veth with the given index in the current net NS if its peer index is equal to the given one
+func vethPeer(index, peerIndex int) (netlink.Link, bool) {
+	link, err := netlink.LinkByIndex(index)
+	if err != nil {
+		return nil, false
+	}
+	veth, ok := link.(*netlink.Veth)
+	if !ok {
+		return nil, false
+	}
+	if vethPeerIndex, err := netlink.VethPeerIndex(veth); err != nil || vethPeerIndex != peerIndex {
+		return nil, false
+	}
+	return link, true
+}
+
+func setAdminState(link netlink.Link, up bool) error {
+	if up {
+		if err := netlink.LinkSetUp(link); err != nil {
+			return errors.Wrapf(err, "failed to set up net interface: %v", link.Attrs().Name)
+		}
+		return nil
+	}
+	if err := netlink.LinkSetDown(link); err != nil {
+		return errors.Wrapf(err, "failed to set down net interface: %v", link.Attrs().Name)
+	}
+	return nil
+}
+
+// setCarrier sets IFLA_CARRIER. Equivalent to: `ip link set $ifName carrier on|off`
+func setCarrier(link netlink.Link, up bool) error {
+	req := nl.NewNetlinkRequest(unix.RTM_SETLINK, unix.NLM_F_ACK)
+
+	msg := nl.NewIfInfomsg(unix.AF_UNSPEC)
+	msg.Index = int32(link.Attrs().Index)
+	req.AddData(msg)
+
+	var value uint8
+	if up {
+		value = 1
+	}
+	req.AddData(nl.NewRtAttr(unix.IFLA_CARRIER, nl.Uint8Attr(value)))
+
+	if _, err := req.Execute(unix.NETLINK_ROUTE, 0); err != nil {
+		return errors.Wrapf(err, "failed to set net interface carrier: %v %v", link.Attrs().Name, up)
+	}
+	return nil
+}
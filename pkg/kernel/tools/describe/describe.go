@@ -0,0 +1,126 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package describe provides API for reading back the effective kernel configuration of the connections
+package describe
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// KernelState is the effective kernel configuration of the connection net interface read from the live kernel
+type KernelState struct {
+	NetNSURL  string
+	Link      *Link
+	Addrs     []string
+	Routes    []*Route
+	Neighbors []*Neighbor
+	Qdiscs    []*Qdisc
+}
+
+// Link is the net interface attributes
+type Link struct {
+	Name         string
+	Index        int
+	Type         string
+	MTU          int
+	HardwareAddr string
+	Alias        string
+	Up           bool
+	OperState    string
+}
+
+// Route is the net interface route
+type Route struct {
+	Dst   string
+	Gw    string
+	Src   string
+	Table int
+}
+
+// Neighbor is the net interface neighbor
+type Neighbor struct {
+	IP           string
+	HardwareAddr string
+	State        int
+}
+
+// Qdisc is the net interface qdisc
+type Qdisc struct {
+	Type   string
+	Handle string
+	Parent string
+}
+
+type entry struct {
+	netNSURL string
+	ifName   string
+}
+
+// Registry tracks the connections net interfaces, it should be filled with the networkservice/describe chain element
+type Registry struct {
+	entries sync.Map
+}
+
+// NewRegistry returns a new Registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Describe reads the effective kernel configuration of the connection net interface
+func (r *Registry) Describe(ctx context.Context, connID string) (*KernelState, error) {
+	raw, ok := r.entries.Load(connID)
+	if !ok {
+		return nil, errors.Errorf("unknown connection: %v", connID)
+	}
+	e := raw.(*entry)
+
+	state, err := read(ctx, e.netNSURL, e.ifName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to describe connection: %v", connID)
+	}
+	state.NetNSURL = e.netNSURL
+
+	return state, nil
+}
+
+// Connections returns IDs of the registered connections
+func (r *Registry) Connections() []string {
+	var connIDs []string
+	r.entries.Range(func(key, _ interface{}) bool {
+		connIDs = append(connIDs, key.(string))
+		return true
+	})
+	sort.Strings(connIDs)
+	return connIDs
+}
+
+// Register registers the connection net interface in the net NS, empty net NS URL means the current net NS
+func (r *Registry) Register(connID, netNSURL, ifName string) {
+	r.entries.Store(connID, &entry{
+		netNSURL: netNSURL,
+		ifName:   ifName,
+	})
+}
+
+// Unregister unregisters the connection net interface
+func (r *Registry) Unregister(connID string) {
+	r.entries.Delete(connID)
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package describe_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/describe"
+)
+
+const (
+	ifName   = "describe-1"
+	peerName = "describe-2"
+)
+
+func TestRegistry_Describe(t *testing.T) {
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: ifName, MTU: 1400},
+		PeerName:  peerName,
+	}))
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	defer func() { _ = netlink.LinkDel(link) }()
+
+	addr, err := netlink.ParseAddr("10.0.0.1/24")
+	require.NoError(t, err)
+	require.NoError(t, netlink.AddrAdd(link, addr))
+
+	registry := describe.NewRegistry()
+	registry.Register("conn-1", "file:///proc/self/ns/net", ifName)
+	require.Equal(t, []string{"conn-1"}, registry.Connections())
+
+	state, err := registry.Describe(context.TODO(), "conn-1")
+	require.NoError(t, err)
+	require.Equal(t, ifName, state.Link.Name)
+	require.Equal(t, "veth", state.Link.Type)
+	require.Equal(t, 1400, state.Link.MTU)
+	require.Equal(t, []string{"10.0.0.1/24"}, state.Addrs)
+
+	registry.Unregister("conn-1")
+
+	_, err = registry.Describe(context.TODO(), "conn-1")
+	require.Error(t, err)
+}
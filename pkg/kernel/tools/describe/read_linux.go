@@ -0,0 +1,125 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package describe
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+func read(_ context.Context, netNSURL, ifName string) (*KernelState, error) {
+	netNS, err := nshandle.FromURL(netNSURL)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = netNS.Close() }()
+
+	handle, err := netlink.NewHandleAt(netNS)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create netlink handle in net NS: %v", netNSURL)
+	}
+	defer handle.Delete()
+
+	link, err := handle.LinkByName(ifName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+
+	attrs := link.Attrs()
+	state := &KernelState{
+		Link: &Link{
+			Name:         attrs.Name,
+			Index:        attrs.Index,
+			Type:         link.Type(),
+			MTU:          attrs.MTU,
+			HardwareAddr: attrs.HardwareAddr.String(),
+			Alias:        attrs.Alias,
+			Up:           attrs.Flags&net.FlagUp != 0,
+			OperState:    attrs.OperState.String(),
+		},
+	}
+
+	addrs, err := handle.AddrList(link, kernel.FamilyAll)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the net interface IP addresses: %v", ifName)
+	}
+	for i := range addrs {
+		state.Addrs = append(state.Addrs, addrs[i].IPNet.String())
+	}
+
+	// unspecified table filter stands for all the tables
+	routes, err := handle.RouteListFiltered(kernel.FamilyAll, &netlink.Route{
+		LinkIndex: attrs.Index,
+	}, netlink.RT_FILTER_OIF|netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the net interface routes: %v", ifName)
+	}
+	for i := range routes {
+		state.Routes = append(state.Routes, &Route{
+			Dst:   ipNetString(routes[i].Dst),
+			Gw:    ipString(routes[i].Gw),
+			Src:   ipString(routes[i].Src),
+			Table: routes[i].Table,
+		})
+	}
+
+	neighbors, err := handle.NeighList(attrs.Index, kernel.FamilyAll)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the net interface neighbors: %v", ifName)
+	}
+	for i := range neighbors {
+		state.Neighbors = append(state.Neighbors, &Neighbor{
+			IP:           ipString(neighbors[i].IP),
+			HardwareAddr: neighbors[i].HardwareAddr.String(),
+			State:        neighbors[i].State,
+		})
+	}
+
+	qdiscs, err := handle.QdiscList(link)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the net interface qdiscs: %v", ifName)
+	}
+	for _, qdisc := range qdiscs {
+		state.Qdiscs = append(state.Qdiscs, &Qdisc{
+			Type:   qdisc.Type(),
+			Handle: netlink.HandleStr(qdisc.Attrs().Handle),
+			Parent: netlink.HandleStr(qdisc.Attrs().Parent),
+		})
+	}
+
+	return state, nil
+}
+
+func ipString(ip net.IP) string {
+	if ip == nil {
+		return ""
+	}
+	return ip.String()
+}
+
+func ipNetString(ipNet *net.IPNet) string {
+	if ipNet == nil {
+		return ""
+	}
+	return ipNet.String()
+}
@@ -0,0 +1,731 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package desiredstate
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/neighbor"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/optime"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/strict"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+// defaultIPv6Priority is the kernel IP6_RT_PRIO_USER route metric
+const defaultIPv6Priority = 1024
+
+// op is a single kernel change with its inverse used for the rollback and the read-back check used in the strict
+// verification mode
+type op struct {
+	name   string
+	object interface{}
+	do     func() error
+	undo   func() error
+	verify func() (bool, error)
+}
+
+// Reconcile makes the kernel state match desired. Kernel objects of applied missing in desired are deleted, MTUs and
+// sysctls missing in desired are restored to their original values. Missing desired objects are added, so
+// Reconcile(ctx, applied, applied) resyncs the kernel state drifted from the applied one. nil applied means there is
+// no applied state, nil desired removes the applied state. Reconcile returns the new applied state, on failure all the
+// changes are rolled back, so applied remains the applied state.
+func Reconcile(ctx context.Context, applied, desired *KernelDesiredState) (*KernelDesiredState, error) {
+	switch {
+	case applied == nil && desired == nil:
+		return nil, nil
+	case applied == nil:
+		applied = New(desired.NetNSURL)
+	case desired == nil:
+		desired = New(applied.NetNSURL)
+	case applied.NetNSURL != desired.NetNSURL:
+		// objects can't be moved between the net NSs, so the applied state is removed first
+		if _, err := Reconcile(ctx, applied, nil); err != nil {
+			return nil, err
+		}
+		applied = New(desired.NetNSURL)
+	}
+
+	netNS, err := netNSHandle(desired.NetNSURL)
+	if err != nil {
+		if os.IsNotExist(errors.Cause(err)) && desired.isEmpty() {
+			// everything is deleted with the net NS
+			return New(desired.NetNSURL), nil
+		}
+		return nil, err
+	}
+	defer func() { _ = netNS.Close() }()
+
+	handle, err := netlink.NewHandleAt(netNS)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create netlink handle in net NS: %v", desired.NetNSURL)
+	}
+	defer handle.Delete()
+
+	setDefaults(desired)
+
+	p := &planner{
+		netNS:    netNS,
+		handle:   handle,
+		netNSURL: desired.NetNSURL,
+		result: &KernelDesiredState{
+			NetNSURL:        desired.NetNSURL,
+			Links:           desired.Links,
+			Rules:           desired.Rules,
+			Sysctls:         desired.Sysctls,
+			originalMTUs:    make(map[string]int),
+			originalSysctls: make(map[string]string),
+		},
+		addrsDeleted: make(map[string]bool),
+	}
+	if err := p.planRemove(applied, desired); err != nil {
+		return nil, err
+	}
+	if err := p.planAdd(applied, desired); err != nil {
+		return nil, err
+	}
+
+	for i, o := range p.ops {
+		if err := optime.Time(ctx, o.name, o.object, o.do); err != nil {
+			rollback(ctx, p.ops[:i])
+			return nil, err
+		}
+		if o.verify == nil {
+			continue
+		}
+		if err := strict.Verify(ctx, o.name, o.object, o.verify); err != nil {
+			rollback(ctx, p.ops[:i+1])
+			return nil, err
+		}
+	}
+	return p.result, nil
+}
+
+// Drift returns the number of the kernel objects of applied missing in the live kernel state: IP addresses, routes,
+// neighbors and rules of the net interfaces, the already deleted net interfaces are skipped
+func Drift(applied *KernelDesiredState) (int, error) {
+	if applied == nil || applied.isEmpty() {
+		return 0, nil
+	}
+
+	netNS, err := netNSHandle(applied.NetNSURL)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = netNS.Close() }()
+
+	handle, err := netlink.NewHandleAt(netNS)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to create netlink handle in net NS: %v", applied.NetNSURL)
+	}
+	defer handle.Delete()
+
+	p := &planner{
+		handle:   handle,
+		netNSURL: applied.NetNSURL,
+	}
+
+	var drift int
+	for _, ifName := range linkNames(applied) {
+		live, err := p.liveLink(ifName)
+		if err != nil {
+			return 0, err
+		}
+		if live == nil {
+			continue
+		}
+		appliedLink := applied.Links[ifName]
+		for _, addr := range appliedLink.Addrs {
+			if !containsAddr(toAddrPtrs(live.addrs), addr) {
+				drift++
+			}
+		}
+		for _, route := range appliedLink.Routes {
+			if !containsRoute(toRoutePtrs(live.routes), withRouteLinkIndex(route, live.link)) {
+				drift++
+			}
+		}
+		for _, neigh := range appliedLink.Neighbors {
+			if !containsNeigh(toNeighPtrs(live.neighbors), withLinkIndex(neigh, live.link)) {
+				drift++
+			}
+		}
+	}
+	if len(applied.Rules) != 0 {
+		live, err := listRules(handle)
+		if err != nil {
+			return 0, err
+		}
+		for _, rule := range applied.Rules {
+			if !containsRule(live, rule) {
+				drift++
+			}
+		}
+	}
+	return drift, nil
+}
+
+// rollback undoes the done ops in the reverse order, it is a best effort: rollback failures are only logged
+func rollback(ctx context.Context, done []*op) {
+	for i := len(done) - 1; i >= 0; i-- {
+		if err := done[i].undo(); err != nil {
+			log.Entry(ctx).WithField("desiredstate", "rollback").Warnf("failed to roll back %s: %s", done[i].name, err.Error())
+		}
+	}
+}
+
+type planner struct {
+	netNS    netns.NsHandle
+	handle   *netlink.Handle
+	netNSURL string
+	ops      []*op
+	result   *KernelDesiredState
+	// addrsDeleted are the net interfaces with the IP addresses planned to delete
+	addrsDeleted map[string]bool
+}
+
+// liveLink is the live kernel state of the net interface
+type liveLink struct {
+	link      netlink.Link
+	addrs     []netlink.Addr
+	routes    []netlink.Route
+	neighbors []netlink.Neigh
+}
+
+func (p *planner) add(o *op) {
+	p.ops = append(p.ops, o)
+}
+
+// planRemove plans removal of the applied objects missing in desired, it skips the already deleted net interfaces
+func (p *planner) planRemove(applied, desired *KernelDesiredState) error {
+	for _, ifName := range linkNames(applied) {
+		ifName := ifName
+		appliedLink := applied.Links[ifName]
+		desiredLink, ok := desired.Links[ifName]
+		if !ok {
+			desiredLink = new(LinkState)
+		}
+
+		live, err := p.liveLink(ifName)
+		if err != nil {
+			return err
+		}
+		if live == nil {
+			continue
+		}
+
+		var neighs []*netlink.Neigh
+		for _, neigh := range appliedLink.Neighbors {
+			neigh := withLinkIndex(neigh, live.link)
+			if !containsNeigh(desiredLink.Neighbors, neigh) && containsNeigh(toNeighPtrs(live.neighbors), neigh) {
+				neighs = append(neighs, neigh)
+			}
+		}
+		if len(neighs) != 0 {
+			p.add(&op{
+				name:   "NeighDel",
+				object: neighs,
+				do: func() error {
+					return errors.Wrapf(neighbor.DeleteAt(p.netNS, neighs), "failed to delete neighbors: %v", ifName)
+				},
+				undo: func() error { return neighbor.SetAt(p.netNS, neighs) },
+			})
+		}
+		for _, route := range appliedLink.Routes {
+			route := withRouteLinkIndex(route, live.link)
+			if !containsRoute(desiredLink.Routes, route) && containsRoute(toRoutePtrs(live.routes), route) {
+				p.add(&op{
+					name:   "RouteDel",
+					object: route,
+					do:     func() error { return errors.Wrapf(p.handle.RouteDel(route), "failed to delete route: %v", route) },
+					undo:   func() error { return p.handle.RouteAdd(route) },
+				})
+			}
+		}
+		for _, addr := range appliedLink.Addrs {
+			addr, link := addr, live.link
+			if !containsAddr(desiredLink.Addrs, addr) && containsAddr(toAddrPtrs(live.addrs), addr) {
+				p.addrsDeleted[ifName] = true
+				p.add(&op{
+					name:   "AddrDel",
+					object: addr,
+					do: func() error {
+						return errors.Wrapf(p.handle.AddrDel(link, addr), "failed to delete IP address: %v %v", ifName, addr)
+					},
+					undo: func() error { return p.handle.AddrAdd(link, addr) },
+				})
+			}
+		}
+		if original, ok := applied.originalMTUs[ifName]; ok && desiredLink.MTU == 0 {
+			p.setMTU(live.link, original)
+		}
+	}
+
+	if len(applied.Rules) != 0 {
+		live, err := listRules(p.handle)
+		if err != nil {
+			return err
+		}
+		for _, rule := range applied.Rules {
+			rule := rule
+			if !containsRule(desired.Rules, rule) && containsRule(live, rule) {
+				p.add(&op{
+					name:   "RuleDel",
+					object: rule,
+					do:     func() error { return errors.Wrapf(p.handle.RuleDel(rule), "failed to delete rule: %v", rule) },
+					undo:   func() error { return p.handle.RuleAdd(rule) },
+				})
+			}
+		}
+	}
+
+	for _, name := range sysctlNames(applied.originalSysctls) {
+		if _, ok := desired.Sysctls[name]; ok {
+			continue
+		}
+		current, err := p.getSysctl(name)
+		if os.IsNotExist(errors.Cause(err)) {
+			// per interface sysctls are deleted with the net interface
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		p.setSysctl(name, applied.originalSysctls[name], current)
+	}
+
+	return nil
+}
+
+// planAdd plans adding of the desired objects missing in the live kernel state. Sysctls are set first, they can
+// enable the address families, routes are added after all the IP addresses, so the routes preferred sources exist,
+// rules are added after all the routes, so the looked up route tables are complete.
+func (p *planner) planAdd(applied, desired *KernelDesiredState) error {
+	for _, name := range sysctlNames(desired.Sysctls) {
+		current, err := p.getSysctl(name)
+		if err != nil {
+			return err
+		}
+		original, ok := applied.originalSysctls[name]
+		if !ok {
+			original = current
+		}
+		p.result.originalSysctls[name] = original
+		if current != desired.Sysctls[name] {
+			p.setSysctl(name, desired.Sysctls[name], current)
+		}
+	}
+
+	lives := make(map[string]*liveLink, len(desired.Links))
+	ifNames := linkNames(desired)
+	for _, ifName := range ifNames {
+		ifName := ifName
+		live, err := p.liveLink(ifName)
+		if err != nil {
+			return err
+		}
+		if live == nil {
+			return errors.Errorf("net interface not found: %v", ifName)
+		}
+		lives[ifName] = live
+
+		desiredLink := desired.Links[ifName]
+		if desiredLink.MTU != 0 {
+			original, ok := applied.originalMTUs[ifName]
+			if !ok {
+				original = live.link.Attrs().MTU
+			}
+			p.result.originalMTUs[ifName] = original
+			if live.link.Attrs().MTU != desiredLink.MTU {
+				p.setMTU(live.link, desiredLink.MTU)
+			}
+		}
+		if link := live.link; desiredLink.Up && link.Attrs().Flags&net.FlagUp == 0 {
+			p.add(&op{
+				name:   "LinkSetUp",
+				object: ifName,
+				do: func() error {
+					return errors.Wrapf(p.handle.LinkSetUp(link), "failed to set up net interface: %v", ifName)
+				},
+				undo: func() error { return p.handle.LinkSetDown(link) },
+			})
+		}
+	}
+
+	for _, ifName := range ifNames {
+		live := lives[ifName]
+		for _, addr := range desired.Links[ifName].Addrs {
+			addr, link, ifName := addr, live.link, ifName
+			if !containsAddr(toAddrPtrs(live.addrs), addr) {
+				p.add(&op{
+					name:   "AddrAdd",
+					object: addr,
+					do: func() error {
+						return errors.Wrapf(p.handle.AddrAdd(link, addr), "failed to add IP address: %v %v", ifName, addr)
+					},
+					undo: func() error { return p.handle.AddrDel(link, addr) },
+					verify: func() (bool, error) {
+						list, err := p.handle.AddrList(link, kernel.FamilyAll)
+						return containsAddr(toAddrPtrs(list), addr), err
+					},
+				})
+			}
+		}
+	}
+
+	for _, ifName := range ifNames {
+		live := lives[ifName]
+		desiredLink := desired.Links[ifName]
+		for i, route := range desiredLink.Routes {
+			route, link := withRouteLinkIndex(route, live.link), live.link
+			desiredLink.Routes[i] = route
+			if !containsRoute(toRoutePtrs(live.routes), route) {
+				p.add(&op{
+					name:   "RouteAdd",
+					object: route,
+					do:     func() error { return errors.Wrapf(p.handle.RouteAdd(route), "failed to add route: %v", route) },
+					undo:   func() error { return p.handle.RouteDel(route) },
+					verify: func() (bool, error) {
+						list, err := listRoutes(p.handle, link)
+						return containsRoute(toRoutePtrs(list), route), err
+					},
+				})
+			}
+		}
+		p.setNeighbors(ifName, live, desiredLink)
+	}
+
+	if len(desired.Rules) != 0 {
+		live, err := listRules(p.handle)
+		if err != nil {
+			return err
+		}
+		for _, rule := range desired.Rules {
+			rule := rule
+			if !containsRule(live, rule) {
+				p.add(&op{
+					name:   "RuleAdd",
+					object: rule,
+					do:     func() error { return errors.Wrapf(p.handle.RuleAdd(rule), "failed to add rule: %v", rule) },
+					undo:   func() error { return p.handle.RuleDel(rule) },
+					verify: func() (bool, error) {
+						list, err := listRules(p.handle)
+						return containsRule(list, rule), err
+					},
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+// setNeighbors plans setting of the desired net interface neighbors missing in the live kernel state, they are set
+// in batches, so setting hundreds of neighbors doesn't cost hundreds of netlink round trips. Deleting the last IPv4
+// address of the net interface flushes its neighbors, so all the desired ones are set if the IP addresses are deleted.
+func (p *planner) setNeighbors(ifName string, live *liveLink, desiredLink *LinkState) {
+	var neighs []*netlink.Neigh
+	for i, neigh := range desiredLink.Neighbors {
+		neigh := withLinkIndex(neigh, live.link)
+		desiredLink.Neighbors[i] = neigh
+		if p.addrsDeleted[ifName] || !containsNeigh(toNeighPtrs(live.neighbors), neigh) {
+			neighs = append(neighs, neigh)
+		}
+	}
+	if len(neighs) == 0 {
+		return
+	}
+	index := live.link.Attrs().Index
+	p.add(&op{
+		name:   "NeighSet",
+		object: neighs,
+		do: func() error {
+			return errors.Wrapf(neighbor.SetAt(p.netNS, neighs), "failed to set neighbors: %v", ifName)
+		},
+		undo: func() error { return neighbor.DeleteAt(p.netNS, neighs) },
+		verify: func() (bool, error) {
+			list, err := p.handle.NeighList(index, kernel.FamilyAll)
+			for _, neigh := range neighs {
+				if !containsNeigh(toNeighPtrs(list), neigh) {
+					return false, err
+				}
+			}
+			return true, err
+		},
+	})
+}
+
+func (p *planner) setMTU(link netlink.Link, mtu int) {
+	current := link.Attrs().MTU
+	p.add(&op{
+		name:   "LinkSetMTU",
+		object: link.Attrs().Name + " " + strconv.Itoa(mtu),
+		do: func() error {
+			return errors.Wrapf(p.handle.LinkSetMTU(link, mtu), "failed to set MTU: %v %v", link.Attrs().Name, mtu)
+		},
+		undo: func() error { return p.handle.LinkSetMTU(link, current) },
+	})
+}
+
+func (p *planner) setSysctl(name, value, current string) {
+	p.add(&op{
+		name:   "SysctlSet",
+		object: name + " = " + value,
+		do:     func() error { return p.runIn(func() error { return sysctl.Set(name, value) }) },
+		undo:   func() error { return p.runIn(func() error { return sysctl.Set(name, current) }) },
+	})
+}
+
+func (p *planner) getSysctl(name string) (value string, err error) {
+	err = p.runIn(func() (err error) {
+		value, err = sysctl.Get(name)
+		return err
+	})
+	return value, err
+}
+
+// runIn runs runner in the state net NS, sysctls are accessible only from inside the net NS
+func (p *planner) runIn(runner func() error) error {
+	if p.netNSURL == "" {
+		return runner()
+	}
+	return nshandle.RunInURL(p.netNSURL, runner)
+}
+
+// liveLink returns the live net interface state, it returns nil if there is no such net interface
+func (p *planner) liveLink(ifName string) (*liveLink, error) {
+	link, err := p.handle.LinkByName(ifName)
+	if _, ok := err.(netlink.LinkNotFoundError); ok {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+
+	live := &liveLink{link: link}
+	if live.addrs, err = p.handle.AddrList(link, kernel.FamilyAll); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the net interface IP addresses: %v", ifName)
+	}
+	if live.routes, err = listRoutes(p.handle, link); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the net interface routes: %v", ifName)
+	}
+	if live.neighbors, err = p.handle.NeighList(link.Attrs().Index, kernel.FamilyAll); err != nil {
+		return nil, errors.Wrapf(err, "failed to get the net interface neighbors: %v", ifName)
+	}
+	return live, nil
+}
+
+// listRules returns the live rules of both families, the kernel doesn't report the rule family, so it is set by the
+// listed one
+func listRules(handle *netlink.Handle) ([]*netlink.Rule, error) {
+	var result []*netlink.Rule
+	for _, family := range []int{kernel.FamilyV4, kernel.FamilyV6} {
+		rules, err := handle.RuleList(family)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get rules")
+		}
+		for i := range rules {
+			rules[i].Family = family
+			result = append(result, &rules[i])
+		}
+	}
+	return result, nil
+}
+
+// setDefaults sets the defaults for the fields not set by the chain elements: routes are marked with the NSM protocol,
+// so they are found by the owned resync, neighbors are permanent
+func setDefaults(s *KernelDesiredState) {
+	for _, l := range s.Links {
+		for _, route := range l.Routes {
+			if route.Protocol == 0 {
+				route.Protocol = kernel.RouteProtoNSM
+			}
+		}
+		for _, neigh := range l.Neighbors {
+			if neigh.State == 0 {
+				neigh.State = kernel.NudPermanent
+			}
+		}
+	}
+}
+
+func (s *KernelDesiredState) isEmpty() bool {
+	return len(s.Links) == 0 && len(s.Rules) == 0 && len(s.Sysctls) == 0
+}
+
+// netNSHandle returns the net NS handle by the net NS URL, empty URL means the current net NS
+func netNSHandle(netNSURL string) (netns.NsHandle, error) {
+	if netNSURL == "" {
+		return nshandle.Current()
+	}
+	return nshandle.FromURL(netNSURL)
+}
+
+func linkNames(s *KernelDesiredState) []string {
+	names := make([]string, 0, len(s.Links))
+	for name := range s.Links {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sysctlNames(sysctls map[string]string) []string {
+	names := make([]string, 0, len(sysctls))
+	for name := range sysctls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func withLinkIndex(neigh *netlink.Neigh, link netlink.Link) *netlink.Neigh {
+	n := *neigh
+	n.LinkIndex = link.Attrs().Index
+	return &n
+}
+
+// withRouteLinkIndex sets the route net interface, multipath route has no top level net interface, so it is set for
+// all the nexthops
+func withRouteLinkIndex(route *netlink.Route, link netlink.Link) *netlink.Route {
+	r := *route
+	if len(r.MultiPath) == 0 {
+		r.LinkIndex = link.Attrs().Index
+		return &r
+	}
+	r.LinkIndex = 0
+	r.MultiPath = make([]*netlink.NexthopInfo, 0, len(route.MultiPath))
+	for _, nh := range route.MultiPath {
+		n := *nh
+		n.LinkIndex = link.Attrs().Index
+		r.MultiPath = append(r.MultiPath, &n)
+	}
+	return &r
+}
+
+func toAddrPtrs(addrs []netlink.Addr) []*netlink.Addr {
+	ptrs := make([]*netlink.Addr, 0, len(addrs))
+	for i := range addrs {
+		ptrs = append(ptrs, &addrs[i])
+	}
+	return ptrs
+}
+
+func toRoutePtrs(routes []netlink.Route) []*netlink.Route {
+	ptrs := make([]*netlink.Route, 0, len(routes))
+	for i := range routes {
+		ptrs = append(ptrs, &routes[i])
+	}
+	return ptrs
+}
+
+func toNeighPtrs(neighs []netlink.Neigh) []*netlink.Neigh {
+	ptrs := make([]*netlink.Neigh, 0, len(neighs))
+	for i := range neighs {
+		ptrs = append(ptrs, &neighs[i])
+	}
+	return ptrs
+}
+
+func containsAddr(addrs []*netlink.Addr, addr *netlink.Addr) bool {
+	for _, a := range addrs {
+		if addr.Equal(*a) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsRoute compares routes by the kernel route key: destination, table and metric, and by the gateways and the
+// preferred source
+func containsRoute(routes []*netlink.Route, route *netlink.Route) bool {
+	for _, r := range routes {
+		if dstString(r) == dstString(route) && tableID(r) == tableID(route) && priority(r) == priority(route) &&
+			r.Gw.Equal(route.Gw) && r.Src.Equal(route.Src) && nexthopsString(r) == nexthopsString(route) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsRule compares rules by the matched family, source and firewall mark, table and priority
+func containsRule(rules []*netlink.Rule, rule *netlink.Rule) bool {
+	for _, r := range rules {
+		if r.Family == rule.Family && r.Src.String() == rule.Src.String() && r.Mark == rule.Mark &&
+			r.Table == rule.Table && r.Priority == rule.Priority {
+			return true
+		}
+	}
+	return false
+}
+
+func containsNeigh(neighs []*netlink.Neigh, neigh *netlink.Neigh) bool {
+	for _, n := range neighs {
+		if n.IP.Equal(neigh.IP) && bytes.Equal(n.HardwareAddr, neigh.HardwareAddr) && n.State == neigh.State {
+			return true
+		}
+	}
+	return false
+}
+
+func dstString(route *netlink.Route) string {
+	if route.Dst == nil {
+		return "default"
+	}
+	if ones, _ := route.Dst.Mask.Size(); ones == 0 {
+		return "default"
+	}
+	return route.Dst.String()
+}
+
+// nexthopsString returns the multipath route nexthops gateways and net interfaces in the sorted order
+func nexthopsString(route *netlink.Route) string {
+	nexthops := make([]string, 0, len(route.MultiPath))
+	for _, nh := range route.MultiPath {
+		nexthops = append(nexthops, nh.Gw.String()+"@"+strconv.Itoa(nh.LinkIndex))
+	}
+	sort.Strings(nexthops)
+	return strings.Join(nexthops, " ")
+}
+
+// priority returns the route metric, the kernel sets the default metric for the IPv6 routes added with no one
+func priority(route *netlink.Route) int {
+	if route.Priority == 0 && route.Dst != nil && route.Dst.IP.To4() == nil {
+		return defaultIPv6Priority
+	}
+	return route.Priority
+}
+
+func tableID(route *netlink.Route) int {
+	if route.Table == 0 {
+		return kernel.RouteTableMain
+	}
+	return route.Table
+}
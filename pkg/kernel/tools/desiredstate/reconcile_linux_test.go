@@ -0,0 +1,270 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package desiredstate_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/desiredstate"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+const (
+	ifName      = "desired-1"
+	peerName    = "desired-2"
+	arpIgnore   = "net/ipv4/conf/" + ifName + "/arp_ignore"
+	neighborMAC = "02:00:00:00:00:01"
+)
+
+func addVeth(t *testing.T) netlink.Link {
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: ifName},
+		PeerName:  peerName,
+	}))
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	return link
+}
+
+func delVeth() {
+	_ = netlink.LinkDel(&netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: ifName}})
+}
+
+func parseAddr(t *testing.T, s string) *netlink.Addr {
+	addr, err := netlink.ParseAddr(s)
+	require.NoError(t, err)
+	return addr
+}
+
+func parseIPNet(t *testing.T, s string) *net.IPNet {
+	ipNet, err := netlink.ParseIPNet(s)
+	require.NoError(t, err)
+	return ipNet
+}
+
+func newState(t *testing.T, addr string) *desiredstate.KernelDesiredState {
+	mac, err := net.ParseMAC(neighborMAC)
+	require.NoError(t, err)
+
+	state := desiredstate.New("")
+	link := state.Link(ifName)
+	link.Up = true
+	link.MTU = 1400
+	link.Addrs = append(link.Addrs, parseAddr(t, addr))
+	link.Routes = append(link.Routes, &netlink.Route{
+		Dst:   parseIPNet(t, "172.16.10.0/24"),
+		Scope: netlink.SCOPE_LINK,
+	})
+	link.Neighbors = append(link.Neighbors, &netlink.Neigh{
+		IP:           net.ParseIP("172.16.1.2"),
+		HardwareAddr: mac,
+	})
+	state.SetSysctl(arpIgnore, "1")
+	return state
+}
+
+func requireAddrs(t *testing.T, link netlink.Link, expected ...string) {
+	addrs, err := netlink.AddrList(link, netlink.FAMILY_V4)
+	require.NoError(t, err)
+	var actual []string
+	for i := range addrs {
+		actual = append(actual, addrs[i].IPNet.String())
+	}
+	require.ElementsMatch(t, expected, actual)
+}
+
+func requireRoutes(t *testing.T, link netlink.Link, expected int) {
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_V4, &netlink.Route{
+		LinkIndex: link.Attrs().Index,
+		Protocol:  kernel.RouteProtoNSM,
+	}, netlink.RT_FILTER_OIF|netlink.RT_FILTER_PROTOCOL)
+	require.NoError(t, err)
+	require.Len(t, routes, expected)
+}
+
+func requireSysctl(t *testing.T, expected string) {
+	value, err := sysctl.Get(arpIgnore)
+	require.NoError(t, err)
+	require.Equal(t, expected, value)
+}
+
+func TestReconcile(t *testing.T) {
+	link := addVeth(t)
+	defer delVeth()
+
+	ctx := context.Background()
+
+	applied, err := desiredstate.Reconcile(ctx, nil, newState(t, "172.16.1.1/24"))
+	require.NoError(t, err)
+
+	link, err = netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	require.Equal(t, 1400, link.Attrs().MTU)
+	require.NotZero(t, link.Attrs().Flags&net.FlagUp)
+	requireAddrs(t, link, "172.16.1.1/24")
+	requireRoutes(t, link, 1)
+	neighs, err := netlink.NeighList(link.Attrs().Index, netlink.FAMILY_V4)
+	require.NoError(t, err)
+	require.Len(t, neighs, 1)
+	require.Equal(t, neighborMAC, neighs[0].HardwareAddr.String())
+	requireSysctl(t, "1")
+
+	// resync restores the drifted state
+	require.NoError(t, netlink.AddrDel(link, parseAddr(t, "172.16.1.1/24")))
+	require.NoError(t, sysctl.Set(arpIgnore, "0"))
+	applied, err = desiredstate.Reconcile(ctx, applied, applied)
+	require.NoError(t, err)
+	requireAddrs(t, link, "172.16.1.1/24")
+	requireRoutes(t, link, 1)
+	requireSysctl(t, "1")
+
+	// update replaces the IP address and deletes the route
+	updated := newState(t, "172.16.1.3/24")
+	updated.Link(ifName).Routes = nil
+	applied, err = desiredstate.Reconcile(ctx, applied, updated)
+	require.NoError(t, err)
+	requireAddrs(t, link, "172.16.1.3/24")
+	requireRoutes(t, link, 0)
+
+	// remove restores the original MTU and sysctls
+	_, err = desiredstate.Reconcile(ctx, applied, nil)
+	require.NoError(t, err)
+	link, err = netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	require.Equal(t, 1500, link.Attrs().MTU)
+	requireAddrs(t, link)
+	neighs, err = netlink.NeighList(link.Attrs().Index, netlink.FAMILY_V4)
+	require.NoError(t, err)
+	require.Empty(t, neighs)
+	requireSysctl(t, "0")
+}
+
+func TestReconcile_Rollback(t *testing.T) {
+	link := addVeth(t)
+	defer delVeth()
+
+	state := newState(t, "172.16.1.1/24")
+	// gateway is not reachable via the net interface
+	state.Link(ifName).Routes = append(state.Link(ifName).Routes, &netlink.Route{
+		Dst: parseIPNet(t, "172.16.20.0/24"),
+		Gw:  net.ParseIP("10.0.0.1"),
+	})
+
+	_, err := desiredstate.Reconcile(context.Background(), nil, state)
+	require.Error(t, err)
+
+	link, err = netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	require.Equal(t, 1500, link.Attrs().MTU)
+	requireAddrs(t, link)
+	requireRoutes(t, link, 0)
+	requireSysctl(t, "0")
+}
+
+func TestReconcile_NoLink(t *testing.T) {
+	_, err := desiredstate.Reconcile(context.Background(), nil, newState(t, "172.16.1.1/24"))
+	require.Error(t, err)
+
+	// there is nothing to remove if the net interface has been already deleted
+	applied := newState(t, "172.16.1.1/24")
+	_, err = desiredstate.Reconcile(context.Background(), applied, nil)
+	require.NoError(t, err)
+}
+
+func requireRules(t *testing.T, table, expected int) {
+	rules, err := netlink.RuleList(netlink.FAMILY_V4)
+	require.NoError(t, err)
+	var actual int
+	for i := range rules {
+		if rules[i].Table == table {
+			actual++
+		}
+	}
+	require.Equal(t, expected, actual)
+}
+
+func TestReconcile_RulesAndMultipath(t *testing.T) {
+	link := addVeth(t)
+	defer delVeth()
+
+	const table = 1010
+
+	state := desiredstate.New("")
+	l := state.Link(ifName)
+	l.Up = true
+	l.Addrs = append(l.Addrs, parseAddr(t, "172.16.1.1/32"), parseAddr(t, "fd00:16::1/128"))
+	l.Routes = append(l.Routes,
+		&netlink.Route{
+			Dst:   parseIPNet(t, "172.16.10.0/24"),
+			Src:   net.ParseIP("172.16.1.1"),
+			Scope: netlink.SCOPE_LINK,
+			Table: table,
+		},
+		&netlink.Route{
+			Dst:   parseIPNet(t, "172.16.20.0/24"),
+			Table: table,
+			MultiPath: []*netlink.NexthopInfo{
+				{Gw: net.ParseIP("172.16.1.2"), Flags: int(netlink.FLAG_ONLINK)},
+				{Gw: net.ParseIP("172.16.1.3"), Flags: int(netlink.FLAG_ONLINK)},
+			},
+		},
+		&netlink.Route{
+			Dst:   parseIPNet(t, "fd00:17::/64"),
+			Table: table,
+		},
+	)
+	rule := netlink.NewRule()
+	rule.Family = netlink.FAMILY_V4
+	rule.Src = parseIPNet(t, "172.16.1.1/32")
+	rule.Priority = 200
+	rule.Table = table
+	state.Rules = append(state.Rules, rule)
+
+	ctx := context.Background()
+
+	applied, err := desiredstate.Reconcile(ctx, nil, state)
+	require.NoError(t, err)
+	requireRules(t, table, 1)
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_ALL, &netlink.Route{Table: table}, netlink.RT_FILTER_TABLE)
+	require.NoError(t, err)
+	require.Len(t, routes, 3)
+
+	// resync of the live state is a no-op: the multipath, preferred source and IPv6 routes are found
+	applied, err = desiredstate.Reconcile(ctx, applied, applied)
+	require.NoError(t, err)
+	requireRules(t, table, 1)
+
+	// dropped rule is deleted
+	updated := desiredstate.New("")
+	updated.Links = applied.Links
+	applied, err = desiredstate.Reconcile(ctx, applied, updated)
+	require.NoError(t, err)
+	requireRules(t, table, 0)
+
+	_, err = desiredstate.Reconcile(ctx, applied, nil)
+	require.NoError(t, err)
+	routes, err = netlink.RouteListFiltered(netlink.FAMILY_ALL, &netlink.Route{Table: table}, netlink.RT_FILTER_TABLE)
+	require.NoError(t, err)
+	require.Empty(t, routes)
+	requireAddrs(t, link)
+}
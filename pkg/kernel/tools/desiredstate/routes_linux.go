@@ -0,0 +1,52 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package desiredstate
+
+import (
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+)
+
+// listRoutes returns the net interface routes in all the tables, unspecified table filter stands for all the tables.
+// Multipath routes have no top level net interface, so the routes are filtered by the net interface here, not by the
+// kernel.
+func listRoutes(handle *netlink.Handle, link netlink.Link) ([]netlink.Route, error) {
+	routes, err := handle.RouteListFiltered(kernel.FamilyAll, &netlink.Route{}, netlink.RT_FILTER_TABLE)
+	if err != nil {
+		return nil, err
+	}
+	var result []netlink.Route
+	for i := range routes {
+		if viaLink(&routes[i], link.Attrs().Index) {
+			result = append(result, routes[i])
+		}
+	}
+	return result, nil
+}
+
+func viaLink(route *netlink.Route, index int) bool {
+	if route.LinkIndex == index {
+		return true
+	}
+	for _, nh := range route.MultiPath {
+		if nh.LinkIndex == index {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package desiredstate provides the declarative kernel state of the connection built by the chain elements and the
+// reconciler applying it. Elements only describe the kernel objects they need, the reconciler diffs the description
+// against the live kernel state and the previously applied one, so idempotent refresh, rollback on failure, resync and
+// cleanup on Close are implemented once for all of them. If there is the reconcile chain element in the chain, the
+// ipcontext (IP addresses, routes, neighbors) and mtu chain elements describe their objects in the State from context
+// and the reconcile chain element applies them. Otherwise the routes and neighbors chain elements keep their applied
+// state with Reconcile on their own, and IP addresses are kept by the ipcontext chain element in the net interface
+// alias (see owned), so their ownership survives the Forwarder restart.
+package desiredstate
+
+import (
+	"context"
+
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	stateKey key = "desiredstate.KernelDesiredState"
+)
+
+type key string
+
+// KernelDesiredState is the kernel state of the connection in its net NS
+type KernelDesiredState struct {
+	// NetNSURL is the net NS URL, empty URL means the current net NS
+	NetNSURL string
+	// Links are the net interfaces states by names, net interfaces are not created by the reconciler
+	Links map[string]*LinkState
+	// Rules are the policy routing rules, they are not bound to the net interfaces
+	Rules []*netlink.Rule
+	// Sysctls are the kernel parameters values by names, see sysctl.Path for the names format
+	Sysctls map[string]string
+
+	// originals are the values replaced by the reconciler, they are restored on removal
+	originalMTUs    map[string]int
+	originalSysctls map[string]string
+}
+
+// LinkState is the net interface state
+type LinkState struct {
+	// Up means the net interface should be up, it is not set down on removal
+	Up bool
+	// MTU is the net interface MTU, 0 means the MTU is not managed
+	MTU int
+	// Addrs are the net interface IP addresses
+	Addrs []*netlink.Addr
+	// Routes are the routes via the net interface, LinkIndex (the nexthops ones for the multipath routes) is set by the
+	// reconciler
+	Routes []*netlink.Route
+	// Neighbors are the net interface neighbors, LinkIndex is set by the reconciler
+	Neighbors []*netlink.Neigh
+}
+
+// New returns a new empty KernelDesiredState in the given net NS
+func New(netNSURL string) *KernelDesiredState {
+	return &KernelDesiredState{
+		NetNSURL: netNSURL,
+		Links:    make(map[string]*LinkState),
+		Sysctls:  make(map[string]string),
+	}
+}
+
+// Link returns the net interface state, it is created if there is no such net interface in the state yet
+func (s *KernelDesiredState) Link(ifName string) *LinkState {
+	l, ok := s.Links[ifName]
+	if !ok {
+		l = new(LinkState)
+		s.Links[ifName] = l
+	}
+	return l
+}
+
+// SetSysctl sets the kernel parameter value
+func (s *KernelDesiredState) SetSysctl(name, value string) {
+	s.Sysctls[name] = value
+}
+
+// Merge adds the other state objects to the state, the other state net NS is ignored
+func (s *KernelDesiredState) Merge(other *KernelDesiredState) {
+	if other == nil {
+		return
+	}
+	for ifName, otherLink := range other.Links {
+		link := s.Link(ifName)
+		link.Up = link.Up || otherLink.Up
+		if otherLink.MTU != 0 {
+			link.MTU = otherLink.MTU
+		}
+		link.Addrs = append(link.Addrs, otherLink.Addrs...)
+		link.Routes = append(link.Routes, otherLink.Routes...)
+		link.Neighbors = append(link.Neighbors, otherLink.Neighbors...)
+	}
+	s.Rules = append(s.Rules, other.Rules...)
+	for name, value := range other.Sysctls {
+		s.SetSysctl(name, value)
+	}
+}
+
+// WithState returns a new context with KernelDesiredState for the chain elements building it
+func WithState(parent context.Context, state *KernelDesiredState) context.Context {
+	if parent == nil {
+		parent = context.TODO()
+	}
+	return context.WithValue(parent, stateKey, state)
+}
+
+// State returns KernelDesiredState from context, it returns nil if there is no reconciler in the chain. The chain
+// elements building the state describe their objects in it on Request and leave them to the reconciler on Close.
+func State(ctx context.Context) *KernelDesiredState {
+	if rv, ok := ctx.Value(stateKey).(*KernelDesiredState); ok {
+		return rv
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package devlink provides devlink port and subfunction (SF) management utils
+package devlink
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const netdevPollInterval = 100 * time.Millisecond
+
+var auxiliaryDevicesPath = "/sys/bus/auxiliary/devices"
+
+// Port is a devlink port
+type Port struct {
+	// BusName is a devlink device bus name, e.g. "pci"
+	BusName string
+	// DeviceName is a devlink device name, e.g. PCI address
+	DeviceName string
+	// Index is a devlink port index
+	Index uint32
+	// PFNumber is a PF number the port belongs to
+	PFNumber uint16
+	// SFNumber is a SF number of the SF port
+	SFNumber uint32
+	// NetdevName is a port net interface name, for the SF ports it is the SF representor
+	NetdevName string
+}
+
+// SFNetdevName waits for the SF net interface to appear and returns its name. SF net interface is created
+// asynchronously by the SF auxiliary device driver after the SF port function activation.
+func SFNetdevName(ctx context.Context, pciAddress string, sfNum uint32) (string, error) {
+	for {
+		if name, err := findSFNetdev(pciAddress, sfNum); err == nil {
+			return name, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", errors.Errorf("timeout waiting for the SF net interface: %v %v", pciAddress, sfNum)
+		case <-time.After(netdevPollInterval):
+		}
+	}
+}
+
+func findSFNetdev(pciAddress string, sfNum uint32) (string, error) {
+	infos, err := ioutil.ReadDir(auxiliaryDevicesPath)
+	if err != nil {
+		return "", err
+	}
+	for _, info := range infos {
+		devicePath := filepath.Join(auxiliaryDevicesPath, info.Name())
+
+		data, err := ioutil.ReadFile(filepath.Clean(filepath.Join(devicePath, "sfnum")))
+		if err != nil || strings.TrimSpace(string(data)) != strconv.FormatUint(uint64(sfNum), 10) {
+			continue
+		}
+
+		// auxiliary device lives under its parent PCI device: .../<pciAddress>/<auxiliaryDevice>
+		realPath, err := filepath.EvalSymlinks(devicePath)
+		if err != nil || filepath.Base(filepath.Dir(realPath)) != pciAddress {
+			continue
+		}
+
+		netInfos, err := ioutil.ReadDir(filepath.Join(devicePath, "net"))
+		if err != nil || len(netInfos) == 0 {
+			continue
+		}
+		return netInfos[0].Name(), nil
+	}
+	return "", errors.Errorf("SF net interface not found: %v %v", pciAddress, sfNum)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devlink_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/devlink"
+)
+
+const pciAddress = "0000:03:00.0"
+
+// addSF creates a fake SF auxiliary device of the PCI device with the net interface
+func addSF(t *testing.T, root, pciAddress, name, sfNum, ifName string) {
+	devicePath := filepath.Join(root, "devices", pciAddress, name)
+	require.NoError(t, os.MkdirAll(filepath.Join(devicePath, "net", ifName), 0o700))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(devicePath, "sfnum"), []byte(sfNum+"\n"), 0o600))
+	require.NoError(t, os.Symlink(devicePath, filepath.Join(root, "auxiliary", name)))
+}
+
+func TestSFNetdevName(t *testing.T) {
+	root, err := ioutil.TempDir("", "devlink")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(root) }()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "auxiliary"), 0o700))
+	addSF(t, root, pciAddress, "mlx5_core.sf.2", "5", "eth5")
+	// the SF with the number 6 exists only on another PCI device
+	addSF(t, root, "0000:04:00.0", "mlx5_core.sf.3", "6", "eth6")
+
+	defer devlink.SetAuxiliaryDevicesPath(filepath.Join(root, "auxiliary"))()
+
+	ifName, err := devlink.SFNetdevName(context.TODO(), pciAddress, 5)
+	require.NoError(t, err)
+	require.Equal(t, "eth5", ifName)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 300*time.Millisecond)
+	defer cancel()
+
+	_, err = devlink.SFNetdevName(ctx, pciAddress, 6)
+	require.Error(t, err)
+}
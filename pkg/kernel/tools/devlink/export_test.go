@@ -0,0 +1,24 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devlink
+
+// SetAuxiliaryDevicesPath replaces the sysfs auxiliary devices path and returns a function setting it back
+func SetAuxiliaryDevicesPath(path string) func() {
+	old := auxiliaryDevicesPath
+	auxiliaryDevicesPath = path
+	return func() { auxiliaryDevicesPath = old }
+}
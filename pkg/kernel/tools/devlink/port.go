@@ -0,0 +1,49 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build !linux
+
+package devlink
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// NewSFPort creates a new SF port on the given PCI device PF.
+// Equivalent to: `devlink port add pci/$pciAddress flavour pcisf pfnum $pfNum sfnum $sfNum`
+func NewSFPort(_ string, _ uint16, _ uint32) (*Port, error) {
+	return nil, errors.New("devlink is supported only on linux")
+}
+
+// DeletePort deletes the given devlink port.
+// Equivalent to: `devlink port del $bus/$device/$index`
+func DeletePort(_ *Port) error {
+	return errors.New("devlink is supported only on linux")
+}
+
+// SetPortFunctionHwAddr sets hardware address of the given port function.
+// Equivalent to: `devlink port function set $bus/$device/$index hw_addr $hwAddr`
+func SetPortFunctionHwAddr(_ *Port, _ net.HardwareAddr) error {
+	return errors.New("devlink is supported only on linux")
+}
+
+// SetPortFunctionState activates or deactivates the given port function.
+// Equivalent to: `devlink port function set $bus/$device/$index state active|inactive`
+func SetPortFunctionState(_ *Port, _ bool) error {
+	return errors.New("devlink is supported only on linux")
+}
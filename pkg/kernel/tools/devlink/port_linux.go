@@ -0,0 +1,163 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devlink
+
+import (
+	"net"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// Values from the linux/devlink.h
+const (
+	cmdPortNew = 7
+	cmdPortDel = 8
+	cmdPortSet = 6
+
+	attrBusName         = 1
+	attrDevName         = 2
+	attrPortIndex       = 3
+	attrPortNetdevName  = 7
+	attrPortFlavour     = 77
+	attrPortPCIPFNumber = 127
+	attrPortFunction    = 145
+	attrPortPCISFNumber = 164
+
+	portFunctionAttrHwAddr = 1
+	portFunctionAttrState  = 2
+
+	portFlavourPCISF = 7
+
+	portFunctionStateInactive = 0
+	portFunctionStateActive   = 1
+
+	pciBus = "pci"
+)
+
+// NewSFPort creates a new SF port on the given PCI device PF.
+// Equivalent to: `devlink port add pci/$pciAddress flavour pcisf pfnum $pfNum sfnum $sfNum`
+func NewSFPort(pciAddress string, pfNum uint16, sfNum uint32) (*Port, error) {
+	req, err := newRequest(cmdPortNew, pciBus, pciAddress)
+	if err != nil {
+		return nil, err
+	}
+	req.AddData(nl.NewRtAttr(attrPortFlavour, nl.Uint16Attr(portFlavourPCISF)))
+	req.AddData(nl.NewRtAttr(attrPortPCIPFNumber, nl.Uint16Attr(pfNum)))
+	req.AddData(nl.NewRtAttr(attrPortPCISFNumber, nl.Uint32Attr(sfNum)))
+
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create SF port: %v %v %v", pciAddress, pfNum, sfNum)
+	}
+
+	port := &Port{
+		BusName:    pciBus,
+		DeviceName: pciAddress,
+		PFNumber:   pfNum,
+		SFNumber:   sfNum,
+	}
+	if len(msgs) == 0 {
+		return nil, errors.Errorf("no reply for the SF port creation: %v %v %v", pciAddress, pfNum, sfNum)
+	}
+	attrs, err := nl.ParseRouteAttr(msgs[0][nl.SizeofGenlmsg:])
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse SF port creation reply")
+	}
+	port.parseAttributes(attrs)
+
+	return port, nil
+}
+
+// DeletePort deletes the given devlink port.
+// Equivalent to: `devlink port del $bus/$device/$index`
+func DeletePort(port *Port) error {
+	req, err := newRequest(cmdPortDel, port.BusName, port.DeviceName)
+	if err != nil {
+		return err
+	}
+	req.AddData(nl.NewRtAttr(attrPortIndex, nl.Uint32Attr(port.Index)))
+
+	if _, err := req.Execute(unix.NETLINK_GENERIC, 0); err != nil {
+		return errors.Wrapf(err, "failed to delete devlink port: %v/%v/%v", port.BusName, port.DeviceName, port.Index)
+	}
+	return nil
+}
+
+// SetPortFunctionHwAddr sets hardware address of the given port function.
+// Equivalent to: `devlink port function set $bus/$device/$index hw_addr $hwAddr`
+func SetPortFunctionHwAddr(port *Port, hwAddr net.HardwareAddr) error {
+	return setPortFunction(port, portFunctionAttrHwAddr, hwAddr)
+}
+
+// SetPortFunctionState activates or deactivates the given port function.
+// Equivalent to: `devlink port function set $bus/$device/$index state active|inactive`
+func SetPortFunctionState(port *Port, active bool) error {
+	state := uint8(portFunctionStateInactive)
+	if active {
+		state = portFunctionStateActive
+	}
+	return setPortFunction(port, portFunctionAttrState, []byte{state})
+}
+
+func setPortFunction(port *Port, attrType int, data []byte) error {
+	req, err := newRequest(cmdPortSet, port.BusName, port.DeviceName)
+	if err != nil {
+		return err
+	}
+	req.AddData(nl.NewRtAttr(attrPortIndex, nl.Uint32Attr(port.Index)))
+
+	function := nl.NewRtAttr(attrPortFunction|unix.NLA_F_NESTED, nil)
+	function.AddRtAttr(attrType, data)
+	req.AddData(function)
+
+	if _, err := req.Execute(unix.NETLINK_GENERIC, 0); err != nil {
+		return errors.Wrapf(err, "failed to set devlink port function: %v/%v/%v", port.BusName, port.DeviceName, port.Index)
+	}
+	return nil
+}
+
+func newRequest(cmd uint8, bus, device string) (*nl.NetlinkRequest, error) {
+	family, err := netlink.GenlFamilyGet(nl.GENL_DEVLINK_NAME)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get devlink generic netlink family")
+	}
+
+	req := nl.NewNetlinkRequest(int(family.ID), unix.NLM_F_REQUEST|unix.NLM_F_ACK)
+	req.AddData(&nl.Genlmsg{
+		Command: cmd,
+		Version: nl.GENL_DEVLINK_VERSION,
+	})
+	req.AddData(nl.NewRtAttr(attrBusName, nl.ZeroTerminated(bus)))
+	req.AddData(nl.NewRtAttr(attrDevName, nl.ZeroTerminated(device)))
+
+	return req, nil
+}
+
+func (p *Port) parseAttributes(attrs []syscall.NetlinkRouteAttr) {
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case attrPortIndex:
+			p.Index = nl.NativeEndian().Uint32(attr.Value)
+		case attrPortNetdevName:
+			p.NetdevName = string(attr.Value[:len(attr.Value)-1])
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ethtool provides net interface ring buffer and interrupt coalescing utils working with the ethtool generic
+// netlink family
+package ethtool
+
+// Rings is a net interface ring buffer sizes, 0 means not set
+type Rings struct {
+	// RX is the RX ring size
+	RX uint32
+	// TX is the TX ring size
+	TX uint32
+	// RXMax is the max RX ring size supported by the device, it is only reported by GetRings
+	RXMax uint32
+	// TXMax is the max TX ring size supported by the device, it is only reported by GetRings
+	TXMax uint32
+}
+
+// Coalesce is a net interface interrupt coalescing parameters, 0 means not set
+type Coalesce struct {
+	// RXUsecs is the delay in usecs after the RX packet arrival before the interrupt
+	RXUsecs uint32
+	// RXMaxFrames is the max number of the RX packets before the interrupt
+	RXMaxFrames uint32
+	// TXUsecs is the delay in usecs after the TX packet sending before the interrupt
+	TXUsecs uint32
+	// TXMaxFrames is the max number of the TX packets before the interrupt
+	TXMaxFrames uint32
+}
+
+// RingsFields is a mask of the Rings fields to set
+type RingsFields uint8
+
+// Rings fields
+const (
+	RingsRX RingsFields = 1 << iota
+	RingsTX
+)
+
+// Fields returns the mask of the not zero Rings fields
+func (r *Rings) Fields() RingsFields {
+	var fields RingsFields
+	if r.RX != 0 {
+		fields |= RingsRX
+	}
+	if r.TX != 0 {
+		fields |= RingsTX
+	}
+	return fields
+}
+
+// CoalesceFields is a mask of the Coalesce fields to set
+type CoalesceFields uint8
+
+// Coalesce fields
+const (
+	CoalesceRXUsecs CoalesceFields = 1 << iota
+	CoalesceRXMaxFrames
+	CoalesceTXUsecs
+	CoalesceTXMaxFrames
+)
+
+// Fields returns the mask of the not zero Coalesce fields
+func (c *Coalesce) Fields() CoalesceFields {
+	var fields CoalesceFields
+	if c.RXUsecs != 0 {
+		fields |= CoalesceRXUsecs
+	}
+	if c.RXMaxFrames != 0 {
+		fields |= CoalesceRXMaxFrames
+	}
+	if c.TXUsecs != 0 {
+		fields |= CoalesceTXUsecs
+	}
+	if c.TXMaxFrames != 0 {
+		fields |= CoalesceTXMaxFrames
+	}
+	return fields
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethtool_test
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ethtool"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName   = "ethtool-1"
+	peerName = "ethtool-2"
+)
+
+func TestRings(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	rings, err := ethtool.GetRings(ifName)
+	if errors.Cause(err) == syscall.EOPNOTSUPP {
+		t.Skip("ring buffer sizes are not supported by the net interface driver")
+	}
+	require.NoError(t, err)
+	require.NotZero(t, rings.RXMax)
+
+	require.NoError(t, ethtool.SetRings(ifName, &ethtool.Rings{RX: rings.RXMax}))
+
+	changed, err := ethtool.GetRings(ifName)
+	require.NoError(t, err)
+	require.Equal(t, rings.RXMax, changed.RX)
+	require.Equal(t, rings.TX, changed.TX)
+}
+
+func TestCoalesce(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	_, err := ethtool.GetCoalesce(ifName)
+	if errors.Cause(err) == syscall.EOPNOTSUPP {
+		t.Skip("interrupt coalescing is not supported by the net interface driver")
+	}
+	require.NoError(t, err)
+
+	require.NoError(t, ethtool.SetCoalesce(ifName, &ethtool.Coalesce{RXUsecs: 10}))
+
+	changed, err := ethtool.GetCoalesce(ifName)
+	require.NoError(t, err)
+	require.Equal(t, uint32(10), changed.RXUsecs)
+}
+
+func TestCoalesce_ZeroField(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	err := ethtool.SetCoalesce(ifName, &ethtool.Coalesce{RXUsecs: 10})
+	if errors.Cause(err) == syscall.EOPNOTSUPP {
+		t.Skip("interrupt coalescing is not supported by the net interface driver")
+	}
+	require.NoError(t, err)
+
+	require.NoError(t, ethtool.SetCoalesceFields(ifName, &ethtool.Coalesce{}, ethtool.CoalesceRXUsecs))
+
+	changed, err := ethtool.GetCoalesce(ifName)
+	require.NoError(t, err)
+	require.Zero(t, changed.RXUsecs)
+}
+
+func TestRings_NoNetInterface(t *testing.T) {
+	_, err := ethtool.GetRings("ethtool-none")
+	require.Error(t, err)
+}
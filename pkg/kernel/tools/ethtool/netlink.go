@@ -0,0 +1,57 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build !linux
+
+package ethtool
+
+import "github.com/pkg/errors"
+
+// GetRings returns the net interface ring buffer sizes in the current net NS.
+// Equivalent to: `ethtool -g $ifName`
+func GetRings(_ string) (*Rings, error) {
+	return nil, errors.New("ethtool is supported only on linux")
+}
+
+// SetRings sets the not zero net interface ring buffer sizes in the current net NS.
+// Equivalent to: `ethtool -G $ifName rx $rx tx $tx`
+func SetRings(_ string, _ *Rings) error {
+	return errors.New("ethtool is supported only on linux")
+}
+
+// SetRingsFields sets the given net interface ring buffer sizes fields in the current net NS, the fields are set even
+// if they are zero.
+func SetRingsFields(_ string, _ *Rings, _ RingsFields) error {
+	return errors.New("ethtool is supported only on linux")
+}
+
+// GetCoalesce returns the net interface interrupt coalescing parameters in the current net NS.
+// Equivalent to: `ethtool -c $ifName`
+func GetCoalesce(_ string) (*Coalesce, error) {
+	return nil, errors.New("ethtool is supported only on linux")
+}
+
+// SetCoalesce sets the not zero net interface interrupt coalescing parameters in the current net NS.
+// Equivalent to: `ethtool -C $ifName rx-usecs $rxUsecs rx-frames $rxMaxFrames tx-usecs $txUsecs tx-frames $txMaxFrames`
+func SetCoalesce(_ string, _ *Coalesce) error {
+	return errors.New("ethtool is supported only on linux")
+}
+
+// SetCoalesceFields sets the given net interface interrupt coalescing parameters fields in the current net NS, the
+// fields are set even if they are zero.
+func SetCoalesceFields(_ string, _ *Coalesce, _ CoalesceFields) error {
+	return errors.New("ethtool is supported only on linux")
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ethtool
+
+import (
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// Values from the linux/ethtool_netlink.h
+const (
+	genlName    = "ethtool"
+	genlVersion = 1
+
+	msgRingsGet    = 15
+	msgRingsSet    = 16
+	msgCoalesceGet = 19
+	msgCoalesceSet = 20
+
+	headerAttrDevName = 2
+
+	ringsAttrHeader = 1
+	ringsAttrRXMax  = 2
+	ringsAttrTXMax  = 5
+	ringsAttrRX     = 6
+	ringsAttrTX     = 9
+
+	coalesceAttrHeader      = 1
+	coalesceAttrRXUsecs     = 2
+	coalesceAttrRXMaxFrames = 3
+	coalesceAttrTXUsecs     = 6
+	coalesceAttrTXMaxFrames = 7
+)
+
+// GetRings returns the net interface ring buffer sizes in the current net NS.
+// Equivalent to: `ethtool -g $ifName`
+func GetRings(ifName string) (*Rings, error) {
+	attrs, err := get(msgRingsGet, ringsAttrHeader, ifName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get ring buffer sizes: %v", ifName)
+	}
+	rings := &Rings{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case ringsAttrRX:
+			rings.RX = nl.NativeEndian().Uint32(attr.Value)
+		case ringsAttrTX:
+			rings.TX = nl.NativeEndian().Uint32(attr.Value)
+		case ringsAttrRXMax:
+			rings.RXMax = nl.NativeEndian().Uint32(attr.Value)
+		case ringsAttrTXMax:
+			rings.TXMax = nl.NativeEndian().Uint32(attr.Value)
+		}
+	}
+	return rings, nil
+}
+
+// SetRings sets the not zero net interface ring buffer sizes in the current net NS.
+// Equivalent to: `ethtool -G $ifName rx $rx tx $tx`
+func SetRings(ifName string, rings *Rings) error {
+	return SetRingsFields(ifName, rings, rings.Fields())
+}
+
+// SetRingsFields sets the given net interface ring buffer sizes fields in the current net NS, the fields are set even
+// if they are zero.
+func SetRingsFields(ifName string, rings *Rings, fields RingsFields) error {
+	req, err := newRequest(msgRingsSet, unix.NLM_F_REQUEST|unix.NLM_F_ACK, ringsAttrHeader, ifName)
+	if err != nil {
+		return err
+	}
+	addUint32(req, fields&RingsRX != 0, ringsAttrRX, rings.RX)
+	addUint32(req, fields&RingsTX != 0, ringsAttrTX, rings.TX)
+
+	if _, err := req.Execute(unix.NETLINK_GENERIC, 0); err != nil {
+		return errors.Wrapf(err, "failed to set ring buffer sizes: %v %+v", ifName, *rings)
+	}
+	return nil
+}
+
+// GetCoalesce returns the net interface interrupt coalescing parameters in the current net NS.
+// Equivalent to: `ethtool -c $ifName`
+func GetCoalesce(ifName string) (*Coalesce, error) {
+	attrs, err := get(msgCoalesceGet, coalesceAttrHeader, ifName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get interrupt coalescing: %v", ifName)
+	}
+	coalesce := &Coalesce{}
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case coalesceAttrRXUsecs:
+			coalesce.RXUsecs = nl.NativeEndian().Uint32(attr.Value)
+		case coalesceAttrRXMaxFrames:
+			coalesce.RXMaxFrames = nl.NativeEndian().Uint32(attr.Value)
+		case coalesceAttrTXUsecs:
+			coalesce.TXUsecs = nl.NativeEndian().Uint32(attr.Value)
+		case coalesceAttrTXMaxFrames:
+			coalesce.TXMaxFrames = nl.NativeEndian().Uint32(attr.Value)
+		}
+	}
+	return coalesce, nil
+}
+
+// SetCoalesce sets the not zero net interface interrupt coalescing parameters in the current net NS.
+// Equivalent to: `ethtool -C $ifName rx-usecs $rxUsecs rx-frames $rxMaxFrames tx-usecs $txUsecs tx-frames $txMaxFrames`
+func SetCoalesce(ifName string, coalesce *Coalesce) error {
+	return SetCoalesceFields(ifName, coalesce, coalesce.Fields())
+}
+
+// SetCoalesceFields sets the given net interface interrupt coalescing parameters fields in the current net NS, the
+// fields are set even if they are zero.
+func SetCoalesceFields(ifName string, coalesce *Coalesce, fields CoalesceFields) error {
+	req, err := newRequest(msgCoalesceSet, unix.NLM_F_REQUEST|unix.NLM_F_ACK, coalesceAttrHeader, ifName)
+	if err != nil {
+		return err
+	}
+	addUint32(req, fields&CoalesceRXUsecs != 0, coalesceAttrRXUsecs, coalesce.RXUsecs)
+	addUint32(req, fields&CoalesceRXMaxFrames != 0, coalesceAttrRXMaxFrames, coalesce.RXMaxFrames)
+	addUint32(req, fields&CoalesceTXUsecs != 0, coalesceAttrTXUsecs, coalesce.TXUsecs)
+	addUint32(req, fields&CoalesceTXMaxFrames != 0, coalesceAttrTXMaxFrames, coalesce.TXMaxFrames)
+
+	if _, err := req.Execute(unix.NETLINK_GENERIC, 0); err != nil {
+		return errors.Wrapf(err, "failed to set interrupt coalescing: %v %+v", ifName, *coalesce)
+	}
+	return nil
+}
+
+func get(cmd uint8, headerAttr int, ifName string) ([]syscall.NetlinkRouteAttr, error) {
+	req, err := newRequest(cmd, unix.NLM_F_REQUEST, headerAttr, ifName)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, errors.New("no reply")
+	}
+	return nl.ParseRouteAttr(msgs[0][nl.SizeofGenlmsg:])
+}
+
+func newRequest(cmd uint8, flags, headerAttr int, ifName string) (*nl.NetlinkRequest, error) {
+	family, err := netlink.GenlFamilyGet(genlName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get ethtool generic netlink family")
+	}
+
+	req := nl.NewNetlinkRequest(int(family.ID), flags)
+	req.AddData(&nl.Genlmsg{
+		Command: cmd,
+		Version: genlVersion,
+	})
+	header := nl.NewRtAttr(headerAttr|unix.NLA_F_NESTED, nil)
+	header.AddRtAttr(headerAttrDevName, nl.ZeroTerminated(ifName))
+	req.AddData(header)
+
+	return req, nil
+}
+
+func addUint32(req *nl.NetlinkRequest, set bool, attrType int, value uint32) {
+	if set {
+		req.AddData(nl.NewRtAttr(attrType, nl.Uint32Attr(value)))
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcthresh
+
+import "time"
+
+// Option is an option for the neighbor table GC thresholds tuner
+type Option func(t *tuner)
+
+// WithInterval sets neighbor tables check interval
+func WithInterval(interval time.Duration) Option {
+	return func(t *tuner) {
+		t.interval = interval
+	}
+}
+
+// WithWatermark sets the part of gc_thresh3 the neighbors count should exceed to trigger thresholds raise
+func WithWatermark(watermark float64) Option {
+	return func(t *tuner) {
+		t.watermark = watermark
+	}
+}
+
+// WithMaxThreshold sets the upper limit for gc_thresh3
+func WithMaxThreshold(maxThreshold int64) Option {
+	return func(t *tuner) {
+		t.maxThreshold = maxThreshold
+	}
+}
+
+// WithCounter sets the function counting neighbors of the given family, it can be used to count only NSM managed
+// neighbors instead of the whole neighbor table
+func WithCounter(count func(family int) (int, error)) Option {
+	return func(t *tuner) {
+		t.count = count
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package gcthresh provides neighbor table GC thresholds auto-tuning
+package gcthresh
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+const (
+	defaultInterval     = 10 * time.Second
+	defaultWatermark    = 0.8
+	defaultMaxThreshold = 1 << 16
+)
+
+var families = []*family{
+	{
+		name:   "ipv4",
+		family: kernel.FamilyV4,
+		prefix: "net.ipv4.neigh.default.",
+	},
+	{
+		name:   "ipv6",
+		family: kernel.FamilyV6,
+		prefix: "net.ipv6.neigh.default.",
+	},
+}
+
+type family struct {
+	name   string
+	family int
+	prefix string
+}
+
+type tuner struct {
+	interval     time.Duration
+	watermark    float64
+	maxThreshold int64
+	count        func(family int) (int, error)
+
+	// snapshot has the original values of the raised thresholds, it is taken on the first raise
+	snapshot *sysctl.Snapshot
+}
+
+// Start starts monitoring neighbor tables in the current net NS and raises gc_thresh1..3 sysctls each time the
+// neighbors count exceeds watermark * gc_thresh3. It stops when ctx is done and restores the original thresholds.
+func Start(ctx context.Context, options ...Option) {
+	t := &tuner{
+		interval:     defaultInterval,
+		watermark:    defaultWatermark,
+		maxThreshold: defaultMaxThreshold,
+		count:        countNeighbors,
+	}
+	for _, opt := range options {
+		opt(t)
+	}
+
+	go func() {
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+		for {
+			for _, f := range families {
+				if err := t.tune(ctx, f); err != nil {
+					log.Entry(ctx).Warnf("failed to tune %s neighbor table GC thresholds: %s", f.name, err.Error())
+				}
+			}
+			select {
+			case <-ctx.Done():
+				t.restore(ctx)
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func (t *tuner) tune(ctx context.Context, f *family) error {
+	count, err := t.count(f.family)
+	if err != nil {
+		return err
+	}
+
+	thresh3, err := sysctl.GetInt(f.prefix + "gc_thresh3")
+	if err != nil {
+		return err
+	}
+	if thresh3 <= 0 {
+		// the thresholds are scaled by gc_thresh3, so they can't be raised from the non positive one
+		log.Entry(ctx).Warnf("%s neighbor table GC thresholds are not tuned, gc_thresh3 is not positive: %d",
+			f.name, thresh3)
+		return nil
+	}
+	if float64(count) < t.watermark*float64(thresh3) {
+		return nil
+	}
+	if thresh3 >= t.maxThreshold {
+		log.Entry(ctx).Warnf("%s neighbor table is close to the GC threshold, but it is already at max: %d/%d",
+			f.name, count, thresh3)
+		return nil
+	}
+
+	newThresh3 := thresh3 * 2
+	if newThresh3 > t.maxThreshold {
+		newThresh3 = t.maxThreshold
+	}
+
+	if t.snapshot == nil {
+		if t.snapshot, err = sysctl.Take(); err != nil {
+			return err
+		}
+	}
+
+	// gc_thresh3 goes first to keep gc_thresh1 <= gc_thresh2 <= gc_thresh3 during the update, restore goes in the
+	// reverse order
+	for _, name := range []string{"gc_thresh3", "gc_thresh2", "gc_thresh1"} {
+		value, err := sysctl.GetInt(f.prefix + name)
+		if err != nil {
+			return err
+		}
+		if err := t.snapshot.Set(f.prefix+name, strconv.FormatInt(value*newThresh3/thresh3, 10)); err != nil {
+			return err
+		}
+	}
+	log.Entry(ctx).Infof("raised %s neighbor table GC thresholds: %d neighbors, gc_thresh3 %d -> %d",
+		f.name, count, thresh3, newThresh3)
+
+	return nil
+}
+
+// restore sets the raised thresholds back to the original values
+func (t *tuner) restore(ctx context.Context) {
+	if t.snapshot == nil {
+		return
+	}
+	if err := t.snapshot.Restore(); err != nil {
+		log.Entry(ctx).Warnf("failed to restore neighbor table GC thresholds: %s", err.Error())
+	}
+}
+
+func countNeighbors(family int) (int, error) {
+	neighs, err := netlink.NeighList(0, family)
+	if err != nil {
+		return 0, err
+	}
+	return len(neighs), nil
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcthresh_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/gcthresh"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+const (
+	thresh1 = "net.ipv4.neigh.default.gc_thresh1"
+	thresh2 = "net.ipv4.neigh.default.gc_thresh2"
+	thresh3 = "net.ipv4.neigh.default.gc_thresh3"
+)
+
+// setThresholds sets the IPv4 thresholds, they exist only in the init net NS, so the originals are restored by the
+// returned function
+func setThresholds(t *testing.T, values ...int64) func() {
+	snapshot, err := sysctl.Take(thresh1, thresh2, thresh3)
+	require.NoError(t, err)
+	// gc_thresh3 goes first to keep gc_thresh1 <= gc_thresh2 <= gc_thresh3 during the update
+	for i, name := range []string{thresh3, thresh2, thresh1} {
+		require.NoError(t, sysctl.SetInt(name, values[len(values)-1-i]))
+	}
+	return func() { require.NoError(t, snapshot.Restore()) }
+}
+
+func requireThresholds(t *testing.T, values ...int64) {
+	for i, name := range []string{thresh1, thresh2, thresh3} {
+		value, err := sysctl.GetInt(name)
+		require.NoError(t, err)
+		require.Equal(t, values[i], value, name)
+	}
+}
+
+func counter(v4Count int) func(family int) (int, error) {
+	return func(family int) (int, error) {
+		if family == kernel.FamilyV4 {
+			return v4Count, nil
+		}
+		return 0, nil
+	}
+}
+
+func TestTuner(t *testing.T) {
+	defer setThresholds(t, 128, 512, 1024)()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gcthresh.Start(ctx,
+		gcthresh.WithInterval(time.Hour),
+		gcthresh.WithMaxThreshold(1536),
+		gcthresh.WithCounter(counter(900)),
+	)
+
+	require.Eventually(t, func() bool {
+		value, err := sysctl.GetInt(thresh3)
+		return err == nil && value == 1536
+	}, time.Second, 10*time.Millisecond)
+	requireThresholds(t, 192, 768, 1536)
+
+	// the original thresholds are restored on stop
+	cancel()
+	require.Eventually(t, func() bool {
+		value, err := sysctl.GetInt(thresh3)
+		return err == nil && value == 1024
+	}, time.Second, 10*time.Millisecond)
+	requireThresholds(t, 128, 512, 1024)
+}
+
+func TestTuner_ZeroThreshold(t *testing.T) {
+	defer setThresholds(t, 0, 0, 0)()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gcthresh.Start(ctx,
+		gcthresh.WithInterval(10*time.Millisecond),
+		gcthresh.WithCounter(counter(900)),
+	)
+
+	// the thresholds can't be scaled from zero, the tuner keeps running
+	time.Sleep(50 * time.Millisecond)
+	requireThresholds(t, 0, 0, 0)
+}
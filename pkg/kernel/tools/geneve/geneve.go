@@ -0,0 +1,35 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package geneve provides Geneve net interface utils, the used netlink library version doesn't support Geneve links
+package geneve
+
+import "net"
+
+// LinkType is the Geneve netlink link type
+const LinkType = "geneve"
+
+// Geneve is a Geneve net interface configuration
+type Geneve struct {
+	// Name is the net interface name
+	Name string
+	// VNI is the Geneve virtual network ID
+	VNI uint32
+	// Remote is the remote tunnel endpoint IP address
+	Remote net.IP
+	// DstPort is the remote UDP port
+	DstPort uint16
+}
@@ -0,0 +1,34 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build !linux
+
+package geneve
+
+import "github.com/pkg/errors"
+
+// Add creates a new Geneve net interface in the current net NS.
+// Equivalent to: `ip link add $name type geneve id $vni remote $remote dstport $dstPort`
+func Add(_ *Geneve) error {
+	return errors.New("geneve is supported only on linux")
+}
+
+// Get returns the Geneve net interface configuration in the current net NS, it returns an error if there is no such
+// net interface or it is not a Geneve one.
+// Equivalent to: `ip -d link show $name`
+func Get(_ string) (*Geneve, error) {
+	return nil, errors.New("geneve is supported only on linux")
+}
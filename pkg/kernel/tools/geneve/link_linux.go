@@ -0,0 +1,127 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geneve
+
+import (
+	"encoding/binary"
+	"net"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// Values from the linux/if_link.h
+const (
+	attrID      = 1
+	attrRemote  = 2
+	attrPort    = 5
+	attrRemote6 = 7
+)
+
+// Add creates a new Geneve net interface in the current net NS.
+// Equivalent to: `ip link add $name type geneve id $vni remote $remote dstport $dstPort`
+func Add(geneve *Geneve) error {
+	req := nl.NewNetlinkRequest(unix.RTM_NEWLINK, unix.NLM_F_CREATE|unix.NLM_F_EXCL|unix.NLM_F_ACK)
+	req.AddData(nl.NewIfInfomsg(unix.AF_UNSPEC))
+	req.AddData(nl.NewRtAttr(unix.IFLA_IFNAME, nl.ZeroTerminated(geneve.Name)))
+
+	linkInfo := nl.NewRtAttr(unix.IFLA_LINKINFO, nil)
+	linkInfo.AddRtAttr(nl.IFLA_INFO_KIND, nl.NonZeroTerminated(LinkType))
+	data := linkInfo.AddRtAttr(nl.IFLA_INFO_DATA, nil)
+	data.AddRtAttr(attrID, nl.Uint32Attr(geneve.VNI))
+	if ip := geneve.Remote.To4(); ip != nil {
+		data.AddRtAttr(attrRemote, ip)
+	} else if ip := geneve.Remote.To16(); ip != nil {
+		data.AddRtAttr(attrRemote6, ip)
+	} else {
+		return errors.Errorf("invalid Geneve remote IP: %v", geneve.Remote)
+	}
+	port := make([]byte, 2)
+	binary.BigEndian.PutUint16(port, geneve.DstPort)
+	data.AddRtAttr(attrPort, port)
+	req.AddData(linkInfo)
+
+	if _, err := req.Execute(unix.NETLINK_ROUTE, 0); err != nil {
+		return errors.Wrapf(err, "failed to create Geneve net interface: %v", geneve.Name)
+	}
+	return nil
+}
+
+// Get returns the Geneve net interface configuration in the current net NS, it returns an error if there is no such
+// net interface or it is not a Geneve one.
+// Equivalent to: `ip -d link show $name`
+func Get(name string) (*Geneve, error) {
+	req := nl.NewNetlinkRequest(unix.RTM_GETLINK, unix.NLM_F_ACK)
+	req.AddData(nl.NewIfInfomsg(unix.AF_UNSPEC))
+	req.AddData(nl.NewRtAttr(unix.IFLA_IFNAME, nl.ZeroTerminated(name)))
+
+	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWLINK)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get net interface: %v", name)
+	}
+	if len(msgs) == 0 {
+		return nil, errors.Errorf("no reply for the net interface: %v", name)
+	}
+
+	attrs, err := nl.ParseRouteAttr(msgs[0][unix.SizeofIfInfomsg:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse net interface reply: %v", name)
+	}
+	for _, attr := range attrs {
+		if attr.Attr.Type != unix.IFLA_LINKINFO {
+			continue
+		}
+		linkInfo, err := nl.ParseRouteAttr(attr.Value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse net interface link info: %v", name)
+		}
+		if geneve, ok := parseLinkInfo(name, linkInfo); ok {
+			return geneve, nil
+		}
+	}
+	return nil, errors.Errorf("not a Geneve net interface: %v", name)
+}
+
+func parseLinkInfo(name string, linkInfo []syscall.NetlinkRouteAttr) (*Geneve, bool) {
+	var isGeneve bool
+	geneve := &Geneve{Name: name}
+	for _, info := range linkInfo {
+		switch info.Attr.Type {
+		case nl.IFLA_INFO_KIND:
+			isGeneve = strings.TrimRight(string(info.Value), "\x00") == LinkType
+		case nl.IFLA_INFO_DATA:
+			data, err := nl.ParseRouteAttr(info.Value)
+			if err != nil {
+				return nil, false
+			}
+			for _, attr := range data {
+				switch attr.Attr.Type {
+				case attrID:
+					geneve.VNI = nl.NativeEndian().Uint32(attr.Value)
+				case attrRemote, attrRemote6:
+					geneve.Remote = net.IP(attr.Value)
+				case attrPort:
+					geneve.DstPort = binary.BigEndian.Uint16(attr.Value)
+				}
+			}
+		}
+	}
+	return geneve, isGeneve
+}
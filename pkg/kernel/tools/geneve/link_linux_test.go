@@ -0,0 +1,62 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package geneve_test
+
+import (
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/geneve"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+func TestGeneve(t *testing.T) {
+	expected := &geneve.Geneve{
+		Name:    "geneve-1",
+		VNI:     100,
+		Remote:  net.ParseIP("172.16.5.2").To4(),
+		DstPort: 6081,
+	}
+
+	err := geneve.Add(expected)
+	if errors.Cause(err) == syscall.EOPNOTSUPP {
+		t.Skip("Geneve is not supported by the kernel")
+	}
+	require.NoError(t, err)
+	defer func() { _ = netlink.LinkDel(&netlink.GenericLink{LinkAttrs: netlink.LinkAttrs{Name: expected.Name}}) }()
+
+	actual, err := geneve.Get(expected.Name)
+	require.NoError(t, err)
+	require.Equal(t, expected.VNI, actual.VNI)
+	require.Equal(t, expected.DstPort, actual.DstPort)
+	require.True(t, expected.Remote.Equal(actual.Remote))
+}
+
+func TestGeneve_NotGeneve(t *testing.T) {
+	kerneltest.AddVeth(t, "geneve-2", "geneve-3")
+
+	_, err := geneve.Get("geneve-2")
+	require.Error(t, err)
+
+	_, err = geneve.Get("geneve-none")
+	require.Error(t, err)
+}
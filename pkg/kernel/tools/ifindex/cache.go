@@ -0,0 +1,213 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ifindex provides net interface index cache invalidated by the kernel link events. The link events
+// subscription keeps its net NS alive, so it is closed when the last cached net interface of the net NS is deleted
+// (e.g. the connection is closed in the deleted pod), and is started again on the next lookup in the net NS.
+package ifindex
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+type key struct {
+	netNS string
+	name  string
+}
+
+// netNSState is a link events subscription state for the net NS. Generation is incremented on every invalidation, so
+// concurrent lookup racing with the link event wouldn't store a stale index.
+type netNSState struct {
+	generation uint64
+	cancel     context.CancelFunc
+}
+
+// Cache is a (net NS, net interface name) -> net interface index cache. Entries are invalidated on the net interface
+// deletion (RTM_DELLINK) and rename, so the cached index always belongs to the net interface with the given name.
+// Cache is safe for the concurrent use.
+type Cache struct {
+	ctx     context.Context
+	mu      sync.RWMutex
+	indexes map[key]int
+	netNSs  map[string]*netNSState
+}
+
+// NewCache returns a new Cache, all link events subscriptions are closed on ctx.Done()
+func NewCache(ctx context.Context) *Cache {
+	return &Cache{
+		ctx:     ctx,
+		indexes: make(map[key]int),
+		netNSs:  make(map[string]*netNSState),
+	}
+}
+
+// Index returns index of the net interface with the given name in the given net NS
+func (c *Cache) Index(handle netns.NsHandle, name string) (int, error) {
+	k := key{
+		netNS: handle.UniqueId(),
+		name:  name,
+	}
+
+	c.mu.RLock()
+	index, ok := c.indexes[k]
+	c.mu.RUnlock()
+	if ok {
+		return index, nil
+	}
+
+	// subscription should be started before the lookup to not miss any link event
+	generation, subscribed := c.subscribe(k.netNS, handle)
+
+	index, err := linkIndex(handle, name)
+	if err != nil {
+		if subscribed {
+			c.release(k.netNS)
+		}
+		return 0, err
+	}
+
+	if subscribed {
+		c.mu.Lock()
+		if state, ok := c.netNSs[k.netNS]; ok && state.generation == generation {
+			c.indexes[k] = index
+		}
+		c.mu.Unlock()
+	}
+
+	return index, nil
+}
+
+// Link returns the net interface with the given name in the given net NS looked up by the cached index, netlinkHandle
+// should be created in the same net NS. The net interface is looked up by name if the cached index belongs to another
+// net interface (the link event is not received yet). Nil Cache always looks up the net interface by name.
+func (c *Cache) Link(handle netns.NsHandle, netlinkHandle *netlink.Handle, name string) (netlink.Link, error) {
+	if c == nil {
+		return netlinkHandle.LinkByName(name)
+	}
+	index, err := c.Index(handle, name)
+	if err != nil {
+		return nil, err
+	}
+	if link, err := netlinkHandle.LinkByIndex(index); err == nil && link.Attrs().Name == name {
+		return link, nil
+	}
+	return netlinkHandle.LinkByName(name)
+}
+
+// subscribe starts link events subscription for the net NS if it is not started yet, it returns current net NS
+// generation and false if subscription cannot be started
+func (c *Cache) subscribe(netNS string, handle netns.NsHandle) (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if state, ok := c.netNSs[netNS]; ok {
+		return state.generation, true
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	updates, err := subscribe(ctx, handle)
+	if err != nil {
+		cancel()
+		return 0, false
+	}
+	state := &netNSState{cancel: cancel}
+	c.netNSs[netNS] = state
+
+	go func() {
+		for update := range updates {
+			c.invalidate(netNS, update)
+		}
+		c.unsubscribe(netNS, state)
+	}()
+
+	return 0, true
+}
+
+// invalidate deletes entries not matching the link event, the subscription is closed if there are no entries of the
+// net NS left after the net interface deletion
+func (c *Cache) invalidate(netNS string, update *linkUpdate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state, ok := c.netNSs[netNS]
+	if !ok {
+		return
+	}
+	state.generation++
+
+	var left int
+	for k, index := range c.indexes {
+		if k.netNS != netNS {
+			continue
+		}
+		switch {
+		case index == update.index && (update.deleted || k.name != update.name):
+			delete(c.indexes, k)
+		case index != update.index && k.name == update.name:
+			delete(c.indexes, k)
+		default:
+			left++
+		}
+	}
+
+	if update.deleted && left == 0 {
+		delete(c.netNSs, netNS)
+		state.cancel()
+	}
+}
+
+// release closes the net NS subscription if there are no entries of the net NS
+func (c *Cache) release(netNS string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k := range c.indexes {
+		if k.netNS == netNS {
+			return
+		}
+	}
+	if state, ok := c.netNSs[netNS]; ok {
+		delete(c.netNSs, netNS)
+		state.cancel()
+	}
+}
+
+// unsubscribe deletes the net NS state with all its entries, if it is not replaced with the new subscription yet
+func (c *Cache) unsubscribe(netNS string, state *netNSState) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state.cancel()
+	if c.netNSs[netNS] != state {
+		return
+	}
+	delete(c.netNSs, netNS)
+	for k := range c.indexes {
+		if k.netNS == netNS {
+			delete(c.indexes, k)
+		}
+	}
+}
+
+type linkUpdate struct {
+	index   int
+	name    string
+	deleted bool
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifindex_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ifindex"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+const (
+	ifName   = "ifindex-1"
+	peerName = "ifindex-2"
+)
+
+func TestCache_InvalidatedOnDelete(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handle, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = handle.Close() }()
+
+	cache := ifindex.NewCache(ctx)
+
+	_, err = cache.Index(handle, ifName)
+	require.Error(t, err)
+
+	index := addVeth(t)
+	defer func() { _ = netlink.LinkDel(&netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: ifName}}) }()
+
+	cachedIndex, err := cache.Index(handle, ifName)
+	require.NoError(t, err)
+	require.Equal(t, index, cachedIndex)
+
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	require.NoError(t, netlink.LinkDel(link))
+
+	index = addVeth(t)
+
+	require.Eventually(t, func() bool {
+		cachedIndex, err = cache.Index(handle, ifName)
+		return err == nil && cachedIndex == index
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCache_UnsubscribedOnLastDelete(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	netNS := kerneltest.AddVethPeer(t, ifName, "", peerName, "")
+
+	cache := ifindex.NewCache(ctx)
+
+	netlinkHandle, err := netlink.NewHandleAt(netNS)
+	require.NoError(t, err)
+	defer netlinkHandle.Delete()
+
+	link, err := cache.Link(netNS, netlinkHandle, peerName)
+	require.NoError(t, err)
+	require.Equal(t, peerName, link.Attrs().Name)
+	require.True(t, cache.Subscribed(netNS))
+
+	// the subscription doesn't keep the net NS alive after its last cached net interface is deleted
+	require.NoError(t, netlinkHandle.LinkDel(link))
+	require.Eventually(t, func() bool {
+		return !cache.Subscribed(netNS)
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = cache.Link(netNS, netlinkHandle, peerName)
+	require.Error(t, err)
+	require.False(t, cache.Subscribed(netNS))
+}
+
+func addVeth(t *testing.T) int {
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: ifName},
+		PeerName:  peerName,
+	}))
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	return link.Attrs().Index
+}
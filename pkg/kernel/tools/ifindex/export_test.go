@@ -0,0 +1,28 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifindex
+
+import "github.com/vishvananda/netns"
+
+// Subscribed returns true if the link events subscription for the net NS is started
+func (c *Cache) Subscribed(handle netns.NsHandle) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.netNSs[handle.UniqueId()]
+	return ok
+}
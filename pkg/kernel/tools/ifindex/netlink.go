@@ -0,0 +1,34 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build !linux
+
+package ifindex
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netns"
+)
+
+func linkIndex(_ netns.NsHandle, _ string) (int, error) {
+	return 0, errors.New("not supported")
+}
+
+func subscribe(_ context.Context, _ netns.NsHandle) (<-chan *linkUpdate, error) {
+	return nil, errors.New("not supported")
+}
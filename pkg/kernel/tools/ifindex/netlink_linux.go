@@ -0,0 +1,95 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifindex
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// receiveTimeout is the link events socket receive timeout, the closed subscription is released no later than it
+const receiveTimeout = time.Second
+
+func linkIndex(handle netns.NsHandle, name string) (int, error) {
+	netlinkHandle, err := netlink.NewHandleAt(handle)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to create netlink handle in net NS: %v", handle)
+	}
+	defer netlinkHandle.Delete()
+
+	link, err := netlinkHandle.LinkByName(name)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to get net interface: %v", name)
+	}
+	return link.Attrs().Index, nil
+}
+
+// subscribe starts the link events subscription in the net NS. The socket is polled with the receive timeout: close
+// doesn't interrupt the blocked receive, and the socket keeps the net NS alive until the receive returns.
+func subscribe(ctx context.Context, handle netns.NsHandle) (<-chan *linkUpdate, error) {
+	s, err := nl.SubscribeAt(handle, netns.None(), unix.NETLINK_ROUTE, unix.RTNLGRP_LINK)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to subscribe for link events in net NS: %v", handle)
+	}
+	timeout := unix.NsecToTimeval(receiveTimeout.Nanoseconds())
+	if err := s.SetReceiveTimeout(&timeout); err != nil {
+		s.Close()
+		return nil, errors.Wrapf(err, "failed to set link events receive timeout in net NS: %v", handle)
+	}
+
+	updates := make(chan *linkUpdate)
+	go func() {
+		defer close(updates)
+		defer s.Close()
+		for ctx.Err() == nil {
+			msgs, _, err := s.Receive()
+			if err == unix.EAGAIN || err == unix.EINTR {
+				continue
+			}
+			if err != nil {
+				return
+			}
+			for i := range msgs {
+				if msgs[i].Header.Type != unix.RTM_NEWLINK && msgs[i].Header.Type != unix.RTM_DELLINK {
+					continue
+				}
+				header := unix.NlMsghdr(msgs[i].Header)
+				link, err := netlink.LinkDeserialize(&header, msgs[i].Data)
+				if err != nil {
+					continue
+				}
+				select {
+				case updates <- &linkUpdate{
+					index:   link.Attrs().Index,
+					name:    link.Attrs().Name,
+					deleted: header.Type == unix.RTM_DELLINK,
+				}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return updates, nil
+}
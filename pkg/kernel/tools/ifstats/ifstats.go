@@ -0,0 +1,55 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ifstats provides net interface counters read with RTM_GETSTATS netlink request, so the counters are always
+// 64-bit ones (IFLA_STATS_LINK_64) and are read in the net NS of the netlink socket instead of the /sys mounted one
+package ifstats
+
+// Stats is a subset of the net interface rtnl_link_stats64 counters
+type Stats struct {
+	RxPackets uint64
+	TxPackets uint64
+	RxBytes   uint64
+	TxBytes   uint64
+	RxErrors  uint64
+	TxErrors  uint64
+	RxDropped uint64
+	TxDropped uint64
+	Multicast uint64
+}
+
+// Sub returns the counters increase since prev. The counters are reset if the net interface is recreated, so the
+// counter less than the previous one is the increase itself. The 64-bit counters don't wrap around in practice.
+func (s *Stats) Sub(prev *Stats) *Stats {
+	return &Stats{
+		RxPackets: delta(s.RxPackets, prev.RxPackets),
+		TxPackets: delta(s.TxPackets, prev.TxPackets),
+		RxBytes:   delta(s.RxBytes, prev.RxBytes),
+		TxBytes:   delta(s.TxBytes, prev.TxBytes),
+		RxErrors:  delta(s.RxErrors, prev.RxErrors),
+		TxErrors:  delta(s.TxErrors, prev.TxErrors),
+		RxDropped: delta(s.RxDropped, prev.RxDropped),
+		TxDropped: delta(s.TxDropped, prev.TxDropped),
+		Multicast: delta(s.Multicast, prev.Multicast),
+	}
+}
+
+func delta(current, prev uint64) uint64 {
+	if current < prev {
+		return current
+	}
+	return current - prev
+}
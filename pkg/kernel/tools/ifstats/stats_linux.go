@@ -0,0 +1,97 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifstats
+
+import (
+	"net"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// Values from the linux/if_link.h
+const (
+	sizeofIfStatsMsg = 12
+	attrLink64       = 1
+	sizeofStats      = 9 * 8
+)
+
+// Get returns the counters of the net interface with the given name in the current net NS.
+// Equivalent to: `ip -s link show $name`
+func Get(ifName string) (*Stats, error) {
+	iface, err := net.InterfaceByName(ifName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+
+	// struct if_stats_msg { family, pad1, pad2, ifindex, filter_mask }
+	msg := make([]byte, sizeofIfStatsMsg)
+	nl.NativeEndian().PutUint32(msg[4:], uint32(iface.Index))
+	nl.NativeEndian().PutUint32(msg[8:], 1<<(attrLink64-1))
+
+	req := nl.NewNetlinkRequest(unix.RTM_GETSTATS, 0)
+	req.AddRawData(msg)
+
+	msgs, err := req.Execute(unix.NETLINK_ROUTE, unix.RTM_NEWSTATS)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get net interface statistics: %v", ifName)
+	}
+	if len(msgs) == 0 || len(msgs[0]) < sizeofIfStatsMsg {
+		return nil, errors.Errorf("no reply for the net interface statistics: %v", ifName)
+	}
+
+	attrs, err := nl.ParseRouteAttr(msgs[0][sizeofIfStatsMsg:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse net interface statistics reply: %v", ifName)
+	}
+	for _, attr := range attrs {
+		if attr.Attr.Type == attrLink64 && len(attr.Value) >= sizeofStats {
+			return parseStats(attr.Value), nil
+		}
+	}
+	return nil, errors.Errorf("no 64-bit statistics for the net interface: %v", ifName)
+}
+
+// GetAt returns the counters of the net interface with the given name in the net NS given by file://path URL
+func GetAt(netNSURL, ifName string) (stats *Stats, err error) {
+	err = nshandle.RunInURL(netNSURL, func() error {
+		stats, err = Get(ifName)
+		return err
+	})
+	return stats, err
+}
+
+// parseStats parses the leading struct rtnl_link_stats64 fields, the struct grows with the kernel versions
+func parseStats(b []byte) *Stats {
+	field := func(i int) uint64 {
+		return nl.NativeEndian().Uint64(b[i*8:])
+	}
+	return &Stats{
+		RxPackets: field(0),
+		TxPackets: field(1),
+		RxBytes:   field(2),
+		TxBytes:   field(3),
+		RxErrors:  field(4),
+		TxErrors:  field(5),
+		RxDropped: field(6),
+		TxDropped: field(7),
+		Multicast: field(8),
+	}
+}
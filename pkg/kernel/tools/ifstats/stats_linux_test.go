@@ -0,0 +1,77 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ifstats_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ifstats"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+)
+
+const (
+	ifName   = "ifstats-1"
+	peerName = "ifstats-2"
+)
+
+func TestGet(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	for _, name := range []string{ifName, peerName} {
+		link, err := netlink.LinkByName(name)
+		require.NoError(t, err)
+		require.NoError(t, netlink.LinkSetUp(link))
+	}
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	addr, err := netlink.ParseAddr("10.0.32.1/24")
+	require.NoError(t, err)
+	require.NoError(t, netlink.AddrAdd(link, addr))
+
+	before, err := ifstats.Get(ifName)
+	require.NoError(t, err)
+
+	conn, err := net.Dial("udp", "10.0.32.2:5000")
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	for i := 0; i < 10; i++ {
+		_, _ = conn.Write([]byte("ping"))
+	}
+
+	// the packets are sent after the neighbor resolution
+	require.Eventually(t, func() bool {
+		after, err := ifstats.GetAt("file:///proc/self/ns/net", ifName)
+		require.NoError(t, err)
+		return after.Sub(before).TxPackets > 0
+	}, 5*time.Second, 100*time.Millisecond)
+
+	_, err = ifstats.Get("ifstats-none")
+	require.Error(t, err)
+}
+
+func TestStats_Sub(t *testing.T) {
+	current := &ifstats.Stats{RxBytes: 100, TxBytes: 10}
+	prev := &ifstats.Stats{RxBytes: 40, TxBytes: 20}
+
+	// TxBytes counter is reset
+	require.Equal(t, &ifstats.Stats{RxBytes: 60, TxBytes: 10}, current.Sub(prev))
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ipaddrs provides parsing of the IP context IP address fields. IPContext has a single Src and a single Dst
+// IP address field, so dual-stack connections list both IPv4 and IPv6 addresses in it separated with comma, e.g.
+// "10.0.0.1/32,fd00::1/128".
+package ipaddrs
+
+import (
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Separator separates IP addresses in the IP context IP address field
+const Separator = ","
+
+// Parse parses the given IP context IP address field into the list of IP addresses with prefixes, each address
+// should be in the <address>/<prefix> format. Returned IPNet.IP is the address itself, not the network one.
+func Parse(ipAddrs string) ([]*net.IPNet, error) {
+	var result []*net.IPNet
+	for _, ipAddr := range strings.Split(ipAddrs, Separator) {
+		if ipAddr = strings.TrimSpace(ipAddr); ipAddr == "" {
+			continue
+		}
+		ipNet, err := parse(ipAddr)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ipNet)
+	}
+	return result, nil
+}
+
+// First returns the first IP address of the given family from the IP context IP address field, it returns nil if
+// there is no such address. Family is selected by the IP address: nil matches any family.
+func First(ipAddrs string, sameFamily net.IP) (*net.IPNet, error) {
+	ipNets, err := Parse(ipAddrs)
+	if err != nil {
+		return nil, err
+	}
+	for _, ipNet := range ipNets {
+		if sameFamily == nil || IsIPv4(ipNet.IP) == IsIPv4(sameFamily) {
+			return ipNet, nil
+		}
+	}
+	return nil, nil
+}
+
+// IsIPv4 returns true if the given IP address is IPv4 one
+func IsIPv4(ip net.IP) bool {
+	return ip.To4() != nil
+}
+
+// HostMask returns the host mask for the given IP address family
+func HostMask(ip net.IP) net.IPMask {
+	if IsIPv4(ip) {
+		return net.CIDRMask(net.IPv4len*8, net.IPv4len*8)
+	}
+	return net.CIDRMask(net.IPv6len*8, net.IPv6len*8)
+}
+
+func parse(ipAddr string) (*net.IPNet, error) {
+	ip, ipNet, err := net.ParseCIDR(ipAddr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid IP address: %v", ipAddr)
+	}
+	if IsIPv4(ip) {
+		ip = ip.To4()
+	}
+	return &net.IPNet{IP: ip, Mask: ipNet.Mask}, nil
+}
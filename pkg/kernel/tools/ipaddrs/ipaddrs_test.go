@@ -0,0 +1,62 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipaddrs_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ipaddrs"
+)
+
+func TestParse(t *testing.T) {
+	ipNets, err := ipaddrs.Parse(" 10.0.0.1/24, fd00::1/64,10.0.1.1/32,fd00:1::1/128 ,")
+	require.NoError(t, err)
+
+	var result []string
+	for _, ipNet := range ipNets {
+		result = append(result, ipNet.String())
+	}
+	require.Equal(t, []string{"10.0.0.1/24", "fd00::1/64", "10.0.1.1/32", "fd00:1::1/128"}, result)
+
+	ipNets, err = ipaddrs.Parse("")
+	require.NoError(t, err)
+	require.Empty(t, ipNets)
+
+	_, err = ipaddrs.Parse("10.0.0.1/24,fd00::1/129")
+	require.Error(t, err)
+	_, err = ipaddrs.Parse("10.0.0.1")
+	require.Error(t, err)
+}
+
+func TestFirst(t *testing.T) {
+	const ipAddrs = "fd00::1/64,10.0.0.1/24"
+
+	ipNet, err := ipaddrs.First(ipAddrs, nil)
+	require.NoError(t, err)
+	require.Equal(t, "fd00::1/64", ipNet.String())
+
+	ipNet, err = ipaddrs.First(ipAddrs, net.ParseIP("192.168.0.1"))
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1/24", ipNet.String())
+
+	ipNet, err = ipaddrs.First("10.0.0.1/24", net.ParseIP("fd01::1"))
+	require.NoError(t, err)
+	require.Nil(t, ipNet)
+}
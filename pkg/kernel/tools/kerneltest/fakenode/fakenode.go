@@ -0,0 +1,265 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fakenode provides multi-node test harness: fake nodes are net NSes connected with the veth underlay, the
+// kernel Forwarder chains run in the node net NSes and program the pod net NSes, so the end-to-end tests of the kernel
+// chain elements (including tunnels) can run without clusters
+package fakenode
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"path"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// UnderlayIfName is the node underlay net interface name
+const UnderlayIfName = "ul0"
+
+const netNSPath = "/run/netns"
+
+// NetNS is a named net NS of the node or pod
+type NetNS struct {
+	// Name is the node or pod name
+	Name   string
+	handle netns.NsHandle
+	ns     string
+}
+
+func newNetNS(name string) (*NetNS, error) {
+	ns := name + "-" + uuid.New().String()[:8]
+	handle, err := newNamedNetNS(ns)
+	if err != nil {
+		return nil, err
+	}
+	return &NetNS{
+		Name:   name,
+		handle: handle,
+		ns:     ns,
+	}, nil
+}
+
+// Handle returns the net NS handle, it is closed on Close
+func (n *NetNS) Handle() netns.NsHandle {
+	return n.handle
+}
+
+// NetNSURL returns the kernel mechanism net NS URL
+func (n *NetNS) NetNSURL() string {
+	return (&url.URL{Scheme: "file", Path: path.Join(netNSPath, n.ns)}).String()
+}
+
+// Run runs f in the net NS
+func (n *NetNS) Run(f func() error) error {
+	curNetNS, err := nshandle.Current()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = curNetNS.Close() }()
+
+	return nshandle.RunIn(curNetNS, n.handle, f)
+}
+
+func (n *NetNS) close() error {
+	_ = n.handle.Close()
+	return deleteNamedNetNS(n.ns)
+}
+
+// Pod is a fake pod in the node
+type Pod struct {
+	NetNS
+}
+
+// Node is a fake node
+type Node struct {
+	NetNS
+	// UnderlayIP is the node underlay IP address, it is set by Connect
+	UnderlayIP net.IP
+	pods       []*Pod
+}
+
+// NewNode returns a new fake node
+func NewNode(name string) (*Node, error) {
+	netNS, err := newNetNS(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Node{
+		NetNS: *netNS,
+	}, nil
+}
+
+// NewPod returns a new fake pod in the node, it is deleted on the node Close
+func (n *Node) NewPod(name string) (*Pod, error) {
+	netNS, err := newNetNS(name)
+	if err != nil {
+		return nil, err
+	}
+	pod := &Pod{
+		NetNS: *netNS,
+	}
+	n.pods = append(n.pods, pod)
+	return pod, nil
+}
+
+// Close deletes the node with its pods
+func (n *Node) Close() error {
+	var err error
+	for _, pod := range n.pods {
+		if closeErr := pod.close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	n.pods = nil
+	if closeErr := n.close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Connect connects the nodes with the veth underlay: UnderlayIfName net interfaces with the given IP addresses
+func Connect(a, b *Node, aAddr, bAddr string) error {
+	aIPNet, err := netlink.ParseIPNet(aAddr)
+	if err != nil {
+		return errors.Wrapf(err, "invalid underlay IP address: %v", aAddr)
+	}
+	bIPNet, err := netlink.ParseIPNet(bAddr)
+	if err != nil {
+		return errors.Wrapf(err, "invalid underlay IP address: %v", bAddr)
+	}
+
+	peerName := "ul-" + uuid.New().String()[:8]
+	if err := a.Run(func() error {
+		if err := netlink.LinkAdd(&netlink.Veth{
+			LinkAttrs: netlink.LinkAttrs{Name: UnderlayIfName},
+			PeerName:  peerName,
+		}); err != nil {
+			return errors.Wrapf(err, "failed to create underlay: %v %v", a.Name, b.Name)
+		}
+		peer, err := netlink.LinkByName(peerName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get net interface: %v", peerName)
+		}
+		if err := netlink.LinkSetNsFd(peer, int(b.handle)); err != nil {
+			return errors.Wrapf(err, "failed to move net interface to the node: %v %v", peerName, b.Name)
+		}
+		return setupUnderlay(UnderlayIfName, aIPNet)
+	}); err != nil {
+		return err
+	}
+
+	if err := b.Run(func() error {
+		link, err := netlink.LinkByName(peerName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get net interface: %v", peerName)
+		}
+		if err := netlink.LinkSetName(link, UnderlayIfName); err != nil {
+			return errors.Wrapf(err, "failed to rename net interface: %v -> %v", peerName, UnderlayIfName)
+		}
+		return setupUnderlay(UnderlayIfName, bIPNet)
+	}); err != nil {
+		return err
+	}
+
+	a.UnderlayIP, b.UnderlayIP = aIPNet.IP, bIPNet.IP
+
+	return nil
+}
+
+func setupUnderlay(ifName string, ipNet *net.IPNet) error {
+	link, err := netlink.LinkByName(ifName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get net interface: %v", ifName)
+	}
+	if err := netlink.AddrAdd(link, &netlink.Addr{IPNet: ipNet}); err != nil {
+		return errors.Wrapf(err, "failed to add IP address to net interface: %v %v", ifName, ipNet)
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		return errors.Wrapf(err, "failed to set up net interface: %v", ifName)
+	}
+	return nil
+}
+
+type nodeServer struct {
+	node   *Node
+	server networkservice.NetworkServiceServer
+}
+
+// NewServer returns a new server chain element running the wrapped server (e.g. the kernel Forwarder chain) in the
+// node net NS, the following chain elements run in the node net NS too
+func (n *Node) NewServer(server networkservice.NetworkServiceServer) networkservice.NetworkServiceServer {
+	return &nodeServer{
+		node:   n,
+		server: server,
+	}
+}
+
+func (s *nodeServer) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (conn *networkservice.Connection, err error) {
+	err = s.node.Run(func() error {
+		conn, err = s.server.Request(ctx, request)
+		return err
+	})
+	return conn, err
+}
+
+func (s *nodeServer) Close(ctx context.Context, conn *networkservice.Connection) (_ *empty.Empty, err error) {
+	err = s.node.Run(func() error {
+		_, err = s.server.Close(ctx, conn)
+		return err
+	})
+	return &empty.Empty{}, err
+}
+
+type nodeClient struct {
+	node   *Node
+	client networkservice.NetworkServiceClient
+}
+
+// NewClient returns a new client chain element running the wrapped client in the node net NS, the following chain
+// elements run in the node net NS too
+func (n *Node) NewClient(client networkservice.NetworkServiceClient) networkservice.NetworkServiceClient {
+	return &nodeClient{
+		node:   n,
+		client: client,
+	}
+}
+
+func (c *nodeClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (conn *networkservice.Connection, err error) {
+	err = c.node.Run(func() error {
+		conn, err = c.client.Request(ctx, request, opts...)
+		return err
+	})
+	return conn, err
+}
+
+func (c *nodeClient) Close(ctx context.Context, conn *networkservice.Connection, opts ...grpc.CallOption) (_ *empty.Empty, err error) {
+	err = c.node.Run(func() error {
+		_, err = c.client.Close(ctx, conn, opts...)
+		return err
+	})
+	return &empty.Empty{}, err
+}
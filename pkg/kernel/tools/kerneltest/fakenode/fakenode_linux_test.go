@@ -0,0 +1,53 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakenode_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest/fakenode"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/probe"
+)
+
+func TestFakeNodes_Underlay(t *testing.T) {
+	node1, err := fakenode.NewNode("node1")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, node1.Close()) }()
+
+	node2, err := fakenode.NewNode("node2")
+	require.NoError(t, err)
+	defer func() { require.NoError(t, node2.Close()) }()
+
+	require.NoError(t, fakenode.Connect(node1, node2, "172.30.0.1/24", "172.30.0.2/24"))
+
+	require.NoError(t, node1.Run(func() error {
+		_, probeErr := probe.ICMPEcho(context.TODO(), fakenode.UnderlayIfName, node2.UnderlayIP, 3*time.Second)
+		return probeErr
+	}))
+
+	pod, err := node1.NewPod("pod")
+	require.NoError(t, err)
+	require.Error(t, pod.Run(func() error {
+		_, linkErr := netlink.LinkByName(fakenode.UnderlayIfName)
+		return linkErr
+	}))
+}
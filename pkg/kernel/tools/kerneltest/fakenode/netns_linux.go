@@ -0,0 +1,60 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fakenode
+
+import (
+	"runtime"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+func newNamedNetNS(name string) (netns.NsHandle, error) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = curNetNS.Close() }()
+
+	handle, err := netns.NewNamed(name)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to create net NS: %v", name)
+	}
+
+	// netns.NewNamed switches the current thread into the new net NS
+	if err := netns.Set(curNetNS); err != nil {
+		_ = handle.Close()
+		_ = netns.DeleteNamed(name)
+		// the thread is left locked if it remains in the new net NS, so it is terminated with the goroutine
+		runtime.LockOSThread()
+		return 0, errors.Wrapf(err, "failed to switch back to the current net NS: %v", curNetNS)
+	}
+
+	return handle, nil
+}
+
+func deleteNamedNetNS(name string) error {
+	if err := netns.DeleteNamed(name); err != nil {
+		return errors.Wrapf(err, "failed to delete net NS: %v", name)
+	}
+	return nil
+}
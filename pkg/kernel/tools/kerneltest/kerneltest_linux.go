@@ -0,0 +1,119 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kerneltest
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// AddVeth adds a veth pair in the current net NS and returns its first end, the pair is deleted on the test cleanup by
+// any end left in the current net NS
+func AddVeth(t testing.TB, name, peerName string) netlink.Link {
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: name},
+		PeerName:  peerName,
+	}))
+	t.Cleanup(func() {
+		if err := netlink.LinkDel(&netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: name}}); err != nil {
+			_ = netlink.LinkDel(&netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: peerName}})
+		}
+	})
+
+	link, err := netlink.LinkByName(name)
+	require.NoError(t, err)
+	return link
+}
+
+// AddAddr adds the IP address to the net interface in the current net NS and sets the net interface up. The address
+// skips the duplicate address detection, so IPv6 addresses are usable right away.
+func AddAddr(t testing.TB, name, addr string) {
+	link, err := netlink.LinkByName(name)
+	require.NoError(t, err)
+
+	ipNet, err := netlink.ParseIPNet(addr)
+	require.NoError(t, err)
+	require.NoError(t, netlink.AddrAdd(link, &netlink.Addr{IPNet: ipNet, Flags: unix.IFA_F_NODAD}))
+	require.NoError(t, netlink.LinkSetUp(link))
+}
+
+// NewNetNS creates a throwaway net NS and returns its handle, the current thread stays in the current net NS. The net
+// NS is deleted with all its net interfaces on the test cleanup.
+func NewNetNS(t testing.TB) netns.NsHandle {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	netNS, err := netns.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = netNS.Close() })
+
+	require.NoError(t, netns.Set(curNetNS))
+	return netNS
+}
+
+// AddVethPeer adds a veth pair in the current net NS and moves its peer end to a new throwaway net NS. The ends get
+// the given IP addresses and are set up, empty address means no address. It returns the throwaway net NS handle.
+func AddVethPeer(t testing.TB, name, addr, peerName, peerAddr string) netns.NsHandle {
+	netNS := NewNetNS(t)
+
+	AddVeth(t, name, peerName)
+	peer, err := netlink.LinkByName(peerName)
+	require.NoError(t, err)
+	require.NoError(t, netlink.LinkSetNsFd(peer, int(netNS)))
+
+	setUp(t, name, addr)
+	RunIn(t, netNS, func() {
+		setUp(t, peerName, peerAddr)
+	})
+	return netNS
+}
+
+// RunIn runs the runner in the given net NS and switches back to the current one
+func RunIn(t testing.TB, netNS netns.NsHandle, runner func()) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	require.NoError(t, nshandle.RunIn(curNetNS, netNS, func() error {
+		runner()
+		return nil
+	}))
+}
+
+func setUp(t testing.TB, name, addr string) {
+	if addr != "" {
+		AddAddr(t, name, addr)
+		return
+	}
+	link, err := netlink.LinkByName(name)
+	require.NoError(t, err)
+	require.NoError(t, netlink.LinkSetUp(link))
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkprovider
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+type bridgedProvider struct {
+	vethProvider
+	bridgeName string
+}
+
+// NewBridged returns a new LinkProvider creating veth pairs like NewVeth with the peer end attached to the given
+// bridge in the Forwarder's net NS, the bridge is created if it doesn't exist
+func NewBridged(bridgeName string) LinkProvider {
+	return &bridgedProvider{
+		bridgeName: bridgeName,
+	}
+}
+
+func (p *bridgedProvider) CreateLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	bridge, err := p.bridge(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := p.vethProvider.CreateLink(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	peer, err := linkByName(VethPeerName(conn))
+	if err == nil {
+		if err = netlink.LinkSetMaster(peer, bridge); err != nil {
+			err = errors.Wrapf(err, "failed to attach net interface to bridge: %v %v", peer.Attrs().Name, p.bridgeName)
+		} else if err = netlink.LinkSetUp(peer); err != nil {
+			err = errors.Wrapf(err, "failed to set up net interface: %v", peer.Attrs().Name)
+		}
+	}
+	if err != nil {
+		_ = delLink(ctx, link)
+		return nil, err
+	}
+
+	return link, nil
+}
+
+func (p *bridgedProvider) bridge(ctx context.Context) (*netlink.Bridge, error) {
+	link, err := netlink.LinkByName(p.bridgeName)
+	if err != nil {
+		if link, err = addLink(ctx, &netlink.Bridge{
+			LinkAttrs: netlink.LinkAttrs{
+				Name: p.bridgeName,
+			},
+		}); err != nil {
+			return nil, err
+		}
+	}
+	bridge, ok := link.(*netlink.Bridge)
+	if !ok {
+		return nil, errors.Errorf("net interface is not a bridge: %v %v", p.bridgeName, link.Type())
+	}
+	if err := netlink.LinkSetUp(bridge); err != nil {
+		return nil, errors.Wrapf(err, "failed to set up bridge: %v", p.bridgeName)
+	}
+	return bridge, nil
+}
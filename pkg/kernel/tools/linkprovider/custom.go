@@ -0,0 +1,64 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkprovider
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+type customProvider struct {
+	prefix   string
+	template netlink.Link
+}
+
+// NewCustom returns a new LinkProvider creating net interfaces of any kind supported by netlink from the template, e.g.
+// &netlink.Xfrmi{Ifid: 7} or &netlink.Ip6tnl{Local: local, Remote: remote}, for the kinds not modeled by the other
+// providers. The template is copied for each connection and the copy gets the connection unique name with the given
+// prefix (up to 7 characters), the other template fields are applied as is.
+func NewCustom(prefix string, template netlink.Link) LinkProvider {
+	return &customProvider{
+		prefix:   prefix,
+		template: template,
+	}
+}
+
+func (p *customProvider) CreateLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	return addLink(ctx, p.newLink(LinkName(p.prefix, conn)))
+}
+
+func (p *customProvider) AdoptLink(_ context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	return linkByName(LinkName(p.prefix, conn))
+}
+
+func (p *customProvider) DeleteLink(ctx context.Context, _ *networkservice.Connection, link netlink.Link) error {
+	return delLink(ctx, link)
+}
+
+// newLink returns a shallow copy of the template with the given name
+func (p *customProvider) newLink(name string) netlink.Link {
+	value := reflect.New(reflect.TypeOf(p.template).Elem())
+	value.Elem().Set(reflect.ValueOf(p.template).Elem())
+
+	link := value.Interface().(netlink.Link)
+	link.Attrs().Name = name
+	return link
+}
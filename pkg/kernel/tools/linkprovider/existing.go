@@ -0,0 +1,51 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkprovider
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+)
+
+type existingProvider struct{}
+
+// NewExisting returns a new LinkProvider providing already existing Forwarder's net interface named after the
+// mechanism interface name. The net interface is never deleted.
+func NewExisting() LinkProvider {
+	return &existingProvider{}
+}
+
+func (p *existingProvider) CreateLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	return p.AdoptLink(ctx, conn)
+}
+
+func (p *existingProvider) AdoptLink(_ context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	mech := kernel.ToMechanism(conn.GetMechanism())
+	if mech == nil {
+		return nil, errors.New("not a kernel mechanism")
+	}
+	return linkByName(mech.GetInterfaceName(conn))
+}
+
+func (p *existingProvider) DeleteLink(_ context.Context, _ *networkservice.Connection, _ netlink.Link) error {
+	return nil
+}
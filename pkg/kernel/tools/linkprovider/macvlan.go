@@ -0,0 +1,63 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkprovider
+
+import (
+	"context"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+const macvlanPrefix = "nsmmv"
+
+type macvlanProvider struct {
+	parentName string
+	mode       netlink.MacvlanMode
+}
+
+// NewMacvlan returns a new LinkProvider creating macvlan net interfaces in the given mode on the given parent net
+// interface, so multiple connections can share the same uplink
+func NewMacvlan(parentName string, mode netlink.MacvlanMode) LinkProvider {
+	return &macvlanProvider{
+		parentName: parentName,
+		mode:       mode,
+	}
+}
+
+func (p *macvlanProvider) CreateLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	parent, err := linkByName(p.parentName)
+	if err != nil {
+		return nil, err
+	}
+	return addLink(ctx, &netlink.Macvlan{
+		LinkAttrs: netlink.LinkAttrs{
+			Name:        LinkName(macvlanPrefix, conn),
+			ParentIndex: parent.Attrs().Index,
+		},
+		Mode: p.mode,
+	})
+}
+
+func (p *macvlanProvider) AdoptLink(_ context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	return linkByName(LinkName(macvlanPrefix, conn))
+}
+
+func (p *macvlanProvider) DeleteLink(ctx context.Context, _ *networkservice.Connection, link netlink.Link) error {
+	return delLink(ctx, link)
+}
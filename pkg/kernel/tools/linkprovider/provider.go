@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package linkprovider provides pluggable sources of the kernel net interfaces for the connections
+package linkprovider
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/optime"
+)
+
+// LinkProvider provides kernel net interfaces for the connections. All methods are called in the Forwarder's net NS.
+type LinkProvider interface {
+	// CreateLink creates a new net interface for the connection. The interface name is chosen by the provider, it is
+	// renamed to the mechanism interface name on injection into the Client's net NS.
+	CreateLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error)
+	// AdoptLink returns already existing net interface previously created for the connection, it returns an error if
+	// there is no such net interface
+	AdoptLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error)
+	// DeleteLink deletes or releases the given net interface created for the connection
+	DeleteLink(ctx context.Context, conn *networkservice.Connection, link netlink.Link) error
+}
+
+// LinkName returns the connection unique net interface name with the given prefix, the name fits into the linux
+// interface name length limit if the prefix is not longer than 7 characters
+func LinkName(prefix string, conn *networkservice.Connection) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(conn.GetId()))
+	return fmt.Sprintf("%s%08x", prefix, hash.Sum32())
+}
+
+func linkByName(name string) (netlink.Link, error) {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get net interface: %v", name)
+	}
+	return link, nil
+}
+
+func addLink(ctx context.Context, link netlink.Link) (netlink.Link, error) {
+	if err := optime.Time(ctx, "LinkAdd", link, func() error { return netlink.LinkAdd(link) }); err != nil {
+		return nil, errors.Wrapf(err, "failed to create %s net interface: %v", link.Type(), link.Attrs().Name)
+	}
+	return linkByName(link.Attrs().Name)
+}
+
+func delLink(ctx context.Context, link netlink.Link) error {
+	if err := optime.Time(ctx, "LinkDel", link, func() error { return netlink.LinkDel(link) }); err != nil {
+		return errors.Wrapf(err, "failed to delete net interface: %v", link.Attrs().Name)
+	}
+	return nil
+}
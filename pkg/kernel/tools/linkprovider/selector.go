@@ -0,0 +1,101 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkprovider
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+)
+
+const (
+	// LinkTypeKey is a kernel mechanism parameter key with the net interface type requested by the Client
+	LinkTypeKey = "linkType"
+
+	// LinkTypeVeth requests veth net interface
+	LinkTypeVeth = "veth"
+	// LinkTypeTap requests tap net interface, e.g. for VM launchers
+	LinkTypeTap = "tap"
+	// LinkTypeTun requests tun net interface carrying IP packets
+	LinkTypeTun = "tun"
+	// LinkTypeMacvlan requests macvlan net interface
+	LinkTypeMacvlan = "macvlan"
+	// LinkTypeIPVlan requests ipvlan net interface
+	LinkTypeIPVlan = "ipvlan"
+	// LinkTypeVLAN requests 802.1Q VLAN sub-interface, VLAN ID is requested with VLANIDKey
+	LinkTypeVLAN = "vlan"
+)
+
+// LinkType returns the net interface type requested by the connection kernel mechanism, it returns "" if no type is
+// requested
+func LinkType(conn *networkservice.Connection) string {
+	return kernel.ToMechanism(conn.GetMechanism()).GetParameters()[LinkTypeKey]
+}
+
+type selectorProvider struct {
+	defaultProvider LinkProvider
+	providers       map[string]LinkProvider
+}
+
+// NewSelector returns a new LinkProvider delegating to the provider registered for the link type requested with
+// LinkTypeKey mechanism parameter, defaultProvider is used if no type is requested. Requests for the not registered
+// types fail.
+func NewSelector(defaultProvider LinkProvider, providers map[string]LinkProvider) LinkProvider {
+	return &selectorProvider{
+		defaultProvider: defaultProvider,
+		providers:       providers,
+	}
+}
+
+func (p *selectorProvider) CreateLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	provider, err := p.provider(conn)
+	if err != nil {
+		return nil, err
+	}
+	return provider.CreateLink(ctx, conn)
+}
+
+func (p *selectorProvider) AdoptLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	provider, err := p.provider(conn)
+	if err != nil {
+		return nil, err
+	}
+	return provider.AdoptLink(ctx, conn)
+}
+
+func (p *selectorProvider) DeleteLink(ctx context.Context, conn *networkservice.Connection, link netlink.Link) error {
+	provider, err := p.provider(conn)
+	if err != nil {
+		return err
+	}
+	return provider.DeleteLink(ctx, conn, link)
+}
+
+func (p *selectorProvider) provider(conn *networkservice.Connection) (LinkProvider, error) {
+	linkType := LinkType(conn)
+	if linkType == "" {
+		return p.defaultProvider, nil
+	}
+	if provider, ok := p.providers[linkType]; ok {
+		return provider, nil
+	}
+	return nil, errors.Errorf("unsupported link type: %v", linkType)
+}
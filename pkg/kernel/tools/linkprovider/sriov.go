@@ -0,0 +1,59 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkprovider
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/vfconfig"
+)
+
+type sriovProvider struct{}
+
+// NewSRIOV returns a new LinkProvider providing the VF net interface from the connection VFConfig. The VF is never
+// deleted, it just gets its original name back.
+func NewSRIOV() LinkProvider {
+	return &sriovProvider{}
+}
+
+func (p *sriovProvider) CreateLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	return p.AdoptLink(ctx, conn)
+}
+
+func (p *sriovProvider) AdoptLink(ctx context.Context, _ *networkservice.Connection) (netlink.Link, error) {
+	vfConfig, ok := vfconfig.Load(ctx, false)
+	if !ok {
+		return nil, errors.New("no VF config for the connection")
+	}
+	return linkByName(vfConfig.VFInterfaceName)
+}
+
+func (p *sriovProvider) DeleteLink(ctx context.Context, _ *networkservice.Connection, link netlink.Link) error {
+	vfConfig, ok := vfconfig.Load(ctx, false)
+	if !ok || link.Attrs().Name == vfConfig.VFInterfaceName {
+		return nil
+	}
+	if err := netlink.LinkSetName(link, vfConfig.VFInterfaceName); err != nil {
+		return errors.Wrapf(err, "failed to rename net interface: %v -> %v", link.Attrs().Name, vfConfig.VFInterfaceName)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkprovider
+
+import (
+	"context"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+)
+
+const (
+	tapPrefix = "nsmtap"
+	tunPrefix = "nsmtun"
+)
+
+type tapProvider struct {
+	prefix string
+	mode   netlink.TuntapMode
+}
+
+// NewTap returns a new LinkProvider creating persistent tap net interfaces carrying ethernet frames, e.g. for VM
+// launchers. The device can be attached with tuntap.Open in its net NS.
+func NewTap() LinkProvider {
+	return &tapProvider{
+		prefix: tapPrefix,
+		mode:   kernel.TuntapModeTap,
+	}
+}
+
+// NewTun returns a new LinkProvider creating persistent tun net interfaces carrying IP packets. The device can be
+// attached with tuntap.Open in its net NS.
+func NewTun() LinkProvider {
+	return &tapProvider{
+		prefix: tunPrefix,
+		mode:   kernel.TuntapModeTun,
+	}
+}
+
+func (p *tapProvider) CreateLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	return addLink(ctx, &netlink.Tuntap{
+		LinkAttrs: netlink.LinkAttrs{
+			Name: LinkName(p.prefix, conn),
+		},
+		Mode:  p.mode,
+		Flags: kernel.TuntapDefaults,
+	})
+}
+
+func (p *tapProvider) AdoptLink(_ context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	return linkByName(LinkName(p.prefix, conn))
+}
+
+func (p *tapProvider) DeleteLink(ctx context.Context, _ *networkservice.Connection, link netlink.Link) error {
+	return delLink(ctx, link)
+}
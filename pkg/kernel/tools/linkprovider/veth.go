@@ -0,0 +1,61 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkprovider
+
+import (
+	"context"
+
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+const (
+	vethPrefix     = "nsm"
+	vethPeerPrefix = "nsp"
+)
+
+type vethProvider struct{}
+
+// NewVeth returns a new LinkProvider creating veth pairs. One end of the pair is provided for the connection, the peer
+// end stays in the Forwarder's net NS, its name can be get with VethPeerName.
+func NewVeth() LinkProvider {
+	return &vethProvider{}
+}
+
+// VethPeerName returns name of the Forwarder's end of the veth pair created for the connection
+func VethPeerName(conn *networkservice.Connection) string {
+	return LinkName(vethPeerPrefix, conn)
+}
+
+func (p *vethProvider) CreateLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	return addLink(ctx, &netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{
+			Name: LinkName(vethPrefix, conn),
+		},
+		PeerName: VethPeerName(conn),
+	})
+}
+
+func (p *vethProvider) AdoptLink(_ context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	return linkByName(LinkName(vethPrefix, conn))
+}
+
+func (p *vethProvider) DeleteLink(ctx context.Context, _ *networkservice.Connection, link netlink.Link) error {
+	// deleting one end of veth pair deletes the peer end too
+	return delLink(ctx, link)
+}
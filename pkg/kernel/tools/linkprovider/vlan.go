@@ -0,0 +1,90 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkprovider
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+)
+
+const (
+	// VLANIDKey is a kernel mechanism parameter key with the 802.1Q VLAN ID of the VLAN sub-interface
+	VLANIDKey = "vlanID"
+
+	vlanPrefix = "nsmvl"
+	maxVLANID  = 4094
+)
+
+// VLANID returns the connection VLAN ID: VLANIDKey kernel mechanism parameter or ethernet context VLAN tag if there
+// is no such parameter
+func VLANID(conn *networkservice.Connection) (int, error) {
+	vlanID := int(conn.GetContext().GetEthernetContext().GetVlanTag())
+	if value, ok := kernel.ToMechanism(conn.GetMechanism()).GetParameters()[VLANIDKey]; ok {
+		var err error
+		if vlanID, err = strconv.Atoi(value); err != nil {
+			return 0, errors.Errorf("invalid VLAN ID: %v", value)
+		}
+	}
+	if vlanID < 1 || vlanID > maxVLANID {
+		return 0, errors.Errorf("invalid VLAN ID: %v", vlanID)
+	}
+	return vlanID, nil
+}
+
+type vlanProvider struct {
+	baseName string
+}
+
+// NewVLAN returns a new LinkProvider creating 802.1Q VLAN sub-interfaces with the connection VLAN ID (see VLANID) on
+// the given base net interface
+func NewVLAN(baseName string) LinkProvider {
+	return &vlanProvider{
+		baseName: baseName,
+	}
+}
+
+func (p *vlanProvider) CreateLink(ctx context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	vlanID, err := VLANID(conn)
+	if err != nil {
+		return nil, err
+	}
+	base, err := linkByName(p.baseName)
+	if err != nil {
+		return nil, err
+	}
+	return addLink(ctx, &netlink.Vlan{
+		LinkAttrs: netlink.LinkAttrs{
+			Name:        LinkName(vlanPrefix, conn),
+			ParentIndex: base.Attrs().Index,
+		},
+		VlanId: vlanID,
+	})
+}
+
+func (p *vlanProvider) AdoptLink(_ context.Context, conn *networkservice.Connection) (netlink.Link, error) {
+	return linkByName(LinkName(vlanPrefix, conn))
+}
+
+func (p *vlanProvider) DeleteLink(ctx context.Context, _ *networkservice.Connection, link netlink.Link) error {
+	return delLink(ctx, link)
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metamap provides the access to the connection metadata for the chain elements working with and without
+// metadata chain element in the chain
+package metamap
+
+import (
+	"context"
+	"sync"
+
+	"github.com/networkservicemesh/sdk/pkg/networkservice/utils/metadata"
+)
+
+// Load returns the client (or server) per connection metadata map, it returns false if there is no metadata chain
+// element in the chain. metadata.Map panics in such case, so the chain elements keeping the state in metadata use Load
+// to fall back to the stateless behavior instead.
+func Load(ctx context.Context, isClient bool) (m *sync.Map, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			m, ok = nil, false
+		}
+	}()
+	return metadata.Map(ctx, isClient), true
+}
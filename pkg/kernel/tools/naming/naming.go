@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package naming provides net interface naming strategies
+package naming
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+)
+
+const maxPrefixLength = 3
+
+// NameFunc returns net interface name for the connection
+type NameFunc func(conn *networkservice.Connection) string
+
+// Default returns NameFunc returning the mechanism interface name
+func Default() NameFunc {
+	return func(conn *networkservice.Connection) string {
+		return kernel.ToMechanism(conn.GetMechanism()).GetInterfaceName(conn)
+	}
+}
+
+// PathAware returns NameFunc returning names like "nsm-1a2b3c4d-2":
+//   - prefix truncated to 3 characters;
+//   - 4 hex digits hash of the source NSC name (the first path segment name);
+//   - 4 hex digits hash of the end-to-end connection ID (the first path segment ID);
+//   - the current path segment index.
+//
+// Interfaces belonging to the same end-to-end connection get the same hashes on all the nodes, so they can be
+// easily correlated.
+func PathAware(prefix string) NameFunc {
+	if len(prefix) > maxPrefixLength {
+		prefix = prefix[:maxPrefixLength]
+	}
+	return func(conn *networkservice.Connection) string {
+		var nscName, connID string
+		if segments := conn.GetPath().GetPathSegments(); len(segments) != 0 {
+			nscName, connID = segments[0].GetName(), segments[0].GetId()
+		} else {
+			connID = conn.GetId()
+		}
+
+		name := fmt.Sprintf("%s-%04x%04x-%x", prefix, hash16(nscName), hash16(connID), conn.GetPath().GetIndex())
+		if len(name) > kernel.LinuxIfMaxLength {
+			name = name[:kernel.LinuxIfMaxLength]
+		}
+		return name
+	}
+}
+
+func hash16(s string) uint16 {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(s))
+	sum := hash.Sum32()
+	return uint16(sum>>16) ^ uint16(sum)
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package naming_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/naming"
+)
+
+func TestPathAware(t *testing.T) {
+	path := func(index uint32) *networkservice.Path {
+		return &networkservice.Path{
+			Index: index,
+			PathSegments: []*networkservice.PathSegment{
+				{Name: "nsc-1", Id: "conn-1"},
+				{Name: "forwarder-1", Id: "conn-2"},
+				{Name: "forwarder-2", Id: "conn-3"},
+			},
+		}
+	}
+
+	nameFunc := naming.PathAware("nsmx")
+	first := nameFunc(&networkservice.Connection{Id: "conn-2", Path: path(1)})
+	second := nameFunc(&networkservice.Connection{Id: "conn-3", Path: path(2)})
+
+	require.Regexp(t, `^nsm-[0-9a-f]{8}-1$`, first)
+	require.Regexp(t, `^nsm-[0-9a-f]{8}-2$`, second)
+	require.Equal(t, first[:len(first)-1], second[:len(second)-1])
+}
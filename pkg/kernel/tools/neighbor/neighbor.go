@@ -0,0 +1,56 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build !linux
+
+// Package neighbor provides batched neighbors programming for the large neighbor sets
+package neighbor
+
+import (
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+// Add adds the neighbors in the current net NS, already existing neighbors are kept as is
+func Add(_ []*netlink.Neigh) error {
+	return errors.New("batched neighbors programming is supported only on linux")
+}
+
+// AddAt adds the neighbors in the given net NS, already existing neighbors are kept as is
+func AddAt(_ netns.NsHandle, _ []*netlink.Neigh) error {
+	return errors.New("batched neighbors programming is supported only on linux")
+}
+
+// Set adds or replaces the neighbors in the current net NS
+func Set(_ []*netlink.Neigh) error {
+	return errors.New("batched neighbors programming is supported only on linux")
+}
+
+// SetAt adds or replaces the neighbors in the given net NS
+func SetAt(_ netns.NsHandle, _ []*netlink.Neigh) error {
+	return errors.New("batched neighbors programming is supported only on linux")
+}
+
+// Delete deletes the neighbors in the current net NS
+func Delete(_ []*netlink.Neigh) error {
+	return errors.New("batched neighbors programming is supported only on linux")
+}
+
+// DeleteAt deletes the neighbors in the given net NS
+func DeleteAt(_ netns.NsHandle, _ []*netlink.Neigh) error {
+	return errors.New("batched neighbors programming is supported only on linux")
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package neighbor provides batched neighbors programming for the large neighbor sets
+package neighbor
+
+import (
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
+)
+
+// batchSize is a number of requests sent in a single message, it is limited so the acks don't overflow the socket
+// receive buffer
+const batchSize = 128
+
+// Values from the linux/neighbour.h
+const (
+	ndaDst    = 1
+	ndaLLAddr = 2
+)
+
+// Add adds the neighbors in the current net NS, already existing neighbors are kept as is. Requests are sent in batches
+// of multiple RTM_NEWNEIGH messages, so programming hundreds of neighbors doesn't cost hundreds of netlink round trips.
+func Add(neighs []*netlink.Neigh) error {
+	return AddAt(netns.None(), neighs)
+}
+
+// AddAt adds the neighbors in the given net NS, already existing neighbors are kept as is. The calling goroutine net
+// NS is not switched.
+func AddAt(netNS netns.NsHandle, neighs []*netlink.Neigh) error {
+	return execute(netNS, unix.RTM_NEWNEIGH, unix.NLM_F_CREATE|unix.NLM_F_EXCL, neighs, unix.EEXIST)
+}
+
+// Set adds or replaces the neighbors in the current net NS in batches
+func Set(neighs []*netlink.Neigh) error {
+	return SetAt(netns.None(), neighs)
+}
+
+// SetAt adds or replaces the neighbors in the given net NS in batches, the calling goroutine net NS is not switched
+func SetAt(netNS netns.NsHandle, neighs []*netlink.Neigh) error {
+	return execute(netNS, unix.RTM_NEWNEIGH, unix.NLM_F_CREATE|unix.NLM_F_REPLACE, neighs, 0)
+}
+
+// Delete deletes the neighbors in the current net NS in batches, already not existing neighbors are ignored
+func Delete(neighs []*netlink.Neigh) error {
+	return DeleteAt(netns.None(), neighs)
+}
+
+// DeleteAt deletes the neighbors in the given net NS in batches, already not existing neighbors are ignored
+func DeleteAt(netNS netns.NsHandle, neighs []*netlink.Neigh) error {
+	return execute(netNS, unix.RTM_DELNEIGH, 0, neighs, unix.ENOENT)
+}
+
+func execute(netNS netns.NsHandle, msgType, flags int, neighs []*netlink.Neigh, ignored syscall.Errno) error {
+	if len(neighs) == 0 {
+		return nil
+	}
+
+	s, err := nl.GetNetlinkSocketAt(netNS, netns.None(), unix.NETLINK_ROUTE)
+	if err != nil {
+		return errors.Wrap(err, "failed to open netlink socket")
+	}
+	defer s.Close()
+
+	var errs []string
+	for start := 0; start < len(neighs); start += batchSize {
+		end := start + batchSize
+		if end > len(neighs) {
+			end = len(neighs)
+		}
+
+		batchErrs, err := executeBatch(s, msgType, flags, neighs[start:end], ignored)
+		if err != nil {
+			return err
+		}
+		errs = append(errs, batchErrs...)
+	}
+
+	if len(errs) != 0 {
+		return errors.Errorf("failed to program neighbors: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// executeBatch sends the batch in a single message and waits for all the acks, it returns per neighbor errors
+func executeBatch(s *nl.NetlinkSocket, msgType, flags int, neighs []*netlink.Neigh, ignored syscall.Errno) ([]string, error) {
+	pending := make(map[uint32]*netlink.Neigh, len(neighs))
+	var data []byte
+	for _, neigh := range neighs {
+		req := nl.NewNetlinkRequest(msgType, flags|unix.NLM_F_ACK)
+		addNeighData(req, neigh)
+		pending[req.Seq] = neigh
+		data = append(data, req.Serialize()...)
+	}
+
+	if err := unix.Sendto(s.GetFd(), data, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		return nil, errors.Wrap(err, "failed to send netlink message")
+	}
+
+	var errs []string
+	for len(pending) != 0 {
+		msgs, _, err := s.Receive()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to receive netlink message")
+		}
+		for i := range msgs {
+			neigh, ok := pending[msgs[i].Header.Seq]
+			if !ok || msgs[i].Header.Type != unix.NLMSG_ERROR {
+				continue
+			}
+			delete(pending, msgs[i].Header.Seq)
+
+			if errno := syscall.Errno(-int32(nl.NativeEndian().Uint32(msgs[i].Data[0:4]))); errno != 0 && errno != ignored {
+				errs = append(errs, errors.Wrapf(errno, "%v", neigh.IP).Error())
+			}
+		}
+	}
+	return errs, nil
+}
+
+func addNeighData(req *nl.NetlinkRequest, neigh *netlink.Neigh) {
+	family := neigh.Family
+	if family == 0 {
+		family = nl.GetIPFamily(neigh.IP)
+	}
+	req.AddData(&netlink.Ndmsg{
+		Family: uint8(family),
+		Index:  uint32(neigh.LinkIndex),
+		State:  uint16(neigh.State),
+		Type:   uint8(neigh.Type),
+		Flags:  uint8(neigh.Flags),
+	})
+
+	ip := neigh.IP.To4()
+	if ip == nil {
+		ip = neigh.IP.To16()
+	}
+	req.AddData(nl.NewRtAttr(ndaDst, ip))
+	if neigh.HardwareAddr != nil {
+		req.AddData(nl.NewRtAttr(ndaLLAddr, neigh.HardwareAddr))
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package neighbor_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/neighbor"
+)
+
+const (
+	ifName   = "neigh-1"
+	peerName = "neigh-2"
+	count    = 500
+)
+
+// newLink adds a veth pair in a throwaway net NS, the pair is deleted with the net NS
+func newLink(t testing.TB) (netns.NsHandle, *netlink.Handle, netlink.Link) {
+	netNS := kerneltest.NewNetNS(t)
+
+	handle, err := netlink.NewHandleAt(netNS)
+	require.NoError(t, err)
+	t.Cleanup(handle.Delete)
+
+	require.NoError(t, handle.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: ifName},
+		PeerName:  peerName,
+	}))
+	link, err := handle.LinkByName(ifName)
+	require.NoError(t, err)
+
+	return netNS, handle, link
+}
+
+func neighs(link netlink.Link, macByte byte) []*netlink.Neigh {
+	var result []*netlink.Neigh
+	for i := 0; i < count; i++ {
+		result = append(result, &netlink.Neigh{
+			LinkIndex:    link.Attrs().Index,
+			State:        netlink.NUD_PERMANENT,
+			IP:           net.IPv4(10, 0, byte(i/256), byte(i%256)),
+			HardwareAddr: net.HardwareAddr{0x02, 0, 0, macByte, byte(i / 256), byte(i % 256)},
+		})
+	}
+	return result
+}
+
+func macAddrs(t *testing.T, handle *netlink.Handle, link netlink.Link) map[string]string {
+	list, err := handle.NeighList(link.Attrs().Index, kernel.FamilyV4)
+	require.NoError(t, err)
+
+	result := make(map[string]string, len(list))
+	for i := range list {
+		result[list[i].IP.String()] = list[i].HardwareAddr.String()
+	}
+	return result
+}
+
+func TestAddDelete(t *testing.T) {
+	netNS, handle, link := newLink(t)
+
+	require.NoError(t, neighbor.AddAt(netNS, neighs(link, 0)))
+	require.Len(t, macAddrs(t, handle, link), count)
+
+	// already existing neighbors are kept as is
+	require.NoError(t, neighbor.AddAt(netNS, neighs(link, 1)))
+	require.Equal(t, "02:00:00:00:00:01", macAddrs(t, handle, link)["10.0.0.1"])
+
+	require.NoError(t, neighbor.DeleteAt(netNS, neighs(link, 0)))
+	// deleting not existing neighbors is not an error
+	require.NoError(t, neighbor.DeleteAt(netNS, neighs(link, 0)))
+	require.Empty(t, macAddrs(t, handle, link))
+}
+
+func TestSet(t *testing.T) {
+	netNS, handle, link := newLink(t)
+
+	require.NoError(t, neighbor.SetAt(netNS, neighs(link, 0)))
+	require.Len(t, macAddrs(t, handle, link), count)
+
+	// already existing neighbors are replaced
+	require.NoError(t, neighbor.SetAt(netNS, neighs(link, 1)))
+	require.Len(t, macAddrs(t, handle, link), count)
+	require.Equal(t, "02:00:00:01:00:01", macAddrs(t, handle, link)["10.0.0.1"])
+}
+
+func TestAdd_Error(t *testing.T) {
+	require.Error(t, neighbor.Add([]*netlink.Neigh{{
+		LinkIndex: 1 << 30,
+		State:     netlink.NUD_PERMANENT,
+		IP:        net.IPv4(10, 0, 0, 1),
+	}}))
+}
+
+func BenchmarkAdd_Batched(b *testing.B) {
+	netNS, _, link := newLink(b)
+	list := neighs(link, 0)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		require.NoError(b, neighbor.AddAt(netNS, list))
+		require.NoError(b, neighbor.DeleteAt(netNS, list))
+	}
+}
+
+func BenchmarkAdd_Sequential(b *testing.B) {
+	_, handle, link := newLink(b)
+	list := neighs(link, 0)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, neigh := range list {
+			require.NoError(b, handle.NeighAdd(neigh))
+		}
+		for _, neigh := range list {
+			require.NoError(b, handle.NeighDel(neigh))
+		}
+	}
+}
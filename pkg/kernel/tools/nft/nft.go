@@ -0,0 +1,162 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nft provides utils for the atomic nftables programming. Every connection owns a separate nftables table,
+// the table is always replaced as a whole in a single nftables transaction, so partially programmed rule sets are
+// never visible and a failed programming leaves nothing behind.
+package nft
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FamilyInet is nftables family for both IPv4 and IPv6 rules
+	FamilyInet = "inet"
+	// FamilyIP is nftables family for IPv4 rules
+	FamilyIP = "ip"
+	// FamilyIP6 is nftables family for IPv6 rules
+	FamilyIP6 = "ip6"
+
+	tablePrefix = "nsm_"
+)
+
+// Binary is a path to the nft binary used to program rules
+var Binary = "nft"
+
+// Table is a nftables table owned by a single connection. Objects are stateful object declarations like
+// `ct helper ftp { type "ftp" protocol tcp; }`.
+type Table struct {
+	Family  string
+	Name    string
+	Objects []string
+	Chains  []*Chain
+}
+
+// Chain is a nftables chain. Base chain is created if Hook is set, otherwise regular chain is created.
+type Chain struct {
+	Name     string
+	Type     string
+	Hook     string
+	Priority int
+	Policy   string
+	Rules    []string
+}
+
+// TableName returns the nftables table name for the given connection
+func TableName(connID string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(connID))
+	return fmt.Sprintf("%s%08x", tablePrefix, hash.Sum32())
+}
+
+// NewTable returns a new empty Table for the given connection
+func NewTable(family, connID string) *Table {
+	return &Table{
+		Family: family,
+		Name:   TableName(connID),
+	}
+}
+
+// AddChain adds a new chain to the table and returns it
+func (t *Table) AddChain(chain *Chain) *Chain {
+	t.Chains = append(t.Chains, chain)
+	return chain
+}
+
+// AddObject adds a new stateful object declaration to the table
+func (t *Table) AddObject(format string, args ...interface{}) {
+	t.Objects = append(t.Objects, fmt.Sprintf(format, args...))
+}
+
+// AddRule adds a new rule to the chain
+func (c *Chain) AddRule(format string, args ...interface{}) {
+	c.Rules = append(c.Rules, fmt.Sprintf(format, args...))
+}
+
+// String returns nft script atomically replacing the table
+func (t *Table) String() string {
+	builder := new(strings.Builder)
+
+	writeDelete(builder, t.Family, t.Name)
+	_, _ = fmt.Fprintf(builder, "table %s %s {\n", t.Family, t.Name)
+	for _, object := range t.Objects {
+		_, _ = fmt.Fprintf(builder, "\t%s\n", object)
+	}
+	for _, chain := range t.Chains {
+		_, _ = fmt.Fprintf(builder, "\tchain %s {\n", chain.Name)
+		if chain.Hook != "" {
+			_, _ = fmt.Fprintf(builder, "\t\ttype %s hook %s priority %d;", chain.Type, chain.Hook, chain.Priority)
+			if chain.Policy != "" {
+				_, _ = fmt.Fprintf(builder, " policy %s;", chain.Policy)
+			}
+			builder.WriteString("\n")
+		}
+		for _, rule := range chain.Rules {
+			_, _ = fmt.Fprintf(builder, "\t\t%s\n", rule)
+		}
+		builder.WriteString("\t}\n")
+	}
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+// Apply atomically replaces the table in the current net NS
+func Apply(ctx context.Context, t *Table) error {
+	if err := run(ctx, t.String()); err != nil {
+		return errors.Wrapf(err, "failed to apply nftables table: %v %v", t.Family, t.Name)
+	}
+	return nil
+}
+
+// Delete deletes the table in the current net NS, it is not an error if the table doesn't exist
+func Delete(ctx context.Context, family, name string) error {
+	builder := new(strings.Builder)
+	writeDelete(builder, family, name)
+	if err := run(ctx, builder.String()); err != nil {
+		return errors.Wrapf(err, "failed to delete nftables table: %v %v", family, name)
+	}
+	return nil
+}
+
+// writeDelete writes table deletion which doesn't fail if the table doesn't exist: adding already existing table is
+// not an error, so the table is first added and then deleted
+func writeDelete(builder *strings.Builder, family, name string) {
+	_, _ = fmt.Fprintf(builder, "add table %s %s\n", family, name)
+	_, _ = fmt.Fprintf(builder, "delete table %s %s\n", family, name)
+}
+
+// run runs the given nft script in a single nftables transaction
+func run(ctx context.Context, script string) error {
+	cmd := exec.CommandContext(ctx, Binary, "-f", "-")
+	cmd.Stdin = strings.NewReader(script)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return errors.Wrap(err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
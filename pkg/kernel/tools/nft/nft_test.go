@@ -0,0 +1,52 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nft_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nft"
+)
+
+func TestTable_String(t *testing.T) {
+	table := nft.NewTable(nft.FamilyInet, "conn-1")
+	table.AddObject(`ct helper ftp { type "ftp" protocol tcp; }`)
+	chain := table.AddChain(&nft.Chain{
+		Name:   "forward",
+		Type:   "filter",
+		Hook:   "forward",
+		Policy: "accept",
+	})
+	chain.AddRule("iifname %q accept", "nsm-1")
+	table.AddChain(&nft.Chain{Name: "regular"})
+
+	name := nft.TableName("conn-1")
+	require.Equal(t, ""+
+		"add table inet "+name+"\n"+
+		"delete table inet "+name+"\n"+
+		"table inet "+name+" {\n"+
+		"\tct helper ftp { type \"ftp\" protocol tcp; }\n"+
+		"\tchain forward {\n"+
+		"\t\ttype filter hook forward priority 0; policy accept;\n"+
+		"\t\tiifname \"nsm-1\" accept\n"+
+		"\t}\n"+
+		"\tchain regular {\n"+
+		"\t}\n"+
+		"}\n", table.String())
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nodelock provides node level advisory locks of the kernel resources (net interface names, VNIs, routing
+// table IDs, ports), so multiple Forwarder instances on the same node don't allocate conflicting resources
+package nodelock
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// DefaultDir is the default node state directory for the lock files, it should be shared by all Forwarder instances
+// on the node
+const DefaultDir = "/run/networkservicemesh/sdk-kernel/locks"
+
+// Resource kinds
+const (
+	KindIfName  = "ifname"
+	KindVNI     = "vni"
+	KindTableID = "table"
+	KindPort    = "port"
+)
+
+// LockedError is returned when the resource is already locked by another Lock
+type LockedError struct {
+	Kind string
+	Name string
+}
+
+func (e *LockedError) Error() string {
+	return "resource is already locked: " + e.Kind + "/" + e.Name
+}
+
+// IsLocked returns true if err is caused by LockedError
+func IsLocked(err error) bool {
+	_, ok := errors.Cause(err).(*LockedError)
+	return ok
+}
+
+// Locker creates the locks with flock on the files in the state directory. Locks are released by the kernel when the
+// holding process dies, so there are no stale locks after the Forwarder crash.
+type Locker struct {
+	dir string
+}
+
+// NewLocker returns a new Locker using the given state directory, it creates the directory if needed
+func NewLocker(dir string) (*Locker, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, errors.Wrapf(err, "failed to create lock directory: %v", dir)
+	}
+	return &Locker{
+		dir: dir,
+	}, nil
+}
+
+// Lock is a held resource lock
+type Lock struct {
+	file *os.File
+}
+
+// TryLock locks the resource of the given kind with the given name, it doesn't wait and returns LockedError if the
+// resource is already locked by another Lock (in any process on the node)
+func (l *Locker) TryLock(kind, name string) (*Lock, error) {
+	path, err := l.path(kind, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, errors.Wrapf(err, "failed to create lock directory: %v", filepath.Dir(path))
+	}
+
+	for {
+		file, err := os.OpenFile(filepath.Clean(path), os.O_RDWR|os.O_CREATE, 0o600)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open lock file: %v", path)
+		}
+
+		if err := unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB); err != nil {
+			_ = file.Close()
+			if err == unix.EWOULDBLOCK {
+				return nil, &LockedError{Kind: kind, Name: name}
+			}
+			return nil, errors.Wrapf(err, "failed to lock file: %v", path)
+		}
+
+		// the file could be unlinked by the previous holder between open and flock, so the lock is taken on the
+		// already removed file
+		if same, err := sameFile(file, path); err != nil || !same {
+			_ = file.Close()
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		return &Lock{file: file}, nil
+	}
+}
+
+// Unlock releases the lock
+func (lk *Lock) Unlock() error {
+	path := lk.file.Name()
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		_ = lk.file.Close()
+		return errors.Wrapf(err, "failed to remove lock file: %v", path)
+	}
+	if err := lk.file.Close(); err != nil {
+		return errors.Wrapf(err, "failed to close lock file: %v", path)
+	}
+	return nil
+}
+
+func (l *Locker) path(kind, name string) (string, error) {
+	for _, part := range []string{kind, name} {
+		if part == "" || part == "." || part == ".." {
+			return "", errors.Errorf("invalid lock name: %v/%v", kind, name)
+		}
+	}
+	return filepath.Join(l.dir, url.PathEscape(kind), url.PathEscape(name)), nil
+}
+
+func sameFile(file *os.File, path string) (bool, error) {
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to stat lock file: %v", path)
+	}
+	pathInfo, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to stat lock file: %v", path)
+	}
+	return os.SameFile(fileInfo, pathInfo), nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nodelock_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nodelock"
+)
+
+func TestLocker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "nodelock")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// lockers in the different Forwarder instances
+	locker1, err := nodelock.NewLocker(dir)
+	require.NoError(t, err)
+	locker2, err := nodelock.NewLocker(dir)
+	require.NoError(t, err)
+
+	lock, err := locker1.TryLock(nodelock.KindIfName, "nsm-1")
+	require.NoError(t, err)
+
+	_, err = locker2.TryLock(nodelock.KindIfName, "nsm-1")
+	require.True(t, nodelock.IsLocked(err))
+	require.True(t, nodelock.IsLocked(errors.Wrap(err, "wrapped")))
+
+	// the same name of another kind is another resource
+	vniLock, err := locker2.TryLock(nodelock.KindVNI, "nsm-1")
+	require.NoError(t, err)
+	require.NoError(t, vniLock.Unlock())
+
+	require.NoError(t, lock.Unlock())
+
+	lock, err = locker2.TryLock(nodelock.KindIfName, "nsm-1")
+	require.NoError(t, err)
+	require.NoError(t, lock.Unlock())
+
+	_, err = locker1.TryLock(nodelock.KindPort, "..")
+	require.Error(t, err)
+	require.False(t, nodelock.IsLocked(err))
+
+	lock, err = locker1.TryLock(nodelock.KindPort, "10.0.0.1/udp:4789")
+	require.NoError(t, err)
+	require.NoError(t, lock.Unlock())
+}
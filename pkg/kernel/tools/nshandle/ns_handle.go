@@ -23,6 +23,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/vishvananda/netns"
+	"golang.org/x/sys/unix"
 )
 
 // Current creates net NS handle for the current net NS
@@ -33,13 +34,20 @@ func Current() (handle netns.NsHandle, err error) {
 	return netns.Get()
 }
 
-// FromURL creates net NS handle by file://path URL
+// FromURL creates net NS handle by file://path URL, empty URL means the current net NS
 func FromURL(urlString string) (handle netns.NsHandle, err error) {
+	if urlString == "" {
+		return Current()
+	}
+
 	var netNSURL *url.URL
 	netNSURL, err = url.Parse(urlString)
-	if err != nil || netNSURL.Scheme != "file" {
+	if err != nil {
 		return -1, errors.Wrapf(err, "invalid url: %v", urlString)
 	}
+	if netNSURL.Scheme != "file" {
+		return -1, errors.Errorf("invalid url: %v", urlString)
+	}
 
 	handle, err = netns.GetFromPath(netNSURL.Path)
 	if err != nil {
@@ -49,8 +57,10 @@ func FromURL(urlString string) (handle netns.NsHandle, err error) {
 	return handle, nil
 }
 
-// RunIn runs runner in the given net NS
-func RunIn(current, target netns.NsHandle, runner func() error) error {
+// RunIn runs runner in the given net NS. If it fails to switch back to the current net NS, the calling goroutine is
+// left locked to the OS thread, so the thread is never reused by other goroutines and gets terminated on the goroutine
+// exit.
+func RunIn(current, target netns.NsHandle, runner func() error) (err error) {
 	runtime.LockOSThread()
 	defer runtime.UnlockOSThread()
 
@@ -69,11 +79,47 @@ func RunIn(current, target netns.NsHandle, runner func() error) error {
 			return errors.Wrapf(err, "failed to switch to the target net NS: %v", target)
 		}
 		defer func() {
-			if err := netns.Set(current); err != nil {
-				panic(errors.Wrapf(err, "failed to switch back to the current net NS: %v", current).Error())
+			if setErr := netns.Set(current); setErr != nil {
+				runtime.LockOSThread()
+				setErr = errors.Wrapf(setErr, "failed to switch back to the current net NS: %v", current)
+				if err != nil {
+					setErr = errors.Wrap(err, setErr.Error())
+				}
+				err = setErr
 			}
 		}()
 	}
 
 	return runner()
 }
+
+// RunInURL runs runner in the net NS given by file://path URL, empty URL means the current net NS, so runner is run
+// without switching the net NS
+func RunInURL(netNSURL string, runner func() error) error {
+	if netNSURL == "" {
+		return runner()
+	}
+
+	current, err := Current()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = current.Close() }()
+
+	target, err := FromURL(netNSURL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = target.Close() }()
+
+	return RunIn(current, target, runner)
+}
+
+// Inode returns the net NS inode number identifying the net NS on the node, it is the same as in `ls -iL /proc/$pid/ns/net`
+func Inode(handle netns.NsHandle) (uint64, error) {
+	var stat unix.Stat_t
+	if err := unix.Fstat(int(handle), &stat); err != nil {
+		return 0, errors.Wrapf(err, "failed to stat net NS handle: %v", handle)
+	}
+	return stat.Ino, nil
+}
@@ -17,8 +17,10 @@
 package nshandle_test
 
 import (
+	"os"
 	"runtime"
 	"sync"
+	"syscall"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -87,3 +89,46 @@ func newNSHandle(t *testing.T) netns.NsHandle {
 
 	return newHandle
 }
+
+func TestNSHandle_Inode(t *testing.T) {
+	current, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = current.Close() }()
+
+	inode, err := nshandle.Inode(current)
+	require.NoError(t, err)
+
+	info, err := os.Stat("/proc/self/ns/net")
+	require.NoError(t, err)
+	require.Equal(t, info.Sys().(*syscall.Stat_t).Ino, inode)
+
+	target := newNSHandle(t)
+	defer func() { _ = target.Close() }()
+
+	targetInode, err := nshandle.Inode(target)
+	require.NoError(t, err)
+	require.NotEqual(t, inode, targetInode)
+}
+
+func TestNSHandle_EmptyURL(t *testing.T) {
+	current, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = current.Close() }()
+
+	handle, err := nshandle.FromURL("")
+	require.NoError(t, err)
+	defer func() { _ = handle.Close() }()
+	require.True(t, current.Equal(handle), equalFormat, current, handle)
+
+	require.NoError(t, nshandle.RunInURL("", func() error {
+		h, err := netns.Get()
+		require.NoError(t, err)
+		defer func() { _ = h.Close() }()
+
+		require.True(t, current.Equal(h), equalFormat, current, h)
+		return nil
+	}))
+
+	_, err = nshandle.FromURL("tcp://127.0.0.1")
+	require.Error(t, err)
+}
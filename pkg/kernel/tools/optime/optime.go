@@ -0,0 +1,198 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package optime provides timing of the kernel operations: per operation duration histograms and logging of the
+// slow operations with the full object detail, so node specific netlink slowness (e.g. caused by the other agents
+// holding rtnl lock) is surfaced
+package optime
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+)
+
+const (
+	// DefaultSlowThreshold is the default duration after which the operation is logged as the slow one
+	DefaultSlowThreshold = 100 * time.Millisecond
+
+	minBucket  = time.Microsecond
+	bucketsLen = 25
+)
+
+// Default is the Recorder used by Time
+var Default = NewRecorder(DefaultSlowThreshold)
+
+// Time runs the kernel operation f and records its duration with the Default Recorder
+func Time(ctx context.Context, op string, object interface{}, f func() error) error {
+	return Default.Time(ctx, op, object, f)
+}
+
+// Bucket is a histogram bucket: number of operations with the duration <= UpperBound and > previous bucket
+// UpperBound. Last bucket UpperBound is 0 meaning +Inf.
+type Bucket struct {
+	UpperBound time.Duration
+	Count      uint64
+}
+
+// Histogram is a snapshot of the operation duration histogram. Buckets upper bounds grow exponentially from 1us to
+// ~16s.
+type Histogram struct {
+	Count   uint64
+	Sum     time.Duration
+	Max     time.Duration
+	Slow    uint64
+	Buckets []Bucket
+}
+
+// Quantile returns the upper bound of the bucket containing the q-quantile (0 < q <= 1), it returns Max for the
+// overflow bucket
+func (h *Histogram) Quantile(q float64) time.Duration {
+	rank := uint64(q*float64(h.Count) + 0.5)
+	if rank == 0 {
+		rank = 1
+	}
+	var count uint64
+	for _, b := range h.Buckets {
+		if count += b.Count; count >= rank {
+			if b.UpperBound == 0 {
+				return h.Max
+			}
+			return b.UpperBound
+		}
+	}
+	return h.Max
+}
+
+type histogram struct {
+	count   uint64
+	sum     time.Duration
+	max     time.Duration
+	slow    uint64
+	buckets [bucketsLen + 1]uint64
+}
+
+func (h *histogram) observe(d time.Duration, slow bool) {
+	h.count++
+	h.sum += d
+	if d > h.max {
+		h.max = d
+	}
+	if slow {
+		h.slow++
+	}
+
+	i := 0
+	for bound := minBucket; i < bucketsLen && d > bound; bound *= 2 {
+		i++
+	}
+	h.buckets[i]++
+}
+
+func (h *histogram) snapshot() *Histogram {
+	result := &Histogram{
+		Count:   h.count,
+		Sum:     h.sum,
+		Max:     h.max,
+		Slow:    h.slow,
+		Buckets: make([]Bucket, 0, len(h.buckets)),
+	}
+	bound := minBucket
+	for i, count := range h.buckets {
+		b := Bucket{Count: count}
+		if i < bucketsLen {
+			b.UpperBound = bound
+			bound *= 2
+		}
+		result.Buckets = append(result.Buckets, b)
+	}
+	return result
+}
+
+// Recorder records the kernel operations durations
+type Recorder struct {
+	lock          sync.Mutex
+	slowThreshold time.Duration
+	histograms    map[string]*histogram
+}
+
+// NewRecorder returns a new Recorder logging operations slower than slowThreshold, 0 disables slow operations logging
+func NewRecorder(slowThreshold time.Duration) *Recorder {
+	return &Recorder{
+		slowThreshold: slowThreshold,
+		histograms:    make(map[string]*histogram),
+	}
+}
+
+// SetSlowThreshold sets the duration after which the operation is logged as the slow one
+func (r *Recorder) SetSlowThreshold(slowThreshold time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.slowThreshold = slowThreshold
+}
+
+// Time runs the kernel operation f and records its duration. If the operation is slow, it is logged with the object
+// it has been applied to.
+func (r *Recorder) Time(ctx context.Context, op string, object interface{}, f func() error) error {
+	start := time.Now()
+	err := f()
+	d := time.Since(start)
+
+	r.lock.Lock()
+	slow := r.slowThreshold > 0 && d > r.slowThreshold
+	h, ok := r.histograms[op]
+	if !ok {
+		h = new(histogram)
+		r.histograms[op] = h
+	}
+	h.observe(d, slow)
+	r.lock.Unlock()
+
+	if slow {
+		log.Entry(ctx).WithField("optime", op).Warnf("slow kernel operation %s took %s (error: %v): %+v", op, d, err, object)
+	}
+
+	return err
+}
+
+// Histogram returns the operation duration histogram snapshot, it returns nil if the operation has never been
+// recorded
+func (r *Recorder) Histogram(op string) *Histogram {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if h, ok := r.histograms[op]; ok {
+		return h.snapshot()
+	}
+	return nil
+}
+
+// Operations returns the recorded operations names
+func (r *Recorder) Operations() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ops := make([]string, 0, len(r.histograms))
+	for op := range r.histograms {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	return ops
+}
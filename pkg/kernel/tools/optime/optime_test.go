@@ -0,0 +1,75 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package optime_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk/pkg/tools/log"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/optime"
+)
+
+type object struct {
+	Name string
+}
+
+func TestRecorder(t *testing.T) {
+	buf := new(bytes.Buffer)
+	ctx := log.WithField(context.Background(), "test", t.Name())
+	logger := log.Entry(ctx).Logger
+	out := logger.Out
+	logger.SetOutput(buf)
+	defer logger.SetOutput(out)
+
+	r := optime.NewRecorder(10 * time.Millisecond)
+
+	for i := 0; i < 9; i++ {
+		require.NoError(t, r.Time(ctx, "LinkAdd", &object{Name: "fast"}, func() error { return nil }))
+	}
+	err := r.Time(ctx, "LinkAdd", &object{Name: "slow"}, func() error {
+		time.Sleep(20 * time.Millisecond)
+		return errors.New("failed")
+	})
+	require.EqualError(t, err, "failed")
+
+	require.Equal(t, []string{"LinkAdd"}, r.Operations())
+	require.Nil(t, r.Histogram("RouteAdd"))
+
+	h := r.Histogram("LinkAdd")
+	require.Equal(t, uint64(10), h.Count)
+	require.Equal(t, uint64(1), h.Slow)
+	require.GreaterOrEqual(t, int64(h.Max), int64(20*time.Millisecond))
+	require.Less(t, int64(h.Quantile(0.5)), int64(10*time.Millisecond))
+	require.GreaterOrEqual(t, int64(h.Quantile(1)), int64(20*time.Millisecond))
+
+	var count uint64
+	for _, b := range h.Buckets {
+		count += b.Count
+	}
+	require.Equal(t, h.Count, count)
+
+	require.Contains(t, buf.String(), "slow kernel operation LinkAdd")
+	require.Contains(t, buf.String(), "Name:slow")
+	require.NotContains(t, buf.String(), "Name:fast")
+}
@@ -0,0 +1,230 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package owned provides marking of the kernel state added by NSM, so resync can list and bulk delete only its own
+// objects: routes are added with kernel.RouteProtoNSM routing protocol, IP addresses and the connection the net
+// interface is created for are stored in the net interface alias
+package owned
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+)
+
+// AddrsAliasPrefix is a prefix of the version 0 net interface alias storing IP addresses added by NSM: the comma
+// separated addresses list. Storing them in the kernel keeps ownership across refreshes and Forwarder restarts, so NSM
+// never deletes IP addresses added by someone else (e.g. CNI). Version 0 aliases are still read, they are migrated to
+// the current version on the next store.
+const AddrsAliasPrefix = "nsm-addrs="
+
+const (
+	// aliasPrefix is a prefix of the versioned net interface alias: "nsm:<version>;<key>=<value>;..."
+	aliasPrefix    = "nsm:"
+	aliasVersion   = 1
+	fieldSeparator = ";"
+	addrsKey       = "addrs"
+	connKey        = "conn"
+)
+
+// ownership is the NSM ownership stored in the net interface alias
+type ownership struct {
+	addrs []*netlink.Addr
+	conn  string
+}
+
+// parseAlias returns the ownership stored in the net interface alias, it returns false if the alias is used by someone
+// else or is written by the newer NSM version, so it cannot be changed
+func parseAlias(alias string) (*ownership, bool) {
+	o := new(ownership)
+	switch {
+	case alias == "":
+		return o, true
+	case strings.HasPrefix(alias, AddrsAliasPrefix):
+		o.addrs = parseAddrs(strings.TrimPrefix(alias, AddrsAliasPrefix))
+		return o, true
+	case !strings.HasPrefix(alias, aliasPrefix):
+		return nil, false
+	}
+
+	fields := strings.Split(strings.TrimPrefix(alias, aliasPrefix), fieldSeparator)
+	if version, err := strconv.Atoi(fields[0]); err != nil || version < 1 || version > aliasVersion {
+		return nil, false
+	}
+	for _, field := range fields[1:] {
+		if value := strings.TrimPrefix(field, addrsKey+"="); value != field {
+			o.addrs = parseAddrs(value)
+		}
+		if value := strings.TrimPrefix(field, connKey+"="); value != field {
+			o.conn = value
+		}
+	}
+	return o, true
+}
+
+// alias returns the net interface alias storing the ownership in the current version
+func (o *ownership) alias() string {
+	if len(o.addrs) == 0 && o.conn == "" {
+		return ""
+	}
+	alias := aliasPrefix + strconv.Itoa(aliasVersion)
+	if o.conn != "" {
+		alias += fieldSeparator + connKey + "=" + o.conn
+	}
+	if len(o.addrs) != 0 {
+		addrStrings := make([]string, 0, len(o.addrs))
+		for _, addr := range o.addrs {
+			addrStrings = append(addrStrings, addr.IPNet.String())
+		}
+		alias += fieldSeparator + addrsKey + "=" + strings.Join(addrStrings, ",")
+	}
+	return alias
+}
+
+// connHash returns the connection ID hash stored in the alias instead of the ID: it has the fixed length and never
+// contains the alias separators
+func connHash(connID string) string {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(connID))
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+func parseAddrs(value string) (addrs []*netlink.Addr) {
+	for _, addrString := range strings.Split(value, ",") {
+		if addr, err := netlink.ParseAddr(addrString); err == nil {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// Addrs returns IP addresses added by NSM to the net interface, it returns false if the net interface alias is used
+// by someone else, so ownership cannot be stored
+func Addrs(link netlink.Link) ([]*netlink.Addr, bool) {
+	o, ok := parseAlias(link.Attrs().Alias)
+	if !ok {
+		return nil, false
+	}
+	return o.addrs, true
+}
+
+// SetAddrs stores IP addresses added by NSM to the net interface
+func SetAddrs(link netlink.Link, addrs []*netlink.Addr) error {
+	return SetAddrsAt(&netlink.Handle{}, link, addrs)
+}
+
+// SetAddrsAt stores IP addresses added by NSM to the net interface using the given netlink handle
+func SetAddrsAt(handle *netlink.Handle, link netlink.Link, addrs []*netlink.Addr) error {
+	o, ok := parseAlias(link.Attrs().Alias)
+	if !ok {
+		return errors.Errorf("the net interface alias is used by someone else: %v %v", link.Attrs().Name, link.Attrs().Alias)
+	}
+	o.addrs = addrs
+	return setAlias(handle, link, o.alias())
+}
+
+// IsForeign returns true if the net interface alias is used by someone else (e.g. set by the operator for the physical
+// device), so no ownership can be stored in it
+func IsForeign(link netlink.Link) bool {
+	_, ok := parseAlias(link.Attrs().Alias)
+	return !ok
+}
+
+// IsConnLink returns true if the net interface is marked as created by NSM for the connection with the given ID
+func IsConnLink(link netlink.Link, connID string) bool {
+	o, ok := parseAlias(link.Attrs().Alias)
+	return ok && o.conn != "" && o.conn == connHash(connID)
+}
+
+// SetConnAt marks the net interface as created by NSM for the connection with the given ID using the given netlink
+// handle, so it can be told apart from the net interface with the same name created by someone else
+func SetConnAt(handle *netlink.Handle, link netlink.Link, connID string) error {
+	o, ok := parseAlias(link.Attrs().Alias)
+	if !ok {
+		return errors.Errorf("the net interface alias is used by someone else: %v %v", link.Attrs().Name, link.Attrs().Alias)
+	}
+	o.conn = connHash(connID)
+	return setAlias(handle, link, o.alias())
+}
+
+func setAlias(handle *netlink.Handle, link netlink.Link, alias string) error {
+	if alias == link.Attrs().Alias {
+		return nil
+	}
+	if err := handle.LinkSetAlias(link, alias); err != nil {
+		return errors.Wrapf(err, "failed to set the net interface alias: %v %v", link.Attrs().Name, alias)
+	}
+	link.Attrs().Alias = alias
+
+	return nil
+}
+
+// DeleteAddrs deletes all IP addresses added by NSM from the net interface
+func DeleteAddrs(link netlink.Link) error {
+	return DeleteAddrsAt(&netlink.Handle{}, link)
+}
+
+// DeleteAddrsAt deletes all IP addresses added by NSM from the net interface using the given netlink handle
+func DeleteAddrsAt(handle *netlink.Handle, link netlink.Link) error {
+	owned, canOwn := Addrs(link)
+	if !canOwn || len(owned) == 0 {
+		return nil
+	}
+
+	current, err := handle.AddrList(link, kernel.FamilyAll)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the net interface IP addresses: %v", link.Attrs().Name)
+	}
+	for _, ipAddr := range owned {
+		for i := range current {
+			if !ipAddr.Equal(current[i]) {
+				continue
+			}
+			if err := handle.AddrDel(link, ipAddr); err != nil {
+				return errors.Wrapf(err, "failed to delete IP address from the net interface: %v %v", link.Attrs().Name, ipAddr)
+			}
+		}
+	}
+
+	return SetAddrsAt(handle, link, nil)
+}
+
+// Routes returns all routes added by NSM in all routing tables, if link is not nil only the net interface routes are
+// returned
+func Routes(link netlink.Link) ([]netlink.Route, error) {
+	return listRoutes(link)
+}
+
+// DeleteRoutes deletes all routes added by NSM in all routing tables, if link is not nil only the net interface
+// routes are deleted
+func DeleteRoutes(link netlink.Link) error {
+	routes, err := listRoutes(link)
+	if err != nil {
+		return err
+	}
+	for i := range routes {
+		if err := netlink.RouteDel(&routes[i]); err != nil {
+			return errors.Wrapf(err, "failed to delete route: %v", routes[i].Dst)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owned_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/owned"
+)
+
+const (
+	ifName   = "owned-1"
+	peerName = "owned-2"
+)
+
+func TestOwned(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+	require.NoError(t, netlink.LinkSetUp(link))
+
+	ownedAddr, err := netlink.ParseAddr("10.0.9.1/24")
+	require.NoError(t, err)
+	foreignAddr, err := netlink.ParseAddr("10.0.10.1/24")
+	require.NoError(t, err)
+	for _, addr := range []*netlink.Addr{ownedAddr, foreignAddr} {
+		require.NoError(t, netlink.AddrAdd(link, addr))
+	}
+	require.NoError(t, owned.SetAddrs(link, []*netlink.Addr{ownedAddr}))
+
+	ownedRoute := &netlink.Route{
+		LinkIndex: link.Attrs().Index,
+		Dst:       &net.IPNet{IP: net.ParseIP("10.0.11.0"), Mask: net.CIDRMask(24, 32)},
+		Protocol:  kernel.RouteProtoNSM,
+	}
+	foreignRoute := &netlink.Route{
+		LinkIndex: link.Attrs().Index,
+		Dst:       &net.IPNet{IP: net.ParseIP("10.0.12.0"), Mask: net.CIDRMask(24, 32)},
+	}
+	for _, route := range []*netlink.Route{ownedRoute, foreignRoute} {
+		require.NoError(t, netlink.RouteAdd(route))
+	}
+
+	routes, err := owned.Routes(link)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	require.Equal(t, ownedRoute.Dst.String(), routes[0].Dst.String())
+
+	link, err = netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	addrs, canOwn := owned.Addrs(link)
+	require.True(t, canOwn)
+	require.Len(t, addrs, 1)
+	require.True(t, addrs[0].Equal(*ownedAddr))
+
+	require.NoError(t, owned.DeleteRoutes(link))
+	require.NoError(t, owned.DeleteAddrs(link))
+
+	routes, err = netlink.RouteList(link, kernel.FamilyV4)
+	require.NoError(t, err)
+	var dsts []string
+	for i := range routes {
+		dsts = append(dsts, routes[i].Dst.String())
+	}
+	require.Contains(t, dsts, foreignRoute.Dst.String())
+	require.NotContains(t, dsts, ownedRoute.Dst.String())
+
+	current, err := netlink.AddrList(link, kernel.FamilyV4)
+	require.NoError(t, err)
+	require.Len(t, current, 1)
+	require.True(t, current[0].Equal(*foreignAddr))
+}
+
+func TestOwned_AliasVersion(t *testing.T) {
+	link := kerneltest.AddVeth(t, ifName, peerName)
+
+	// version 0 alias written by the previous Forwarder is read and migrated on store
+	require.NoError(t, netlink.LinkSetAlias(link, owned.AddrsAliasPrefix+"10.0.9.1/24"))
+	link, err := netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	addrs, canOwn := owned.Addrs(link)
+	require.True(t, canOwn)
+	require.Len(t, addrs, 1)
+
+	addr, err := netlink.ParseAddr("10.0.9.2/24")
+	require.NoError(t, err)
+	require.NoError(t, owned.SetAddrs(link, append(addrs, addr)))
+	link, err = netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	require.Equal(t, "nsm:1;addrs=10.0.9.1/24,10.0.9.2/24", link.Attrs().Alias)
+	require.False(t, owned.IsForeign(link))
+	addrs, canOwn = owned.Addrs(link)
+	require.True(t, canOwn)
+	require.Len(t, addrs, 2)
+
+	// alias written by the newer Forwarder is not changed
+	require.NoError(t, netlink.LinkSetAlias(link, "nsm:2;addrs=10.0.9.1/24"))
+	link, err = netlink.LinkByName(ifName)
+	require.NoError(t, err)
+	_, canOwn = owned.Addrs(link)
+	require.False(t, canOwn)
+	require.True(t, owned.IsForeign(link))
+	require.Error(t, owned.SetAddrs(link, nil))
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package owned
+
+import (
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel"
+)
+
+func listRoutes(link netlink.Link) ([]netlink.Route, error) {
+	// unspecified table filter stands for all the tables
+	filter := &netlink.Route{
+		Protocol: kernel.RouteProtoNSM,
+	}
+	filterMask := netlink.RT_FILTER_TABLE | netlink.RT_FILTER_PROTOCOL
+	if link != nil {
+		filter.LinkIndex = link.Attrs().Index
+		filterMask |= netlink.RT_FILTER_OIF
+	}
+
+	routes, err := netlink.RouteListFiltered(kernel.FamilyAll, filter, filterMask)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list routes")
+	}
+	return routes, nil
+}
@@ -0,0 +1,39 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package probe provides active datapath checks sent to the peer through the given net interface
+package probe
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// ICMPEcho sends ICMP (ICMPv6) echo request to dst through the net interface in the current net NS and waits for the
+// reply, it returns the round trip time or an error if there is no reply until the timeout or the ctx deadline. On
+// ethernet interfaces reply also means the ARP (ND) resolution of the peer has succeeded.
+func ICMPEcho(ctx context.Context, ifName string, dst net.IP, timeout time.Duration) (time.Duration, error) {
+	return icmpEcho(ctx, ifName, dst, timeout)
+}
+
+// UDP sends UDP datagram to dst through the net interface in the current net NS and waits for any UDP reply or ICMP
+// port unreachable error, both mean the datagram has reached the peer. It is useful for checking the datapath through
+// the tunnels dropping ICMP. It returns the round trip time or an error if there is no reply until the timeout or the
+// ctx deadline.
+func UDP(ctx context.Context, ifName string, dst *net.UDPAddr, timeout time.Duration) (time.Duration, error) {
+	return udp(ctx, ifName, dst, timeout)
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/probe"
+)
+
+const (
+	ifName   = "probe-1"
+	peerName = "probe-2"
+	timeout  = time.Second
+	// replyTimeout covers the ND retransmit if the first neighbor solicitation is sent before the peer is ready
+	replyTimeout = 3 * time.Second
+)
+
+func TestProbe(t *testing.T) {
+	netNS := kerneltest.AddVethPeer(t, ifName, "10.0.7.1/30", peerName, "10.0.7.2/30")
+	kerneltest.AddAddr(t, ifName, "fd00:7::1/126")
+	kerneltest.RunIn(t, netNS, func() {
+		kerneltest.AddAddr(t, peerName, "fd00:7::2/126")
+	})
+
+	for _, dst := range []string{"10.0.7.2", "fd00:7::2"} {
+		_, err := probe.ICMPEcho(context.TODO(), ifName, net.ParseIP(dst), replyTimeout)
+		require.NoError(t, err, dst)
+
+		_, err = probe.UDP(context.TODO(), ifName, &net.UDPAddr{IP: net.ParseIP(dst), Port: 9}, replyTimeout)
+		require.NoError(t, err, dst)
+	}
+
+	for _, dst := range []string{"10.0.7.3", "fd00:7::3"} {
+		_, err := probe.ICMPEcho(context.TODO(), ifName, net.ParseIP(dst), timeout)
+		require.Error(t, err, dst)
+
+		_, err = probe.UDP(context.TODO(), ifName, &net.UDPAddr{IP: net.ParseIP(dst), Port: 9}, timeout)
+		require.Error(t, err, dst)
+	}
+}
+
+func TestProbe_Deadline(t *testing.T) {
+	kerneltest.AddVethPeer(t, ifName, "10.0.7.1/30", peerName, "10.0.7.2/30")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := probe.ICMPEcho(ctx, ifName, net.ParseIP("10.0.7.3"), timeout)
+	require.Error(t, err)
+	require.Less(t, int64(time.Since(start)), int64(timeout/2))
+
+	<-ctx.Done()
+	_, err = probe.UDP(ctx, ifName, &net.UDPAddr{IP: net.ParseIP("10.0.7.2"), Port: 9}, timeout)
+	require.Equal(t, context.DeadlineExceeded, errors.Cause(err))
+}
@@ -0,0 +1,214 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const (
+	icmpEchoRequest   = 8
+	icmpEchoReply     = 0
+	icmpv6EchoRequest = 128
+	icmpv6EchoReply   = 129
+
+	ipv4HeaderMinLen = 20
+	icmpHeaderLen    = 8
+)
+
+func icmpEcho(ctx context.Context, ifName string, dst net.IP, timeout time.Duration) (time.Duration, error) {
+	family, proto, requestType, replyType := unix.AF_INET, unix.IPPROTO_ICMP, byte(icmpEchoRequest), byte(icmpEchoReply)
+	if dst.To4() == nil {
+		family, proto, requestType, replyType = unix.AF_INET6, unix.IPPROTO_ICMPV6, icmpv6EchoRequest, icmpv6EchoReply
+	}
+
+	timeout, err := clampTimeout(ctx, timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	fd, err := openSocket(family, unix.SOCK_RAW, proto, ifName, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	// #nosec
+	id, seq := uint16(rand.Uint32()), uint16(rand.Uint32())
+
+	request := make([]byte, icmpHeaderLen)
+	request[0] = requestType
+	binary.BigEndian.PutUint16(request[4:], id)
+	binary.BigEndian.PutUint16(request[6:], seq)
+	if family == unix.AF_INET {
+		// kernel computes checksum only for ICMPv6
+		binary.BigEndian.PutUint16(request[2:], checksum(request))
+	}
+
+	start := time.Now()
+	if err := unix.Sendto(fd, request, 0, sockaddr(dst, 0)); err != nil {
+		return 0, errors.Wrapf(err, "failed to send ICMP echo request: %v %v", ifName, dst)
+	}
+
+	deadline := start.Add(timeout)
+	reply := make([]byte, 1500)
+	for time.Now().Before(deadline) {
+		n, from, err := unix.Recvfrom(fd, reply, 0)
+		if err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			return 0, errors.Wrapf(err, "no ICMP echo reply: %v %v", ifName, dst)
+		}
+
+		icmp := reply[:n]
+		if family == unix.AF_INET {
+			// IPv4 raw sockets receive IP header
+			if n < ipv4HeaderMinLen {
+				continue
+			}
+			icmp = icmp[int(reply[0]&0x0f)*4:]
+		}
+		if len(icmp) < icmpHeaderLen || !sameAddr(from, dst) || icmp[0] != replyType ||
+			binary.BigEndian.Uint16(icmp[4:]) != id || binary.BigEndian.Uint16(icmp[6:]) != seq {
+			continue
+		}
+
+		return time.Since(start), nil
+	}
+
+	return 0, errors.Errorf("no ICMP echo reply: %v %v", ifName, dst)
+}
+
+func udp(ctx context.Context, ifName string, dst *net.UDPAddr, timeout time.Duration) (time.Duration, error) {
+	family := unix.AF_INET
+	if dst.IP.To4() == nil {
+		family = unix.AF_INET6
+	}
+
+	timeout, err := clampTimeout(ctx, timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	fd, err := openSocket(family, unix.SOCK_DGRAM, unix.IPPROTO_UDP, ifName, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	// connected UDP socket receives ICMP port unreachable as ECONNREFUSED
+	if err := unix.Connect(fd, sockaddr(dst.IP, dst.Port)); err != nil {
+		return 0, errors.Wrapf(err, "failed to connect UDP socket: %v %v", ifName, dst)
+	}
+
+	start := time.Now()
+	if _, err := unix.Write(fd, []byte("nsm-probe")); err != nil {
+		return 0, errors.Wrapf(err, "failed to send UDP probe: %v %v", ifName, dst)
+	}
+
+	for {
+		_, err = unix.Read(fd, make([]byte, 1500))
+		if err != unix.EINTR {
+			break
+		}
+	}
+	if err != nil && err != unix.ECONNREFUSED {
+		return 0, errors.Wrapf(err, "no UDP probe reply: %v %v", ifName, dst)
+	}
+
+	return time.Since(start), nil
+}
+
+// clampTimeout returns the timeout clamped to the ctx deadline. Zero SO_RCVTIMEO means no timeout, so it fails if the
+// deadline has already passed.
+func clampTimeout(ctx context.Context, timeout time.Duration) (time.Duration, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return timeout, nil
+	}
+	if untilDeadline := time.Until(deadline); untilDeadline < timeout {
+		timeout = untilDeadline
+	}
+	if timeout <= 0 {
+		return 0, errors.Wrap(context.DeadlineExceeded, "no time left to probe")
+	}
+	return timeout, nil
+}
+
+func openSocket(family, sotype, proto int, ifName string, timeout time.Duration) (int, error) {
+	fd, err := unix.Socket(family, sotype|unix.SOCK_CLOEXEC, proto)
+	if err != nil {
+		return -1, errors.Wrap(err, "failed to open socket")
+	}
+
+	if err := unix.BindToDevice(fd, ifName); err != nil {
+		_ = unix.Close(fd)
+		return -1, errors.Wrapf(err, "failed to bind socket to net interface: %v", ifName)
+	}
+
+	tv := unix.NsecToTimeval(timeout.Nanoseconds())
+	if err := unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &tv); err != nil {
+		_ = unix.Close(fd)
+		return -1, errors.Wrap(err, "failed to set socket receive timeout")
+	}
+
+	return fd, nil
+}
+
+func sockaddr(ip net.IP, port int) unix.Sockaddr {
+	if ip4 := ip.To4(); ip4 != nil {
+		sa := &unix.SockaddrInet4{Port: port}
+		copy(sa.Addr[:], ip4)
+		return sa
+	}
+	sa := &unix.SockaddrInet6{Port: port}
+	copy(sa.Addr[:], ip.To16())
+	return sa
+}
+
+func sameAddr(sa unix.Sockaddr, ip net.IP) bool {
+	switch sa := sa.(type) {
+	case *unix.SockaddrInet4:
+		return net.IP(sa.Addr[:]).Equal(ip)
+	case *unix.SockaddrInet6:
+		return net.IP(sa.Addr[:]).Equal(ip)
+	default:
+		return false
+	}
+}
+
+func checksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(binary.BigEndian.Uint16(b[i:]))
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = (sum & 0xffff) + (sum >> 16)
+	}
+	return ^uint16(sum)
+}
@@ -0,0 +1,37 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shutdown
+
+import "time"
+
+// Option is an option for the connections close fan-out
+type Option func(c *closer)
+
+// WithConcurrency sets the max number of the connections closed in parallel
+func WithConcurrency(concurrency int) Option {
+	return func(c *closer) {
+		c.concurrency = concurrency
+	}
+}
+
+// WithTimeout sets the global deadline for closing all the connections, it should be less than the pod termination
+// grace period. 0 means there is no deadline other than ctx one.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *closer) {
+		c.timeout = timeout
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shutdown provides parallel teardown of the kernel state of all the managed connections on the Forwarder
+// shutdown, so it fits into the pod termination grace period even with thousands of the connections
+package shutdown
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+)
+
+const (
+	defaultConcurrency = 16
+	defaultTimeout     = 20 * time.Second
+)
+
+// Error is returned when some connections have failed to close or haven't been closed before the deadline
+type Error struct {
+	// Failed are the Close errors by connection IDs
+	Failed map[string]error
+	// Pending are the IDs of the connections not closed before the deadline
+	Pending []string
+}
+
+func (e *Error) Error() string {
+	return "failed to close connections: " + strconv.Itoa(len(e.Failed)) + " failed, " +
+		strconv.Itoa(len(e.Pending)) + " not closed before the deadline"
+}
+
+type closer struct {
+	concurrency int
+	timeout     time.Duration
+}
+
+// Close closes all the conns on the server in parallel with the bounded concurrency. The server is expected to be the
+// Forwarder chain, so the chain elements remove the kernel state they have created for the connections. All Closes
+// share the deadline, Close returns when all the connections are closed or the deadline is exceeded, it doesn't wait
+// for the Closes still in progress at the deadline. It returns *Error if some connections are not closed.
+func Close(ctx context.Context, server networkservice.NetworkServiceServer, conns []*networkservice.Connection, options ...Option) error {
+	c := &closer{
+		concurrency: defaultConcurrency,
+		timeout:     defaultTimeout,
+	}
+	for _, opt := range options {
+		opt(c)
+	}
+	if c.concurrency < 1 {
+		c.concurrency = 1
+	}
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var lock sync.Mutex
+	failed := make(map[string]error)
+	pending := make(map[string]struct{}, len(conns))
+	for _, conn := range conns {
+		pending[conn.GetId()] = struct{}{}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.concurrency)
+dispatch:
+	for _, conn := range conns {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(conn *networkservice.Connection) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			_, err := server.Close(ctx, conn)
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			if ctx.Err() != nil && err != nil {
+				// the Close has been interrupted by the deadline, the connection is left pending
+				return
+			}
+			delete(pending, conn.GetId())
+			if err != nil {
+				failed[conn.GetId()] = err
+			}
+		}(conn)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	if len(failed) == 0 && len(pending) == 0 {
+		return nil
+	}
+	// the Closes still in progress may complete after the return, so the results are copied
+	closeErr := &Error{
+		Failed: make(map[string]error, len(failed)),
+	}
+	for id, err := range failed {
+		closeErr.Failed[id] = err
+	}
+	for id := range pending {
+		closeErr.Pending = append(closeErr.Pending, id)
+	}
+	sort.Strings(closeErr.Pending)
+	return closeErr
+}
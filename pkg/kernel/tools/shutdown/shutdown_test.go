@@ -0,0 +1,123 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shutdown_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/shutdown"
+)
+
+type closeServer struct {
+	closeFunc func(ctx context.Context, conn *networkservice.Connection) error
+}
+
+func (s *closeServer) Request(_ context.Context, request *networkservice.NetworkServiceRequest) (*networkservice.Connection, error) {
+	return request.GetConnection(), nil
+}
+
+func (s *closeServer) Close(ctx context.Context, conn *networkservice.Connection) (*empty.Empty, error) {
+	if err := s.closeFunc(ctx, conn); err != nil {
+		return nil, err
+	}
+	return &empty.Empty{}, nil
+}
+
+func newConns(ids ...string) []*networkservice.Connection {
+	var conns []*networkservice.Connection
+	for _, id := range ids {
+		conns = append(conns, &networkservice.Connection{Id: id})
+	}
+	return conns
+}
+
+func TestClose(t *testing.T) {
+	var inFlight, maxInFlight, closed int32
+	server := &closeServer{
+		closeFunc: func(_ context.Context, _ *networkservice.Connection) error {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+			for {
+				prev := atomic.LoadInt32(&maxInFlight)
+				if n <= prev || atomic.CompareAndSwapInt32(&maxInFlight, prev, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			atomic.AddInt32(&closed, 1)
+			return nil
+		},
+	}
+
+	conns := newConns("conn-1", "conn-2", "conn-3", "conn-4", "conn-5", "conn-6", "conn-7")
+	require.NoError(t, shutdown.Close(context.Background(), server, conns, shutdown.WithConcurrency(3)))
+	require.Equal(t, int32(len(conns)), atomic.LoadInt32(&closed))
+	require.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(3))
+}
+
+func TestClose_Failed(t *testing.T) {
+	server := &closeServer{
+		closeFunc: func(_ context.Context, conn *networkservice.Connection) error {
+			if conn.GetId() == "conn-2" {
+				return errors.New("error")
+			}
+			return nil
+		},
+	}
+
+	err := shutdown.Close(context.Background(), server, newConns("conn-1", "conn-2", "conn-3"))
+	require.Error(t, err)
+
+	closeErr, ok := err.(*shutdown.Error)
+	require.True(t, ok)
+	require.Len(t, closeErr.Failed, 1)
+	require.Error(t, closeErr.Failed["conn-2"])
+	require.Empty(t, closeErr.Pending)
+}
+
+func TestClose_Deadline(t *testing.T) {
+	server := &closeServer{
+		closeFunc: func(ctx context.Context, conn *networkservice.Connection) error {
+			if conn.GetId() == "conn-1" {
+				return nil
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	start := time.Now()
+	err := shutdown.Close(context.Background(), server, newConns("conn-1", "conn-2", "conn-3", "conn-4"),
+		shutdown.WithConcurrency(2),
+		shutdown.WithTimeout(100*time.Millisecond))
+	require.Error(t, err)
+	require.Less(t, int64(time.Since(start)), int64(time.Second))
+
+	closeErr, ok := err.(*shutdown.Error)
+	require.True(t, ok)
+	require.Empty(t, closeErr.Failed)
+	require.Equal(t, []string{"conn-2", "conn-3", "conn-4"}, closeErr.Pending)
+}
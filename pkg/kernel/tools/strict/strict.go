@@ -0,0 +1,86 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package strict provides the strict verification mode: the kernel mutations are followed by a read-back and the
+// mismatches are reported as MismatchError. It is intended for CI and canary deployments catching kernel and driver
+// quirks early, the read-backs cost an extra netlink round trip per mutation.
+package strict
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+type contextKeyType struct{}
+
+// MismatchError is returned if the kernel state read back after the mutation doesn't match the expected one
+type MismatchError struct {
+	// Op is the kernel operation, e.g. "RouteAdd"
+	Op string
+	// Object is the kernel object passed to the operation
+	Object interface{}
+}
+
+func (e *MismatchError) Error() string {
+	return fmt.Sprintf("kernel state doesn't match after %s: %+v", e.Op, e.Object)
+}
+
+// IsMismatch returns true if err is caused by MismatchError
+func IsMismatch(err error) bool {
+	for err != nil {
+		if _, ok := err.(*MismatchError); ok {
+			return true
+		}
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			return false
+		}
+		err = cause.Cause()
+	}
+	return false
+}
+
+// WithStrict returns a context enabling the strict verification mode
+func WithStrict(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKeyType{}, true)
+}
+
+// IsStrict returns true if the strict verification mode is enabled for the context
+func IsStrict(ctx context.Context) bool {
+	strict, _ := ctx.Value(contextKeyType{}).(bool)
+	return strict
+}
+
+// Verify runs the read-back check of the kernel operation op applied to the object if the strict verification mode
+// is enabled, it returns MismatchError if the check reports a mismatch
+func Verify(ctx context.Context, op string, object interface{}, check func() (bool, error)) error {
+	if !IsStrict(ctx) {
+		return nil
+	}
+	ok, err := check()
+	if err != nil {
+		return errors.Wrapf(err, "failed to read back after %s: %+v", op, object)
+	}
+	if !ok {
+		return &MismatchError{
+			Op:     op,
+			Object: object,
+		}
+	}
+	return nil
+}
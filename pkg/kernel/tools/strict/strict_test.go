@@ -0,0 +1,51 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package strict_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/strict"
+)
+
+func TestVerify(t *testing.T) {
+	var checked bool
+	check := func() (bool, error) {
+		checked = true
+		return false, nil
+	}
+
+	require.NoError(t, strict.Verify(context.TODO(), "RouteAdd", "route", check))
+	require.False(t, checked)
+
+	ctx := strict.WithStrict(context.TODO())
+	require.True(t, strict.IsStrict(ctx))
+
+	err := strict.Verify(ctx, "RouteAdd", "route", check)
+	require.True(t, checked)
+	require.True(t, strict.IsMismatch(errors.Wrap(err, "failed to apply routes")))
+
+	require.NoError(t, strict.Verify(ctx, "RouteAdd", "route", func() (bool, error) { return true, nil }))
+
+	err = strict.Verify(ctx, "RouteAdd", "route", func() (bool, error) { return false, errors.New("error") })
+	require.Error(t, err)
+	require.False(t, strict.IsMismatch(err))
+}
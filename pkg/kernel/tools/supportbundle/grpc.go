@@ -0,0 +1,116 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle
+
+import (
+	"bufio"
+	"context"
+	"io"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+const (
+	// ServiceName is the support bundle debug gRPC service name
+	ServiceName = "supportbundle.SupportBundle"
+	// GenerateMethod is the support bundle debug gRPC service method streaming the generated bundle in chunks
+	GenerateMethod = "/" + ServiceName + "/Generate"
+
+	chunkSize = 64 * 1024
+)
+
+// generator is the support bundle debug gRPC service handler type
+type generator interface {
+	Write(ctx context.Context, w io.Writer) error
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*generator)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Generate",
+			Handler:       generateHandler,
+			ServerStreams: true,
+		},
+	},
+}
+
+// Register registers the support bundle debug gRPC service on the server, the service generates the bundles with g
+func Register(server *grpc.Server, g *Generator) {
+	server.RegisterService(&serviceDesc, g)
+}
+
+// Fetch requests the support bundle from the debug gRPC service and writes the gzipped tarball into w
+func Fetch(ctx context.Context, cc grpc.ClientConnInterface, w io.Writer) error {
+	stream, err := cc.NewStream(ctx, &serviceDesc.Streams[0], GenerateMethod)
+	if err != nil {
+		return errors.Wrap(err, "failed to request support bundle")
+	}
+	if err := stream.SendMsg(new(empty.Empty)); err != nil {
+		return errors.Wrap(err, "failed to request support bundle")
+	}
+	if err := stream.CloseSend(); err != nil {
+		return errors.Wrap(err, "failed to request support bundle")
+	}
+
+	for {
+		chunk := new(wrappers.BytesValue)
+		if err := stream.RecvMsg(chunk); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Wrap(err, "failed to receive support bundle")
+		}
+		if _, err := w.Write(chunk.GetValue()); err != nil {
+			return errors.Wrap(err, "failed to write support bundle")
+		}
+	}
+}
+
+func generateHandler(srv interface{}, stream grpc.ServerStream) error {
+	if err := stream.RecvMsg(new(empty.Empty)); err != nil {
+		return err
+	}
+
+	w := bufio.NewWriterSize(&chunkWriter{stream: stream}, chunkSize)
+	if err := srv.(generator).Write(stream.Context(), w); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// chunkWriter sends the written data to the stream in chunks not bigger than chunkSize
+type chunkWriter struct {
+	stream grpc.ServerStream
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	for written := 0; written < len(p); {
+		end := written + chunkSize
+		if end > len(p) {
+			end = len(p)
+		}
+		if err := w.stream.SendMsg(&wrappers.BytesValue{Value: p[written:end]}); err != nil {
+			return written, errors.Wrap(err, "failed to send support bundle")
+		}
+		written = end
+	}
+	return len(p), nil
+}
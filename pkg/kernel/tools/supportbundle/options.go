@@ -0,0 +1,43 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle
+
+import "github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/describe"
+
+// Option is an option pattern for New
+type Option func(g *Generator)
+
+// WithRegistry sets the describe registry to collect the connections kernel state from
+func WithRegistry(registry *describe.Registry) Option {
+	return func(g *Generator) {
+		g.registry = registry
+	}
+}
+
+// WithSysctls sets the sysctls to collect instead of DefaultSysctls
+func WithSysctls(names ...string) Option {
+	return func(g *Generator) {
+		g.sysctls = names
+	}
+}
+
+// WithSource adds the diagnostic source collected into the bundle file with the given name
+func WithSource(name string, source Source) Option {
+	return func(g *Generator) {
+		g.sources[name] = source
+	}
+}
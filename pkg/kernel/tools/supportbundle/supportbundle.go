@@ -0,0 +1,197 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package supportbundle provides generating of the support bundle: a tarball with the connections kernel state, the
+// relevant sysctls and the other diagnostic sources for attaching to bug reports. The bundle is generated
+// programmatically with Generator or requested with Fetch from the debug gRPC service registered with Register.
+package supportbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/describe"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+const (
+	// ConnectionsDir is a bundle directory with the connections kernel state: <path escaped connection ID>.json
+	ConnectionsDir = "connections"
+	// SysctlsFile is a bundle file with the sysctl values
+	SysctlsFile = "sysctls.json"
+	// ErrorsFile is a bundle file with the collection errors, bundle is generated even if some parts fail
+	ErrorsFile = "errors.txt"
+)
+
+// DefaultSysctls are the sysctls collected by default
+var DefaultSysctls = []string{
+	"net.ipv4.ip_forward",
+	"net.ipv4.conf.all.rp_filter",
+	"net.ipv4.conf.all.proxy_arp",
+	"net.ipv4.neigh.default.gc_thresh1",
+	"net.ipv4.neigh.default.gc_thresh2",
+	"net.ipv4.neigh.default.gc_thresh3",
+	"net.ipv6.conf.all.forwarding",
+	"net.ipv6.conf.all.proxy_ndp",
+	"net.ipv6.conf.all.disable_ipv6",
+	"net.ipv6.neigh.default.gc_thresh3",
+}
+
+// Source is a diagnostic source collected into the bundle file, e.g. recent audit log entries or kernel
+// capabilities report
+type Source func(ctx context.Context) ([]byte, error)
+
+// Generator generates support bundles
+type Generator struct {
+	registry *describe.Registry
+	sysctls  []string
+	sources  map[string]Source
+}
+
+// New returns a new Generator
+func New(options ...Option) *Generator {
+	g := &Generator{
+		sysctls: DefaultSysctls,
+		sources: make(map[string]Source),
+	}
+	for _, opt := range options {
+		opt(g)
+	}
+	return g
+}
+
+// Write writes the gzipped support bundle tarball into w. Failed parts are listed in ErrorsFile, only w errors are
+// returned.
+func (g *Generator) Write(ctx context.Context, w io.Writer) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	b := &bundle{
+		tarWriter: tarWriter,
+		modTime:   time.Now(),
+	}
+
+	if g.registry != nil {
+		for _, connID := range g.registry.Connections() {
+			state, err := g.registry.Describe(ctx, connID)
+			if err != nil {
+				b.errs = append(b.errs, err.Error())
+				continue
+			}
+			// connection ID is escaped to keep the file in ConnectionsDir for any ID, e.g. "../../etc/passwd"
+			b.writeJSON(filepath.Join(ConnectionsDir, url.PathEscape(connID)+".json"), state)
+		}
+	}
+
+	sysctls := make(map[string]string, len(g.sysctls))
+	for _, name := range g.sysctls {
+		value, err := sysctl.Get(name)
+		if err != nil {
+			b.errs = append(b.errs, err.Error())
+			continue
+		}
+		sysctls[name] = value
+	}
+	b.writeJSON(SysctlsFile, sysctls)
+
+	names := make([]string, 0, len(g.sources))
+	for name := range g.sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data, err := g.sources[name](ctx)
+		if err != nil {
+			b.errs = append(b.errs, errors.Wrapf(err, "failed to collect: %v", name).Error())
+			continue
+		}
+		b.write(name, data)
+	}
+
+	if len(b.errs) != 0 {
+		b.write(ErrorsFile, []byte(strings.Join(b.errs, "\n")+"\n"))
+	}
+
+	if b.err != nil {
+		return b.err
+	}
+	if err := tarWriter.Close(); err != nil {
+		return errors.Wrap(err, "failed to write support bundle")
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return errors.Wrap(err, "failed to write support bundle")
+	}
+	return nil
+}
+
+// WriteFile writes the gzipped support bundle tarball into the file
+func (g *Generator) WriteFile(ctx context.Context, path string) (err error) {
+	file, err := os.OpenFile(filepath.Clean(path), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create support bundle file: %v", path)
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil && closeErr != nil {
+			err = errors.Wrapf(closeErr, "failed to write support bundle file: %v", path)
+		}
+	}()
+
+	return g.Write(ctx, file)
+}
+
+type bundle struct {
+	tarWriter *tar.Writer
+	modTime   time.Time
+	errs      []string
+	err       error
+}
+
+func (b *bundle) writeJSON(name string, v interface{}) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		b.errs = append(b.errs, errors.Wrapf(err, "failed to marshal: %v", name).Error())
+		return
+	}
+	b.write(name, data)
+}
+
+func (b *bundle) write(name string, data []byte) {
+	if b.err != nil {
+		return
+	}
+	if b.err = b.tarWriter.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: b.modTime,
+	}); b.err != nil {
+		b.err = errors.Wrapf(b.err, "failed to write support bundle file header: %v", name)
+		return
+	}
+	if _, b.err = b.tarWriter.Write(data); b.err != nil {
+		b.err = errors.Wrapf(b.err, "failed to write support bundle file: %v", name)
+	}
+}
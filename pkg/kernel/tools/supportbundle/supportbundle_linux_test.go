@@ -0,0 +1,149 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package supportbundle_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/networkservice/describe"
+	describetool "github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/describe"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/supportbundle"
+)
+
+const (
+	ifName   = "bundle-1"
+	peerName = "bundle-2"
+)
+
+func readBundle(t *testing.T, r io.Reader) map[string][]byte {
+	gzipReader, err := gzip.NewReader(r)
+	require.NoError(t, err)
+	tarReader := tar.NewReader(gzipReader)
+
+	files := make(map[string][]byte)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.NoError(t, err)
+		files[header.Name], err = ioutil.ReadAll(tarReader)
+		require.NoError(t, err)
+	}
+}
+
+func TestGenerator_Write(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	registry := describetool.NewRegistry()
+	server := describe.NewServer(registry)
+	for _, conn := range []*networkservice.Connection{
+		{Id: "../../conn-1", Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.InterfaceNameKey: ifName,
+				kernel.NetNSURL:         "file:///proc/self/ns/net",
+			},
+		}},
+		{Id: "conn-2", Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.InterfaceNameKey: "bundle-missing",
+				kernel.NetNSURL:         "file:///proc/self/ns/net",
+			},
+		}},
+	} {
+		_, err := server.Request(context.TODO(), &networkservice.NetworkServiceRequest{Connection: conn})
+		require.NoError(t, err)
+	}
+
+	generator := supportbundle.New(
+		supportbundle.WithRegistry(registry),
+		supportbundle.WithSysctls("net.ipv4.ip_forward", "net.ipv4.missing"),
+		supportbundle.WithSource("audit.log", func(context.Context) ([]byte, error) {
+			return []byte("audit\n"), nil
+		}),
+		supportbundle.WithSource("kernelcaps.json", func(context.Context) ([]byte, error) {
+			return nil, errors.New("kernelcaps failed")
+		}),
+	)
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, generator.Write(context.TODO(), buf))
+	files := readBundle(t, buf)
+
+	state := new(describetool.KernelState)
+	require.NoError(t, json.Unmarshal(files["connections/..%2F..%2Fconn-1.json"], state))
+	require.Equal(t, ifName, state.Link.Name)
+	require.NotContains(t, files, "connections/conn-2.json")
+
+	sysctls := make(map[string]string)
+	require.NoError(t, json.Unmarshal(files[supportbundle.SysctlsFile], &sysctls))
+	require.Contains(t, sysctls, "net.ipv4.ip_forward")
+	require.NotContains(t, sysctls, "net.ipv4.missing")
+
+	require.Equal(t, "audit\n", string(files["audit.log"]))
+	require.NotContains(t, files, "kernelcaps.json")
+
+	errs := string(files[supportbundle.ErrorsFile])
+	require.Contains(t, errs, "conn-2")
+	require.Contains(t, errs, "net.ipv4.missing")
+	require.Contains(t, errs, "kernelcaps failed")
+}
+
+func TestFetch(t *testing.T) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	supportbundle.Register(server, supportbundle.New(
+		supportbundle.WithSysctls("net.ipv4.ip_forward"),
+		supportbundle.WithSource("audit.log", func(context.Context) ([]byte, error) {
+			return bytes.Repeat([]byte("audit\n"), 100*1024), nil
+		}),
+	))
+	go func() { _ = server.Serve(listener) }()
+	defer server.Stop()
+
+	cc, err := grpc.DialContext(context.TODO(), "bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure())
+	require.NoError(t, err)
+	defer func() { _ = cc.Close() }()
+
+	buf := new(bytes.Buffer)
+	require.NoError(t, supportbundle.Fetch(context.TODO(), cc, buf))
+	files := readBundle(t, buf)
+
+	require.Contains(t, files, supportbundle.SysctlsFile)
+	require.Len(t, files["audit.log"], len("audit\n")*100*1024)
+}
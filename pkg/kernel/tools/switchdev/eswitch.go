@@ -0,0 +1,34 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build !linux
+
+package switchdev
+
+import (
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+)
+
+// EswitchMode returns devlink eswitch mode of the given PCI device: "legacy" or "switchdev"
+func EswitchMode(_ string) (string, error) {
+	return "", errors.New("devlink is supported only on linux")
+}
+
+// SetupOffloadTarget prepares the given representor net interface to be a target for the tc flower offload rules
+func SetupOffloadTarget(_ netlink.Link) error {
+	return errors.New("tc offload is supported only on linux")
+}
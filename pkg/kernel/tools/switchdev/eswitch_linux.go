@@ -0,0 +1,50 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package switchdev
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+)
+
+const pciBus = "pci"
+
+// EswitchMode returns devlink eswitch mode of the given PCI device: "legacy" or "switchdev"
+func EswitchMode(pciAddress string) (string, error) {
+	dev, err := netlink.DevLinkGetDeviceByName(pciBus, pciAddress)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get devlink device: %v", pciAddress)
+	}
+	return dev.Attrs.Eswitch.Mode, nil
+}
+
+// SetupOffloadTarget prepares the given representor net interface to be a target for the tc flower offload rules
+func SetupOffloadTarget(link netlink.Link) error {
+	qdisc := &netlink.Ingress{
+		QdiscAttrs: netlink.QdiscAttrs{
+			LinkIndex: link.Attrs().Index,
+			Handle:    netlink.MakeHandle(0xffff, 0),
+			Parent:    netlink.HANDLE_INGRESS,
+		},
+	}
+	if err := netlink.QdiscAdd(qdisc); err != nil && !os.IsExist(err) {
+		return errors.Wrapf(err, "failed to add ingress qdisc to the representor: %v", link.Attrs().Name)
+	}
+	return nil
+}
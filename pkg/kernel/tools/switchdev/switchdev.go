@@ -0,0 +1,99 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package switchdev provides utils for the switchdev mode SR-IOV NICs
+package switchdev
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// EswitchModeSwitchdev is a devlink eswitch mode for the switchdev NICs
+const EswitchModeSwitchdev = "switchdev"
+
+var netClassPath = "/sys/class/net"
+
+// phys_port_name of VF representor is "pf<pf>vf<vf>" on the newer kernels and "<vf>" on the older ones
+var vfPortNameRegexp = regexp.MustCompile(`^(?:pf\d+vf)?(\d+)$`)
+
+// PCIAddress returns PCI address of the given net interface
+func PCIAddress(ifName string) (string, error) {
+	devicePath, err := filepath.EvalSymlinks(filepath.Join(netClassPath, ifName, "device"))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get PCI device for the net interface: %v", ifName)
+	}
+	return filepath.Base(devicePath), nil
+}
+
+// IsSwitchdev returns true if the given PF net interface is in the switchdev eswitch mode
+func IsSwitchdev(pfName string) (bool, error) {
+	pciAddress, err := PCIAddress(pfName)
+	if err != nil {
+		return false, err
+	}
+	mode, err := EswitchMode(pciAddress)
+	if err != nil {
+		return false, err
+	}
+	return mode == EswitchModeSwitchdev, nil
+}
+
+// FindVFRepresentor returns name of the representor net interface for the given PF VF
+func FindVFRepresentor(pfName string, vfNum int) (string, error) {
+	switchID, err := readNetAttr(pfName, "phys_switch_id")
+	if err != nil || switchID == "" {
+		return "", errors.Errorf("PF net interface %v doesn't have switch ID, probably it isn't in switchdev mode", pfName)
+	}
+
+	infos, err := ioutil.ReadDir(netClassPath)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read net interfaces: %v", netClassPath)
+	}
+	for _, info := range infos {
+		ifName := info.Name()
+		if ifName == pfName {
+			continue
+		}
+		if id, err := readNetAttr(ifName, "phys_switch_id"); err != nil || id != switchID {
+			continue
+		}
+		portName, err := readNetAttr(ifName, "phys_port_name")
+		if err != nil {
+			continue
+		}
+		if match := vfPortNameRegexp.FindStringSubmatch(portName); match != nil {
+			if num, err := strconv.Atoi(match[1]); err == nil && num == vfNum {
+				return ifName, nil
+			}
+		}
+	}
+
+	return "", errors.Errorf("failed to find representor for the PF VF: %v %v", pfName, vfNum)
+}
+
+func readNetAttr(ifName, attr string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(filepath.Join(netClassPath, ifName, attr)))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
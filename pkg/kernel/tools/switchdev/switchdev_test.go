@@ -0,0 +1,94 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package switchdev_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/switchdev"
+)
+
+const pciAddress = "0000:03:00.0"
+
+// netClass creates a fake sysfs net interfaces tree: interface name -> attribute name -> value
+func netClass(t *testing.T, ifaces map[string]map[string]string) string {
+	root, err := ioutil.TempDir("", "switchdev")
+	require.NoError(t, err)
+
+	for ifName, attrs := range ifaces {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, "net", ifName), 0o700))
+		for name, value := range attrs {
+			require.NoError(t, ioutil.WriteFile(filepath.Join(root, "net", ifName, name), []byte(value+"\n"), 0o600))
+		}
+	}
+	return root
+}
+
+func TestFindVFRepresentor(t *testing.T) {
+	root := netClass(t, map[string]map[string]string{
+		"pf0":   {"phys_switch_id": "abcd", "phys_port_name": "p0"},
+		"rep0":  {"phys_switch_id": "abcd", "phys_port_name": "pf0vf0"},
+		"rep3":  {"phys_switch_id": "abcd", "phys_port_name": "pf0vf3"},
+		"old5":  {"phys_switch_id": "abcd", "phys_port_name": "5"},
+		"other": {"phys_switch_id": "ef01", "phys_port_name": "pf0vf1"},
+		"eth0":  {},
+	})
+	defer func() { _ = os.RemoveAll(root) }()
+	defer switchdev.SetNetClassPath(filepath.Join(root, "net"))()
+
+	repName, err := switchdev.FindVFRepresentor("pf0", 3)
+	require.NoError(t, err)
+	require.Equal(t, "rep3", repName)
+
+	repName, err = switchdev.FindVFRepresentor("pf0", 5)
+	require.NoError(t, err)
+	require.Equal(t, "old5", repName)
+
+	// the representor of another switch is skipped
+	_, err = switchdev.FindVFRepresentor("pf0", 1)
+	require.Error(t, err)
+
+	// the PF has no switch ID if it isn't in the switchdev mode
+	_, err = switchdev.FindVFRepresentor("eth0", 0)
+	require.Error(t, err)
+}
+
+func TestPCIAddress(t *testing.T) {
+	root := netClass(t, map[string]map[string]string{
+		"pf0":   {},
+		"veth0": {},
+	})
+	defer func() { _ = os.RemoveAll(root) }()
+	defer switchdev.SetNetClassPath(filepath.Join(root, "net"))()
+
+	devicePath := filepath.Join(root, "devices", pciAddress)
+	require.NoError(t, os.MkdirAll(devicePath, 0o700))
+	require.NoError(t, os.Symlink(devicePath, filepath.Join(root, "net", "pf0", "device")))
+
+	address, err := switchdev.PCIAddress("pf0")
+	require.NoError(t, err)
+	require.Equal(t, pciAddress, address)
+
+	// the virtual net interfaces have no device
+	_, err = switchdev.IsSwitchdev("veth0")
+	require.Error(t, err)
+}
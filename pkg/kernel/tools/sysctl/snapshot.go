@@ -0,0 +1,121 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysctl
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Snapshot is a set of the kernel parameters original values in the net NS, it restores exactly the prior state of
+// the parameters changed by NSM. Snapshot should be taken, changed and restored in the same net NS.
+type Snapshot struct {
+	names  []string
+	values map[string]string
+}
+
+// Take returns a new snapshot of the given kernel parameters in the current net NS
+func Take(names ...string) (*Snapshot, error) {
+	s := &Snapshot{
+		values: make(map[string]string),
+	}
+	for _, name := range names {
+		if err := s.add(name); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Set sets the given kernel parameter value in the current net NS, the parameter original value is added to the
+// snapshot if it is not there yet
+func (s *Snapshot) Set(name, value string) error {
+	if err := s.add(name); err != nil {
+		return err
+	}
+	if current, err := Get(name); err == nil && current == value {
+		return nil
+	}
+	return Set(name, value)
+}
+
+// Len returns the number of the kernel parameters in the snapshot
+func (s *Snapshot) Len() int {
+	return len(s.names)
+}
+
+// Restore sets the changed kernel parameters back to the snapshot values in the current net NS in the reverse order,
+// the parameters not existing anymore (e.g. with the deleted net interface) are skipped. It doesn't stop on errors
+// and returns them combined.
+func (s *Snapshot) Restore() error {
+	var errs []string
+	for i := len(s.names) - 1; i >= 0; i-- {
+		if err := s.restore(s.names[i]); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) != 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Revert sets the given kernel parameter back to the snapshot value in the current net NS and removes it from the
+// snapshot, so it is not restored anymore. The parameter not existing anymore is skipped.
+func (s *Snapshot) Revert(name string) error {
+	if _, ok := s.values[name]; !ok {
+		return nil
+	}
+	err := s.restore(name)
+	for i := range s.names {
+		if s.names[i] == name {
+			s.names = append(s.names[:i], s.names[i+1:]...)
+			break
+		}
+	}
+	delete(s.values, name)
+	return err
+}
+
+func (s *Snapshot) restore(name string) error {
+	current, err := Get(name)
+	if err != nil {
+		if os.IsNotExist(errors.Cause(err)) {
+			return nil
+		}
+		return err
+	}
+	if current == s.values[name] {
+		return nil
+	}
+	return Set(name, s.values[name])
+}
+
+func (s *Snapshot) add(name string) error {
+	if _, ok := s.values[name]; ok {
+		return nil
+	}
+	value, err := Get(name)
+	if err != nil {
+		return err
+	}
+	s.names = append(s.names, name)
+	s.values[name] = value
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sysctl_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+func addVeth(t *testing.T, name string) func() {
+	require.NoError(t, netlink.LinkAdd(&netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: name},
+		PeerName:  name + "-p",
+	}))
+	return func() { _ = netlink.LinkDel(&netlink.Veth{LinkAttrs: netlink.LinkAttrs{Name: name}}) }
+}
+
+func TestSnapshot(t *testing.T) {
+	defer addVeth(t, "sysctl-1")()
+	deleteLink := addVeth(t, "sysctl-2")
+	defer deleteLink()
+
+	arpIgnore := "net/ipv4/conf/sysctl-1/arp_ignore"
+	arpAnnounce := "net/ipv4/conf/sysctl-1/arp_announce"
+
+	snapshot, err := sysctl.Take(arpIgnore)
+	require.NoError(t, err)
+
+	require.NoError(t, sysctl.Set(arpIgnore, "1"))
+	require.NoError(t, snapshot.Set(arpAnnounce, "2"))
+	// the original value is taken only once
+	require.NoError(t, snapshot.Set(arpAnnounce, "1"))
+	require.NoError(t, snapshot.Set("net/ipv4/conf/sysctl-2/arp_ignore", "2"))
+	require.Equal(t, 3, snapshot.Len())
+
+	require.Error(t, snapshot.Set("net/ipv4/conf/not-exists/arp_ignore", "1"))
+	require.Equal(t, 3, snapshot.Len())
+
+	// the parameters are deleted with the net interface
+	deleteLink()
+
+	require.NoError(t, snapshot.Restore())
+	for _, name := range []string{arpIgnore, arpAnnounce} {
+		value, err := sysctl.GetInt(name)
+		require.NoError(t, err)
+		require.Zero(t, value, name)
+	}
+}
+
+func TestSnapshot_Revert(t *testing.T) {
+	defer addVeth(t, "sysctl-1")()
+
+	arpIgnore := "net/ipv4/conf/sysctl-1/arp_ignore"
+
+	snapshot, err := sysctl.Take()
+	require.NoError(t, err)
+
+	require.NoError(t, snapshot.Set(arpIgnore, "1"))
+	require.NoError(t, snapshot.Revert(arpIgnore))
+	require.Zero(t, snapshot.Len())
+
+	value, err := sysctl.GetInt(arpIgnore)
+	require.NoError(t, err)
+	require.Zero(t, value)
+
+	// the reverted parameter is not restored anymore
+	require.NoError(t, sysctl.Set(arpIgnore, "2"))
+	require.NoError(t, snapshot.Restore())
+
+	value, err = sysctl.GetInt(arpIgnore)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), value)
+}
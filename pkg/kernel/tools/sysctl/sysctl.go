@@ -0,0 +1,74 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sysctl provides utils for reading and writing kernel parameters in the current net NS
+package sysctl
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const procSysPath = "/proc/sys"
+
+// Path returns /proc/sys file path for the given kernel parameter. Parameter can be given both in the dotted form
+// (net.ipv4.ip_forward) and in the slashed form (net/ipv4/conf/eth0.100/forwarding), the slashed form should be used
+// if some part of the parameter name (like interface name) contains dots.
+func Path(name string) string {
+	if !strings.Contains(name, "/") {
+		name = strings.ReplaceAll(name, ".", "/")
+	}
+	return filepath.Join(procSysPath, name)
+}
+
+// Get returns the given kernel parameter value
+func Get(name string) (string, error) {
+	value, err := ioutil.ReadFile(Path(name))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read sysctl: %v", name)
+	}
+	return strings.TrimSpace(string(value)), nil
+}
+
+// GetInt returns the given kernel parameter value parsed as an integer
+func GetInt(name string) (int64, error) {
+	value, err := Get(name)
+	if err != nil {
+		return 0, err
+	}
+	intValue, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid integer sysctl value: %v = %v", name, value)
+	}
+	return intValue, nil
+}
+
+// Set sets the given kernel parameter value
+func Set(name, value string) error {
+	if err := ioutil.WriteFile(Path(name), []byte(value), 0); err != nil {
+		return errors.Wrapf(err, "failed to write sysctl: %v = %v", name, value)
+	}
+	return nil
+}
+
+// SetInt sets the given kernel parameter integer value
+func SetInt(name string, value int64) error {
+	return Set(name, strconv.FormatInt(value, 10))
+}
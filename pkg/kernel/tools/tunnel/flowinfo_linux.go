@@ -0,0 +1,31 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunnel
+
+import (
+	"encoding/binary"
+
+	"github.com/vishvananda/netlink/nl"
+)
+
+// flowInfo converts flow label to the netlink.Ip6tnl FlowInfo: kernel expects network byte order, but netlink writes
+// the value in the native byte order
+func flowInfo(flowLabel uint32) uint32 {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, flowLabel)
+	return nl.NativeEndian().Uint32(b)
+}
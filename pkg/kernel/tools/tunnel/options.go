@@ -0,0 +1,42 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunnel
+
+// Option is an option pattern for Apply
+type Option func(c *config)
+
+// WithECNPropagation makes tunnel copy inner TOS/traffic class including ECN bits to the outer header on
+// encapsulation, ECN marks are propagated back to the inner header by the kernel on decapsulation
+func WithECNPropagation() Option {
+	return func(c *config) {
+		c.propagateECN = true
+	}
+}
+
+// WithFlowLabelInheritance makes IPv6 tunnel copy inner IPv6 flow label to the outer header
+func WithFlowLabelInheritance() Option {
+	return func(c *config) {
+		c.inheritFlowLabel = true
+	}
+}
+
+// WithFlowLabel sets fixed IPv6 flow label for the outer header
+func WithFlowLabel(flowLabel uint32) Option {
+	return func(c *config) {
+		c.flowLabel = &flowLabel
+	}
+}
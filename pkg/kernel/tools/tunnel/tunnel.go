@@ -0,0 +1,92 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tunnel provides utils for configuring QoS related properties of the kernel tunnel net interfaces
+package tunnel
+
+import (
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+)
+
+const (
+	// tosInherit is a special TOS value making tunnel copy inner TOS (including ECN bits) to the outer header
+	tosInherit = 1
+	// ip6TnlUseOrigTClass is IP6_TNL_F_USE_ORIG_TCLASS
+	ip6TnlUseOrigTClass = 0x2
+	// ip6TnlUseOrigFlowLabel is IP6_TNL_F_USE_ORIG_FLOWLABEL
+	ip6TnlUseOrigFlowLabel = 0x4
+	// flowLabelMask is a mask for the 20-bit IPv6 flow label
+	flowLabelMask = 0xfffff
+)
+
+type config struct {
+	propagateECN     bool
+	inheritFlowLabel bool
+	flowLabel        *uint32
+}
+
+// Apply configures the tunnel net interface with the given options. It should be called before the net interface
+// creation. It returns an error if some option is not supported for the tunnel type.
+func Apply(link netlink.Link, options ...Option) error {
+	c := new(config)
+	for _, opt := range options {
+		opt(c)
+	}
+
+	if c.inheritFlowLabel && c.flowLabel != nil {
+		return errors.New("flow label cannot be both inherited and fixed")
+	}
+	if c.flowLabel != nil && *c.flowLabel&^flowLabelMask != 0 {
+		return errors.Errorf("invalid flow label: %v", *c.flowLabel)
+	}
+
+	if ip6tnl, ok := link.(*netlink.Ip6tnl); ok {
+		if c.propagateECN {
+			ip6tnl.Flags |= ip6TnlUseOrigTClass
+		}
+		if c.inheritFlowLabel {
+			ip6tnl.Flags |= ip6TnlUseOrigFlowLabel
+		}
+		if c.flowLabel != nil {
+			ip6tnl.FlowInfo = flowInfo(*c.flowLabel)
+		}
+		return nil
+	}
+
+	if c.inheritFlowLabel || c.flowLabel != nil {
+		return errors.Errorf("flow label is not supported for the %s tunnel", link.Type())
+	}
+	if !c.propagateECN {
+		return nil
+	}
+
+	switch tunnel := link.(type) {
+	case *netlink.Vxlan:
+		tunnel.TOS = tosInherit
+	case *netlink.Gretap:
+		tunnel.Tos = tosInherit
+	case *netlink.Gretun:
+		tunnel.Tos = tosInherit
+	case *netlink.Iptun:
+		tunnel.Tos = tosInherit
+	case *netlink.Sittun:
+		tunnel.Tos = tosInherit
+	default:
+		return errors.Errorf("ECN propagation is not supported for the %s tunnel", link.Type())
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunnel_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/tunnel"
+)
+
+func TestApply_Ip6tnl(t *testing.T) {
+	link := &netlink.Ip6tnl{}
+	require.NoError(t, tunnel.Apply(link, tunnel.WithECNPropagation(), tunnel.WithFlowLabelInheritance()))
+	require.Equal(t, uint32(0x6), link.Flags)
+
+	require.Error(t, tunnel.Apply(link, tunnel.WithFlowLabelInheritance(), tunnel.WithFlowLabel(1)))
+	require.Error(t, tunnel.Apply(link, tunnel.WithFlowLabel(0x100000)))
+}
+
+func TestApply_Vxlan(t *testing.T) {
+	link := &netlink.Vxlan{}
+	require.NoError(t, tunnel.Apply(link, tunnel.WithECNPropagation()))
+	require.Equal(t, 1, link.TOS)
+
+	require.Error(t, tunnel.Apply(link, tunnel.WithFlowLabel(1)))
+}
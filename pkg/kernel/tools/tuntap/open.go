@@ -0,0 +1,36 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build !linux
+
+// Package tuntap provides attaching to the persistent tap/tun net interfaces created by linkprovider.NewTap,
+// linkprovider.NewTun
+package tuntap
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+)
+
+// Open attaches to the persistent tap/tun net interface in the current net NS and returns its file, frames (tap) or
+// IP packets (tun) are read and written without the packet information header. Use nshandle.RunInURL to attach to
+// the net interface in another net NS, the file keeps working after switching back.
+// Equivalent to: `open("/dev/net/tun")` + `ioctl(TUNSETIFF, $ifName)`
+func Open(_ string, _ netlink.TuntapMode) (*os.File, error) {
+	return nil, errors.New("tuntap is supported only on linux")
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tuntap provides attaching to the persistent tap/tun net interfaces created by linkprovider.NewTap,
+// linkprovider.NewTun
+package tuntap
+
+import (
+	"os"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+const tunPath = "/dev/net/tun"
+
+type ifReq struct {
+	name  [unix.IFNAMSIZ]byte
+	flags uint16
+	_     [40 - unix.IFNAMSIZ - 2]byte
+}
+
+// Open attaches to the persistent tap/tun net interface in the current net NS and returns its file, frames (tap) or
+// IP packets (tun) are read and written without the packet information header. Use nshandle.RunInURL to attach to
+// the net interface in another net NS, the file keeps working after switching back.
+// Equivalent to: `open("/dev/net/tun")` + `ioctl(TUNSETIFF, $ifName)`
+func Open(ifName string, mode netlink.TuntapMode) (*os.File, error) {
+	if len(ifName) >= unix.IFNAMSIZ {
+		return nil, errors.Errorf("invalid net interface name: %v", ifName)
+	}
+
+	fd, err := unix.Open(tunPath, unix.O_RDWR|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open %v", tunPath)
+	}
+
+	req := ifReq{
+		flags: uint16(mode) | unix.IFF_NO_PI,
+	}
+	copy(req.name[:], ifName)
+	if _, _, errno := unix.Syscall(unix.SYS_IOCTL, uintptr(fd), unix.TUNSETIFF, uintptr(unsafe.Pointer(&req))); errno != 0 {
+		_ = unix.Close(fd)
+		return nil, errors.Wrapf(errno, "failed to attach to tap/tun net interface: %v", ifName)
+	}
+
+	return os.NewFile(uintptr(fd), tunPath), nil
+}
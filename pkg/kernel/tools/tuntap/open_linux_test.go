@@ -0,0 +1,51 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tuntap_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vishvananda/netlink"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/tuntap"
+)
+
+func TestOpen(t *testing.T) {
+	for name, mode := range map[string]netlink.TuntapMode{
+		"tuntap-tap": netlink.TUNTAP_MODE_TAP,
+		"tuntap-tun": netlink.TUNTAP_MODE_TUN,
+	} {
+		require.NoError(t, netlink.LinkAdd(&netlink.Tuntap{
+			LinkAttrs: netlink.LinkAttrs{Name: name},
+			Mode:      mode,
+			Flags:     netlink.TUNTAP_DEFAULTS,
+		}))
+		defer func(name string) { _ = netlink.LinkDel(&netlink.Tuntap{LinkAttrs: netlink.LinkAttrs{Name: name}}) }(name)
+
+		file, err := tuntap.Open(name, mode)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+	}
+
+	// persistent net interface is attached only in its own mode
+	_, err := tuntap.Open("tuntap-tap", netlink.TUNTAP_MODE_TUN)
+	require.Error(t, err)
+
+	_, err = tuntap.Open("tuntap-long-name-1", netlink.TUNTAP_MODE_TAP)
+	require.Error(t, err)
+}
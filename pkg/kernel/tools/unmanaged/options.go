@@ -0,0 +1,42 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unmanaged
+
+type unmanagedOptions struct {
+	root string
+}
+
+// Option is an option pattern for Set, Unset
+type Option func(o *unmanagedOptions)
+
+// WithRoot sets the root directory the daemons directories are looked up in, e.g. "/proc/1/root" for the host daemons
+// seen from a container with the host PID NS. Default is "/".
+func WithRoot(root string) Option {
+	return func(o *unmanagedOptions) {
+		o.root = root
+	}
+}
+
+func newOptions(options []Option) *unmanagedOptions {
+	o := &unmanagedOptions{
+		root: "/",
+	}
+	for _, opt := range options {
+		opt(o)
+	}
+	return o
+}
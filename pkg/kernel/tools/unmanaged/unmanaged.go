@@ -0,0 +1,153 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package unmanaged provides utils writing and cleaning per-interface "unmanaged" hints for the network management
+// daemons, so systemd-networkd and NetworkManager don't reconfigure (e.g. flush IP addresses of) NSM net interfaces.
+// The hints are written into /run, so they don't survive the node reboot as well as the net interfaces.
+package unmanaged
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	filePrefix = "10-nsm-"
+
+	networkdStateDir  = "/run/systemd/netif"
+	networkdConfigDir = "/run/systemd/network"
+	nmStateDir        = "/run/NetworkManager"
+	nmConfigDir       = "/run/NetworkManager/conf.d"
+)
+
+var (
+	// NetworkctlBinary is a path to the networkctl binary used to reload systemd-networkd configuration
+	NetworkctlBinary = "networkctl"
+	// NmcliBinary is a path to the nmcli binary used to reload NetworkManager configuration
+	NmcliBinary = "nmcli"
+)
+
+type daemon struct {
+	stateDir  string
+	configDir string
+	ext       string
+	content   string
+	reload    []string
+}
+
+func daemons() []*daemon {
+	return []*daemon{
+		{
+			stateDir:  networkdStateDir,
+			configDir: networkdConfigDir,
+			ext:       ".network",
+			content:   "[Match]\nName=%s\n\n[Link]\nUnmanaged=yes\n",
+			reload:    []string{NetworkctlBinary, "reload"},
+		},
+		{
+			stateDir:  nmStateDir,
+			configDir: nmConfigDir,
+			ext:       ".conf",
+			// "+=" appends to the value set by the other configuration files instead of overriding it
+			content: "[keyfile]\nunmanaged-devices+=interface-name:%s\n",
+			reload:  []string{NmcliBinary, "general", "reload", "conf"},
+		},
+	}
+}
+
+// Set writes the unmanaged hints for the net interface with the given name for the daemons running on the node (their
+// runtime state directory exists) and makes the daemons reload their configuration. Reload is skipped for WithRoot,
+// the daemons are expected to run in another mount NS then. It should be called before the net interface is created
+// or moved into the daemon net NS, so the daemon never starts managing it.
+func Set(ctx context.Context, ifName string, options ...Option) error {
+	o := newOptions(options)
+	for _, d := range daemons() {
+		if !o.exists(d.stateDir) {
+			continue
+		}
+		path := o.path(d.configDir, filePrefix+ifName+d.ext)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return errors.Wrapf(err, "failed to create configuration directory: %v", filepath.Dir(path))
+		}
+		if err := ioutil.WriteFile(path, []byte(fmt.Sprintf(d.content, ifName)), 0o644); err != nil {
+			return errors.Wrapf(err, "failed to write unmanaged hint: %v", path)
+		}
+		if err := o.reload(ctx, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unset deletes the unmanaged hints for the net interface with the given name written by Set, it is not an error if
+// there are no hints
+func Unset(ctx context.Context, ifName string, options ...Option) error {
+	o := newOptions(options)
+	for _, d := range daemons() {
+		path := o.path(d.configDir, filePrefix+ifName+d.ext)
+		if err := os.Remove(path); err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to delete unmanaged hint: %v", path)
+		}
+		if !o.exists(d.stateDir) {
+			continue
+		}
+		if err := o.reload(ctx, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *unmanagedOptions) path(elem ...string) string {
+	return filepath.Join(append([]string{o.root}, elem...)...)
+}
+
+func (o *unmanagedOptions) exists(dir string) bool {
+	info, err := os.Stat(o.path(dir))
+	return err == nil && info.IsDir()
+}
+
+func (o *unmanagedOptions) reload(ctx context.Context, d *daemon) error {
+	if o.root != "/" {
+		return nil
+	}
+	if _, err := exec.LookPath(d.reload[0]); err != nil {
+		return nil
+	}
+
+	// #nosec
+	cmd := exec.CommandContext(ctx, d.reload[0], d.reload[1:]...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "failed to reload configuration: %v: %v", strings.Join(d.reload, " "),
+			strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
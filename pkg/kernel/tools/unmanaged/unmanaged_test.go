@@ -0,0 +1,67 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unmanaged_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/unmanaged"
+)
+
+func TestSetUnset(t *testing.T) {
+	root, err := ioutil.TempDir("", "unmanaged")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(root) }()
+
+	// only systemd-networkd is running
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "run", "systemd", "netif"), 0o700))
+
+	require.NoError(t, unmanaged.Set(context.TODO(), "nsm-1", unmanaged.WithRoot(root)))
+
+	data, err := ioutil.ReadFile(filepath.Join(root, "run", "systemd", "network", "10-nsm-nsm-1.network"))
+	require.NoError(t, err)
+	require.Equal(t, "[Match]\nName=nsm-1\n\n[Link]\nUnmanaged=yes\n", string(data))
+
+	_, err = os.Stat(filepath.Join(root, "run", "NetworkManager"))
+	require.True(t, os.IsNotExist(err))
+
+	// NetworkManager is started
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "run", "NetworkManager"), 0o700))
+
+	require.NoError(t, unmanaged.Set(context.TODO(), "nsm-1", unmanaged.WithRoot(root)))
+
+	data, err = ioutil.ReadFile(filepath.Join(root, "run", "NetworkManager", "conf.d", "10-nsm-nsm-1.conf"))
+	require.NoError(t, err)
+	require.Equal(t, "[keyfile]\nunmanaged-devices+=interface-name:nsm-1\n", string(data))
+
+	require.NoError(t, unmanaged.Unset(context.TODO(), "nsm-1", unmanaged.WithRoot(root)))
+	require.NoError(t, unmanaged.Unset(context.TODO(), "nsm-1", unmanaged.WithRoot(root)))
+
+	for _, path := range []string{
+		filepath.Join(root, "run", "systemd", "network", "10-nsm-nsm-1.network"),
+		filepath.Join(root, "run", "NetworkManager", "conf.d", "10-nsm-nsm-1.conf"),
+	} {
+		_, err = os.Stat(path)
+		require.True(t, os.IsNotExist(err), path)
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/sysctl"
+)
+
+const (
+	sysModulePath = "/sys/module"
+	libModulePath = "/lib/modules"
+)
+
+// DefaultChecks returns the list of checks covering kernel settings commonly exhausted by the kernel forwarders
+func DefaultChecks() []*Check {
+	return []*Check{
+		FileDescriptorLimit(65536),
+		KernelVersion(4, 14),
+		SysctlMin("net.ipv4.neigh.default.gc_thresh3", 4096),
+		SysctlMin("net.ipv6.neigh.default.gc_thresh3", 4096),
+		SysctlMin("net.ipv6.route.max_size", 16384),
+		KernelModule("veth"),
+	}
+}
+
+// FileDescriptorLimit checks that the process open files soft limit is at least min
+func FileDescriptorLimit(min uint64) *Check {
+	return &Check{
+		Name: fmt.Sprintf("open files limit >= %d", min),
+		Run: func() error {
+			var rlimit syscall.Rlimit
+			if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rlimit); err != nil {
+				return errors.Wrap(err, "failed to get open files limit")
+			}
+			if uint64(rlimit.Cur) < min {
+				return errors.Errorf("open files limit is %d", rlimit.Cur)
+			}
+			return nil
+		},
+		Remediation: fmt.Sprintf("raise the open files limit to %d (ulimit -n, LimitNOFILE or container runtime settings)", min),
+	}
+}
+
+// SysctlMin checks that the given integer kernel parameter is at least min
+func SysctlMin(name string, min int64) *Check {
+	return &Check{
+		Name: fmt.Sprintf("sysctl %s >= %d", name, min),
+		Run: func() error {
+			value, err := sysctl.GetInt(name)
+			if err != nil {
+				return err
+			}
+			if value < min {
+				return errors.Errorf("sysctl %s is %d", name, value)
+			}
+			return nil
+		},
+		Remediation: fmt.Sprintf("sysctl -w %s=%d", name, min),
+	}
+}
+
+// KernelVersion checks that the node kernel version is at least major.minor
+func KernelVersion(major, minor int) *Check {
+	return &Check{
+		Name: fmt.Sprintf("kernel version >= %d.%d", major, minor),
+		Run: func() error {
+			release, err := sysctl.Get("kernel.osrelease")
+			if err != nil {
+				return err
+			}
+			curMajor, curMinor, err := parseKernelRelease(release)
+			if err != nil {
+				return err
+			}
+			if curMajor < major || curMajor == major && curMinor < minor {
+				return errors.Errorf("kernel version is %s", release)
+			}
+			return nil
+		},
+		Remediation: fmt.Sprintf("upgrade the node kernel to %d.%d or newer", major, minor),
+	}
+}
+
+// KernelModule checks that the given kernel module is loaded, built in or available for loading
+func KernelModule(name string) *Check {
+	moduleName := strings.ReplaceAll(name, "-", "_")
+	return &Check{
+		Name: fmt.Sprintf("kernel module %s", name),
+		Run: func() error {
+			if _, err := os.Stat(filepath.Join(sysModulePath, moduleName)); err == nil {
+				return nil
+			}
+
+			release, err := sysctl.Get("kernel.osrelease")
+			if err != nil {
+				return err
+			}
+			for _, file := range []string{"modules.builtin", "modules.dep"} {
+				if found, err := moduleListed(filepath.Join(libModulePath, release, file), moduleName); err == nil && found {
+					return nil
+				}
+			}
+			return errors.Errorf("kernel module %s is neither loaded nor available", name)
+		},
+		Remediation: fmt.Sprintf("modprobe %s or install the kernel modules package for the running kernel", name),
+	}
+}
+
+func parseKernelRelease(release string) (major, minor int, err error) {
+	parts := strings.SplitN(release, ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, errors.Errorf("invalid kernel release: %s", release)
+	}
+	if major, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid kernel release: %s", release)
+	}
+	minorPart := strings.FieldsFunc(parts[1], func(r rune) bool { return r < '0' || r > '9' })
+	if len(minorPart) == 0 {
+		return 0, 0, errors.Errorf("invalid kernel release: %s", release)
+	}
+	if minor, err = strconv.Atoi(minorPart[0]); err != nil {
+		return 0, 0, errors.Wrapf(err, "invalid kernel release: %s", release)
+	}
+	return major, minor, nil
+}
+
+func moduleListed(path, name string) (bool, error) {
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return false, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			line = line[:i]
+		}
+		module := filepath.Base(strings.TrimSpace(line))
+		if i := strings.Index(module, ".ko"); i >= 0 {
+			module = module[:i]
+		}
+		if strings.ReplaceAll(module, "-", "_") == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package validate provides pre-flight checks of the node kernel settings required by the kernel forwarders
+package validate
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// Check is a single pre-flight check
+type Check struct {
+	// Name is a human readable check name
+	Name string
+	// Run performs the check and returns an error describing the problem if the check fails
+	Run func() error
+	// Remediation is an actionable hint for fixing the failed check
+	Remediation string
+}
+
+// Result is a result of the single pre-flight check
+type Result struct {
+	*Check
+	// Err is nil if the check has passed
+	Err error
+}
+
+// Run runs all the given checks and returns their results
+func Run(checks ...*Check) []*Result {
+	results := make([]*Result, 0, len(checks))
+	for _, check := range checks {
+		results = append(results, &Result{
+			Check: check,
+			Err:   check.Run(),
+		})
+	}
+	return results
+}
+
+// Validate runs all the given checks, prints the report to w and returns an error if any of the checks has failed
+func Validate(w io.Writer, checks ...*Check) error {
+	results := Run(checks...)
+
+	if err := Report(w, results); err != nil {
+		return err
+	}
+
+	var failed int
+	for _, result := range results {
+		if result.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("%d of %d pre-flight checks failed", failed, len(results))
+	}
+	return nil
+}
+
+// Report prints human readable report for the given results to w
+func Report(w io.Writer, results []*Result) error {
+	for _, result := range results {
+		var err error
+		if result.Err == nil {
+			_, err = fmt.Fprintf(w, "[ OK ] %s\n", result.Name)
+		} else {
+			_, err = fmt.Fprintf(w, "[FAIL] %s: %s\n", result.Name, result.Err.Error())
+			if err == nil && result.Remediation != "" {
+				_, err = fmt.Fprintf(w, "       remediation: %s\n", result.Remediation)
+			}
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to write pre-flight report")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validate_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/validate"
+)
+
+func TestValidate(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	err := validate.Validate(buf,
+		&validate.Check{
+			Name: "passed",
+			Run:  func() error { return nil },
+		},
+		&validate.Check{
+			Name:        "failed",
+			Run:         func() error { return errors.New("error") },
+			Remediation: "fix it",
+		},
+	)
+	require.Error(t, err)
+
+	require.Equal(t, "[ OK ] passed\n"+
+		"[FAIL] failed: error\n"+
+		"       remediation: fix it\n", buf.String())
+}
+
+func TestValidate_Passed(t *testing.T) {
+	err := validate.Validate(new(bytes.Buffer), &validate.Check{
+		Name: "passed",
+		Run:  func() error { return nil },
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,327 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vni provides allocator of the tunnel IDs: VXLAN, Geneve VNIs and GRE keys
+package vni
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nodelock"
+)
+
+const (
+	// MaxVNI is the max VXLAN, Geneve VNI
+	MaxVNI = 1<<24 - 1
+	// MaxGREKey is the max GRE key
+	MaxGREKey = 1<<32 - 1
+
+	// fileVersion is the version of the persistence file format. Version 0 is the unversioned owner -> ID map, it is
+	// migrated on the first save.
+	fileVersion = 1
+)
+
+// allocations is the persistence file content
+type allocations struct {
+	Version int               `json:"version"`
+	Owners  map[string]uint32 `json:"owners"`
+}
+
+// ConflictError is returned when the ID is already allocated by another owner (possibly in another Forwarder
+// instance on the node)
+type ConflictError struct {
+	ID    uint32
+	Owner string
+}
+
+func (e *ConflictError) Error() string {
+	if e.Owner == "" {
+		return "tunnel ID is already allocated on the node: " + strconv.FormatUint(uint64(e.ID), 10)
+	}
+	return "tunnel ID is already allocated: " + strconv.FormatUint(uint64(e.ID), 10) + " by " + e.Owner
+}
+
+// IsConflict returns true if err is caused by ConflictError
+func IsConflict(err error) bool {
+	_, ok := errors.Cause(err).(*ConflictError)
+	return ok
+}
+
+// Allocator allocates the tunnel IDs for the owners (e.g. connection IDs). It is safe for concurrent use.
+type Allocator struct {
+	min, max uint32
+	path     string
+	locker   *nodelock.Locker
+	space    string
+
+	mu      sync.Mutex
+	next    uint32
+	owners  map[string]uint32
+	ids     map[uint32]string
+	locks   map[uint32]*nodelock.Lock
+	persist bool
+}
+
+// NewAllocator returns a new Allocator, by default it allocates VNIs in [1, MaxVNI] range without persistence and
+// node level conflict detection. Persisted allocations are restored.
+func NewAllocator(options ...Option) (*Allocator, error) {
+	a := &Allocator{
+		min:    1,
+		max:    MaxVNI,
+		space:  "vni",
+		owners: make(map[string]uint32),
+		ids:    make(map[uint32]string),
+		locks:  make(map[uint32]*nodelock.Lock),
+	}
+	for _, opt := range options {
+		opt(a)
+	}
+	if a.min > a.max {
+		return nil, errors.Errorf("invalid tunnel ID range: [%d, %d]", a.min, a.max)
+	}
+	a.next = a.min
+
+	migrate, err := a.restore()
+	if err != nil {
+		a.unlockAll()
+		return nil, err
+	}
+	a.persist = a.path != ""
+
+	if migrate {
+		if err := a.save(); err != nil {
+			a.unlockAll()
+			return nil, err
+		}
+	}
+
+	return a, nil
+}
+
+// Allocate returns the ID allocated for the owner, it allocates a new one if there is no such
+func (a *Allocator) Allocate(owner string) (uint32, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if id, ok := a.owners[owner]; ok {
+		return id, nil
+	}
+
+	for i, id := uint64(0), a.next; i <= uint64(a.max-a.min); i++ {
+		candidate := id
+		if id == a.max {
+			id = a.min
+		} else {
+			id++
+		}
+
+		if _, ok := a.ids[candidate]; ok {
+			continue
+		}
+		err := a.reserve(owner, candidate)
+		if IsConflict(err) {
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		a.next = id
+		return candidate, nil
+	}
+
+	return 0, errors.Errorf("no free tunnel IDs in range: [%d, %d]", a.min, a.max)
+}
+
+// Reserve allocates the given ID for the owner, it is used for the IDs coordinated externally. It returns
+// ConflictError if the ID is already allocated for another owner.
+func (a *Allocator) Reserve(owner string, id uint32) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if current, ok := a.owners[owner]; ok {
+		if current == id {
+			return nil
+		}
+		return errors.Errorf("owner already has another tunnel ID allocated: %v %d", owner, current)
+	}
+	if id < a.min || id > a.max {
+		return errors.Errorf("tunnel ID is out of range: %d [%d, %d]", id, a.min, a.max)
+	}
+	if current, ok := a.ids[id]; ok {
+		return &ConflictError{ID: id, Owner: current}
+	}
+
+	return a.reserve(owner, id)
+}
+
+// Lookup returns the ID allocated for the owner
+func (a *Allocator) Lookup(owner string) (uint32, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	id, ok := a.owners[owner]
+	return id, ok
+}
+
+// Release releases the ID allocated for the owner
+func (a *Allocator) Release(owner string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	id, ok := a.owners[owner]
+	if !ok {
+		return nil
+	}
+
+	delete(a.owners, owner)
+	delete(a.ids, id)
+
+	var unlockErr error
+	if lock, ok := a.locks[id]; ok {
+		delete(a.locks, id)
+		unlockErr = lock.Unlock()
+	}
+
+	if err := a.save(); err != nil {
+		return err
+	}
+	return unlockErr
+}
+
+func (a *Allocator) reserve(owner string, id uint32) error {
+	if a.locker != nil {
+		lock, err := a.locker.TryLock(nodelock.KindVNI, a.space+"-"+strconv.FormatUint(uint64(id), 10))
+		if nodelock.IsLocked(err) {
+			return &ConflictError{ID: id}
+		}
+		if err != nil {
+			return err
+		}
+		a.locks[id] = lock
+	}
+
+	a.owners[owner] = id
+	a.ids[id] = owner
+
+	if err := a.save(); err != nil {
+		delete(a.owners, owner)
+		delete(a.ids, id)
+		if lock, ok := a.locks[id]; ok {
+			delete(a.locks, id)
+			_ = lock.Unlock()
+		}
+		return err
+	}
+
+	return nil
+}
+
+// save atomically writes the allocations into the persistence file
+func (a *Allocator) save() error {
+	if !a.persist {
+		return nil
+	}
+
+	data, err := json.Marshal(&allocations{
+		Version: fileVersion,
+		Owners:  a.owners,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal tunnel IDs")
+	}
+
+	tmpPath := a.path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0o600); err != nil {
+		return errors.Wrapf(err, "failed to write tunnel IDs: %v", tmpPath)
+	}
+	if err := os.Rename(tmpPath, a.path); err != nil {
+		return errors.Wrapf(err, "failed to write tunnel IDs: %v", a.path)
+	}
+
+	return nil
+}
+
+// restore restores the persisted allocations, it returns true if the persistence file should be migrated to the
+// current format version
+func (a *Allocator) restore() (migrate bool, err error) {
+	if a.path == "" {
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(a.path), 0o750); err != nil {
+		return false, errors.Wrapf(err, "failed to create tunnel IDs directory: %v", filepath.Dir(a.path))
+	}
+
+	data, err := ioutil.ReadFile(a.path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to read tunnel IDs: %v", a.path)
+	}
+
+	version, owners, err := unmarshalAllocations(data)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal tunnel IDs: %v", a.path)
+	}
+
+	for owner, id := range owners {
+		if id < a.min || id > a.max {
+			return false, errors.Errorf("persisted tunnel ID is out of range: %v %d [%d, %d]", owner, id, a.min, a.max)
+		}
+		if current, ok := a.ids[id]; ok {
+			return false, errors.Wrapf(&ConflictError{ID: id, Owner: current}, "invalid persisted tunnel IDs: %v", a.path)
+		}
+		if err := a.reserve(owner, id); err != nil {
+			return false, err
+		}
+	}
+
+	return version != fileVersion, nil
+}
+
+// unmarshalAllocations returns the persistence file format version and the persisted owner -> ID map, it supports
+// all the versions up to fileVersion and returns an error for the newer ones written by the newer Forwarder
+func unmarshalAllocations(data []byte) (int, map[string]uint32, error) {
+	versioned := new(allocations)
+	if err := json.Unmarshal(data, versioned); err == nil && versioned.Version != 0 && versioned.Owners != nil {
+		if versioned.Version > fileVersion {
+			return 0, nil, errors.Errorf("unsupported file version: %d, max supported is %d", versioned.Version, fileVersion)
+		}
+		return versioned.Version, versioned.Owners, nil
+	}
+
+	owners := make(map[string]uint32)
+	if err := json.Unmarshal(data, &owners); err != nil {
+		return 0, nil, err
+	}
+	return 0, owners, nil
+}
+
+func (a *Allocator) unlockAll() {
+	for id, lock := range a.locks {
+		delete(a.locks, id)
+		_ = lock.Unlock()
+	}
+}
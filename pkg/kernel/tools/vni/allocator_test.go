@@ -0,0 +1,138 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vni_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nodelock"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/vni"
+)
+
+func TestAllocator(t *testing.T) {
+	allocator, err := vni.NewAllocator(vni.WithRange(10, 12))
+	require.NoError(t, err)
+
+	id, err := allocator.Allocate("conn-1")
+	require.NoError(t, err)
+	require.Equal(t, uint32(10), id)
+
+	// allocation is idempotent per owner
+	id, err = allocator.Allocate("conn-1")
+	require.NoError(t, err)
+	require.Equal(t, uint32(10), id)
+
+	err = allocator.Reserve("conn-2", 10)
+	require.True(t, vni.IsConflict(err))
+	require.NoError(t, allocator.Reserve("conn-2", 12))
+	require.Error(t, allocator.Reserve("conn-3", 13))
+
+	id, err = allocator.Allocate("conn-3")
+	require.NoError(t, err)
+	require.Equal(t, uint32(11), id)
+
+	_, err = allocator.Allocate("conn-4")
+	require.Error(t, err)
+
+	require.NoError(t, allocator.Release("conn-1"))
+	id, err = allocator.Allocate("conn-4")
+	require.NoError(t, err)
+	require.Equal(t, uint32(10), id)
+
+	_, ok := allocator.Lookup("conn-1")
+	require.False(t, ok)
+}
+
+func TestAllocator_Persistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vni")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "vxlan.json")
+
+	allocator, err := vni.NewAllocator(vni.WithPersistence(path))
+	require.NoError(t, err)
+	id, err := allocator.Allocate("conn-1")
+	require.NoError(t, err)
+
+	// Forwarder restart
+	allocator, err = vni.NewAllocator(vni.WithPersistence(path))
+	require.NoError(t, err)
+	restored, ok := allocator.Lookup("conn-1")
+	require.True(t, ok)
+	require.Equal(t, id, restored)
+
+	id, err = allocator.Allocate("conn-2")
+	require.NoError(t, err)
+	require.NotEqual(t, restored, id)
+}
+
+func TestAllocator_NodeLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vni")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	locker, err := nodelock.NewLocker(dir)
+	require.NoError(t, err)
+
+	// allocators in the different Forwarder instances on the node
+	allocator1, err := vni.NewAllocator(vni.WithNodeLock(locker, "vxlan"))
+	require.NoError(t, err)
+	allocator2, err := vni.NewAllocator(vni.WithNodeLock(locker, "vxlan"))
+	require.NoError(t, err)
+
+	id1, err := allocator1.Allocate("conn-1")
+	require.NoError(t, err)
+	id2, err := allocator2.Allocate("conn-2")
+	require.NoError(t, err)
+	require.NotEqual(t, id1, id2)
+
+	require.True(t, vni.IsConflict(allocator2.Reserve("conn-3", id1)))
+
+	require.NoError(t, allocator1.Release("conn-1"))
+	require.NoError(t, allocator2.Reserve("conn-3", id1))
+}
+
+func TestAllocator_PersistenceVersion(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vni")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "vxlan.json")
+
+	// unversioned file written by the previous Forwarder is migrated
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"conn-1":10}`), 0o600))
+	allocator, err := vni.NewAllocator(vni.WithPersistence(path))
+	require.NoError(t, err)
+	id, ok := allocator.Lookup("conn-1")
+	require.True(t, ok)
+	require.Equal(t, uint32(10), id)
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"version":1,"owners":{"conn-1":10}}`, string(data))
+
+	// file written by the newer Forwarder is not silently misread
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{"version":2,"owners":{"conn-1":10}}`), 0o600))
+	_, err = vni.NewAllocator(vni.WithPersistence(path))
+	require.Error(t, err)
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vni
+
+import (
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nodelock"
+)
+
+// Option is an option pattern for NewAllocator
+type Option func(a *Allocator)
+
+// WithRange sets the allocated IDs range, e.g. [1, MaxGREKey] for GRE keys
+func WithRange(min, max uint32) Option {
+	return func(a *Allocator) {
+		a.min, a.max = min, max
+	}
+}
+
+// WithPersistence sets the file for persisting the allocations, so they survive the Forwarder restart. The file format
+// is versioned: the files written by the previous Forwarder versions are migrated, NewAllocator fails on the files
+// written by the newer ones.
+func WithPersistence(path string) Option {
+	return func(a *Allocator) {
+		a.path = path
+	}
+}
+
+// WithNodeLock enables node level conflict detection with the given Locker, so the Forwarder instances on the node
+// sharing the space (e.g. "vxlan", "gre") don't allocate the same IDs
+func WithNodeLock(locker *nodelock.Locker, space string) Option {
+	return func(a *Allocator) {
+		a.locker = locker
+		a.space = space
+	}
+}
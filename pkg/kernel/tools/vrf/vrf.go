@@ -0,0 +1,99 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vrf provides "ip vrf exec" like API for running the code with the sockets bound to the connection VRF, so
+// the probes, DHCP responders, RA daemons traffic stays inside the connection routing domain
+package vrf
+
+import (
+	"net"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+// Context creates the sockets bound to the VRF device with SO_BINDTODEVICE. Unlike "ip vrf exec" it doesn't affect
+// the sockets created another way, so all the callback sockets should be created with the Context.
+type Context struct {
+	name string
+}
+
+// New returns a new Context for the VRF device with the given name in the current net NS, it doesn't check the device
+// so it can be used with any net interface
+func New(name string) *Context {
+	return &Context{
+		name: name,
+	}
+}
+
+// Name returns the VRF device name
+func (c *Context) Name() string {
+	return c.name
+}
+
+// Bind binds the socket to the VRF device
+func (c *Context) Bind(fd int) error {
+	if err := bindToDevice(fd, c.name); err != nil {
+		return errors.Wrapf(err, "failed to bind socket to VRF: %v", c.name)
+	}
+	return nil
+}
+
+// Control is a net.Dialer, net.ListenConfig control function binding the socket to the VRF device
+func (c *Context) Control(_, _ string, conn syscall.RawConn) error {
+	var bindErr error
+	if err := conn.Control(func(fd uintptr) {
+		bindErr = c.Bind(int(fd))
+	}); err != nil {
+		return err
+	}
+	return bindErr
+}
+
+// Dialer returns a new net.Dialer creating the sockets bound to the VRF device
+func (c *Context) Dialer() *net.Dialer {
+	return &net.Dialer{Control: c.Control}
+}
+
+// ListenConfig returns a new net.ListenConfig creating the sockets bound to the VRF device
+func (c *Context) ListenConfig() *net.ListenConfig {
+	return &net.ListenConfig{Control: c.Control}
+}
+
+// Exec runs f in the net NS with the Context for the given VRF device, it fails if there is no such VRF device in the
+// net NS. The sockets should be created inside f, they remain in the net NS after f returns.
+func Exec(netNS netns.NsHandle, name string, f func(vrf *Context) error) error {
+	curNetNS, err := nshandle.Current()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = curNetNS.Close() }()
+
+	return nshandle.RunIn(curNetNS, netNS, func() error {
+		link, err := netlink.LinkByName(name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get VRF device: %v", name)
+		}
+		if _, ok := link.(*netlink.Vrf); !ok {
+			return errors.Errorf("net interface is not a VRF device: %v %v", name, link.Type())
+		}
+		return f(New(name))
+	})
+}
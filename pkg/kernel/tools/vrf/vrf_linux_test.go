@@ -0,0 +1,79 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vrf_test
+
+import (
+	"context"
+	"net"
+	"runtime"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/vrf"
+)
+
+const (
+	ifName   = "vrf-1"
+	peerName = "vrf-2"
+)
+
+func boundDevice(t *testing.T, conn syscall.Conn) string {
+	rawConn, err := conn.SyscallConn()
+	require.NoError(t, err)
+
+	var device string
+	var sockErr error
+	require.NoError(t, rawConn.Control(func(fd uintptr) {
+		device, sockErr = unix.GetsockoptString(int(fd), unix.SOL_SOCKET, unix.SO_BINDTODEVICE)
+	}))
+	require.NoError(t, sockErr)
+
+	return device
+}
+
+func TestContext(t *testing.T) {
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	conn, err := vrf.New(ifName).ListenConfig().ListenPacket(context.TODO(), "udp", "0.0.0.0:0")
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	require.Equal(t, ifName, boundDevice(t, conn.(*net.UDPConn)))
+}
+
+func TestExec_NotVRF(t *testing.T) {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	kerneltest.AddVeth(t, ifName, peerName)
+
+	curNetNS, err := nshandle.Current()
+	require.NoError(t, err)
+	defer func() { _ = curNetNS.Close() }()
+
+	called := false
+	require.Error(t, vrf.Exec(curNetNS, ifName, func(_ *vrf.Context) error {
+		called = true
+		return nil
+	}))
+	require.False(t, called)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package warmup provides pre-warming of the node-local caches from the connections known after the Forwarder
+// restart (e.g. restored from the persisted state), so the first refresh of each connection doesn't pay the cache miss
+// latency
+package warmup
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netns"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ifindex"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/nshandle"
+)
+
+const defaultConcurrency = 8
+
+type warmer struct {
+	concurrency int
+}
+
+// IfIndexCache warms up the cache with the connection kernel interfaces indexes in their net NSs and the Forwarder's
+// ends of the veth pairs in the current net NS, it also starts the link events subscriptions for these net NSs.
+// Connections with the already deleted net NS or net interface are skipped. It returns the number of the cached net
+// interfaces, it fails only if ctx is done.
+func IfIndexCache(ctx context.Context, cache *ifindex.Cache, conns []*networkservice.Connection, options ...Option) (int, error) {
+	w := &warmer{
+		concurrency: defaultConcurrency,
+	}
+	for _, opt := range options {
+		opt(w)
+	}
+	if w.concurrency < 1 {
+		w.concurrency = 1
+	}
+
+	current, err := nshandle.Current()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = current.Close() }()
+
+	var count int
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, w.concurrency)
+	for _, conn := range conns {
+		mech := kernel.ToMechanism(conn.GetMechanism())
+		if mech == nil {
+			continue
+		}
+
+		// select picks randomly if both cases are ready, so ctx is checked first
+		if ctx.Err() != nil {
+			wg.Wait()
+			return count, errors.Wrap(ctx.Err(), "cache warm-up is interrupted")
+		}
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return count, errors.Wrap(ctx.Err(), "cache warm-up is interrupted")
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(conn *networkservice.Connection, mech *kernel.Mechanism) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			cached := warmIfIndex(cache, current, mech.GetNetNSURL(), mech.GetInterfaceName(conn))
+			if _, err := cache.Index(current, linkprovider.VethPeerName(conn)); err == nil {
+				cached++
+			}
+
+			lock.Lock()
+			count += cached
+			lock.Unlock()
+		}(conn, mech)
+	}
+	wg.Wait()
+
+	return count, nil
+}
+
+// warmIfIndex caches the net interface index in the net NS, empty net NS URL means the current net NS. It returns the
+// number of the cached net interfaces.
+func warmIfIndex(cache *ifindex.Cache, current netns.NsHandle, netNSURL, ifName string) int {
+	handle := current
+	if netNSURL != "" {
+		var err error
+		if handle, err = nshandle.FromURL(netNSURL); err != nil {
+			return 0
+		}
+		defer func() { _ = handle.Close() }()
+	}
+
+	if _, err := cache.Index(handle, ifName); err != nil {
+		return 0
+	}
+	return 1
+}
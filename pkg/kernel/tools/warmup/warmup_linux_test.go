@@ -0,0 +1,75 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package warmup_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/api/pkg/api/networkservice"
+	"github.com/networkservicemesh/api/pkg/api/networkservice/mechanisms/kernel"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/ifindex"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/kerneltest"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/linkprovider"
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/warmup"
+)
+
+const ifName = "warmup-1"
+
+func newConn(id string) *networkservice.Connection {
+	return &networkservice.Connection{
+		Id: id,
+		Mechanism: &networkservice.Mechanism{
+			Type: kernel.MECHANISM,
+			Parameters: map[string]string{
+				kernel.InterfaceNameKey: ifName,
+			},
+		},
+	}
+}
+
+func TestIfIndexCache(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn := newConn("conn-1")
+	kerneltest.AddVeth(t, ifName, linkprovider.VethPeerName(conn))
+
+	missing := newConn("conn-2")
+	missing.GetMechanism().GetParameters()[kernel.NetNSURL] = "file:///proc/0/ns/net"
+
+	count, err := warmup.IfIndexCache(ctx, ifindex.NewCache(ctx), []*networkservice.Connection{
+		conn,
+		missing,
+		{Id: "conn-3"},
+	}, warmup.WithConcurrency(2))
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func TestIfIndexCache_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := warmup.IfIndexCache(ctx, ifindex.NewCache(ctx), []*networkservice.Connection{
+		newConn("conn-1"),
+	}, warmup.WithConcurrency(0))
+	require.Error(t, err)
+}
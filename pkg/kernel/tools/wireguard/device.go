@@ -0,0 +1,33 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//+build !linux
+
+package wireguard
+
+import "github.com/pkg/errors"
+
+// Configure applies the configuration to the WireGuard net interface in the current net NS.
+// Equivalent to: `wg set $ifName private-key ... listen-port ... peer ... endpoint ... allowed-ips ...`
+func Configure(_ string, _ *Config) error {
+	return errors.New("WireGuard is supported only on linux")
+}
+
+// GetDevice returns the WireGuard net interface state in the current net NS.
+// Equivalent to: `wg show $ifName`
+func GetDevice(_ string) (*Device, error) {
+	return nil, errors.New("WireGuard is supported only on linux")
+}
@@ -0,0 +1,190 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard
+
+import (
+	"encoding/binary"
+	"net"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netlink/nl"
+	"golang.org/x/sys/unix"
+)
+
+// Values from the linux/wireguard.h
+const (
+	genlName    = "wireguard"
+	genlVersion = 1
+
+	cmdGetDevice = 0
+	cmdSetDevice = 1
+
+	deviceAttrIfName     = 2
+	deviceAttrPrivateKey = 3
+	deviceAttrPublicKey  = 4
+	deviceAttrFlags      = 5
+	deviceAttrListenPort = 6
+	deviceAttrPeers      = 8
+
+	deviceFlagReplacePeers = 1
+
+	peerAttrPublicKey                   = 1
+	peerAttrFlags                       = 3
+	peerAttrEndpoint                    = 4
+	peerAttrPersistentKeepaliveInterval = 5
+	peerAttrAllowedIPs                  = 9
+
+	peerFlagReplaceAllowedIPs = 2
+
+	allowedIPAttrFamily   = 1
+	allowedIPAttrIPAddr   = 2
+	allowedIPAttrCIDRMask = 3
+)
+
+// Configure applies the configuration to the WireGuard net interface in the current net NS.
+// Equivalent to: `wg set $ifName private-key ... listen-port ... peer ... endpoint ... allowed-ips ...`
+func Configure(ifName string, config *Config) error {
+	req, err := newRequest(cmdSetDevice, unix.NLM_F_REQUEST|unix.NLM_F_ACK, ifName)
+	if err != nil {
+		return err
+	}
+
+	if !config.PrivateKey.IsZero() {
+		req.AddData(nl.NewRtAttr(deviceAttrPrivateKey, config.PrivateKey[:]))
+	}
+	if config.ListenPort != 0 {
+		req.AddData(nl.NewRtAttr(deviceAttrListenPort, nl.Uint16Attr(uint16(config.ListenPort))))
+	}
+	if config.ReplacePeers {
+		req.AddData(nl.NewRtAttr(deviceAttrFlags, nl.Uint32Attr(deviceFlagReplacePeers)))
+	}
+
+	if len(config.Peers) > 0 {
+		peers := nl.NewRtAttr(deviceAttrPeers|unix.NLA_F_NESTED, nil)
+		for i, peer := range config.Peers {
+			if err := addPeer(peers.AddRtAttr(i|unix.NLA_F_NESTED, nil), peer); err != nil {
+				return err
+			}
+		}
+		req.AddData(peers)
+	}
+
+	if _, err := req.Execute(unix.NETLINK_GENERIC, 0); err != nil {
+		return errors.Wrapf(err, "failed to configure WireGuard net interface: %v", ifName)
+	}
+	return nil
+}
+
+// GetDevice returns the WireGuard net interface state in the current net NS.
+// Equivalent to: `wg show $ifName`
+func GetDevice(ifName string) (*Device, error) {
+	req, err := newRequest(cmdGetDevice, unix.NLM_F_REQUEST|unix.NLM_F_DUMP, ifName)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := req.Execute(unix.NETLINK_GENERIC, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get WireGuard net interface: %v", ifName)
+	}
+
+	device := &Device{}
+	for _, msg := range msgs {
+		attrs, err := nl.ParseRouteAttr(msg[nl.SizeofGenlmsg:])
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse WireGuard net interface reply")
+		}
+		device.parseAttributes(attrs)
+	}
+	return device, nil
+}
+
+func newRequest(cmd uint8, flags int, ifName string) (*nl.NetlinkRequest, error) {
+	family, err := netlink.GenlFamilyGet(genlName)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get WireGuard generic netlink family")
+	}
+
+	req := nl.NewNetlinkRequest(int(family.ID), flags)
+	req.AddData(&nl.Genlmsg{
+		Command: cmd,
+		Version: genlVersion,
+	})
+	req.AddData(nl.NewRtAttr(deviceAttrIfName, nl.ZeroTerminated(ifName)))
+
+	return req, nil
+}
+
+func addPeer(attr *nl.RtAttr, peer *Peer) error {
+	attr.AddRtAttr(peerAttrPublicKey, peer.PublicKey[:])
+	attr.AddRtAttr(peerAttrFlags, nl.Uint32Attr(peerFlagReplaceAllowedIPs))
+	if peer.Endpoint != nil {
+		endpoint, err := sockaddr(peer.Endpoint)
+		if err != nil {
+			return err
+		}
+		attr.AddRtAttr(peerAttrEndpoint, endpoint)
+	}
+	attr.AddRtAttr(peerAttrPersistentKeepaliveInterval, nl.Uint16Attr(uint16(peer.PersistentKeepalive.Seconds())))
+
+	allowedIPs := attr.AddRtAttr(peerAttrAllowedIPs|unix.NLA_F_NESTED, nil)
+	for i, ipNet := range peer.AllowedIPs {
+		allowedIP := allowedIPs.AddRtAttr(i|unix.NLA_F_NESTED, nil)
+		ones, _ := ipNet.Mask.Size()
+		if ip := ipNet.IP.To4(); ip != nil {
+			allowedIP.AddRtAttr(allowedIPAttrFamily, nl.Uint16Attr(unix.AF_INET))
+			allowedIP.AddRtAttr(allowedIPAttrIPAddr, ip)
+		} else {
+			allowedIP.AddRtAttr(allowedIPAttrFamily, nl.Uint16Attr(unix.AF_INET6))
+			allowedIP.AddRtAttr(allowedIPAttrIPAddr, ipNet.IP.To16())
+		}
+		allowedIP.AddRtAttr(allowedIPAttrCIDRMask, nl.Uint8Attr(uint8(ones)))
+	}
+	return nil
+}
+
+// sockaddr returns the raw sockaddr_in or sockaddr_in6 for the UDP address
+func sockaddr(addr *net.UDPAddr) ([]byte, error) {
+	if ip := addr.IP.To4(); ip != nil {
+		b := make([]byte, unix.SizeofSockaddrInet4)
+		nl.NativeEndian().PutUint16(b[0:], unix.AF_INET)
+		binary.BigEndian.PutUint16(b[2:], uint16(addr.Port))
+		copy(b[4:], ip)
+		return b, nil
+	}
+	if ip := addr.IP.To16(); ip != nil {
+		b := make([]byte, unix.SizeofSockaddrInet6)
+		nl.NativeEndian().PutUint16(b[0:], unix.AF_INET6)
+		binary.BigEndian.PutUint16(b[2:], uint16(addr.Port))
+		copy(b[8:], ip)
+		return b, nil
+	}
+	return nil, errors.Errorf("invalid WireGuard peer endpoint: %v", addr)
+}
+
+func (d *Device) parseAttributes(attrs []syscall.NetlinkRouteAttr) {
+	for _, attr := range attrs {
+		switch attr.Attr.Type {
+		case deviceAttrPublicKey:
+			copy(d.PublicKey[:], attr.Value)
+		case deviceAttrListenPort:
+			d.ListenPort = int(nl.NativeEndian().Uint16(attr.Value))
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package wireguard provides WireGuard net interface configuration utils working directly with the WireGuard generic
+// netlink family
+package wireguard
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// KeyLen is a WireGuard Curve25519 key length
+const KeyLen = 32
+
+// Key is a WireGuard private or public key
+type Key [KeyLen]byte
+
+// GeneratePrivateKey returns a new random WireGuard private key. The public key is derived by the kernel when the
+// private key is set to the net interface, see GetDevice.
+func GeneratePrivateKey() (Key, error) {
+	var key Key
+	if _, err := rand.Read(key[:]); err != nil {
+		return Key{}, errors.Wrap(err, "failed to generate WireGuard private key")
+	}
+	// Curve25519 private key clamping
+	key[0] &= 248
+	key[31] = (key[31] & 127) | 64
+	return key, nil
+}
+
+// ParseKey parses the base64 encoded WireGuard key
+func ParseKey(s string) (Key, error) {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(data) != KeyLen {
+		return Key{}, errors.Errorf("invalid WireGuard key: %v", s)
+	}
+	var key Key
+	copy(key[:], data)
+	return key, nil
+}
+
+// String returns the base64 encoded key
+func (k Key) String() string {
+	return base64.StdEncoding.EncodeToString(k[:])
+}
+
+// IsZero returns true if the key is not set
+func (k Key) IsZero() bool {
+	return k == Key{}
+}
+
+// Peer is a WireGuard peer configuration
+type Peer struct {
+	// PublicKey is the peer public key
+	PublicKey Key
+	// Endpoint is the peer UDP address, it can be nil for the peer connecting to us
+	Endpoint *net.UDPAddr
+	// PersistentKeepalive is the keepalive interval, 0 disables keepalives
+	PersistentKeepalive time.Duration
+	// AllowedIPs are the inner IP prefixes routed to the peer, they replace the previous ones
+	AllowedIPs []*net.IPNet
+}
+
+// Config is a WireGuard net interface configuration
+type Config struct {
+	// PrivateKey is the net interface private key, zero key is not changed
+	PrivateKey Key
+	// ListenPort is the net interface UDP listen port, 0 is not changed
+	ListenPort int
+	// ReplacePeers makes Peers replace all existing peers instead of being added/updated
+	ReplacePeers bool
+	// Peers are the peers to add or update
+	Peers []*Peer
+}
+
+// Device is a WireGuard net interface state
+type Device struct {
+	// PublicKey is the net interface public key derived from its private key
+	PublicKey Key
+	// ListenPort is the actual UDP listen port
+	ListenPort int
+}
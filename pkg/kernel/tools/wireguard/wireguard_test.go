@@ -0,0 +1,44 @@
+// Copyright (c) 2020 Doc.ai and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguard_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/networkservicemesh/sdk-kernel/pkg/kernel/tools/wireguard"
+)
+
+func TestKey(t *testing.T) {
+	key, err := wireguard.GeneratePrivateKey()
+	require.NoError(t, err)
+	require.False(t, key.IsZero())
+	require.Equal(t, byte(0), key[0]&7)
+	require.Equal(t, byte(64), key[31]&192)
+
+	parsed, err := wireguard.ParseKey(key.String())
+	require.NoError(t, err)
+	require.Equal(t, key, parsed)
+
+	_, err = wireguard.ParseKey("key")
+	require.Error(t, err)
+	_, err = wireguard.ParseKey("AAAA")
+	require.Error(t, err)
+
+	require.True(t, wireguard.Key{}.IsZero())
+}